@@ -0,0 +1,1427 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: eventstream/v1/events.proto
+
+package eventstreamv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// CreateEventRequest mirrors the JSON body accepted by POST /v1/events.
+// Field names match the JSON keys so payloads can move between formats unchanged.
+type CreateEventRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Event name, e.g. "page_view" (required)
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// One of: web, mobile, desktop, tv, console, other (required)
+	ChannelType       string `protobuf:"bytes,2,opt,name=channel_type,json=channelType,proto3" json:"channel_type,omitempty"`
+	Timestamp         int64  `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	PreviousTimestamp int64  `protobuf:"varint,4,opt,name=previous_timestamp,json=previousTimestamp,proto3" json:"previous_timestamp,omitempty"`
+	// RFC3339 event date, e.g. "2024-01-15T10:30:00Z"
+	Date          string   `protobuf:"bytes,5,opt,name=date,proto3" json:"date,omitempty"`
+	EventParams   []*Param `protobuf:"bytes,6,rep,name=event_params,json=eventParams,proto3" json:"event_params,omitempty"`
+	UserId        string   `protobuf:"bytes,7,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	UserPseudoId  string   `protobuf:"bytes,8,opt,name=user_pseudo_id,json=userPseudoId,proto3" json:"user_pseudo_id,omitempty"`
+	UserParams    []*Param `protobuf:"bytes,9,rep,name=user_params,json=userParams,proto3" json:"user_params,omitempty"`
+	Device        *Device  `protobuf:"bytes,10,opt,name=device,proto3" json:"device,omitempty"`
+	AppInfo       *AppInfo `protobuf:"bytes,11,opt,name=app_info,json=appInfo,proto3" json:"app_info,omitempty"`
+	Items         []*Item  `protobuf:"bytes,12,rep,name=items,proto3" json:"items,omitempty"`
+	Geo           *Geo     `protobuf:"bytes,13,opt,name=geo,proto3" json:"geo,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateEventRequest) Reset() {
+	*x = CreateEventRequest{}
+	mi := &file_eventstream_v1_events_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateEventRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateEventRequest) ProtoMessage() {}
+
+func (x *CreateEventRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_eventstream_v1_events_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateEventRequest.ProtoReflect.Descriptor instead.
+func (*CreateEventRequest) Descriptor() ([]byte, []int) {
+	return file_eventstream_v1_events_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CreateEventRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateEventRequest) GetChannelType() string {
+	if x != nil {
+		return x.ChannelType
+	}
+	return ""
+}
+
+func (x *CreateEventRequest) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *CreateEventRequest) GetPreviousTimestamp() int64 {
+	if x != nil {
+		return x.PreviousTimestamp
+	}
+	return 0
+}
+
+func (x *CreateEventRequest) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *CreateEventRequest) GetEventParams() []*Param {
+	if x != nil {
+		return x.EventParams
+	}
+	return nil
+}
+
+func (x *CreateEventRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CreateEventRequest) GetUserPseudoId() string {
+	if x != nil {
+		return x.UserPseudoId
+	}
+	return ""
+}
+
+func (x *CreateEventRequest) GetUserParams() []*Param {
+	if x != nil {
+		return x.UserParams
+	}
+	return nil
+}
+
+func (x *CreateEventRequest) GetDevice() *Device {
+	if x != nil {
+		return x.Device
+	}
+	return nil
+}
+
+func (x *CreateEventRequest) GetAppInfo() *AppInfo {
+	if x != nil {
+		return x.AppInfo
+	}
+	return nil
+}
+
+func (x *CreateEventRequest) GetItems() []*Item {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *CreateEventRequest) GetGeo() *Geo {
+	if x != nil {
+		return x.Geo
+	}
+	return nil
+}
+
+// CreateEventBatchRequest mirrors the JSON body accepted by POST /v1/events/batch
+type CreateEventBatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Events        []*CreateEventRequest  `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateEventBatchRequest) Reset() {
+	*x = CreateEventBatchRequest{}
+	mi := &file_eventstream_v1_events_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateEventBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateEventBatchRequest) ProtoMessage() {}
+
+func (x *CreateEventBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_eventstream_v1_events_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateEventBatchRequest.ProtoReflect.Descriptor instead.
+func (*CreateEventBatchRequest) Descriptor() ([]byte, []int) {
+	return file_eventstream_v1_events_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateEventBatchRequest) GetEvents() []*CreateEventRequest {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+type CreateEventResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Set when the acceptance window quarantined the event instead of storing it
+	Quarantined   bool `protobuf:"varint,2,opt,name=quarantined,proto3" json:"quarantined,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateEventResponse) Reset() {
+	*x = CreateEventResponse{}
+	mi := &file_eventstream_v1_events_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateEventResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateEventResponse) ProtoMessage() {}
+
+func (x *CreateEventResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_eventstream_v1_events_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateEventResponse.ProtoReflect.Descriptor instead.
+func (*CreateEventResponse) Descriptor() ([]byte, []int) {
+	return file_eventstream_v1_events_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateEventResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CreateEventResponse) GetQuarantined() bool {
+	if x != nil {
+		return x.Quarantined
+	}
+	return false
+}
+
+type CreateEventBatchResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Ids   []string               `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	// IDs of the events the acceptance window quarantined instead of storing them
+	QuarantinedIds []string `protobuf:"bytes,2,rep,name=quarantined_ids,json=quarantinedIds,proto3" json:"quarantined_ids,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CreateEventBatchResponse) Reset() {
+	*x = CreateEventBatchResponse{}
+	mi := &file_eventstream_v1_events_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateEventBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateEventBatchResponse) ProtoMessage() {}
+
+func (x *CreateEventBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_eventstream_v1_events_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateEventBatchResponse.ProtoReflect.Descriptor instead.
+func (*CreateEventBatchResponse) Descriptor() ([]byte, []int) {
+	return file_eventstream_v1_events_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CreateEventBatchResponse) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+func (x *CreateEventBatchResponse) GetQuarantinedIds() []string {
+	if x != nil {
+		return x.QuarantinedIds
+	}
+	return nil
+}
+
+type StreamEventsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Ids   []string               `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	// IDs of the events the acceptance window quarantined instead of storing them
+	QuarantinedIds []string `protobuf:"bytes,2,rep,name=quarantined_ids,json=quarantinedIds,proto3" json:"quarantined_ids,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *StreamEventsResponse) Reset() {
+	*x = StreamEventsResponse{}
+	mi := &file_eventstream_v1_events_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamEventsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamEventsResponse) ProtoMessage() {}
+
+func (x *StreamEventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_eventstream_v1_events_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamEventsResponse.ProtoReflect.Descriptor instead.
+func (*StreamEventsResponse) Descriptor() ([]byte, []int) {
+	return file_eventstream_v1_events_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *StreamEventsResponse) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+func (x *StreamEventsResponse) GetQuarantinedIds() []string {
+	if x != nil {
+		return x.QuarantinedIds
+	}
+	return nil
+}
+
+// GetMetricsRequest mirrors the query parameters of GET /v1/events/metrics
+type GetMetricsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Single event name to filter by, merged with event_names
+	EventName string `protobuf:"bytes,1,opt,name=event_name,json=eventName,proto3" json:"event_name,omitempty"`
+	// RFC3339 start timestamp, or a date or date-time without offset in timezone
+	From string `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	// RFC3339 end timestamp, or a date (inclusive) or date-time without offset
+	// in timezone; defaults to now
+	To string `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	// Comma-separated dimensions: a time bucket (minute, 5min, hourly, daily,
+	// weekly, weekly_sunday, monthly, quarterly), channel, event_name or a
+	// field such as device.browser_name or event_param.<key>
+	GroupBy         string `protobuf:"bytes,4,opt,name=group_by,json=groupBy,proto3" json:"group_by,omitempty"`
+	ResolveIdentity bool   `protobuf:"varint,5,opt,name=resolve_identity,json=resolveIdentity,proto3" json:"resolve_identity,omitempty"`
+	IngestEndpoint  string `protobuf:"bytes,6,opt,name=ingest_endpoint,json=ingestEndpoint,proto3" json:"ingest_endpoint,omitempty"`
+	SdkName         string `protobuf:"bytes,7,opt,name=sdk_name,json=sdkName,proto3" json:"sdk_name,omitempty"`
+	SdkVersion      string `protobuf:"bytes,8,opt,name=sdk_version,json=sdkVersion,proto3" json:"sdk_version,omitempty"`
+	ApiKeyId        string `protobuf:"bytes,9,opt,name=api_key_id,json=apiKeyId,proto3" json:"api_key_id,omitempty"`
+	// Event names to filter by; with event_name empty too, every event is counted
+	EventNames []string `protobuf:"bytes,10,rep,name=event_names,json=eventNames,proto3" json:"event_names,omitempty"`
+	// Field filters of the form <field>:<operator>[:<value>], all of which must match
+	Filters []string `protobuf:"bytes,11,rep,name=filters,proto3" json:"filters,omitempty"`
+	// Keep the most frequent groups of the non-time dimensions and count the rest as Other
+	GroupLimit int32 `protobuf:"varint,12,opt,name=group_limit,json=groupLimit,proto3" json:"group_limit,omitempty"`
+	// IANA time zone of time buckets and of from/to without offset; defaults to UTC
+	Timezone string `protobuf:"bytes,13,opt,name=timezone,proto3" json:"timezone,omitempty"`
+	// Time buckets without events with a single time dimension: zero (default),
+	// null (counts left at 0, marked filled) or none
+	Fill string `protobuf:"bytes,14,opt,name=fill,proto3" json:"fill,omitempty"`
+	// Comma-separated measures <function>:<field> over event_param.<key> or
+	// user_param.<key>, e.g. avg:event_param.load_time_ms; functions are sum,
+	// avg, min, max, p50, p90, p95, p99 and count_distinct
+	Measures      string `protobuf:"bytes,15,opt,name=measures,proto3" json:"measures,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMetricsRequest) Reset() {
+	*x = GetMetricsRequest{}
+	mi := &file_eventstream_v1_events_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMetricsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMetricsRequest) ProtoMessage() {}
+
+func (x *GetMetricsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_eventstream_v1_events_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMetricsRequest.ProtoReflect.Descriptor instead.
+func (*GetMetricsRequest) Descriptor() ([]byte, []int) {
+	return file_eventstream_v1_events_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetMetricsRequest) GetEventName() string {
+	if x != nil {
+		return x.EventName
+	}
+	return ""
+}
+
+func (x *GetMetricsRequest) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *GetMetricsRequest) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *GetMetricsRequest) GetGroupBy() string {
+	if x != nil {
+		return x.GroupBy
+	}
+	return ""
+}
+
+func (x *GetMetricsRequest) GetResolveIdentity() bool {
+	if x != nil {
+		return x.ResolveIdentity
+	}
+	return false
+}
+
+func (x *GetMetricsRequest) GetIngestEndpoint() string {
+	if x != nil {
+		return x.IngestEndpoint
+	}
+	return ""
+}
+
+func (x *GetMetricsRequest) GetSdkName() string {
+	if x != nil {
+		return x.SdkName
+	}
+	return ""
+}
+
+func (x *GetMetricsRequest) GetSdkVersion() string {
+	if x != nil {
+		return x.SdkVersion
+	}
+	return ""
+}
+
+func (x *GetMetricsRequest) GetApiKeyId() string {
+	if x != nil {
+		return x.ApiKeyId
+	}
+	return ""
+}
+
+func (x *GetMetricsRequest) GetEventNames() []string {
+	if x != nil {
+		return x.EventNames
+	}
+	return nil
+}
+
+func (x *GetMetricsRequest) GetFilters() []string {
+	if x != nil {
+		return x.Filters
+	}
+	return nil
+}
+
+func (x *GetMetricsRequest) GetGroupLimit() int32 {
+	if x != nil {
+		return x.GroupLimit
+	}
+	return 0
+}
+
+func (x *GetMetricsRequest) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+func (x *GetMetricsRequest) GetFill() string {
+	if x != nil {
+		return x.Fill
+	}
+	return ""
+}
+
+func (x *GetMetricsRequest) GetMeasures() string {
+	if x != nil {
+		return x.Measures
+	}
+	return ""
+}
+
+type GetMetricsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Set when exactly one event was queried
+	EventName       string           `protobuf:"bytes,1,opt,name=event_name,json=eventName,proto3" json:"event_name,omitempty"`
+	From            string           `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To              string           `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	TotalCount      int64            `protobuf:"varint,4,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	UniqueUserCount int64            `protobuf:"varint,5,opt,name=unique_user_count,json=uniqueUserCount,proto3" json:"unique_user_count,omitempty"`
+	GroupedMetrics  []*GroupedMetric `protobuf:"bytes,6,rep,name=grouped_metrics,json=groupedMetrics,proto3" json:"grouped_metrics,omitempty"`
+	EventNames      []string         `protobuf:"bytes,7,rep,name=event_names,json=eventNames,proto3" json:"event_names,omitempty"`
+	GroupBy         []string         `protobuf:"bytes,8,rep,name=group_by,json=groupBy,proto3" json:"group_by,omitempty"`
+	Timezone        string           `protobuf:"bytes,9,opt,name=timezone,proto3" json:"timezone,omitempty"`
+	Fill            string           `protobuf:"bytes,10,opt,name=fill,proto3" json:"fill,omitempty"`
+	// Value per measure; measures without values are left out
+	Measures      map[string]float64 `protobuf:"bytes,11,rep,name=measures,proto3" json:"measures,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed64,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMetricsResponse) Reset() {
+	*x = GetMetricsResponse{}
+	mi := &file_eventstream_v1_events_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMetricsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMetricsResponse) ProtoMessage() {}
+
+func (x *GetMetricsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_eventstream_v1_events_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMetricsResponse.ProtoReflect.Descriptor instead.
+func (*GetMetricsResponse) Descriptor() ([]byte, []int) {
+	return file_eventstream_v1_events_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetMetricsResponse) GetEventName() string {
+	if x != nil {
+		return x.EventName
+	}
+	return ""
+}
+
+func (x *GetMetricsResponse) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *GetMetricsResponse) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *GetMetricsResponse) GetTotalCount() int64 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+func (x *GetMetricsResponse) GetUniqueUserCount() int64 {
+	if x != nil {
+		return x.UniqueUserCount
+	}
+	return 0
+}
+
+func (x *GetMetricsResponse) GetGroupedMetrics() []*GroupedMetric {
+	if x != nil {
+		return x.GroupedMetrics
+	}
+	return nil
+}
+
+func (x *GetMetricsResponse) GetEventNames() []string {
+	if x != nil {
+		return x.EventNames
+	}
+	return nil
+}
+
+func (x *GetMetricsResponse) GetGroupBy() []string {
+	if x != nil {
+		return x.GroupBy
+	}
+	return nil
+}
+
+func (x *GetMetricsResponse) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+func (x *GetMetricsResponse) GetFill() string {
+	if x != nil {
+		return x.Fill
+	}
+	return ""
+}
+
+func (x *GetMetricsResponse) GetMeasures() map[string]float64 {
+	if x != nil {
+		return x.Measures
+	}
+	return nil
+}
+
+type GroupedMetric struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	GroupKey        string                 `protobuf:"bytes,1,opt,name=group_key,json=groupKey,proto3" json:"group_key,omitempty"`
+	TotalCount      int64                  `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	UniqueUserCount int64                  `protobuf:"varint,3,opt,name=unique_user_count,json=uniqueUserCount,proto3" json:"unique_user_count,omitempty"`
+	// Value per group_by dimension; group_key joins them with "|"
+	GroupKeys map[string]string `protobuf:"bytes,4,rep,name=group_keys,json=groupKeys,proto3" json:"group_keys,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// A time bucket without events, added by fill
+	Filled bool `protobuf:"varint,5,opt,name=filled,proto3" json:"filled,omitempty"`
+	// Value per measure; measures without values are left out
+	Measures      map[string]float64 `protobuf:"bytes,6,rep,name=measures,proto3" json:"measures,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed64,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GroupedMetric) Reset() {
+	*x = GroupedMetric{}
+	mi := &file_eventstream_v1_events_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GroupedMetric) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GroupedMetric) ProtoMessage() {}
+
+func (x *GroupedMetric) ProtoReflect() protoreflect.Message {
+	mi := &file_eventstream_v1_events_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GroupedMetric.ProtoReflect.Descriptor instead.
+func (*GroupedMetric) Descriptor() ([]byte, []int) {
+	return file_eventstream_v1_events_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GroupedMetric) GetGroupKey() string {
+	if x != nil {
+		return x.GroupKey
+	}
+	return ""
+}
+
+func (x *GroupedMetric) GetTotalCount() int64 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+func (x *GroupedMetric) GetUniqueUserCount() int64 {
+	if x != nil {
+		return x.UniqueUserCount
+	}
+	return 0
+}
+
+func (x *GroupedMetric) GetGroupKeys() map[string]string {
+	if x != nil {
+		return x.GroupKeys
+	}
+	return nil
+}
+
+func (x *GroupedMetric) GetFilled() bool {
+	if x != nil {
+		return x.Filled
+	}
+	return false
+}
+
+func (x *GroupedMetric) GetMeasures() map[string]float64 {
+	if x != nil {
+		return x.Measures
+	}
+	return nil
+}
+
+type Param struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Parameter key (required)
+	Key           string  `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	StringValue   string  `protobuf:"bytes,2,opt,name=string_value,json=stringValue,proto3" json:"string_value,omitempty"`
+	NumberValue   float64 `protobuf:"fixed64,3,opt,name=number_value,json=numberValue,proto3" json:"number_value,omitempty"`
+	BooleanValue  bool    `protobuf:"varint,4,opt,name=boolean_value,json=booleanValue,proto3" json:"boolean_value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Param) Reset() {
+	*x = Param{}
+	mi := &file_eventstream_v1_events_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Param) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Param) ProtoMessage() {}
+
+func (x *Param) ProtoReflect() protoreflect.Message {
+	mi := &file_eventstream_v1_events_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Param.ProtoReflect.Descriptor instead.
+func (*Param) Descriptor() ([]byte, []int) {
+	return file_eventstream_v1_events_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Param) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *Param) GetStringValue() string {
+	if x != nil {
+		return x.StringValue
+	}
+	return ""
+}
+
+func (x *Param) GetNumberValue() float64 {
+	if x != nil {
+		return x.NumberValue
+	}
+	return 0
+}
+
+func (x *Param) GetBooleanValue() bool {
+	if x != nil {
+		return x.BooleanValue
+	}
+	return false
+}
+
+type Device struct {
+	state                  protoimpl.MessageState `protogen:"open.v1"`
+	Category               string                 `protobuf:"bytes,1,opt,name=category,proto3" json:"category,omitempty"`
+	MobileBrandName        string                 `protobuf:"bytes,2,opt,name=mobile_brand_name,json=mobileBrandName,proto3" json:"mobile_brand_name,omitempty"`
+	MobileModelName        string                 `protobuf:"bytes,3,opt,name=mobile_model_name,json=mobileModelName,proto3" json:"mobile_model_name,omitempty"`
+	OperatingSystem        string                 `protobuf:"bytes,4,opt,name=operating_system,json=operatingSystem,proto3" json:"operating_system,omitempty"`
+	OperatingSystemVersion string                 `protobuf:"bytes,5,opt,name=operating_system_version,json=operatingSystemVersion,proto3" json:"operating_system_version,omitempty"`
+	Language               string                 `protobuf:"bytes,6,opt,name=language,proto3" json:"language,omitempty"`
+	BrowserName            string                 `protobuf:"bytes,7,opt,name=browser_name,json=browserName,proto3" json:"browser_name,omitempty"`
+	BrowserVersion         string                 `protobuf:"bytes,8,opt,name=browser_version,json=browserVersion,proto3" json:"browser_version,omitempty"`
+	Hostname               string                 `protobuf:"bytes,9,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *Device) Reset() {
+	*x = Device{}
+	mi := &file_eventstream_v1_events_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Device) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Device) ProtoMessage() {}
+
+func (x *Device) ProtoReflect() protoreflect.Message {
+	mi := &file_eventstream_v1_events_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Device.ProtoReflect.Descriptor instead.
+func (*Device) Descriptor() ([]byte, []int) {
+	return file_eventstream_v1_events_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Device) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *Device) GetMobileBrandName() string {
+	if x != nil {
+		return x.MobileBrandName
+	}
+	return ""
+}
+
+func (x *Device) GetMobileModelName() string {
+	if x != nil {
+		return x.MobileModelName
+	}
+	return ""
+}
+
+func (x *Device) GetOperatingSystem() string {
+	if x != nil {
+		return x.OperatingSystem
+	}
+	return ""
+}
+
+func (x *Device) GetOperatingSystemVersion() string {
+	if x != nil {
+		return x.OperatingSystemVersion
+	}
+	return ""
+}
+
+func (x *Device) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *Device) GetBrowserName() string {
+	if x != nil {
+		return x.BrowserName
+	}
+	return ""
+}
+
+func (x *Device) GetBrowserVersion() string {
+	if x != nil {
+		return x.BrowserVersion
+	}
+	return ""
+}
+
+func (x *Device) GetHostname() string {
+	if x != nil {
+		return x.Hostname
+	}
+	return ""
+}
+
+type Geo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Continent     string                 `protobuf:"bytes,1,opt,name=continent,proto3" json:"continent,omitempty"`
+	SubContinent  string                 `protobuf:"bytes,2,opt,name=sub_continent,json=subContinent,proto3" json:"sub_continent,omitempty"`
+	Country       string                 `protobuf:"bytes,3,opt,name=country,proto3" json:"country,omitempty"`
+	Region        string                 `protobuf:"bytes,4,opt,name=region,proto3" json:"region,omitempty"`
+	Metro         string                 `protobuf:"bytes,5,opt,name=metro,proto3" json:"metro,omitempty"`
+	City          string                 `protobuf:"bytes,6,opt,name=city,proto3" json:"city,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Geo) Reset() {
+	*x = Geo{}
+	mi := &file_eventstream_v1_events_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Geo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Geo) ProtoMessage() {}
+
+func (x *Geo) ProtoReflect() protoreflect.Message {
+	mi := &file_eventstream_v1_events_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Geo.ProtoReflect.Descriptor instead.
+func (*Geo) Descriptor() ([]byte, []int) {
+	return file_eventstream_v1_events_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Geo) GetContinent() string {
+	if x != nil {
+		return x.Continent
+	}
+	return ""
+}
+
+func (x *Geo) GetSubContinent() string {
+	if x != nil {
+		return x.SubContinent
+	}
+	return ""
+}
+
+func (x *Geo) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *Geo) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *Geo) GetMetro() string {
+	if x != nil {
+		return x.Metro
+	}
+	return ""
+}
+
+func (x *Geo) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+type AppInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Version       string                 `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AppInfo) Reset() {
+	*x = AppInfo{}
+	mi := &file_eventstream_v1_events_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AppInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AppInfo) ProtoMessage() {}
+
+func (x *AppInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_eventstream_v1_events_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AppInfo.ProtoReflect.Descriptor instead.
+func (*AppInfo) Descriptor() ([]byte, []int) {
+	return file_eventstream_v1_events_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *AppInfo) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *AppInfo) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+type Item struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Brand         string                 `protobuf:"bytes,3,opt,name=brand,proto3" json:"brand,omitempty"`
+	Variant       string                 `protobuf:"bytes,4,opt,name=variant,proto3" json:"variant,omitempty"`
+	PriceInUsd    float64                `protobuf:"fixed64,5,opt,name=price_in_usd,json=priceInUsd,proto3" json:"price_in_usd,omitempty"`
+	Quantity      int64                  `protobuf:"varint,6,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	RevenueInUsd  float64                `protobuf:"fixed64,7,opt,name=revenue_in_usd,json=revenueInUsd,proto3" json:"revenue_in_usd,omitempty"`
+	LocationId    string                 `protobuf:"bytes,8,opt,name=location_id,json=locationId,proto3" json:"location_id,omitempty"`
+	ListId        string                 `protobuf:"bytes,9,opt,name=list_id,json=listId,proto3" json:"list_id,omitempty"`
+	ListName      string                 `protobuf:"bytes,10,opt,name=list_name,json=listName,proto3" json:"list_name,omitempty"`
+	PromotionId   string                 `protobuf:"bytes,11,opt,name=promotion_id,json=promotionId,proto3" json:"promotion_id,omitempty"`
+	PromotionName string                 `protobuf:"bytes,12,opt,name=promotion_name,json=promotionName,proto3" json:"promotion_name,omitempty"`
+	Params        []*Param               `protobuf:"bytes,13,rep,name=params,proto3" json:"params,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Item) Reset() {
+	*x = Item{}
+	mi := &file_eventstream_v1_events_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Item) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Item) ProtoMessage() {}
+
+func (x *Item) ProtoReflect() protoreflect.Message {
+	mi := &file_eventstream_v1_events_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Item.ProtoReflect.Descriptor instead.
+func (*Item) Descriptor() ([]byte, []int) {
+	return file_eventstream_v1_events_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Item) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Item) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Item) GetBrand() string {
+	if x != nil {
+		return x.Brand
+	}
+	return ""
+}
+
+func (x *Item) GetVariant() string {
+	if x != nil {
+		return x.Variant
+	}
+	return ""
+}
+
+func (x *Item) GetPriceInUsd() float64 {
+	if x != nil {
+		return x.PriceInUsd
+	}
+	return 0
+}
+
+func (x *Item) GetQuantity() int64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *Item) GetRevenueInUsd() float64 {
+	if x != nil {
+		return x.RevenueInUsd
+	}
+	return 0
+}
+
+func (x *Item) GetLocationId() string {
+	if x != nil {
+		return x.LocationId
+	}
+	return ""
+}
+
+func (x *Item) GetListId() string {
+	if x != nil {
+		return x.ListId
+	}
+	return ""
+}
+
+func (x *Item) GetListName() string {
+	if x != nil {
+		return x.ListName
+	}
+	return ""
+}
+
+func (x *Item) GetPromotionId() string {
+	if x != nil {
+		return x.PromotionId
+	}
+	return ""
+}
+
+func (x *Item) GetPromotionName() string {
+	if x != nil {
+		return x.PromotionName
+	}
+	return ""
+}
+
+func (x *Item) GetParams() []*Param {
+	if x != nil {
+		return x.Params
+	}
+	return nil
+}
+
+var File_eventstream_v1_events_proto protoreflect.FileDescriptor
+
+const file_eventstream_v1_events_proto_rawDesc = "" +
+	"\n" +
+	"\x1beventstream/v1/events.proto\x12\x0eeventstream.v1\"\x94\x04\n" +
+	"\x12CreateEventRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12!\n" +
+	"\fchannel_type\x18\x02 \x01(\tR\vchannelType\x12\x1c\n" +
+	"\ttimestamp\x18\x03 \x01(\x03R\ttimestamp\x12-\n" +
+	"\x12previous_timestamp\x18\x04 \x01(\x03R\x11previousTimestamp\x12\x12\n" +
+	"\x04date\x18\x05 \x01(\tR\x04date\x128\n" +
+	"\fevent_params\x18\x06 \x03(\v2\x15.eventstream.v1.ParamR\veventParams\x12\x17\n" +
+	"\auser_id\x18\a \x01(\tR\x06userId\x12$\n" +
+	"\x0euser_pseudo_id\x18\b \x01(\tR\fuserPseudoId\x126\n" +
+	"\vuser_params\x18\t \x03(\v2\x15.eventstream.v1.ParamR\n" +
+	"userParams\x12.\n" +
+	"\x06device\x18\n" +
+	" \x01(\v2\x16.eventstream.v1.DeviceR\x06device\x122\n" +
+	"\bapp_info\x18\v \x01(\v2\x17.eventstream.v1.AppInfoR\aappInfo\x12*\n" +
+	"\x05items\x18\f \x03(\v2\x14.eventstream.v1.ItemR\x05items\x12%\n" +
+	"\x03geo\x18\r \x01(\v2\x13.eventstream.v1.GeoR\x03geo\"U\n" +
+	"\x17CreateEventBatchRequest\x12:\n" +
+	"\x06events\x18\x01 \x03(\v2\".eventstream.v1.CreateEventRequestR\x06events\"G\n" +
+	"\x13CreateEventResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12 \n" +
+	"\vquarantined\x18\x02 \x01(\bR\vquarantined\"U\n" +
+	"\x18CreateEventBatchResponse\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\tR\x03ids\x12'\n" +
+	"\x0fquarantined_ids\x18\x02 \x03(\tR\x0equarantinedIds\"Q\n" +
+	"\x14StreamEventsResponse\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\tR\x03ids\x12'\n" +
+	"\x0fquarantined_ids\x18\x02 \x03(\tR\x0equarantinedIds\"\xc7\x03\n" +
+	"\x11GetMetricsRequest\x12\x1d\n" +
+	"\n" +
+	"event_name\x18\x01 \x01(\tR\teventName\x12\x12\n" +
+	"\x04from\x18\x02 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x03 \x01(\tR\x02to\x12\x19\n" +
+	"\bgroup_by\x18\x04 \x01(\tR\agroupBy\x12)\n" +
+	"\x10resolve_identity\x18\x05 \x01(\bR\x0fresolveIdentity\x12'\n" +
+	"\x0fingest_endpoint\x18\x06 \x01(\tR\x0eingestEndpoint\x12\x19\n" +
+	"\bsdk_name\x18\a \x01(\tR\asdkName\x12\x1f\n" +
+	"\vsdk_version\x18\b \x01(\tR\n" +
+	"sdkVersion\x12\x1c\n" +
+	"\n" +
+	"api_key_id\x18\t \x01(\tR\bapiKeyId\x12\x1f\n" +
+	"\vevent_names\x18\n" +
+	" \x03(\tR\n" +
+	"eventNames\x12\x18\n" +
+	"\afilters\x18\v \x03(\tR\afilters\x12\x1f\n" +
+	"\vgroup_limit\x18\f \x01(\x05R\n" +
+	"groupLimit\x12\x1a\n" +
+	"\btimezone\x18\r \x01(\tR\btimezone\x12\x12\n" +
+	"\x04fill\x18\x0e \x01(\tR\x04fill\x12\x1a\n" +
+	"\bmeasures\x18\x0f \x01(\tR\bmeasures\"\xe3\x03\n" +
+	"\x12GetMetricsResponse\x12\x1d\n" +
+	"\n" +
+	"event_name\x18\x01 \x01(\tR\teventName\x12\x12\n" +
+	"\x04from\x18\x02 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x03 \x01(\tR\x02to\x12\x1f\n" +
+	"\vtotal_count\x18\x04 \x01(\x03R\n" +
+	"totalCount\x12*\n" +
+	"\x11unique_user_count\x18\x05 \x01(\x03R\x0funiqueUserCount\x12F\n" +
+	"\x0fgrouped_metrics\x18\x06 \x03(\v2\x1d.eventstream.v1.GroupedMetricR\x0egroupedMetrics\x12\x1f\n" +
+	"\vevent_names\x18\a \x03(\tR\n" +
+	"eventNames\x12\x19\n" +
+	"\bgroup_by\x18\b \x03(\tR\agroupBy\x12\x1a\n" +
+	"\btimezone\x18\t \x01(\tR\btimezone\x12\x12\n" +
+	"\x04fill\x18\n" +
+	" \x01(\tR\x04fill\x12L\n" +
+	"\bmeasures\x18\v \x03(\v20.eventstream.v1.GetMetricsResponse.MeasuresEntryR\bmeasures\x1a;\n" +
+	"\rMeasuresEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value:\x028\x01\"\xa2\x03\n" +
+	"\rGroupedMetric\x12\x1b\n" +
+	"\tgroup_key\x18\x01 \x01(\tR\bgroupKey\x12\x1f\n" +
+	"\vtotal_count\x18\x02 \x01(\x03R\n" +
+	"totalCount\x12*\n" +
+	"\x11unique_user_count\x18\x03 \x01(\x03R\x0funiqueUserCount\x12K\n" +
+	"\n" +
+	"group_keys\x18\x04 \x03(\v2,.eventstream.v1.GroupedMetric.GroupKeysEntryR\tgroupKeys\x12\x16\n" +
+	"\x06filled\x18\x05 \x01(\bR\x06filled\x12G\n" +
+	"\bmeasures\x18\x06 \x03(\v2+.eventstream.v1.GroupedMetric.MeasuresEntryR\bmeasures\x1a<\n" +
+	"\x0eGroupKeysEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a;\n" +
+	"\rMeasuresEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value:\x028\x01\"\x84\x01\n" +
+	"\x05Param\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12!\n" +
+	"\fstring_value\x18\x02 \x01(\tR\vstringValue\x12!\n" +
+	"\fnumber_value\x18\x03 \x01(\x01R\vnumberValue\x12#\n" +
+	"\rboolean_value\x18\x04 \x01(\bR\fbooleanValue\"\xe5\x02\n" +
+	"\x06Device\x12\x1a\n" +
+	"\bcategory\x18\x01 \x01(\tR\bcategory\x12*\n" +
+	"\x11mobile_brand_name\x18\x02 \x01(\tR\x0fmobileBrandName\x12*\n" +
+	"\x11mobile_model_name\x18\x03 \x01(\tR\x0fmobileModelName\x12)\n" +
+	"\x10operating_system\x18\x04 \x01(\tR\x0foperatingSystem\x128\n" +
+	"\x18operating_system_version\x18\x05 \x01(\tR\x16operatingSystemVersion\x12\x1a\n" +
+	"\blanguage\x18\x06 \x01(\tR\blanguage\x12!\n" +
+	"\fbrowser_name\x18\a \x01(\tR\vbrowserName\x12'\n" +
+	"\x0fbrowser_version\x18\b \x01(\tR\x0ebrowserVersion\x12\x1a\n" +
+	"\bhostname\x18\t \x01(\tR\bhostname\"\xa4\x01\n" +
+	"\x03Geo\x12\x1c\n" +
+	"\tcontinent\x18\x01 \x01(\tR\tcontinent\x12#\n" +
+	"\rsub_continent\x18\x02 \x01(\tR\fsubContinent\x12\x18\n" +
+	"\acountry\x18\x03 \x01(\tR\acountry\x12\x16\n" +
+	"\x06region\x18\x04 \x01(\tR\x06region\x12\x14\n" +
+	"\x05metro\x18\x05 \x01(\tR\x05metro\x12\x12\n" +
+	"\x04city\x18\x06 \x01(\tR\x04city\"3\n" +
+	"\aAppInfo\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\tR\aversion\"\x8e\x03\n" +
+	"\x04Item\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05brand\x18\x03 \x01(\tR\x05brand\x12\x18\n" +
+	"\avariant\x18\x04 \x01(\tR\avariant\x12 \n" +
+	"\fprice_in_usd\x18\x05 \x01(\x01R\n" +
+	"priceInUsd\x12\x1a\n" +
+	"\bquantity\x18\x06 \x01(\x03R\bquantity\x12$\n" +
+	"\x0erevenue_in_usd\x18\a \x01(\x01R\frevenueInUsd\x12\x1f\n" +
+	"\vlocation_id\x18\b \x01(\tR\n" +
+	"locationId\x12\x17\n" +
+	"\alist_id\x18\t \x01(\tR\x06listId\x12\x1b\n" +
+	"\tlist_name\x18\n" +
+	" \x01(\tR\blistName\x12!\n" +
+	"\fpromotion_id\x18\v \x01(\tR\vpromotionId\x12%\n" +
+	"\x0epromotion_name\x18\f \x01(\tR\rpromotionName\x12-\n" +
+	"\x06params\x18\r \x03(\v2\x15.eventstream.v1.ParamR\x06params2\x97\x02\n" +
+	"\fEventService\x12V\n" +
+	"\vCreateEvent\x12\".eventstream.v1.CreateEventRequest\x1a#.eventstream.v1.CreateEventResponse\x12Z\n" +
+	"\fStreamEvents\x12\".eventstream.v1.CreateEventRequest\x1a$.eventstream.v1.StreamEventsResponse(\x01\x12S\n" +
+	"\n" +
+	"GetMetrics\x12!.eventstream.v1.GetMetricsRequest\x1a\".eventstream.v1.GetMetricsResponseBGZEgithub.com/ebubekir/event-stream/api/gen/eventstream/v1;eventstreamv1b\x06proto3"
+
+var (
+	file_eventstream_v1_events_proto_rawDescOnce sync.Once
+	file_eventstream_v1_events_proto_rawDescData []byte
+)
+
+func file_eventstream_v1_events_proto_rawDescGZIP() []byte {
+	file_eventstream_v1_events_proto_rawDescOnce.Do(func() {
+		file_eventstream_v1_events_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_eventstream_v1_events_proto_rawDesc), len(file_eventstream_v1_events_proto_rawDesc)))
+	})
+	return file_eventstream_v1_events_proto_rawDescData
+}
+
+var file_eventstream_v1_events_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
+var file_eventstream_v1_events_proto_goTypes = []any{
+	(*CreateEventRequest)(nil),       // 0: eventstream.v1.CreateEventRequest
+	(*CreateEventBatchRequest)(nil),  // 1: eventstream.v1.CreateEventBatchRequest
+	(*CreateEventResponse)(nil),      // 2: eventstream.v1.CreateEventResponse
+	(*CreateEventBatchResponse)(nil), // 3: eventstream.v1.CreateEventBatchResponse
+	(*StreamEventsResponse)(nil),     // 4: eventstream.v1.StreamEventsResponse
+	(*GetMetricsRequest)(nil),        // 5: eventstream.v1.GetMetricsRequest
+	(*GetMetricsResponse)(nil),       // 6: eventstream.v1.GetMetricsResponse
+	(*GroupedMetric)(nil),            // 7: eventstream.v1.GroupedMetric
+	(*Param)(nil),                    // 8: eventstream.v1.Param
+	(*Device)(nil),                   // 9: eventstream.v1.Device
+	(*Geo)(nil),                      // 10: eventstream.v1.Geo
+	(*AppInfo)(nil),                  // 11: eventstream.v1.AppInfo
+	(*Item)(nil),                     // 12: eventstream.v1.Item
+	nil,                              // 13: eventstream.v1.GetMetricsResponse.MeasuresEntry
+	nil,                              // 14: eventstream.v1.GroupedMetric.GroupKeysEntry
+	nil,                              // 15: eventstream.v1.GroupedMetric.MeasuresEntry
+}
+var file_eventstream_v1_events_proto_depIdxs = []int32{
+	8,  // 0: eventstream.v1.CreateEventRequest.event_params:type_name -> eventstream.v1.Param
+	8,  // 1: eventstream.v1.CreateEventRequest.user_params:type_name -> eventstream.v1.Param
+	9,  // 2: eventstream.v1.CreateEventRequest.device:type_name -> eventstream.v1.Device
+	11, // 3: eventstream.v1.CreateEventRequest.app_info:type_name -> eventstream.v1.AppInfo
+	12, // 4: eventstream.v1.CreateEventRequest.items:type_name -> eventstream.v1.Item
+	10, // 5: eventstream.v1.CreateEventRequest.geo:type_name -> eventstream.v1.Geo
+	0,  // 6: eventstream.v1.CreateEventBatchRequest.events:type_name -> eventstream.v1.CreateEventRequest
+	7,  // 7: eventstream.v1.GetMetricsResponse.grouped_metrics:type_name -> eventstream.v1.GroupedMetric
+	13, // 8: eventstream.v1.GetMetricsResponse.measures:type_name -> eventstream.v1.GetMetricsResponse.MeasuresEntry
+	14, // 9: eventstream.v1.GroupedMetric.group_keys:type_name -> eventstream.v1.GroupedMetric.GroupKeysEntry
+	15, // 10: eventstream.v1.GroupedMetric.measures:type_name -> eventstream.v1.GroupedMetric.MeasuresEntry
+	8,  // 11: eventstream.v1.Item.params:type_name -> eventstream.v1.Param
+	0,  // 12: eventstream.v1.EventService.CreateEvent:input_type -> eventstream.v1.CreateEventRequest
+	0,  // 13: eventstream.v1.EventService.StreamEvents:input_type -> eventstream.v1.CreateEventRequest
+	5,  // 14: eventstream.v1.EventService.GetMetrics:input_type -> eventstream.v1.GetMetricsRequest
+	2,  // 15: eventstream.v1.EventService.CreateEvent:output_type -> eventstream.v1.CreateEventResponse
+	4,  // 16: eventstream.v1.EventService.StreamEvents:output_type -> eventstream.v1.StreamEventsResponse
+	6,  // 17: eventstream.v1.EventService.GetMetrics:output_type -> eventstream.v1.GetMetricsResponse
+	15, // [15:18] is the sub-list for method output_type
+	12, // [12:15] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
+}
+
+func init() { file_eventstream_v1_events_proto_init() }
+func file_eventstream_v1_events_proto_init() {
+	if File_eventstream_v1_events_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_eventstream_v1_events_proto_rawDesc), len(file_eventstream_v1_events_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   16,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_eventstream_v1_events_proto_goTypes,
+		DependencyIndexes: file_eventstream_v1_events_proto_depIdxs,
+		MessageInfos:      file_eventstream_v1_events_proto_msgTypes,
+	}.Build()
+	File_eventstream_v1_events_proto = out.File
+	file_eventstream_v1_events_proto_goTypes = nil
+	file_eventstream_v1_events_proto_depIdxs = nil
+}
@@ -15,30 +15,68 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/events": {
+        "/admin/quarantine": {
+            "get": {
+                "description": "Lists events held back for review, most recently quarantined first",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List quarantined events",
+                "operationId": "ListQuarantined",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 100, max 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of events to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ListQuarantineResponse"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/quarantine/discard": {
             "post": {
-                "description": "Creates a new event and persists it to the configured database",
+                "description": "Permanently deletes the given events from quarantine",
                 "tags": [
-                    "events"
+                    "admin"
                 ],
-                "summary": "Create a new event",
-                "operationId": "CreateEvent",
+                "summary": "Discard quarantined events",
+                "operationId": "DiscardQuarantined",
                 "parameters": [
                     {
-                        "description": "Event data",
-                        "name": "event",
+                        "description": "Events to discard",
+                        "name": "discard",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/CreateEventRequest"
+                            "$ref": "#/definitions/DiscardQuarantineRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/CreateEventResponse"
+                            "$ref": "#/definitions/StatusResponse"
                         }
                     },
                     "default": {
@@ -50,30 +88,30 @@ const docTemplate = `{
                 }
             }
         },
-        "/events/batch": {
+        "/admin/quarantine/release": {
             "post": {
-                "description": "Creates multiple events in a single batch operation",
+                "description": "Moves the given quarantined events into the events table, optionally clamping their dates into the acceptance window",
                 "tags": [
-                    "events"
+                    "admin"
                 ],
-                "summary": "Create multiple events",
-                "operationId": "CreateEventBatch",
+                "summary": "Release quarantined events",
+                "operationId": "ReleaseQuarantined",
                 "parameters": [
                     {
-                        "description": "Events data",
-                        "name": "events",
+                        "description": "Events to release",
+                        "name": "release",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/CreateEventBatchRequest"
+                            "$ref": "#/definitions/ReleaseQuarantineRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/CreateEventBatchResponse"
+                            "$ref": "#/definitions/ReleaseQuarantineResponse"
                         }
                     },
                     "default": {
@@ -85,46 +123,247 @@ const docTemplate = `{
                 }
             }
         },
-        "/events/metrics": {
+        "/admin/replays": {
             "get": {
-                "description": "Retrieves aggregated metrics for events with optional grouping",
+                "description": "Lists all replay jobs, most recent first",
                 "tags": [
-                    "events"
+                    "replays"
                 ],
-                "summary": "Get event metrics",
-                "operationId": "GetMetrics",
+                "summary": "List replay jobs",
+                "operationId": "ListReplays",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ListReplaysResponse"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Re-ingests the events in [from, to) that match the filters from a source: the raw event archive, the quarantine, or the other database type. The window is replayed in chunks and checkpointed after each, so a cancelled or interrupted replay resumes where it stopped. With delete_first the matching stored events of each chunk are deleted before it is replayed; with reprocess events run through the current ingestion pipeline again. Quarantined events are removed from the quarantine once replayed.",
+                "tags": [
+                    "replays"
+                ],
+                "summary": "Start a replay job",
+                "operationId": "CreateReplay",
+                "parameters": [
+                    {
+                        "description": "Replay",
+                        "name": "replay",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/CreateReplayRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/ReplayJobResponse"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/replays/{id}": {
+            "get": {
+                "tags": [
+                    "replays"
+                ],
+                "summary": "Get a replay job",
+                "operationId": "GetReplay",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Event name to filter by",
-                        "name": "event_name",
-                        "in": "query",
+                        "description": "Replay ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ReplayJobResponse"
+                        }
                     },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Changes the events per second limit of a running replay from its next batch on. Returns 409 when the replay is not running.",
+                "tags": [
+                    "replays"
+                ],
+                "summary": "Throttle a running replay",
+                "operationId": "UpdateReplay",
+                "parameters": [
                     {
                         "type": "string",
-                        "description": "Start timestamp (RFC3339 format)",
-                        "name": "from",
-                        "in": "query"
+                        "description": "Replay ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Rate",
+                        "name": "replay",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/UpdateReplayRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ReplayJobResponse"
+                        }
                     },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/replays/{id}/cancel": {
+            "post": {
+                "description": "Stops a running replay after its current batch. It can be resumed from its last checkpoint.",
+                "tags": [
+                    "replays"
+                ],
+                "summary": "Cancel a running replay",
+                "operationId": "CancelReplay",
+                "parameters": [
                     {
                         "type": "string",
-                        "description": "End timestamp (RFC3339 format)",
-                        "name": "to",
-                        "in": "query"
+                        "description": "Replay ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ReplayJobResponse"
+                        }
                     },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/replays/{id}/resume": {
+            "post": {
+                "description": "Continues a cancelled or failed replay from its last checkpoint. Returns 409 when the replay is running or has succeeded.",
+                "tags": [
+                    "replays"
+                ],
+                "summary": "Resume a replay",
+                "operationId": "ResumeReplay",
+                "parameters": [
                     {
                         "type": "string",
-                        "description": "Aggregation type: channel, daily, hourly",
-                        "name": "group_by",
-                        "in": "query"
+                        "description": "Replay ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/ReplayJobResponse"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
                     }
+                }
+            }
+        },
+        "/admin/webhooks": {
+            "get": {
+                "description": "Lists all webhook subscriptions with their delivery health",
+                "tags": [
+                    "webhooks"
                 ],
+                "summary": "List webhook subscriptions",
+                "operationId": "ListWebhooks",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/GetMetricsResponse"
+                            "$ref": "#/definitions/ListWebhooksResponse"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Registers an endpoint that receives a signed POST for every stored event matching its filters. The signing secret is only returned here.",
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Create a webhook subscription",
+                "operationId": "CreateWebhook",
+                "parameters": [
+                    {
+                        "description": "Subscription",
+                        "name": "webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/CreateWebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/WebhookResponse"
                         }
                     },
                     "default": {
@@ -135,116 +374,2464 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "AppInfoRequest": {
-            "type": "object",
-            "properties": {
+        },
+        "/admin/webhooks/{id}": {
+            "get": {
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Get a webhook subscription",
+                "operationId": "GetWebhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/WebhookResponse"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replaces a subscription's URL, filters and enabled flag, and optionally rotates its secret",
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Update a webhook subscription",
+                "operationId": "UpdateWebhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Subscription",
+                        "name": "webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/UpdateWebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/WebhookResponse"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes a subscription and its delivery history",
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Delete a webhook subscription",
+                "operationId": "DeleteWebhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/StatusResponse"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/webhooks/{id}/deliveries": {
+            "get": {
+                "description": "Lists a subscription's delivery attempts, most recent first",
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List webhook delivery attempts",
+                "operationId": "ListWebhookDeliveries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 100, max 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of attempts to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ListWebhookDeliveriesResponse"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/ecommerce/items": {
+            "get": {
+                "description": "Ranks the items, brands or variants of purchase events by revenue or quantity sold.\nWith an interval, each ranked item also has its sales per time bucket.",
+                "tags": [
+                    "ecommerce"
+                ],
+                "summary": "Get top items",
+                "operationId": "GetTopItems",
+                "parameters": [
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Purchase event names (default purchase)",
+                        "name": "event_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start timestamp: RFC3339, or a date or date-time without offset in timezone",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End timestamp: RFC3339, or a date (inclusive) or date-time without offset in timezone",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone of time buckets and local from/to, e.g. Europe/Istanbul (default UTC)",
+                        "name": "timezone",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "minute",
+                            "5min",
+                            "hourly",
+                            "daily",
+                            "weekly",
+                            "weekly_sunday",
+                            "monthly",
+                            "quarterly"
+                        ],
+                        "type": "string",
+                        "description": "Break each item's sales down by time bucket",
+                        "name": "interval",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Field filter \u003cfield\u003e:\u003coperator\u003e[:\u003cvalue\u003e], e.g. device.category:eq:mobile; repeat to combine",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "item",
+                            "brand",
+                            "variant"
+                        ],
+                        "type": "string",
+                        "default": "item",
+                        "description": "Item field to rank: item ID (name for items without one), brand or variant",
+                        "name": "by",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "revenue",
+                            "quantity"
+                        ],
+                        "type": "string",
+                        "default": "revenue",
+                        "description": "Value to rank by",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Number of items",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/GetTopItemsResponse"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/ecommerce/metrics": {
+            "get": {
+                "description": "Retrieves revenue, orders, purchasing users, average order value and units sold of purchase events.\nEach purchase event is one order; its revenue and units are the sums of its items' revenue_in_usd and quantity.",
+                "tags": [
+                    "ecommerce"
+                ],
+                "summary": "Get ecommerce metrics",
+                "operationId": "GetEcommerceMetrics",
+                "parameters": [
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Purchase event names (default purchase)",
+                        "name": "event_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start timestamp: RFC3339, or a date or date-time without offset in timezone",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End timestamp: RFC3339, or a date (inclusive) or date-time without offset in timezone",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone of time buckets and local from/to, e.g. Europe/Istanbul (default UTC)",
+                        "name": "timezone",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "minute",
+                            "5min",
+                            "hourly",
+                            "daily",
+                            "weekly",
+                            "weekly_sunday",
+                            "monthly",
+                            "quarterly"
+                        ],
+                        "type": "string",
+                        "description": "Break the metrics down by time bucket",
+                        "name": "interval",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Count purchasing users by canonical identity (user_pseudo_id resolved to user_id)",
+                        "name": "resolve_identity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Field filter \u003cfield\u003e:\u003coperator\u003e[:\u003cvalue\u003e], e.g. device.category:eq:mobile; repeat to combine",
+                        "name": "filter",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/GetEcommerceMetricsResponse"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/events": {
+            "get": {
+                "description": "Returns stored events matching the filters, newest first by default.\nPass next_cursor from a response as cursor to fetch the next page; pages are not shifted by events ingested in the meantime.",
+                "tags": [
+                    "events"
+                ],
+                "summary": "Search events",
+                "operationId": "SearchEvents",
+                "parameters": [
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Event names to match",
+                        "name": "event_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start timestamp, inclusive (RFC3339 format)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End timestamp, exclusive (RFC3339 format)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "web",
+                            "mobile",
+                            "desktop",
+                            "tv",
+                            "console",
+                            "other"
+                        ],
+                        "type": "string",
+                        "description": "Channel type",
+                        "name": "channel_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User pseudo ID",
+                        "name": "user_pseudo_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Device category",
+                        "name": "device_category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Device operating system",
+                        "name": "operating_system",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Device browser name",
+                        "name": "browser_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Device language",
+                        "name": "language",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "App ID",
+                        "name": "app_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "App version",
+                        "name": "app_version",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only events with this event param",
+                        "name": "param_key",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Value of param_key: its string value, number value, or true for a boolean",
+                        "name": "param_value",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "default": "desc",
+                        "description": "Sort order by event time",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 1000,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/SearchEventsResponse"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates a new event and persists it to the configured database.\nThe body may be JSON, protobuf (eventstream.v1.CreateEventRequest) or MessagePack, selected by Content-Type.",
+                "consumes": [
+                    "application/json",
+                    "application/x-protobuf",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Create a new event",
+                "operationId": "CreateEvent",
+                "parameters": [
+                    {
+                        "description": "Event data",
+                        "name": "event",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/CreateEventRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/CreateEventResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "The event was quarantined by the acceptance window instead of stored",
+                        "schema": {
+                            "$ref": "#/definitions/CreateEventResponse"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/batch": {
+            "post": {
+                "description": "Creates multiple events in a single batch operation.\nThe body may be JSON, protobuf (eventstream.v1.CreateEventBatchRequest) or MessagePack, selected by Content-Type.",
+                "consumes": [
+                    "application/json",
+                    "application/x-protobuf",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Create multiple events",
+                "operationId": "CreateEventBatch",
+                "parameters": [
+                    {
+                        "description": "Events data",
+                        "name": "events",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/CreateEventBatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/CreateEventBatchResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Some events were quarantined by the acceptance window; see quarantined_ids",
+                        "schema": {
+                            "$ref": "#/definitions/CreateEventBatchResponse"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/metrics": {
+            "get": {
+                "description": "Retrieves aggregated metrics for one, several or all events with optional grouping",
+                "tags": [
+                    "events"
+                ],
+                "summary": "Get event metrics",
+                "operationId": "GetMetrics",
+                "parameters": [
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Event names to filter by; none counts every event",
+                        "name": "event_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start timestamp: RFC3339, or a date or date-time without offset in timezone",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End timestamp: RFC3339, or a date (inclusive) or date-time without offset in timezone",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone of time buckets and local from/to, e.g. Europe/Istanbul (default UTC)",
+                        "name": "timezone",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated dimensions: minute, 5min, hourly, daily, weekly, weekly_sunday, monthly, quarterly, channel, event_name, device.\u003cfield\u003e, app_info.id, app_info.version, channel_type, event_param.\u003ckey\u003e or user_param.\u003ckey\u003e",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Keep the most frequent groups of the non-time dimensions and count the rest as Other (max 1000)",
+                        "name": "group_limit",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "zero",
+                            "null",
+                            "none"
+                        ],
+                        "type": "string",
+                        "description": "Time buckets without events, with a single time dimension: zero (default), null or none",
+                        "name": "fill",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated measures \u003cfunction\u003e:\u003cfield\u003e over event_param.\u003ckey\u003e or user_param.\u003ckey\u003e, e.g. avg:event_param.load_time_ms; functions: sum, avg, min, max, p50, p90, p95, p99, count_distinct",
+                        "name": "measures",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Count unique users by canonical identity (user_pseudo_id resolved to user_id)",
+                        "name": "resolve_identity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only count events received on this endpoint, e.g. /v1/events/batch",
+                        "name": "ingest_endpoint",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only count events sent by this SDK",
+                        "name": "sdk_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only count events sent by this SDK version",
+                        "name": "sdk_version",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only count events sent with this API key ID",
+                        "name": "api_key_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Field filter \u003cfield\u003e:\u003coperator\u003e[:\u003cvalue\u003e], e.g. event_param.value:gt:100; repeat to combine",
+                        "name": "filter",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/GetMetricsResponse"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/tail": {
+            "get": {
+                "description": "Streams events as they are persisted, as Server-Sent Events. Each SSE event is named after the message type\nand carries a TailMessage: \"event\" messages hold an event, \"dropped\" messages report how many events were\nskipped because the client fell behind. Only events ingested after connecting are sent.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Stream newly ingested events",
+                "operationId": "TailEvents",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event name",
+                        "name": "event_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User pseudo ID",
+                        "name": "user_pseudo_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "web",
+                            "mobile",
+                            "desktop",
+                            "tv",
+                            "console",
+                            "other"
+                        ],
+                        "type": "string",
+                        "description": "Channel type",
+                        "name": "channel_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "App ID",
+                        "name": "app_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/TailMessage"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/tail/ws": {
+            "get": {
+                "description": "Upgrades to a WebSocket and sends one JSON TailMessage per text frame, with the same filters and messages\nas the Server-Sent Events tail. Messages sent by the client are ignored.",
+                "tags": [
+                    "events"
+                ],
+                "summary": "Stream newly ingested events over WebSocket",
+                "operationId": "TailEventsWebSocket",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event name",
+                        "name": "event_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User pseudo ID",
+                        "name": "user_pseudo_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "web",
+                            "mobile",
+                            "desktop",
+                            "tv",
+                            "console",
+                            "other"
+                        ],
+                        "type": "string",
+                        "description": "Channel type",
+                        "name": "channel_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "App ID",
+                        "name": "app_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols",
+                        "schema": {
+                            "$ref": "#/definitions/TailMessage"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}": {
+            "get": {
+                "description": "Returns a stored event by the ID CreateEvent returned, with its ingestion metadata.",
+                "tags": [
+                    "events"
+                ],
+                "summary": "Get an event",
+                "operationId": "GetEvent",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/EventResponse"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/exports": {
+            "get": {
+                "description": "Lists all export jobs, most recent first",
+                "tags": [
+                    "exports"
+                ],
+                "summary": "List export jobs",
+                "operationId": "ListExports",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ListExportsResponse"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Starts exporting the raw events in [from, to) that match the filters. NDJSON and Parquet keep params and items nested; CSV flattens the requested params, or every param key in the range, into columns. Poll the job and download its output once it has succeeded.",
+                "tags": [
+                    "exports"
+                ],
+                "summary": "Start an export job",
+                "operationId": "CreateExport",
+                "parameters": [
+                    {
+                        "description": "Export",
+                        "name": "export",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/CreateExportRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/ExportJobResponse"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/exports/{id}": {
+            "get": {
+                "tags": [
+                    "exports"
+                ],
+                "summary": "Get an export job",
+                "operationId": "GetExport",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Export ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ExportJobResponse"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/exports/{id}/download": {
+            "get": {
+                "description": "Streams the output of a succeeded export job. Returns 409 while the job is pending or running, or when it failed.",
+                "produces": [
+                    "application/x-ndjson",
+                    "text/csv",
+                    "application/vnd.apache.parquet"
+                ],
+                "tags": [
+                    "exports"
+                ],
+                "summary": "Download an export",
+                "operationId": "DownloadExport",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Export ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/identify": {
+            "post": {
+                "description": "Aliases a user_pseudo_id to a user_id so earlier anonymous events are attributed to that user",
+                "tags": [
+                    "identity"
+                ],
+                "summary": "Link an anonymous user to a known user",
+                "operationId": "Identify",
+                "parameters": [
+                    {
+                        "description": "Identity link",
+                        "name": "identity",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/IdentifyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/StatusResponse"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/events": {
+            "get": {
+                "description": "Returns the events of a user_id, or of a user_pseudo_id with id_type=user_pseudo_id, oldest first by default.\nPass next_cursor from a response as cursor to fetch the next page.",
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get a user's event timeline",
+                "operationId": "GetUserTimeline",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID or user pseudo ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "user_id",
+                            "user_pseudo_id"
+                        ],
+                        "type": "string",
+                        "default": "user_id",
+                        "description": "Which ID the path holds",
+                        "name": "id_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Event names to match",
+                        "name": "event_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start timestamp, inclusive (RFC3339 format)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End timestamp, exclusive (RFC3339 format)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "default": "asc",
+                        "description": "Sort order by event time",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 1000,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/SearchEventsResponse"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "AppInfoRequest": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "AppInfoResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "CreateEventBatchRequest": {
+            "type": "object",
+            "required": [
+                "events"
+            ],
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/CreateEventRequest"
+                    }
+                }
+            }
+        },
+        "CreateEventBatchResponse": {
+            "type": "object",
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "quarantined_ids": {
+                    "description": "events held back for review instead of stored",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "CreateEventRequest": {
+            "type": "object",
+            "required": [
+                "channel_type",
+                "name"
+            ],
+            "properties": {
+                "app_info": {
+                    "$ref": "#/definitions/AppInfoRequest"
+                },
+                "channel_type": {
+                    "type": "string",
+                    "enum": [
+                        "web",
+                        "mobile",
+                        "desktop",
+                        "tv",
+                        "console",
+                        "other"
+                    ]
+                },
+                "date": {
+                    "type": "string"
+                },
+                "device": {
+                    "$ref": "#/definitions/DeviceRequest"
+                },
+                "event_params": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ParamRequest"
+                    }
+                },
+                "geo": {
+                    "$ref": "#/definitions/GeoRequest"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ItemRequest"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "previous_timestamp": {
+                    "type": "integer"
+                },
+                "timestamp": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "user_params": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ParamRequest"
+                    }
+                },
+                "user_pseudo_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "CreateEventResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "quarantined events are held back for review and not stored",
+                    "type": "string",
+                    "enum": [
+                        "stored",
+                        "quarantined"
+                    ]
+                }
+            }
+        },
+        "CreateExportRequest": {
+            "type": "object",
+            "required": [
+                "format",
+                "from"
+            ],
+            "properties": {
+                "app_id": {
+                    "type": "string"
+                },
+                "channel_type": {
+                    "type": "string"
+                },
+                "event_names": {
+                    "description": "empty matches every event",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "event_params": {
+                    "description": "CSV only: event param keys flattened into columns",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "format": {
+                    "type": "string",
+                    "enum": [
+                        "ndjson",
+                        "csv",
+                        "parquet"
+                    ]
+                },
+                "from": {
+                    "description": "RFC3339 format, inclusive",
+                    "type": "string"
+                },
+                "to": {
+                    "description": "RFC3339 format, exclusive; defaults to now",
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "user_params": {
+                    "description": "CSV only: user param keys flattened into columns",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "user_pseudo_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "CreateReplayRequest": {
+            "type": "object",
+            "required": [
+                "from",
+                "source",
+                "to"
+            ],
+            "properties": {
+                "app_id": {
+                    "type": "string"
+                },
+                "channel_type": {
+                    "type": "string"
+                },
+                "chunk_size": {
+                    "description": "Go duration replayed between checkpoints, e.g. \"1h\" (default)",
+                    "type": "string"
+                },
+                "delete_first": {
+                    "description": "delete the matching events in each chunk before replaying it",
+                    "type": "boolean"
+                },
+                "event_names": {
+                    "description": "empty matches every event",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "from": {
+                    "description": "RFC3339 format, inclusive",
+                    "type": "string"
+                },
+                "rate": {
+                    "description": "events per second; 0 is unlimited",
+                    "type": "number",
+                    "minimum": 0
+                },
+                "reprocess": {
+                    "description": "run events through the current ingestion pipeline",
+                    "type": "boolean"
+                },
+                "source": {
+                    "description": "archive, quarantine, or the other database type: postgres, clickhouse",
+                    "type": "string"
+                },
+                "to": {
+                    "description": "RFC3339 format, exclusive",
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "user_pseudo_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "CreateWebhookRequest": {
+            "type": "object",
+            "required": [
+                "url"
+            ],
+            "properties": {
+                "event_names": {
+                    "description": "empty matches every event",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "param_filters": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/WebhookParamFilterRequest"
+                    }
+                },
+                "secret": {
+                    "description": "generated when empty",
+                    "type": "string",
+                    "minLength": 16
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "DeviceRequest": {
+            "type": "object",
+            "properties": {
+                "browser_name": {
+                    "type": "string"
+                },
+                "browser_version": {
+                    "type": "string"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "hostname": {
+                    "type": "string"
+                },
+                "language": {
+                    "type": "string"
+                },
+                "mobile_brand_name": {
+                    "type": "string"
+                },
+                "mobile_model_name": {
+                    "type": "string"
+                },
+                "operating_system": {
+                    "type": "string"
+                },
+                "operating_system_version": {
+                    "type": "string"
+                }
+            }
+        },
+        "DeviceResponse": {
+            "type": "object",
+            "properties": {
+                "browser_name": {
+                    "type": "string"
+                },
+                "browser_version": {
+                    "type": "string"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "hostname": {
+                    "type": "string"
+                },
+                "language": {
+                    "type": "string"
+                },
+                "mobile_brand_name": {
+                    "type": "string"
+                },
+                "mobile_model_name": {
+                    "type": "string"
+                },
+                "operating_system": {
+                    "type": "string"
+                },
+                "operating_system_version": {
+                    "type": "string"
+                }
+            }
+        },
+        "DiscardQuarantineRequest": {
+            "type": "object",
+            "required": [
+                "ids"
+            ],
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "EcommerceBucketResponse": {
+            "type": "object",
+            "properties": {
+                "average_order_value": {
+                    "description": "revenue per order in USD",
+                    "type": "number"
+                },
+                "bucket": {
+                    "description": "first day, or start time for buckets shorter than a day",
+                    "type": "string"
+                },
+                "orders": {
+                    "description": "purchase events",
+                    "type": "integer"
+                },
+                "purchasing_users": {
+                    "type": "integer"
+                },
+                "revenue": {
+                    "description": "sum of item revenue in USD",
+                    "type": "number"
+                },
+                "units_sold": {
+                    "description": "sum of item quantities",
+                    "type": "integer"
+                }
+            }
+        },
+        "EcommerceMetricsResponse": {
+            "type": "object",
+            "properties": {
+                "average_order_value": {
+                    "description": "revenue per order in USD",
+                    "type": "number"
+                },
+                "orders": {
+                    "description": "purchase events",
+                    "type": "integer"
+                },
+                "purchasing_users": {
+                    "type": "integer"
+                },
+                "revenue": {
+                    "description": "sum of item revenue in USD",
+                    "type": "number"
+                },
+                "units_sold": {
+                    "description": "sum of item quantities",
+                    "type": "integer"
+                }
+            }
+        },
+        "Error": {
+            "type": "object",
+            "required": [
+                "code",
+                "message"
+            ],
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "EventResponse": {
+            "type": "object",
+            "properties": {
+                "app_info": {
+                    "$ref": "#/definitions/AppInfoResponse"
+                },
+                "channel_type": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "device": {
+                    "$ref": "#/definitions/DeviceResponse"
+                },
+                "event_params": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ParamResponse"
+                    }
+                },
+                "geo": {
+                    "$ref": "#/definitions/GeoResponse"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "ingestion": {
+                    "$ref": "#/definitions/IngestionResponse"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ItemResponse"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "previous_timestamp": {
+                    "type": "integer"
+                },
+                "timestamp": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "user_params": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ParamResponse"
+                    }
+                },
+                "user_pseudo_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "ExportJobResponse": {
+            "type": "object",
+            "properties": {
+                "app_id": {
+                    "type": "string"
+                },
+                "channel_type": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "event_count": {
+                    "type": "integer"
+                },
+                "event_names": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "format": {
+                    "type": "string"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "pending, running, succeeded, failed",
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "user_pseudo_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "GeoRequest": {
+            "type": "object",
+            "properties": {
+                "city": {
+                    "type": "string"
+                },
+                "continent": {
+                    "type": "string"
+                },
+                "country": {
+                    "type": "string"
+                },
+                "metro": {
+                    "type": "string"
+                },
+                "region": {
+                    "type": "string"
+                },
+                "sub_continent": {
+                    "type": "string"
+                }
+            }
+        },
+        "GeoResponse": {
+            "type": "object",
+            "properties": {
+                "city": {
+                    "type": "string"
+                },
+                "continent": {
+                    "type": "string"
+                },
+                "country": {
+                    "type": "string"
+                },
+                "metro": {
+                    "type": "string"
+                },
+                "region": {
+                    "type": "string"
+                },
+                "sub_continent": {
+                    "type": "string"
+                }
+            }
+        },
+        "GetEcommerceMetricsResponse": {
+            "type": "object",
+            "properties": {
+                "buckets": {
+                    "description": "buckets with orders, oldest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/EcommerceBucketResponse"
+                    }
+                },
+                "event_names": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "from": {
+                    "type": "string"
+                },
+                "interval": {
+                    "type": "string"
+                },
+                "timezone": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "totals": {
+                    "$ref": "#/definitions/EcommerceMetricsResponse"
+                }
+            }
+        },
+        "GetMetricsResponse": {
+            "type": "object",
+            "properties": {
+                "event_name": {
+                    "description": "the queried event when exactly one was given, else empty",
+                    "type": "string"
+                },
+                "event_names": {
+                    "description": "empty when every event was counted",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "fill": {
+                    "type": "string"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "group_by": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "grouped_metrics": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/GroupedMetricResponse"
+                    }
+                },
+                "measures": {
+                    "description": "value per measure, null without values",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number",
+                        "format": "float64"
+                    }
+                },
+                "timezone": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "total_count": {
+                    "type": "integer"
+                },
+                "unique_user_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "GetTopItemsResponse": {
+            "type": "object",
+            "properties": {
+                "by": {
+                    "type": "string"
+                },
+                "event_names": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "from": {
+                    "type": "string"
+                },
+                "interval": {
+                    "type": "string"
+                },
+                "items": {
+                    "description": "highest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ItemMetricsResponse"
+                    }
+                },
+                "sort_by": {
+                    "type": "string"
+                },
+                "timezone": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "GroupedMetricResponse": {
+            "type": "object",
+            "properties": {
+                "filled": {
+                    "description": "a time bucket without events",
+                    "type": "boolean"
+                },
+                "group_key": {
+                    "description": "group_keys values joined by \"|\"",
+                    "type": "string"
+                },
+                "group_keys": {
+                    "description": "value per group_by dimension",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "measures": {
+                    "description": "value per measure, null without values",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number",
+                        "format": "float64"
+                    }
+                },
+                "total_count": {
+                    "description": "null for filled buckets with fill=null",
+                    "type": "integer"
+                },
+                "unique_user_count": {
+                    "description": "null for filled buckets with fill=null",
+                    "type": "integer"
+                }
+            }
+        },
+        "IdentifyRequest": {
+            "type": "object",
+            "required": [
+                "user_id",
+                "user_pseudo_id"
+            ],
+            "properties": {
+                "user_id": {
+                    "type": "string"
+                },
+                "user_pseudo_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "IngestionResponse": {
+            "type": "object",
+            "properties": {
+                "api_key_id": {
+                    "type": "string"
+                },
+                "client_ip_hash": {
+                    "type": "string"
+                },
+                "endpoint": {
+                    "type": "string"
+                },
+                "received_at": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "sdk_name": {
+                    "type": "string"
+                },
+                "sdk_version": {
+                    "type": "string"
+                }
+            }
+        },
+        "ItemBucketResponse": {
+            "type": "object",
+            "properties": {
+                "bucket": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "revenue": {
+                    "type": "number"
+                }
+            }
+        },
+        "ItemMetricsResponse": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "description": "item ID, brand or variant",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "item name, when ranking by item",
+                    "type": "string"
+                },
+                "orders": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "revenue": {
+                    "type": "number"
+                },
+                "series": {
+                    "description": "buckets with sales, oldest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ItemBucketResponse"
+                    }
+                }
+            }
+        },
+        "ItemRequest": {
+            "type": "object",
+            "properties": {
+                "brand": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "list_id": {
+                    "type": "string"
+                },
+                "list_name": {
+                    "type": "string"
+                },
+                "location_id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "params": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ParamRequest"
+                    }
+                },
+                "price_in_usd": {
+                    "type": "number"
+                },
+                "promotion_id": {
+                    "type": "string"
+                },
+                "promotion_name": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "revenue_in_usd": {
+                    "type": "number"
+                },
+                "variant": {
+                    "type": "string"
+                }
+            }
+        },
+        "ItemResponse": {
+            "type": "object",
+            "properties": {
+                "brand": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "list_id": {
+                    "type": "string"
+                },
+                "list_name": {
+                    "type": "string"
+                },
+                "location_id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "params": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ParamResponse"
+                    }
+                },
+                "price_in_usd": {
+                    "type": "number"
+                },
+                "promotion_id": {
+                    "type": "string"
+                },
+                "promotion_name": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "revenue_in_usd": {
+                    "type": "number"
+                },
+                "variant": {
+                    "type": "string"
+                }
+            }
+        },
+        "ListExportsResponse": {
+            "type": "object",
+            "properties": {
+                "exports": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ExportJobResponse"
+                    }
+                }
+            }
+        },
+        "ListQuarantineResponse": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/QuarantinedEventResponse"
+                    }
+                }
+            }
+        },
+        "ListReplaysResponse": {
+            "type": "object",
+            "properties": {
+                "replays": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ReplayJobResponse"
+                    }
+                }
+            }
+        },
+        "ListWebhookDeliveriesResponse": {
+            "type": "object",
+            "properties": {
+                "deliveries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/WebhookDeliveryResponse"
+                    }
+                }
+            }
+        },
+        "ListWebhooksResponse": {
+            "type": "object",
+            "properties": {
+                "webhooks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/WebhookResponse"
+                    }
+                }
+            }
+        },
+        "ParamRequest": {
+            "type": "object",
+            "required": [
+                "key"
+            ],
+            "properties": {
+                "boolean_value": {
+                    "type": "boolean"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "number_value": {
+                    "type": "number"
+                },
+                "string_value": {
+                    "type": "string"
+                }
+            }
+        },
+        "ParamResponse": {
+            "type": "object",
+            "properties": {
+                "boolean_value": {
+                    "type": "boolean"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "number_value": {
+                    "type": "number"
+                },
+                "string_value": {
+                    "type": "string"
+                }
+            }
+        },
+        "QuarantinedEventResponse": {
+            "type": "object",
+            "properties": {
+                "channel_type": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "quarantined_at": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "user_pseudo_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "ReleaseQuarantineRequest": {
+            "type": "object",
+            "required": [
+                "ids"
+            ],
+            "properties": {
+                "clamp": {
+                    "description": "move dates into the current acceptance window",
+                    "type": "boolean"
+                },
+                "ids": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "ReleaseQuarantineResponse": {
+            "type": "object",
+            "properties": {
+                "released": {
+                    "type": "integer"
+                }
+            }
+        },
+        "ReplayJobResponse": {
+            "type": "object",
+            "properties": {
+                "app_id": {
+                    "type": "string"
+                },
+                "channel_type": {
+                    "type": "string"
+                },
+                "checkpoint": {
+                    "description": "events before this time have been replayed",
+                    "type": "string"
+                },
+                "chunk_size": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "delete_first": {
+                    "type": "boolean"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "event_count": {
+                    "type": "integer"
+                },
+                "event_names": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "from": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "string"
                 },
-                "version": {
+                "rate": {
+                    "type": "number"
+                },
+                "reprocess": {
+                    "type": "boolean"
+                },
+                "runs": {
+                    "type": "integer"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "pending, running, succeeded, failed, cancelled",
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "user_pseudo_id": {
                     "type": "string"
                 }
             }
         },
-        "CreateEventBatchRequest": {
+        "SearchEventsResponse": {
             "type": "object",
-            "required": [
-                "events"
-            ],
             "properties": {
                 "events": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/CreateEventRequest"
+                        "$ref": "#/definitions/EventResponse"
                     }
+                },
+                "next_cursor": {
+                    "description": "NextCursor fetches the next page when passed as cursor; empty on the last page",
+                    "type": "string"
                 }
             }
         },
-        "CreateEventBatchResponse": {
+        "StatusResponse": {
             "type": "object",
+            "required": [
+                "code",
+                "message"
+            ],
             "properties": {
-                "ids": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "code": {
+                    "description": "Code of status which is always 200",
+                    "type": "integer"
+                },
+                "message": {
+                    "description": "Message",
+                    "type": "string"
                 }
             }
         },
-        "CreateEventRequest": {
+        "TailMessage": {
             "type": "object",
-            "required": [
-                "channel_type",
-                "name"
-            ],
             "properties": {
-                "app_info": {
-                    "$ref": "#/definitions/AppInfoRequest"
+                "dropped": {
+                    "description": "total dropped since the tail started, set for dropped messages",
+                    "type": "integer"
                 },
-                "channel_type": {
-                    "type": "string",
-                    "enum": [
-                        "web",
-                        "mobile",
-                        "desktop",
-                        "tv",
-                        "console",
-                        "other"
+                "event": {
+                    "description": "set for event messages",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/eventcodec.Record"
+                        }
                     ]
                 },
-                "date": {
+                "type": {
+                    "description": "event, dropped",
                     "type": "string"
+                }
+            }
+        },
+        "UpdateReplayRequest": {
+            "type": "object",
+            "required": [
+                "rate"
+            ],
+            "properties": {
+                "rate": {
+                    "description": "events per second; 0 is unlimited",
+                    "type": "number",
+                    "minimum": 0
+                }
+            }
+        },
+        "UpdateWebhookRequest": {
+            "type": "object",
+            "required": [
+                "url"
+            ],
+            "properties": {
+                "enabled": {
+                    "description": "re-enabling resets the failure count",
+                    "type": "boolean"
                 },
-                "device": {
-                    "$ref": "#/definitions/DeviceRequest"
-                },
-                "event_params": {
+                "event_names": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/ParamRequest"
+                        "type": "string"
                     }
                 },
-                "items": {
+                "param_filters": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/ItemRequest"
+                        "$ref": "#/definitions/WebhookParamFilterRequest"
                     }
                 },
-                "name": {
+                "secret": {
+                    "description": "rotates the secret when set",
+                    "type": "string",
+                    "minLength": 16
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "WebhookDeliveryResponse": {
+            "type": "object",
+            "properties": {
+                "attempt": {
+                    "type": "integer"
+                },
+                "attempted_at": {
                     "type": "string"
                 },
-                "previous_timestamp": {
+                "duration_ms": {
                     "type": "integer"
                 },
-                "timestamp": {
+                "error": {
+                    "type": "string"
+                },
+                "event_id": {
+                    "type": "string"
+                },
+                "event_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "status_code": {
                     "type": "integer"
                 },
-                "user_id": {
+                "succeeded": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "WebhookParamFilterRequest": {
+            "type": "object",
+            "required": [
+                "key"
+            ],
+            "properties": {
+                "key": {
                     "type": "string"
                 },
-                "user_params": {
+                "value": {
+                    "description": "compared with the param's string, number or boolean value as text",
+                    "type": "string"
+                }
+            }
+        },
+        "WebhookResponse": {
+            "type": "object",
+            "properties": {
+                "consecutive_failures": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "disabled_reason": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "event_names": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/ParamRequest"
+                        "type": "string"
                     }
                 },
-                "user_pseudo_id": {
+                "id": {
+                    "type": "string"
+                },
+                "param_filters": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/WebhookParamFilterRequest"
+                    }
+                },
+                "secret": {
+                    "description": "only returned when created or rotated",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
                     "type": "string"
                 }
             }
         },
-        "CreateEventResponse": {
+        "eventcodec.AppInfo": {
             "type": "object",
             "properties": {
                 "id": {
                     "type": "string"
+                },
+                "version": {
+                    "type": "string"
                 }
             }
         },
-        "DeviceRequest": {
+        "eventcodec.Device": {
             "type": "object",
             "properties": {
                 "browser_name": {
@@ -276,62 +2863,56 @@ const docTemplate = `{
                 }
             }
         },
-        "Error": {
+        "eventcodec.Geo": {
             "type": "object",
-            "required": [
-                "code",
-                "message"
-            ],
             "properties": {
-                "code": {
-                    "type": "integer"
+                "city": {
+                    "type": "string"
                 },
-                "message": {
+                "continent": {
+                    "type": "string"
+                },
+                "country": {
+                    "type": "string"
+                },
+                "metro": {
+                    "type": "string"
+                },
+                "region": {
+                    "type": "string"
+                },
+                "sub_continent": {
                     "type": "string"
                 }
             }
         },
-        "GetMetricsResponse": {
+        "eventcodec.Ingestion": {
             "type": "object",
             "properties": {
-                "event_name": {
+                "api_key_id": {
                     "type": "string"
                 },
-                "from": {
+                "client_ip_hash": {
                     "type": "string"
                 },
-                "grouped_metrics": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/GroupedMetricResponse"
-                    }
-                },
-                "to": {
+                "endpoint": {
                     "type": "string"
                 },
-                "total_count": {
-                    "type": "integer"
+                "received_at": {
+                    "type": "string"
                 },
-                "unique_user_count": {
-                    "type": "integer"
-                }
-            }
-        },
-        "GroupedMetricResponse": {
-            "type": "object",
-            "properties": {
-                "group_key": {
+                "request_id": {
                     "type": "string"
                 },
-                "total_count": {
-                    "type": "integer"
+                "sdk_name": {
+                    "type": "string"
                 },
-                "unique_user_count": {
-                    "type": "integer"
+                "sdk_version": {
+                    "type": "string"
                 }
             }
         },
-        "ItemRequest": {
+        "eventcodec.Item": {
             "type": "object",
             "properties": {
                 "brand": {
@@ -355,7 +2936,7 @@ const docTemplate = `{
                 "params": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/ParamRequest"
+                        "$ref": "#/definitions/eventcodec.Param"
                     }
                 },
                 "price_in_usd": {
@@ -378,11 +2959,8 @@ const docTemplate = `{
                 }
             }
         },
-        "ParamRequest": {
+        "eventcodec.Param": {
             "type": "object",
-            "required": [
-                "key"
-            ],
             "properties": {
                 "boolean_value": {
                     "type": "boolean"
@@ -397,6 +2975,65 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "eventcodec.Record": {
+            "type": "object",
+            "properties": {
+                "app_info": {
+                    "$ref": "#/definitions/eventcodec.AppInfo"
+                },
+                "channel_type": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "device": {
+                    "$ref": "#/definitions/eventcodec.Device"
+                },
+                "event_params": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/eventcodec.Param"
+                    }
+                },
+                "geo": {
+                    "$ref": "#/definitions/eventcodec.Geo"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "ingestion": {
+                    "$ref": "#/definitions/eventcodec.Ingestion"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/eventcodec.Item"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "previous_timestamp": {
+                    "type": "integer"
+                },
+                "timestamp": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "user_params": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/eventcodec.Param"
+                    }
+                },
+                "user_pseudo_id": {
+                    "type": "string"
+                }
+            }
         }
     }
 }`
@@ -2,25 +2,45 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
+
 	"github.com/ebubekir/event-stream/internal/adapter/inbound/http/middleware"
 
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 
+	eventstreamv1 "github.com/ebubekir/event-stream/api/gen/eventstream/v1"
 	"github.com/ebubekir/event-stream/cmd/api/docs"
+	"github.com/ebubekir/event-stream/internal/adapter/eventcodec"
+	"github.com/ebubekir/event-stream/internal/adapter/inbound/grpc/interceptor"
+	grpcServer "github.com/ebubekir/event-stream/internal/adapter/inbound/grpc/server"
 	"github.com/ebubekir/event-stream/internal/adapter/inbound/http/handler"
-	chRepo "github.com/ebubekir/event-stream/internal/adapter/outbound/persistence/clickhouse"
-	pgRepo "github.com/ebubekir/event-stream/internal/adapter/outbound/persistence/postgres"
+	"github.com/ebubekir/event-stream/internal/adapter/inbound/kafka"
+	"github.com/ebubekir/event-stream/internal/adapter/outbound/archive"
+	exportStore "github.com/ebubekir/event-stream/internal/adapter/outbound/export"
+	replayStore "github.com/ebubekir/event-stream/internal/adapter/outbound/replay"
+	"github.com/ebubekir/event-stream/internal/adapter/outbound/webhook"
 	eventApp "github.com/ebubekir/event-stream/internal/application/event"
+	exportApp "github.com/ebubekir/event-stream/internal/application/export"
+	replayApp "github.com/ebubekir/event-stream/internal/application/replay"
+	webhookApp "github.com/ebubekir/event-stream/internal/application/webhook"
+	"github.com/ebubekir/event-stream/internal/bootstrap"
 	eventDomain "github.com/ebubekir/event-stream/internal/domain/event"
-	chMigrations "github.com/ebubekir/event-stream/migrations/clickhouse"
-	"github.com/ebubekir/event-stream/pkg/clickhouse"
+	"github.com/ebubekir/event-stream/pkg/auth"
 	"github.com/ebubekir/event-stream/pkg/config"
 	"github.com/ebubekir/event-stream/pkg/logger"
-	"github.com/ebubekir/event-stream/pkg/postgresql"
 )
 
 func main() {
@@ -35,42 +55,12 @@ func main() {
 	}
 	defer logger.Sync()
 
-	// Initialize repository and metrics reader based on database type
-	var eventRepository eventDomain.EventRepository
-	var metricsReader eventDomain.EventMetricsReader
-
-	switch cfg.DatabaseType {
-	case config.DatabaseTypePostgres:
-		db := postgresql.New(cfg.PostgresSQLUrl, "public")
-		if err := db.CheckConnection(); err != nil {
-			logger.Fatal("failed to connect to PostgreSQL", zap.Error(err))
-		}
-		eventRepository = pgRepo.NewEventRepository(db)
-		metricsReader = pgRepo.NewMetricsReader(db)
-		logger.Info("Using PostgreSQL as event store")
-
-	case config.DatabaseTypeClickhouse:
-		db := clickhouse.New(cfg.ClickhouseUrl, "default")
-		if err := db.CheckConnection(); err != nil {
-			logger.Fatal("failed to connect to ClickHouse", zap.Error(err))
-		}
-
-		// Run ClickHouse migrations
-		migrator, err := clickhouse.NewMigrator(db, chMigrations.MigrationFS)
-		if err != nil {
-			logger.Fatal("failed to create migrator", zap.Error(err))
-		}
-		if err := migrator.Up(context.Background()); err != nil {
-			logger.Fatal("failed to run migrations", zap.Error(err))
-		}
-
-		eventRepository = chRepo.NewEventRepository(db)
-		metricsReader = chRepo.NewMetricsReader(db)
-		logger.Info("Using ClickHouse as event store")
-
-	default:
-		logger.Fatal("unsupported database type", zap.String("type", string(cfg.DatabaseType)))
+	// Initialize repositories based on database type
+	storage, err := bootstrap.OpenStorage(context.Background(), cfg)
+	if err != nil {
+		logger.Fatal("failed to initialize storage", zap.Error(err))
 	}
+	logger.Info("Using event store", zap.String("type", string(cfg.DatabaseType)))
 
 	// Swagger settings
 
@@ -85,11 +75,94 @@ func main() {
 		docs.SwaggerInfo.Schemes = []string{"https"}
 	}
 
+	// Acceptance window for late-arriving and future-dated events
+	window := eventDomain.AcceptanceWindow{
+		MaxLateness:   cfg.Ingestion.MaxLateness,
+		MaxFutureSkew: cfg.Ingestion.MaxFutureSkew,
+		Action:        eventDomain.OutOfWindowAction(cfg.Ingestion.OutOfWindowAction),
+	}
+	switch window.Action {
+	case "", eventDomain.OutOfWindowAccept, eventDomain.OutOfWindowClamp, eventDomain.OutOfWindowReject, eventDomain.OutOfWindowQuarantine:
+	default:
+		logger.Fatal("unsupported out-of-window action", zap.String("action", string(window.Action)))
+	}
+
+	// Webhook dispatcher notified after events are persisted
+	dispatcher := webhook.NewDispatcher(storage.Webhooks, webhook.Config{
+		Workers:        cfg.Webhooks.Workers,
+		QueueSize:      cfg.Webhooks.QueueSize,
+		MaxAttempts:    cfg.Webhooks.MaxAttempts,
+		InitialBackoff: cfg.Webhooks.InitialBackoff,
+		MaxBackoff:     cfg.Webhooks.MaxBackoff,
+		Timeout:        cfg.Webhooks.Timeout,
+		DisableAfter:   cfg.Webhooks.DisableAfter,
+	})
+
+	// Raw event archive written after events are persisted
+	var sinks []eventDomain.EventSink
+	if cfg.Archive.Type != "" {
+		archiveSink, err := newArchiveSink(cfg.Archive)
+		if err != nil {
+			logger.Fatal("failed to initialize event archive", zap.Error(err))
+		}
+		sinks = append(sinks, archiveSink)
+		logger.Info("event archive enabled", zap.String("type", cfg.Archive.Type))
+	}
+
 	// Initialize application services
-	eventService := eventApp.NewEventService(eventRepository, metricsReader)
+	eventService := eventApp.NewEventService(storage.Events, storage.Metrics, storage.Identities,
+		eventApp.WithAcceptanceWindow(window, storage.Quarantine),
+		eventApp.WithClientIPSalt(cfg.Ingestion.ClientIPSalt),
+		eventApp.WithPublisher(dispatcher),
+		eventApp.WithSinks(sinks...),
+	)
+	webhookService := webhookApp.NewWebhookService(storage.Webhooks, webhookApp.WithOnChange(dispatcher.InvalidateCache))
+
+	// Export jobs and their output are kept in a local directory
+	if cfg.Exports.Dir == "" {
+		cfg.Exports.Dir = "./data/exports"
+	}
+	exports, err := exportStore.NewLocalStore(cfg.Exports.Dir)
+	if err != nil {
+		logger.Fatal("failed to initialize export store", zap.Error(err))
+	}
+	exportService := exportApp.NewExportService(storage.Events, eventcodec.NewEncoder,
+		exportApp.WithStore(exports),
+		exportApp.WithMaxConcurrentJobs(cfg.Exports.MaxConcurrentJobs),
+	)
+	if err := exportService.FailInterruptedJobs(context.Background()); err != nil {
+		logger.Fatal("failed to recover export jobs", zap.Error(err))
+	}
+
+	// Replay jobs and their checkpoints are kept in a local directory
+	if cfg.Replays.Dir == "" {
+		cfg.Replays.Dir = "./data/replays"
+	}
+	replays, err := replayStore.NewLocalStore(cfg.Replays.Dir)
+	if err != nil {
+		logger.Fatal("failed to initialize replay store", zap.Error(err))
+	}
+	replaySources := bootstrap.NewReplaySourceOpener(cfg, bootstrap.ReplaySources{
+		ArchiveSlack: cfg.Replays.ArchiveSlack,
+		Quarantine:   storage.Quarantine,
+	})
+	replayService := replayApp.NewReplayService(storage.Events, eventService, replaySources, replays,
+		replayApp.WithBatchSize(cfg.Replays.BatchSize),
+	)
+	if err := replayService.ResumeInterruptedJobs(context.Background()); err != nil {
+		logger.Fatal("failed to resume replay jobs", zap.Error(err))
+	}
 
 	// Initialize HTTP handlers
 	eventHandler := handler.NewEventHandler(eventService)
+	identityHandler := handler.NewIdentityHandler(eventService)
+	userHandler := handler.NewUserHandler(eventService)
+	quarantineHandler := handler.NewQuarantineHandler(eventService)
+	webhookHandler := handler.NewWebhookHandler(webhookService)
+	tailHandler := handler.NewTailHandler(eventService)
+	exportHandler := handler.NewExportHandler(exportService)
+	replayHandler := handler.NewReplayHandler(replayService)
+	ecommerceHandler := handler.NewEcommerceHandler(eventService)
 
 	// Setup Gin router
 	api := gin.Default()
@@ -98,13 +171,209 @@ func main() {
 
 	api.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
+	apiKeys := make([]auth.APIKey, len(cfg.Auth.APIKeys))
+	for i, key := range cfg.Auth.APIKeys {
+		apiKeys[i] = auth.APIKey{ID: key.ID, Key: key.Key}
+	}
+
 	// Register routes
 	v1 := api.Group("/v1")
+	v1.Use(middleware.RequestID())
+	v1.Use(middleware.APIKeyAuth(apiKeys))
+	v1.Use(middleware.Decompress())
 	eventHandler.RegisterRoutes(v1)
+	identityHandler.RegisterRoutes(v1)
+	userHandler.RegisterRoutes(v1)
+	quarantineHandler.RegisterRoutes(v1)
+	webhookHandler.RegisterRoutes(v1)
+	tailHandler.RegisterRoutes(v1)
+	exportHandler.RegisterRoutes(v1)
+	replayHandler.RegisterRoutes(v1)
+	ecommerceHandler.RegisterRoutes(v1)
+
+	if cfg.ShutdownTimeout <= 0 {
+		cfg.ShutdownTimeout = 15 * time.Second
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
 	addr := fmt.Sprintf(":%s", cfg.Port)
-	logger.Info("Starting server", zap.String("address", addr))
-	if err := api.Run(addr); err != nil {
-		logger.Fatal("failed to start server", zap.Error(err))
+	httpServer := &http.Server{
+		Addr:              addr,
+		Handler:           api,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	// Live tails never go idle; end them so Shutdown does not wait for the timeout
+	httpServer.RegisterOnShutdown(eventService.CloseTails)
+	serverErr := make(chan error, 3)
+	go func() {
+		logger.Info("Starting server", zap.String("address", addr))
+		if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- fmt.Errorf("http server: %w", err)
+		}
+	}()
+
+	// Setup gRPC server
+	var rpcServer *grpc.Server
+	healthServer := health.NewServer()
+	if cfg.GRPCPort != "" {
+		rpcServer = grpc.NewServer(
+			grpc.ChainUnaryInterceptor(
+				interceptor.UnaryRecovery(),
+				interceptor.UnaryRequestID(),
+				interceptor.UnaryLogging(),
+				interceptor.UnaryAPIKeyAuth(apiKeys),
+			),
+			grpc.ChainStreamInterceptor(
+				interceptor.StreamRecovery(),
+				interceptor.StreamRequestID(),
+				interceptor.StreamLogging(),
+				interceptor.StreamAPIKeyAuth(apiKeys),
+			),
+		)
+		grpcServer.NewEventServer(eventService).Register(rpcServer)
+		healthpb.RegisterHealthServer(rpcServer, healthServer)
+		healthServer.SetServingStatus(eventstreamv1.EventService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+		reflection.Register(rpcServer)
+
+		grpcAddr := fmt.Sprintf(":%s", cfg.GRPCPort)
+		listener, err := net.Listen("tcp", grpcAddr)
+		if err != nil {
+			logger.Fatal("failed to listen for gRPC", zap.Error(err))
+		}
+		go func() {
+			logger.Info("Starting gRPC server", zap.String("address", grpcAddr))
+			if err := rpcServer.Serve(listener); err != nil {
+				serverErr <- fmt.Errorf("grpc server: %w", err)
+			}
+		}()
+	}
+
+	// Setup Kafka consumer
+	consumerCtx, stopConsumer := context.WithCancel(context.Background())
+	consumerDone := make(chan struct{})
+	if len(cfg.Kafka.Brokers) > 0 {
+		reader := kafka.NewReader(cfg.Kafka.Brokers, cfg.Kafka.Topic, cfg.Kafka.GroupID)
+		consumer := kafka.NewConsumer(reader, eventService, kafka.Config{
+			BatchSize:     cfg.Kafka.BatchSize,
+			FlushInterval: cfg.Kafka.FlushInterval,
+		})
+		go func() {
+			defer close(consumerDone)
+			defer reader.Close()
+			logger.Info("Starting Kafka consumer", zap.String("topic", cfg.Kafka.Topic), zap.String("group_id", cfg.Kafka.GroupID))
+			if err := consumer.Run(consumerCtx); err != nil {
+				serverErr <- fmt.Errorf("kafka consumer: %w", err)
+			}
+		}()
+	} else {
+		close(consumerDone)
+	}
+
+	select {
+	case <-ctx.Done():
+		logger.Info("Shutting down")
+	case err := <-serverErr:
+		logger.Error("server stopped unexpectedly", zap.Error(err))
+	}
+
+	// Stop consuming first; uncommitted messages are redelivered after restart
+	stopConsumer()
+	<-consumerDone
+
+	shutdown(httpServer, rpcServer, healthServer, cfg.ShutdownTimeout)
+
+	// Flush webhook deliveries queued by the last requests
+	closeCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	if err := dispatcher.Close(closeCtx); err != nil {
+		logger.Warn("webhook deliveries still pending at shutdown", zap.Error(err))
+	}
+
+	// Running exports are marked failed; their partial output is removed
+	if err := exportService.Close(closeCtx); err != nil {
+		logger.Warn("export jobs still running at shutdown", zap.Error(err))
+	}
+
+	// Running replays stop at their last checkpoint and resume on the next start
+	if err := replayService.Close(closeCtx); err != nil {
+		logger.Warn("replay jobs still running at shutdown", zap.Error(err))
+	}
+
+	// Roll and upload the archive file written by the last requests
+	for _, sink := range sinks {
+		if err := sink.Close(closeCtx); err != nil {
+			logger.Warn("event sink not flushed at shutdown", zap.Error(err))
+		}
+	}
+
+	if err := storage.Close(); err != nil {
+		logger.Warn("failed to close storage", zap.Error(err))
 	}
 }
+
+// newArchiveSink creates the archive sink for the configured store type
+func newArchiveSink(cfg config.ArchiveConfig) (*archive.Sink, error) {
+	var store archive.Store
+	switch cfg.Type {
+	case "local":
+		store = archive.NewLocalStore(cfg.Dir)
+	case "s3":
+		s3Store, err := archive.NewS3Store(archive.S3Config{
+			Endpoint:  cfg.S3.Endpoint,
+			Region:    cfg.S3.Region,
+			Bucket:    cfg.S3.Bucket,
+			Prefix:    cfg.S3.Prefix,
+			AccessKey: cfg.S3.AccessKey,
+			SecretKey: cfg.S3.SecretKey,
+			UseSSL:    cfg.S3.UseSSL,
+		})
+		if err != nil {
+			return nil, err
+		}
+		store = s3Store
+	default:
+		return nil, fmt.Errorf("unsupported archive type: %s", cfg.Type)
+	}
+
+	return archive.NewSink(store, archive.Config{
+		StagingDir:  cfg.StagingDir,
+		MaxFileSize: cfg.MaxFileSize,
+	})
+}
+
+// shutdown stops accepting new requests and waits up to timeout for in-flight
+// HTTP requests and gRPC calls to finish
+func shutdown(httpServer *http.Server, rpcServer *grpc.Server, healthServer *health.Server, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	// Report NOT_SERVING so load balancers drain traffic first
+	healthServer.Shutdown()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if rpcServer != nil {
+			rpcServer.GracefulStop()
+		}
+	}()
+
+	if err := httpServer.Shutdown(ctx); err != nil {
+		logger.Error("failed to shut down HTTP server", zap.Error(err))
+	}
+
+	// Without a gRPC server done is closed right away, but the select may
+	// still pick ctx.Done() when the HTTP shutdown used up the timeout
+	if rpcServer != nil {
+		select {
+		case <-done:
+		case <-ctx.Done():
+			logger.Warn("gRPC graceful stop timed out, forcing stop")
+			rpcServer.Stop()
+		}
+	}
+
+	logger.Info("Server stopped")
+}
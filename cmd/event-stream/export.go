@@ -0,0 +1,175 @@
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ebubekir/event-stream/internal/adapter/eventcodec"
+	exportApp "github.com/ebubekir/event-stream/internal/application/export"
+	"github.com/ebubekir/event-stream/internal/bootstrap"
+	"github.com/ebubekir/event-stream/pkg/logger"
+)
+
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: event-stream export [flags]")
+		fmt.Fprintln(fs.Output(), "\nExports the raw events in [from, to) from the configured event store.")
+		fmt.Fprintln(fs.Output(), "\nFlags:")
+		fs.PrintDefaults()
+	}
+	format := fs.String("format", "ndjson", "output format: ndjson, csv, parquet")
+	from := fs.String("from", "", "start of the range, RFC3339 or YYYY-MM-DD (required)")
+	to := fs.String("to", "", "end of the range, exclusive, RFC3339 or YYYY-MM-DD (default: now)")
+	names := fs.String("name", "", "comma-separated event names (default: every event)")
+	channel := fs.String("channel", "", "only events from this channel type")
+	userID := fs.String("user-id", "", "only events with this user ID")
+	userPseudoID := fs.String("user-pseudo-id", "", "only events with this user pseudo ID")
+	appID := fs.String("app-id", "", "only events from this app ID")
+	eventParams := fs.String("event-params", "", "csv: comma-separated event param keys written as columns")
+	userParams := fs.String("user-params", "", "csv: comma-separated user param keys written as columns (when neither list is set, every key in the range is used)")
+	output := fs.String("o", "-", "output file, gzip-compressed when it ends in .gz; - writes to stdout")
+	_ = fs.Parse(args)
+
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+	if *from == "" {
+		fs.Usage()
+		return fmt.Errorf("-from is required")
+	}
+
+	cmd := &exportApp.ExportCommand{
+		Format:       *format,
+		EventNames:   splitList(*names),
+		ChannelType:  *channel,
+		UserID:       *userID,
+		UserPseudoID: *userPseudoID,
+		AppID:        *appID,
+		EventParams:  splitList(*eventParams),
+		UserParams:   splitList(*userParams),
+		To:           time.Now(),
+	}
+
+	var err error
+	if cmd.From, err = parseTime(*from); err != nil {
+		return fmt.Errorf("invalid -from: %w", err)
+	}
+	if *to != "" {
+		if cmd.To, err = parseTime(*to); err != nil {
+			return fmt.Errorf("invalid -to: %w", err)
+		}
+	}
+
+	cfg, err := setup()
+	if err != nil {
+		return err
+	}
+	defer logger.Sync()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	storage, err := bootstrap.OpenStorage(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer storage.Close()
+
+	w, err := createOutput(*output, *format)
+	if err != nil {
+		return err
+	}
+
+	service := exportApp.NewExportService(storage.Events, eventcodec.NewEncoder)
+	count, err := service.Export(ctx, cmd, w)
+	if closeErr := w.Close(); err == nil {
+		err = closeErr
+	}
+
+	// The export may be on stdout, so the summary goes to stderr
+	fmt.Fprintf(os.Stderr, "exported %d events\n", count)
+	if err != nil && *output != "-" {
+		_ = os.Remove(*output)
+	}
+	return err
+}
+
+// createOutput opens the export destination. Text formats are gzip-compressed
+// when path ends in .gz; Parquet is compressed internally.
+func createOutput(path, format string) (io.WriteCloser, error) {
+	var file *os.File
+	if path == "-" {
+		file = os.Stdout
+	} else {
+		var err error
+		if file, err = os.Create(path); err != nil {
+			return nil, err
+		}
+	}
+
+	out := &output{file: file, buf: bufio.NewWriterSize(file, 1<<20)}
+	out.w = out.buf
+	if strings.HasSuffix(path, ".gz") && format != "parquet" {
+		out.gz = gzip.NewWriter(out.buf)
+		out.w = out.gz
+	}
+	return out, nil
+}
+
+// output is a buffered, optionally gzip-compressed export destination
+type output struct {
+	file *os.File
+	buf  *bufio.Writer
+	gz   *gzip.Writer
+	w    io.Writer
+}
+
+func (o *output) Write(p []byte) (int, error) {
+	return o.w.Write(p)
+}
+
+// Close flushes all buffered data and closes the file unless it is stdout
+func (o *output) Close() error {
+	if o.gz != nil {
+		if err := o.gz.Close(); err != nil {
+			return err
+		}
+	}
+	if err := o.buf.Flush(); err != nil {
+		return err
+	}
+	if o.file == os.Stdout {
+		return nil
+	}
+	return o.file.Close()
+}
+
+// parseTime parses an RFC3339 timestamp or a YYYY-MM-DD date in UTC
+func parseTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries
+func splitList(value string) []string {
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/ebubekir/event-stream/internal/adapter/inbound/importer"
+	eventApp "github.com/ebubekir/event-stream/internal/application/event"
+	"github.com/ebubekir/event-stream/internal/bootstrap"
+	"github.com/ebubekir/event-stream/pkg/logger"
+)
+
+func runImport(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: event-stream import [flags] <file>")
+		fmt.Fprintln(fs.Output(), "\nImports events into the configured event store. Records are validated like POST /v1/events.")
+		fmt.Fprintln(fs.Output(), "\nFlags:")
+		fs.PrintDefaults()
+	}
+	format := fs.String("format", "", "input format: ndjson, csv, parquet, ga4, ga4-avro (default: from the file extension)")
+	mapping := fs.String("mapping", "", "YAML column mapping file, required for csv")
+	source := fs.String("source", "", "name identifying the input in generated event IDs (default: the file name)")
+	batchSize := fs.Int("batch-size", 1000, "records stored per batch")
+	workers := fs.Int("workers", 4, "batches stored concurrently")
+	skip := fs.Int64("skip", 0, "skip records up to and including this offset, e.g. the committed offset of a failed run")
+	retries := fs.Int("retries", 3, "retries for a batch that failed to store")
+	dryRun := fs.Bool("dry-run", false, "validate records without storing them")
+	errorsPath := fs.String("errors", "", "write invalid records to this NDJSON file")
+	_ = fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("expected exactly one input file")
+	}
+	path := fs.Arg(0)
+
+	if *format == "" {
+		*format = formatFromExtension(path)
+	}
+	if *source == "" {
+		*source = filepath.Base(path)
+	}
+
+	cfg, err := setup()
+	if err != nil {
+		return err
+	}
+	defer logger.Sync()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	src, err := importer.Open(*format, path, *mapping)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	config := importer.Config{
+		Source:     *source,
+		BatchSize:  *batchSize,
+		Workers:    *workers,
+		Skip:       *skip,
+		DryRun:     *dryRun,
+		MaxRetries: *retries,
+	}
+
+	if *errorsPath != "" {
+		report, err := os.Create(*errorsPath)
+		if err != nil {
+			return err
+		}
+		defer report.Close()
+		config.ErrorReport = report
+	}
+
+	// A dry run only validates, so it does not need the database
+	var service *eventApp.EventService
+	if !*dryRun {
+		var storage *bootstrap.Storage
+		service, storage, err = newEventService(ctx, cfg)
+		if err != nil {
+			return err
+		}
+		defer storage.Close()
+	}
+
+	summary, err := importer.NewImporter(service, config).Run(ctx, src)
+
+	verb := "imported"
+	if *dryRun {
+		verb = "valid"
+	}
+	fmt.Printf("read %d, skipped %d, %s %d, invalid %d, committed offset %d\n",
+		summary.Read, summary.Skipped, verb, summary.Imported, summary.Invalid, summary.Committed)
+
+	if err != nil {
+		return fmt.Errorf("%w (resume with -skip %d)", err, summary.Committed)
+	}
+	return nil
+}
+
+func formatFromExtension(path string) string {
+	switch strings.ToLower(filepath.Ext(strings.TrimSuffix(path, ".gz"))) {
+	case ".csv":
+		return importer.FormatCSV
+	case ".parquet":
+		return importer.FormatParquet
+	case ".avro":
+		return importer.FormatGA4Avro
+	default:
+		return importer.FormatNDJSON
+	}
+}
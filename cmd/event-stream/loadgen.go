@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/ebubekir/event-stream/internal/loadgen"
+	"github.com/ebubekir/event-stream/pkg/logger"
+)
+
+// Load generator targets
+const (
+	targetHTTP  = "http"
+	targetBatch = "batch"
+	targetGRPC  = "grpc"
+	targetStore = "store"
+)
+
+func runLoadgen(args []string) error {
+	fs := flag.NewFlagSet("loadgen", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: event-stream loadgen [flags]")
+		fmt.Fprintln(fs.Output(), "\nSends synthetic GA4-style events to a running server, or straight to the")
+		fmt.Fprintln(fs.Output(), "configured event store, and reports throughput, latency and errors.")
+		fmt.Fprintln(fs.Output(), "\nFlags:")
+		fs.PrintDefaults()
+	}
+	target := fs.String("target", targetBatch, "where to send events: http (POST /v1/events), batch (POST /v1/events/batch), grpc (StreamEvents), store (the configured database)")
+	url := fs.String("url", "http://localhost:8080", "HTTP API base URL for the http and batch targets")
+	grpcAddr := fs.String("grpc-addr", "localhost:9090", "gRPC server address for the grpc target")
+	apiKey := fs.String("api-key", "", "API key sent with every request")
+	gzip := fs.Bool("gzip", false, "gzip-compress HTTP request bodies")
+	rate := fs.Float64("rate", 1000, "target events per second; 0 sends as fast as the target accepts")
+	duration := fs.Duration("duration", 0, "how long to run (default 1m unless -events is set)")
+	events := fs.Int64("events", 0, "stop after this many events")
+	batchSize := fs.Int("batch-size", 100, "events per request for the batch, grpc and store targets")
+	workers := fs.Int("workers", 8, "concurrent requests")
+	users := fs.Int("users", 10000, "simulated users; activity follows a power law over them")
+	seed := fs.Uint64("seed", 1, "random seed; the same seed generates the same events")
+	days := fs.Int("days", 0, "spread -events evenly over this many days up to now instead of sending current events, e.g. to seed a demo database")
+	_ = fs.Parse(args)
+
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return fmt.Errorf("unexpected arguments")
+	}
+	if *days > 0 && *events <= 0 {
+		return fmt.Errorf("-days requires -events")
+	}
+	if *duration <= 0 && *events <= 0 {
+		*duration = time.Minute
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	var t loadgen.Target
+	switch *target {
+	case targetHTTP:
+		// One event per request
+		*batchSize = 1
+		t = loadgen.NewHTTPTarget(*url, *apiKey, false, *gzip)
+	case targetBatch:
+		t = loadgen.NewHTTPTarget(*url, *apiKey, true, *gzip)
+	case targetGRPC:
+		grpcTarget, err := loadgen.NewGRPCTarget(*grpcAddr, *apiKey)
+		if err != nil {
+			return err
+		}
+		t = grpcTarget
+	case targetStore:
+		cfg, err := setup()
+		if err != nil {
+			return err
+		}
+		defer logger.Sync()
+
+		service, storage, err := newEventService(ctx, cfg)
+		if err != nil {
+			return err
+		}
+		defer storage.Close()
+		t = loadgen.NewStoreTarget(service)
+	default:
+		return fmt.Errorf("unknown target %q", *target)
+	}
+	defer t.Close()
+
+	config := loadgen.Config{
+		Rate:      *rate,
+		Duration:  *duration,
+		Events:    *events,
+		BatchSize: *batchSize,
+		Workers:   *workers,
+		Progress:  os.Stderr,
+	}
+	if *days > 0 {
+		config.To = time.Now().UTC()
+		config.From = config.To.AddDate(0, 0, -*days)
+	}
+
+	fmt.Fprintf(os.Stderr, "sending to %s: rate %s, %d workers, batch size %d, %d users\n",
+		*target, rateLabel(*rate), *workers, *batchSize, *users)
+
+	report, err := loadgen.NewRunner(loadgen.NewGenerator(*users, *seed), t, config).Run(ctx)
+	if err != nil {
+		return err
+	}
+	report.Print(os.Stdout)
+	return nil
+}
+
+func rateLabel(rate float64) string {
+	if rate <= 0 {
+		return "unlimited"
+	}
+	return fmt.Sprintf("%g events/s", rate)
+}
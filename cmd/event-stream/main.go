@@ -0,0 +1,61 @@
+// Command event-stream runs maintenance tasks against the configured event store.
+//
+// Usage:
+//
+//	event-stream <command> [flags]
+//
+// It reads the same config/config.yaml as the API server.
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+)
+
+// command is a subcommand; run receives the arguments after the command name
+type command struct {
+	summary string
+	run     func(args []string) error
+}
+
+var commands = map[string]command{
+	"export":  {summary: "Export raw events to NDJSON, CSV or Parquet", run: runExport},
+	"import":  {summary: "Import events from CSV, NDJSON or Parquet files", run: runImport},
+	"loadgen": {summary: "Generate synthetic traffic and report ingest throughput", run: runLoadgen},
+	"replay":  {summary: "Re-ingest a time window from the archive, quarantine or another database", run: runReplay},
+}
+
+func main() {
+	if len(os.Args) < 2 || os.Args[1] == "-h" || os.Args[1] == "--help" || os.Args[1] == "help" {
+		usage()
+		os.Exit(2)
+	}
+
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+
+	if err := cmd.run(os.Args[2:]); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: event-stream <command> [flags]")
+	fmt.Fprintln(os.Stderr, "\nCommands:")
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", name, commands[name].summary)
+	}
+	fmt.Fprintln(os.Stderr, "\nRun 'event-stream <command> -h' for command flags.")
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	replayStore "github.com/ebubekir/event-stream/internal/adapter/outbound/replay"
+	replayApp "github.com/ebubekir/event-stream/internal/application/replay"
+	"github.com/ebubekir/event-stream/internal/bootstrap"
+	"github.com/ebubekir/event-stream/pkg/logger"
+)
+
+func runReplay(args []string) error {
+	fs := flag.NewFlagSet("replay", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: event-stream replay [flags]")
+		fmt.Fprintln(fs.Output(), "\nRe-ingests the events in [from, to) from a source into the configured event")
+		fmt.Fprintln(fs.Output(), "store. The window is replayed in chunks and checkpointed after each, so an")
+		fmt.Fprintln(fs.Output(), "interrupted replay can be continued with -resume.")
+		fmt.Fprintln(fs.Output(), "\nFlags:")
+		fs.PrintDefaults()
+	}
+	source := fs.String("source", "", "where to read events: archive, quarantine, or the other database type (postgres, clickhouse)")
+	archiveDir := fs.String("archive-dir", "", "archive directory for the archive source (default: replays.archive_dir, or archive.dir for a local archive)")
+	archiveSlack := fs.Duration("archive-slack", 0, "how far outside the window archive partitions are still read; 0 reads all (default: replays.archive_slack)")
+	from := fs.String("from", "", "start of the window, RFC3339 or YYYY-MM-DD (required)")
+	to := fs.String("to", "", "end of the window, exclusive, RFC3339 or YYYY-MM-DD (required)")
+	names := fs.String("name", "", "comma-separated event names (default: every event)")
+	channel := fs.String("channel", "", "only events from this channel type")
+	userID := fs.String("user-id", "", "only events with this user ID")
+	userPseudoID := fs.String("user-pseudo-id", "", "only events with this user pseudo ID")
+	appID := fs.String("app-id", "", "only events from this app ID")
+	reprocess := fs.Bool("reprocess", false, "run events through the current ingestion pipeline instead of storing them as read")
+	deleteFirst := fs.Bool("delete", false, "delete the stored events matching the filters in each chunk before replaying it")
+	rate := fs.Float64("rate", 0, "events per second; 0 is unlimited")
+	chunk := fs.Duration("chunk", time.Hour, "span of event time replayed between checkpoints")
+	batchSize := fs.Int("batch-size", 0, "events written per batch (default: replays.batch_size)")
+	resume := fs.String("resume", "", "continue the replay with this ID from its last checkpoint instead of starting one")
+	stateDir := fs.String("state-dir", "", "directory keeping replay jobs and checkpoints (default: replays.dir)")
+	_ = fs.Parse(args)
+
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+	if *resume == "" && (*source == "" || *from == "" || *to == "") {
+		fs.Usage()
+		return fmt.Errorf("-source, -from and -to are required")
+	}
+
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+
+	cmd := &replayApp.ReplayCommand{
+		Source:       *source,
+		EventNames:   splitList(*names),
+		ChannelType:  *channel,
+		UserID:       *userID,
+		UserPseudoID: *userPseudoID,
+		AppID:        *appID,
+		Reprocess:    *reprocess,
+		DeleteFirst:  *deleteFirst,
+		Rate:         *rate,
+		ChunkSize:    *chunk,
+	}
+	if *resume == "" {
+		var err error
+		if cmd.From, err = parseTime(*from); err != nil {
+			return fmt.Errorf("invalid -from: %w", err)
+		}
+		if cmd.To, err = parseTime(*to); err != nil {
+			return fmt.Errorf("invalid -to: %w", err)
+		}
+	}
+
+	cfg, err := setup()
+	if err != nil {
+		return err
+	}
+	defer logger.Sync()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	eventService, storage, err := newEventService(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer storage.Close()
+
+	if *stateDir == "" {
+		*stateDir = cfg.Replays.Dir
+	}
+	if *stateDir == "" {
+		*stateDir = "./data/replays"
+	}
+	store, err := replayStore.NewLocalStore(*stateDir)
+	if err != nil {
+		return err
+	}
+
+	if !set["archive-slack"] {
+		*archiveSlack = cfg.Replays.ArchiveSlack
+	}
+	if *batchSize <= 0 {
+		*batchSize = cfg.Replays.BatchSize
+	}
+
+	sources := bootstrap.NewReplaySourceOpener(cfg, bootstrap.ReplaySources{
+		ArchiveDir:   *archiveDir,
+		ArchiveSlack: *archiveSlack,
+		Quarantine:   storage.Quarantine,
+	})
+	service := replayApp.NewReplayService(storage.Events, eventService, sources, store,
+		replayApp.WithBatchSize(*batchSize),
+		replayApp.WithProgress(func(job *replayApp.ReplayJobDTO) {
+			fmt.Fprintf(os.Stderr, "%s  replayed up to %s, %d events\n",
+				job.ID, job.Checkpoint.Format(time.RFC3339), job.EventCount)
+		}),
+	)
+
+	var job *replayApp.ReplayJobDTO
+	if *resume != "" {
+		job, err = service.RunJob(ctx, *resume)
+	} else {
+		job, err = service.Run(ctx, cmd)
+	}
+	if job == nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "replay %s %s: %d events", job.ID, job.Status, job.EventCount)
+	if job.Checkpoint.Before(job.To) {
+		fmt.Fprintf(os.Stderr, ", stopped at %s", job.Checkpoint.Format(time.RFC3339))
+	}
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			return fmt.Errorf("interrupted; continue with -resume %s", job.ID)
+		}
+		return fmt.Errorf("%w; continue with -resume %s", err, job.ID)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	eventApp "github.com/ebubekir/event-stream/internal/application/event"
+	"github.com/ebubekir/event-stream/internal/bootstrap"
+	"github.com/ebubekir/event-stream/pkg/config"
+	"github.com/ebubekir/event-stream/pkg/logger"
+)
+
+// setup reads the config and initializes the logger
+func setup() (*config.AppConfig, error) {
+	cfg := config.Read()
+
+	if err := logger.Init(logger.Config{
+		Level:  cfg.Log.Level,
+		Format: cfg.Log.Format,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to initialize logger: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// newEventService opens the configured storage and creates an EventService on top of it
+func newEventService(ctx context.Context, cfg *config.AppConfig) (*eventApp.EventService, *bootstrap.Storage, error) {
+	storage, err := bootstrap.OpenStorage(ctx, cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	service := eventApp.NewEventService(storage.Events, storage.Metrics, storage.Identities,
+		eventApp.WithClientIPSalt(cfg.Ingestion.ClientIPSalt),
+	)
+	return service, storage, nil
+}
@@ -0,0 +1,185 @@
+package eventcodec
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/writer"
+
+	"github.com/ebubekir/event-stream/internal/domain"
+	eventDomain "github.com/ebubekir/event-stream/internal/domain/event"
+)
+
+// parquetRowGroupSize bounds the rows a Parquet encoder buffers in memory
+const parquetRowGroupSize = 16 << 20
+
+// NewEncoder returns an encoder writing events to w in format. For CSV,
+// columns lists the param keys flattened into columns.
+func NewEncoder(w io.Writer, format eventDomain.ExportFormat, columns eventDomain.ParamKeys) (eventDomain.ExportEncoder, error) {
+	switch format {
+	case eventDomain.ExportFormatNDJSON:
+		return NewNDJSONEncoder(w), nil
+	case eventDomain.ExportFormatCSV:
+		return NewCSVEncoder(w, columns)
+	case eventDomain.ExportFormatParquet:
+		return NewParquetEncoder(w)
+	default:
+		return nil, fmt.Errorf("unsupported export format: %s", format)
+	}
+}
+
+// NDJSONEncoder writes one JSON Record per line
+type NDJSONEncoder struct {
+	encoder *json.Encoder
+}
+
+// NewNDJSONEncoder creates an NDJSONEncoder
+func NewNDJSONEncoder(w io.Writer) *NDJSONEncoder {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	return &NDJSONEncoder{encoder: encoder}
+}
+
+// Encode writes one event
+func (e *NDJSONEncoder) Encode(event *domain.Event) error {
+	return e.encoder.Encode(FromEvent(event))
+}
+
+// Close is a no-op; every line is written by Encode
+func (e *NDJSONEncoder) Close() error {
+	return nil
+}
+
+// csvFixedColumns are the CSV columns written for every event
+var csvFixedColumns = []string{
+	"id", "name", "channel_type", "timestamp", "previous_timestamp", "date",
+	"user_id", "user_pseudo_id",
+	"device.category", "device.mobile_brand_name", "device.mobile_model_name",
+	"device.operating_system", "device.operating_system_version",
+	"device.language", "device.browser_name", "device.browser_version", "device.hostname",
+	"geo.continent", "geo.sub_continent", "geo.country", "geo.region", "geo.metro", "geo.city",
+	"app_info.id", "app_info.version",
+	"ingestion.received_at", "ingestion.endpoint", "ingestion.request_id", "ingestion.client_ip_hash",
+	"ingestion.sdk_name", "ingestion.sdk_version", "ingestion.api_key_id",
+}
+
+// CSVEncoder writes events as CSV rows. Params are flattened into one
+// "event_params.<key>" or "user_params.<key>" column per key; items, which
+// do not flatten into a fixed set of columns, are written as a JSON array.
+type CSVEncoder struct {
+	writer  *csv.Writer
+	columns eventDomain.ParamKeys
+	row     []string
+}
+
+// NewCSVEncoder creates a CSVEncoder and writes the header row
+func NewCSVEncoder(w io.Writer, columns eventDomain.ParamKeys) (*CSVEncoder, error) {
+	header := append([]string{}, csvFixedColumns...)
+	for _, key := range columns.EventParams {
+		header = append(header, "event_params."+key)
+	}
+	for _, key := range columns.UserParams {
+		header = append(header, "user_params."+key)
+	}
+	header = append(header, "items")
+
+	e := &CSVEncoder{writer: csv.NewWriter(w), columns: columns}
+	if err := e.writer.Write(header); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Encode writes one event
+func (e *CSVEncoder) Encode(event *domain.Event) error {
+	items, err := json.Marshal(FromEvent(event).Items)
+	if err != nil {
+		return fmt.Errorf("failed to encode items: %w", err)
+	}
+
+	var receivedAt string
+	if !event.Ingestion.ReceivedAt.IsZero() {
+		receivedAt = event.Ingestion.ReceivedAt.UTC().Format(time.RFC3339Nano)
+	}
+
+	e.row = append(e.row[:0],
+		event.ID, event.Name, string(event.ChannelType),
+		strconv.FormatInt(event.Timestamp, 10), strconv.FormatInt(event.PreviousTimestamp, 10), event.Date,
+		event.UserID, event.UserPseudoID,
+		event.Device.Category, event.Device.MobileBrandName, event.Device.MobileModelName,
+		event.Device.OperatingSystem, event.Device.OperatingSystemVersion,
+		event.Device.Language, event.Device.BrowserName, event.Device.BrowserVersion, event.Device.Hostname,
+		event.Geo.Continent, event.Geo.SubContinent, event.Geo.Country, event.Geo.Region, event.Geo.Metro, event.Geo.City,
+		event.AppInfo.ID, event.AppInfo.Version,
+		receivedAt, event.Ingestion.Endpoint, event.Ingestion.RequestID, event.Ingestion.ClientIPHash,
+		event.Ingestion.SDKName, event.Ingestion.SDKVersion, event.Ingestion.APIKeyID,
+	)
+	for _, key := range e.columns.EventParams {
+		e.row = append(e.row, paramCell(event.EventParams, key))
+	}
+	for _, key := range e.columns.UserParams {
+		e.row = append(e.row, paramCell(event.UserParams, key))
+	}
+	e.row = append(e.row, string(items))
+
+	return e.writer.Write(e.row)
+}
+
+// Close flushes buffered rows
+func (e *CSVEncoder) Close() error {
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+// paramCell formats the first param with key as text: its string value, or
+// its boolean or number value when the string value is empty. It is empty
+// when the event has no such param.
+func paramCell(params []domain.Param, key string) string {
+	for _, p := range params {
+		if p.Key != key {
+			continue
+		}
+
+		switch {
+		case p.StringValue != "":
+			return p.StringValue
+		case p.BooleanValue:
+			return "true"
+		default:
+			return strconv.FormatFloat(p.NumberValue, 'f', -1, 64)
+		}
+	}
+	return ""
+}
+
+// ParquetEncoder writes events with the ParquetRecord schema
+type ParquetEncoder struct {
+	writer *writer.ParquetWriter
+}
+
+// NewParquetEncoder creates a ParquetEncoder
+func NewParquetEncoder(w io.Writer) (*ParquetEncoder, error) {
+	pw, err := writer.NewParquetWriterFromWriter(w, new(ParquetRecord), 4)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create parquet writer: %w", err)
+	}
+	pw.RowGroupSize = parquetRowGroupSize
+	pw.CompressionType = parquet.CompressionCodec_SNAPPY
+
+	return &ParquetEncoder{writer: pw}, nil
+}
+
+// Encode writes one event
+func (e *ParquetEncoder) Encode(event *domain.Event) error {
+	return e.writer.Write(ToParquet(FromEvent(event)))
+}
+
+// Close writes the last row group and the file footer
+func (e *ParquetEncoder) Close() error {
+	return e.writer.WriteStop()
+}
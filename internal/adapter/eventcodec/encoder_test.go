@@ -0,0 +1,141 @@
+package eventcodec
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/xitongsys/parquet-go-source/buffer"
+	"github.com/xitongsys/parquet-go/reader"
+
+	"github.com/ebubekir/event-stream/internal/domain"
+	eventDomain "github.com/ebubekir/event-stream/internal/domain/event"
+)
+
+// encode writes events with a new encoder for format and returns the output
+func encode(t *testing.T, format eventDomain.ExportFormat, columns eventDomain.ParamKeys, events ...*domain.Event) []byte {
+	t.Helper()
+
+	var out bytes.Buffer
+	encoder, err := NewEncoder(&out, format, columns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, event := range events {
+		if err := encoder.Encode(event); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := encoder.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return out.Bytes()
+}
+
+func TestNDJSONEncoderRoundTrip(t *testing.T) {
+	second := sampleEvent()
+	second.ID = "evt-2"
+	second.EventParams = append(second.EventParams, domain.Param{Key: "url", StringValue: "/a?b=<c>&d"})
+
+	out := encode(t, eventDomain.ExportFormatNDJSON, eventDomain.ParamKeys{}, sampleEvent(), second)
+
+	if !bytes.Contains(out, []byte(`"/a?b=<c>&d"`)) {
+		t.Errorf("output escapes HTML: %s", out)
+	}
+
+	var got []*domain.Event
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, record.ToEvent())
+	}
+
+	if want := []*domain.Event{sampleEvent(), second}; !reflect.DeepEqual(got, want) {
+		t.Errorf("decoded %+v, want %+v", got, want)
+	}
+}
+
+func TestParquetEncoderRoundTrip(t *testing.T) {
+	second := sampleEvent()
+	second.ID = "evt-2"
+	second.Items = []domain.Item{}
+
+	out := encode(t, eventDomain.ExportFormatParquet, eventDomain.ParamKeys{}, sampleEvent(), second)
+
+	pr, err := reader.NewParquetReader(buffer.NewBufferFileFromBytes(out), new(ParquetRecord), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pr.ReadStop()
+
+	rows := make([]ParquetRecord, pr.GetNumRows())
+	if err := pr.Read(&rows); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []*domain.Event
+	for _, row := range rows {
+		record := row.Record()
+		got = append(got, record.ToEvent())
+	}
+	if want := []*domain.Event{sampleEvent(), second}; !reflect.DeepEqual(got, want) {
+		t.Errorf("decoded %+v, want %+v", got, want)
+	}
+}
+
+func TestCSVEncoder(t *testing.T) {
+	columns := eventDomain.ParamKeys{EventParams: []string{"currency", "value", "first_purchase", "missing"}, UserParams: []string{"plan"}}
+	out := encode(t, eventDomain.ExportFormatCSV, columns, sampleEvent())
+
+	rows, err := csv.NewReader(bytes.NewReader(out)).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want a header and one event", len(rows))
+	}
+
+	cells := make(map[string]string)
+	for i, column := range rows[0] {
+		cells[column] = rows[1][i]
+	}
+
+	want := map[string]string{
+		"id":                          "evt-1",
+		"channel_type":                "web",
+		"timestamp":                   "1705312800000",
+		"device.browser_name":         "Safari",
+		"geo.city":                    "Berlin",
+		"ingestion.received_at":       "2024-01-15T10:00:01.123456Z",
+		"event_params.currency":       "EUR",
+		"event_params.value":          "59.9",
+		"event_params.first_purchase": "true",
+		"event_params.missing":        "",
+		"user_params.plan":            "pro",
+	}
+	for column, value := range want {
+		if got, ok := cells[column]; !ok || got != value {
+			t.Errorf("%s = %q, want %q", column, got, value)
+		}
+	}
+
+	var items []Item
+	if err := json.Unmarshal([]byte(cells["items"]), &items); err != nil {
+		t.Fatalf("items = %q: %v", cells["items"], err)
+	}
+	if !reflect.DeepEqual(toItems(items), sampleEvent().Items) {
+		t.Errorf("items = %+v, want %+v", items, sampleEvent().Items)
+	}
+}
+
+func TestNewEncoderUnknownFormat(t *testing.T) {
+	if _, err := NewEncoder(&bytes.Buffer{}, "xml", eventDomain.ParamKeys{}); err == nil {
+		t.Error("NewEncoder() error = nil, want an error for an unknown format")
+	}
+}
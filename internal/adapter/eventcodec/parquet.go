@@ -0,0 +1,204 @@
+package eventcodec
+
+import (
+	"time"
+)
+
+// ParquetRecord is the Parquet representation of a stored event. Column names
+// match the JSON Record; nested objects are groups and arrays are lists.
+type ParquetRecord struct {
+	ID                string         `parquet:"name=id, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Name              string         `parquet:"name=name, type=BYTE_ARRAY, convertedtype=UTF8"`
+	ChannelType       string         `parquet:"name=channel_type, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Timestamp         int64          `parquet:"name=timestamp, type=INT64"`
+	PreviousTimestamp int64          `parquet:"name=previous_timestamp, type=INT64"`
+	Date              string         `parquet:"name=date, type=BYTE_ARRAY, convertedtype=UTF8"`
+	EventParams       []ParquetParam `parquet:"name=event_params, type=LIST"`
+	UserID            string         `parquet:"name=user_id, type=BYTE_ARRAY, convertedtype=UTF8"`
+	UserPseudoID      string         `parquet:"name=user_pseudo_id, type=BYTE_ARRAY, convertedtype=UTF8"`
+	UserParams        []ParquetParam `parquet:"name=user_params, type=LIST"`
+	Device            ParquetDevice  `parquet:"name=device"`
+	Geo               ParquetGeo     `parquet:"name=geo"`
+	AppInfo           ParquetAppInfo `parquet:"name=app_info"`
+	Items             []ParquetItem  `parquet:"name=items, type=LIST"`
+	Ingestion         *ParquetIngest `parquet:"name=ingestion"`
+}
+
+// ParquetParam is the Parquet representation of an event or user param
+type ParquetParam struct {
+	Key          string  `parquet:"name=key, type=BYTE_ARRAY, convertedtype=UTF8"`
+	StringValue  string  `parquet:"name=string_value, type=BYTE_ARRAY, convertedtype=UTF8"`
+	NumberValue  float64 `parquet:"name=number_value, type=DOUBLE"`
+	BooleanValue bool    `parquet:"name=boolean_value, type=BOOLEAN"`
+}
+
+// ParquetDevice is the Parquet representation of device information
+type ParquetDevice struct {
+	Category               string `parquet:"name=category, type=BYTE_ARRAY, convertedtype=UTF8"`
+	MobileBrandName        string `parquet:"name=mobile_brand_name, type=BYTE_ARRAY, convertedtype=UTF8"`
+	MobileModelName        string `parquet:"name=mobile_model_name, type=BYTE_ARRAY, convertedtype=UTF8"`
+	OperatingSystem        string `parquet:"name=operating_system, type=BYTE_ARRAY, convertedtype=UTF8"`
+	OperatingSystemVersion string `parquet:"name=operating_system_version, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Language               string `parquet:"name=language, type=BYTE_ARRAY, convertedtype=UTF8"`
+	BrowserName            string `parquet:"name=browser_name, type=BYTE_ARRAY, convertedtype=UTF8"`
+	BrowserVersion         string `parquet:"name=browser_version, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Hostname               string `parquet:"name=hostname, type=BYTE_ARRAY, convertedtype=UTF8"`
+}
+
+// ParquetGeo is the Parquet representation of geographic information
+type ParquetGeo struct {
+	Continent    string `parquet:"name=continent, type=BYTE_ARRAY, convertedtype=UTF8"`
+	SubContinent string `parquet:"name=sub_continent, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Country      string `parquet:"name=country, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Region       string `parquet:"name=region, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Metro        string `parquet:"name=metro, type=BYTE_ARRAY, convertedtype=UTF8"`
+	City         string `parquet:"name=city, type=BYTE_ARRAY, convertedtype=UTF8"`
+}
+
+// ParquetAppInfo is the Parquet representation of app information
+type ParquetAppInfo struct {
+	ID      string `parquet:"name=id, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Version string `parquet:"name=version, type=BYTE_ARRAY, convertedtype=UTF8"`
+}
+
+// ParquetItem is the Parquet representation of an ecommerce item
+type ParquetItem struct {
+	ID            string         `parquet:"name=id, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Name          string         `parquet:"name=name, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Brand         string         `parquet:"name=brand, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Variant       string         `parquet:"name=variant, type=BYTE_ARRAY, convertedtype=UTF8"`
+	PriceInUsd    float64        `parquet:"name=price_in_usd, type=DOUBLE"`
+	Quantity      int64          `parquet:"name=quantity, type=INT64"`
+	RevenueInUsd  float64        `parquet:"name=revenue_in_usd, type=DOUBLE"`
+	LocationId    string         `parquet:"name=location_id, type=BYTE_ARRAY, convertedtype=UTF8"`
+	ListId        string         `parquet:"name=list_id, type=BYTE_ARRAY, convertedtype=UTF8"`
+	ListName      string         `parquet:"name=list_name, type=BYTE_ARRAY, convertedtype=UTF8"`
+	PromotionId   string         `parquet:"name=promotion_id, type=BYTE_ARRAY, convertedtype=UTF8"`
+	PromotionName string         `parquet:"name=promotion_name, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Params        []ParquetParam `parquet:"name=params, type=LIST"`
+}
+
+// ParquetIngest is the Parquet representation of server-side ingestion metadata
+type ParquetIngest struct {
+	ReceivedAt   int64  `parquet:"name=received_at, type=INT64, convertedtype=TIMESTAMP_MICROS"`
+	Endpoint     string `parquet:"name=endpoint, type=BYTE_ARRAY, convertedtype=UTF8"`
+	RequestID    string `parquet:"name=request_id, type=BYTE_ARRAY, convertedtype=UTF8"`
+	ClientIPHash string `parquet:"name=client_ip_hash, type=BYTE_ARRAY, convertedtype=UTF8"`
+	SDKName      string `parquet:"name=sdk_name, type=BYTE_ARRAY, convertedtype=UTF8"`
+	SDKVersion   string `parquet:"name=sdk_version, type=BYTE_ARRAY, convertedtype=UTF8"`
+	APIKeyID     string `parquet:"name=api_key_id, type=BYTE_ARRAY, convertedtype=UTF8"`
+}
+
+// ToParquet converts a JSON record to its Parquet representation
+func ToParquet(r Record) ParquetRecord {
+	p := ParquetRecord{
+		ID:                r.ID,
+		Name:              r.Name,
+		ChannelType:       r.ChannelType,
+		Timestamp:         r.Timestamp,
+		PreviousTimestamp: r.PreviousTimestamp,
+		Date:              r.Date,
+		EventParams:       toParquetParams(r.EventParams),
+		UserID:            r.UserID,
+		UserPseudoID:      r.UserPseudoID,
+		UserParams:        toParquetParams(r.UserParams),
+		Device:            ParquetDevice(r.Device),
+		Geo:               ParquetGeo(r.Geo),
+		AppInfo:           ParquetAppInfo(r.AppInfo),
+		Items:             make([]ParquetItem, len(r.Items)),
+	}
+	for i, item := range r.Items {
+		p.Items[i] = ParquetItem{
+			ID:            item.ID,
+			Name:          item.Name,
+			Brand:         item.Brand,
+			Variant:       item.Variant,
+			PriceInUsd:    item.PriceInUsd,
+			Quantity:      int64(item.Quantity),
+			RevenueInUsd:  item.RevenueInUsd,
+			LocationId:    item.LocationId,
+			ListId:        item.ListId,
+			ListName:      item.ListName,
+			PromotionId:   item.PromotionId,
+			PromotionName: item.PromotionName,
+			Params:        toParquetParams(item.Params),
+		}
+	}
+	if r.Ingestion != nil {
+		p.Ingestion = &ParquetIngest{
+			ReceivedAt:   r.Ingestion.ReceivedAt.UnixMicro(),
+			Endpoint:     r.Ingestion.Endpoint,
+			RequestID:    r.Ingestion.RequestID,
+			ClientIPHash: r.Ingestion.ClientIPHash,
+			SDKName:      r.Ingestion.SDKName,
+			SDKVersion:   r.Ingestion.SDKVersion,
+			APIKeyID:     r.Ingestion.APIKeyID,
+		}
+	}
+	return p
+}
+
+// Record converts a Parquet row back to its JSON record
+func (p *ParquetRecord) Record() Record {
+	r := Record{
+		ID:                p.ID,
+		Name:              p.Name,
+		ChannelType:       p.ChannelType,
+		Timestamp:         p.Timestamp,
+		PreviousTimestamp: p.PreviousTimestamp,
+		Date:              p.Date,
+		EventParams:       fromParquetParams(p.EventParams),
+		UserID:            p.UserID,
+		UserPseudoID:      p.UserPseudoID,
+		UserParams:        fromParquetParams(p.UserParams),
+		Device:            Device(p.Device),
+		Geo:               Geo(p.Geo),
+		AppInfo:           AppInfo(p.AppInfo),
+		Items:             make([]Item, len(p.Items)),
+	}
+	for i, item := range p.Items {
+		r.Items[i] = Item{
+			ID:            item.ID,
+			Name:          item.Name,
+			Brand:         item.Brand,
+			Variant:       item.Variant,
+			PriceInUsd:    item.PriceInUsd,
+			Quantity:      int(item.Quantity),
+			RevenueInUsd:  item.RevenueInUsd,
+			LocationId:    item.LocationId,
+			ListId:        item.ListId,
+			ListName:      item.ListName,
+			PromotionId:   item.PromotionId,
+			PromotionName: item.PromotionName,
+			Params:        fromParquetParams(item.Params),
+		}
+	}
+	if p.Ingestion != nil {
+		r.Ingestion = &Ingestion{
+			ReceivedAt:   time.UnixMicro(p.Ingestion.ReceivedAt).UTC(),
+			Endpoint:     p.Ingestion.Endpoint,
+			RequestID:    p.Ingestion.RequestID,
+			ClientIPHash: p.Ingestion.ClientIPHash,
+			SDKName:      p.Ingestion.SDKName,
+			SDKVersion:   p.Ingestion.SDKVersion,
+			APIKeyID:     p.Ingestion.APIKeyID,
+		}
+	}
+	return r
+}
+
+func toParquetParams(params []Param) []ParquetParam {
+	records := make([]ParquetParam, len(params))
+	for i, p := range params {
+		records[i] = ParquetParam(p)
+	}
+	return records
+}
+
+func fromParquetParams(params []ParquetParam) []Param {
+	records := make([]Param, len(params))
+	for i, p := range params {
+		records[i] = Param(p)
+	}
+	return records
+}
@@ -0,0 +1,40 @@
+package eventcodec
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParquetRecordRoundTrip(t *testing.T) {
+	withoutIngestion := FromEvent(sampleEvent())
+	withoutIngestion.Ingestion = nil
+
+	tests := []struct {
+		name   string
+		record Record
+	}{
+		{name: "every field", record: FromEvent(sampleEvent())},
+		{name: "no ingestion", record: withoutIngestion},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			row := ToParquet(tt.record)
+			if got := row.Record(); !reflect.DeepEqual(got, tt.record) {
+				t.Errorf("Record() = %+v, want %+v", got, tt.record)
+			}
+		})
+	}
+}
+
+func TestParquetReceivedAtMicroseconds(t *testing.T) {
+	record := FromEvent(sampleEvent())
+	record.Ingestion.ReceivedAt = time.Date(2024, 1, 15, 10, 0, 1, 123456789, time.FixedZone("CET", 3600))
+
+	row := ToParquet(record)
+	got := row.Record().Ingestion.ReceivedAt
+	if want := time.Date(2024, 1, 15, 9, 0, 1, 123456000, time.UTC); !got.Equal(want) || got.Location() != time.UTC {
+		t.Errorf("ReceivedAt = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,266 @@
+// Package eventcodec converts stored events to and from the JSON shape
+// shared by outbound adapters. Field names match the HTTP CreateEventRequest,
+// plus the server-assigned id and ingestion metadata.
+package eventcodec
+
+import (
+	"time"
+
+	"github.com/ebubekir/event-stream/internal/domain"
+)
+
+// Record is the JSON representation of a stored event
+type Record struct {
+	ID                string     `json:"id"`
+	Name              string     `json:"name"`
+	ChannelType       string     `json:"channel_type"`
+	Timestamp         int64      `json:"timestamp"`
+	PreviousTimestamp int64      `json:"previous_timestamp"`
+	Date              string     `json:"date"`
+	EventParams       []Param    `json:"event_params"`
+	UserID            string     `json:"user_id"`
+	UserPseudoID      string     `json:"user_pseudo_id"`
+	UserParams        []Param    `json:"user_params"`
+	Device            Device     `json:"device"`
+	Geo               Geo        `json:"geo"`
+	AppInfo           AppInfo    `json:"app_info"`
+	Items             []Item     `json:"items"`
+	Ingestion         *Ingestion `json:"ingestion,omitempty"`
+}
+
+// Param is the JSON representation of an event or user param
+type Param struct {
+	Key          string  `json:"key"`
+	StringValue  string  `json:"string_value,omitempty"`
+	NumberValue  float64 `json:"number_value,omitempty"`
+	BooleanValue bool    `json:"boolean_value,omitempty"`
+}
+
+// Device is the JSON representation of device information
+type Device struct {
+	Category               string `json:"category"`
+	MobileBrandName        string `json:"mobile_brand_name"`
+	MobileModelName        string `json:"mobile_model_name"`
+	OperatingSystem        string `json:"operating_system"`
+	OperatingSystemVersion string `json:"operating_system_version"`
+	Language               string `json:"language"`
+	BrowserName            string `json:"browser_name"`
+	BrowserVersion         string `json:"browser_version"`
+	Hostname               string `json:"hostname"`
+}
+
+// Geo is the JSON representation of geographic information
+type Geo struct {
+	Continent    string `json:"continent"`
+	SubContinent string `json:"sub_continent"`
+	Country      string `json:"country"`
+	Region       string `json:"region"`
+	Metro        string `json:"metro"`
+	City         string `json:"city"`
+}
+
+// AppInfo is the JSON representation of app information
+type AppInfo struct {
+	ID      string `json:"id"`
+	Version string `json:"version"`
+}
+
+// Item is the JSON representation of an ecommerce item
+type Item struct {
+	ID            string  `json:"id"`
+	Name          string  `json:"name"`
+	Brand         string  `json:"brand"`
+	Variant       string  `json:"variant"`
+	PriceInUsd    float64 `json:"price_in_usd"`
+	Quantity      int     `json:"quantity"`
+	RevenueInUsd  float64 `json:"revenue_in_usd"`
+	LocationId    string  `json:"location_id"`
+	ListId        string  `json:"list_id"`
+	ListName      string  `json:"list_name"`
+	PromotionId   string  `json:"promotion_id"`
+	PromotionName string  `json:"promotion_name"`
+	Params        []Param `json:"params"`
+}
+
+// Ingestion is the JSON representation of server-side ingestion metadata
+type Ingestion struct {
+	ReceivedAt   time.Time `json:"received_at"`
+	Endpoint     string    `json:"endpoint"`
+	RequestID    string    `json:"request_id,omitempty"`
+	ClientIPHash string    `json:"client_ip_hash,omitempty"`
+	SDKName      string    `json:"sdk_name,omitempty"`
+	SDKVersion   string    `json:"sdk_version,omitempty"`
+	APIKeyID     string    `json:"api_key_id,omitempty"`
+}
+
+// FromEvent converts a domain event to its JSON record
+func FromEvent(e *domain.Event) Record {
+	return Record{
+		ID:                e.ID,
+		Name:              e.Name,
+		ChannelType:       string(e.ChannelType),
+		Timestamp:         e.Timestamp,
+		PreviousTimestamp: e.PreviousTimestamp,
+		Date:              e.Date,
+		EventParams:       fromParams(e.EventParams),
+		UserID:            e.UserID,
+		UserPseudoID:      e.UserPseudoID,
+		UserParams:        fromParams(e.UserParams),
+		Device: Device{
+			Category:               e.Device.Category,
+			MobileBrandName:        e.Device.MobileBrandName,
+			MobileModelName:        e.Device.MobileModelName,
+			OperatingSystem:        e.Device.OperatingSystem,
+			OperatingSystemVersion: e.Device.OperatingSystemVersion,
+			Language:               e.Device.Language,
+			BrowserName:            e.Device.BrowserName,
+			BrowserVersion:         e.Device.BrowserVersion,
+			Hostname:               e.Device.Hostname,
+		},
+		Geo: Geo{
+			Continent:    e.Geo.Continent,
+			SubContinent: e.Geo.SubContinent,
+			Country:      e.Geo.Country,
+			Region:       e.Geo.Region,
+			Metro:        e.Geo.Metro,
+			City:         e.Geo.City,
+		},
+		AppInfo: AppInfo{
+			ID:      e.AppInfo.ID,
+			Version: e.AppInfo.Version,
+		},
+		Items: fromItems(e.Items),
+		Ingestion: &Ingestion{
+			ReceivedAt:   e.Ingestion.ReceivedAt,
+			Endpoint:     e.Ingestion.Endpoint,
+			RequestID:    e.Ingestion.RequestID,
+			ClientIPHash: e.Ingestion.ClientIPHash,
+			SDKName:      e.Ingestion.SDKName,
+			SDKVersion:   e.Ingestion.SDKVersion,
+			APIKeyID:     e.Ingestion.APIKeyID,
+		},
+	}
+}
+
+// ToEvent converts a JSON record back to a domain event
+func (r *Record) ToEvent() *domain.Event {
+	event := &domain.Event{
+		ID:                r.ID,
+		Name:              r.Name,
+		ChannelType:       domain.ChannelType(r.ChannelType),
+		Timestamp:         r.Timestamp,
+		PreviousTimestamp: r.PreviousTimestamp,
+		Date:              r.Date,
+		EventParams:       toParams(r.EventParams),
+		UserID:            r.UserID,
+		UserPseudoID:      r.UserPseudoID,
+		UserParams:        toParams(r.UserParams),
+		Device: domain.Device{
+			Category:               r.Device.Category,
+			MobileBrandName:        r.Device.MobileBrandName,
+			MobileModelName:        r.Device.MobileModelName,
+			OperatingSystem:        r.Device.OperatingSystem,
+			OperatingSystemVersion: r.Device.OperatingSystemVersion,
+			Language:               r.Device.Language,
+			BrowserName:            r.Device.BrowserName,
+			BrowserVersion:         r.Device.BrowserVersion,
+			Hostname:               r.Device.Hostname,
+		},
+		Geo: domain.Geo{
+			Continent:    r.Geo.Continent,
+			SubContinent: r.Geo.SubContinent,
+			Country:      r.Geo.Country,
+			Region:       r.Geo.Region,
+			Metro:        r.Geo.Metro,
+			City:         r.Geo.City,
+		},
+		AppInfo: domain.AppInfo{
+			ID:      r.AppInfo.ID,
+			Version: r.AppInfo.Version,
+		},
+		Items: toItems(r.Items),
+	}
+
+	if r.Ingestion != nil {
+		event.Ingestion = domain.IngestionMetadata{
+			ReceivedAt:   r.Ingestion.ReceivedAt,
+			Endpoint:     r.Ingestion.Endpoint,
+			RequestID:    r.Ingestion.RequestID,
+			ClientIPHash: r.Ingestion.ClientIPHash,
+			SDKName:      r.Ingestion.SDKName,
+			SDKVersion:   r.Ingestion.SDKVersion,
+			APIKeyID:     r.Ingestion.APIKeyID,
+		}
+	}
+	return event
+}
+
+func fromParams(params []domain.Param) []Param {
+	records := make([]Param, len(params))
+	for i, p := range params {
+		records[i] = Param{
+			Key:          p.Key,
+			StringValue:  p.StringValue,
+			NumberValue:  p.NumberValue,
+			BooleanValue: p.BooleanValue,
+		}
+	}
+	return records
+}
+
+func fromItems(items []domain.Item) []Item {
+	records := make([]Item, len(items))
+	for i, item := range items {
+		records[i] = Item{
+			ID:            item.ID,
+			Name:          item.Name,
+			Brand:         item.Brand,
+			Variant:       item.Variant,
+			PriceInUsd:    item.PriceInUsd,
+			Quantity:      item.Quantity,
+			RevenueInUsd:  item.RevenueInUsd,
+			LocationId:    item.LocationId,
+			ListId:        item.ListId,
+			ListName:      item.ListName,
+			PromotionId:   item.PromotionId,
+			PromotionName: item.PromotionName,
+			Params:        fromParams(item.Params),
+		}
+	}
+	return records
+}
+
+func toParams(records []Param) []domain.Param {
+	params := make([]domain.Param, len(records))
+	for i, p := range records {
+		params[i] = domain.Param{
+			Key:          p.Key,
+			StringValue:  p.StringValue,
+			NumberValue:  p.NumberValue,
+			BooleanValue: p.BooleanValue,
+		}
+	}
+	return params
+}
+
+func toItems(records []Item) []domain.Item {
+	items := make([]domain.Item, len(records))
+	for i, item := range records {
+		items[i] = domain.Item{
+			ID:            item.ID,
+			Name:          item.Name,
+			Brand:         item.Brand,
+			Variant:       item.Variant,
+			PriceInUsd:    item.PriceInUsd,
+			Quantity:      item.Quantity,
+			RevenueInUsd:  item.RevenueInUsd,
+			LocationId:    item.LocationId,
+			ListId:        item.ListId,
+			ListName:      item.ListName,
+			PromotionId:   item.PromotionId,
+			PromotionName: item.PromotionName,
+			Params:        toParams(item.Params),
+		}
+	}
+	return items
+}
@@ -0,0 +1,81 @@
+package eventcodec
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ebubekir/event-stream/internal/domain"
+)
+
+// sampleEvent returns an event with every field set
+func sampleEvent() *domain.Event {
+	return &domain.Event{
+		ID:                "evt-1",
+		Timestamp:         1705312800000,
+		PreviousTimestamp: 1705312700000,
+		Date:              "2024-01-15T10:00:00Z",
+		Name:              "purchase",
+		ChannelType:       domain.ChannelTypeWeb,
+		EventParams: []domain.Param{
+			{Key: "currency", StringValue: "EUR"},
+			{Key: "value", NumberValue: 59.9},
+			{Key: "first_purchase", BooleanValue: true},
+		},
+		UserID:       "user-1",
+		UserPseudoID: "pseudo-1",
+		UserParams:   []domain.Param{{Key: "plan", StringValue: "pro"}},
+		Device: domain.Device{
+			Category: "desktop", MobileBrandName: "Apple", MobileModelName: "Mac",
+			OperatingSystem: "macOS", OperatingSystemVersion: "14.2", Language: "en-us",
+			BrowserName: "Safari", BrowserVersion: "17.2", Hostname: "shop.example.com",
+		},
+		Geo:     domain.Geo{Continent: "Europe", SubContinent: "Western Europe", Country: "Germany", Region: "Berlin", Metro: "(not set)", City: "Berlin"},
+		AppInfo: domain.AppInfo{ID: "shop", Version: "2.1.0"},
+		Items: []domain.Item{{
+			ID: "sku-1", Name: "Shoe", Brand: "Acme", Variant: "red", PriceInUsd: 29.95, Quantity: 2, RevenueInUsd: 59.9,
+			LocationId: "loc-1", ListId: "list-1", ListName: "Shoes", PromotionId: "promo-1", PromotionName: "Winter",
+			Params: []domain.Param{{Key: "size", NumberValue: 42}},
+		}},
+		Ingestion: domain.IngestionMetadata{
+			ReceivedAt: time.Date(2024, 1, 15, 10, 0, 1, 123456000, time.UTC),
+			Endpoint:   "/api/v1/events", RequestID: "req-1", ClientIPHash: "abc123",
+			SDKName: "event-stream-go", SDKVersion: "1.0.0", APIKeyID: "web",
+		},
+	}
+}
+
+func TestRecordRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		event *domain.Event
+	}{
+		{name: "every field", event: sampleEvent()},
+		{name: "no params or items", event: &domain.Event{ID: "evt-2", Name: "page_view", ChannelType: domain.ChannelTypeMobile, EventParams: []domain.Param{}, UserParams: []domain.Param{}, Items: []domain.Item{}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(FromEvent(tt.event))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var record Record
+			if err := json.Unmarshal(data, &record); err != nil {
+				t.Fatal(err)
+			}
+			if got := record.ToEvent(); !reflect.DeepEqual(got, tt.event) {
+				t.Errorf("ToEvent() = %+v, want %+v", got, tt.event)
+			}
+		})
+	}
+}
+
+func TestRecordWithoutIngestion(t *testing.T) {
+	record := Record{ID: "evt-1", Name: "page_view"}
+	if got := record.ToEvent(); !got.Ingestion.ReceivedAt.IsZero() || got.Ingestion.Endpoint != "" {
+		t.Errorf("ToEvent().Ingestion = %+v, want zero", got.Ingestion)
+	}
+}
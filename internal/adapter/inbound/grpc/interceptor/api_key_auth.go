@@ -0,0 +1,72 @@
+package interceptor
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ebubekir/event-stream/pkg/auth"
+)
+
+// APIKeyMetadataKey carries the caller's API key, matching the HTTP X-API-Key header
+const APIKeyMetadataKey = "x-api-key"
+
+// publicServicePrefixes are reachable without an API key so load balancers
+// and tooling can use them
+var publicServicePrefixes = []string{
+	"/grpc.health.v1.Health/",
+	"/grpc.reflection.",
+}
+
+type apiKeyIDKey struct{}
+
+// UnaryAPIKeyAuth rejects calls without a valid x-api-key metadata value.
+// Authentication is disabled when no keys are configured.
+func UnaryAPIKeyAuth(keys []auth.APIKey) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authenticate(ctx, keys, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamAPIKeyAuth is the streaming counterpart of UnaryAPIKeyAuth
+func StreamAPIKeyAuth(keys []auth.APIKey) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), keys, info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &wrappedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// APIKeyIDFromContext returns the ID of the API key that authenticated the call
+func APIKeyIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(apiKeyIDKey{}).(string)
+	return id
+}
+
+func authenticate(ctx context.Context, keys []auth.APIKey, fullMethod string) (context.Context, error) {
+	if len(keys) == 0 {
+		return ctx, nil
+	}
+
+	for _, prefix := range publicServicePrefixes {
+		if strings.HasPrefix(fullMethod, prefix) {
+			return ctx, nil
+		}
+	}
+
+	id, ok := auth.Match(keys, firstMetadata(ctx, APIKeyMetadataKey))
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing or invalid API key")
+	}
+
+	return context.WithValue(ctx, apiKeyIDKey{}, id), nil
+}
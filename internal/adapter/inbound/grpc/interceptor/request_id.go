@@ -0,0 +1,45 @@
+package interceptor
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// RequestIDMetadataKey carries the request ID in both directions, matching the HTTP X-Request-ID header
+const RequestIDMetadataKey = "x-request-id"
+
+type requestIDKey struct{}
+
+// UnaryRequestID reuses the caller's x-request-id or generates one,
+// and echoes it back in the response header
+func UnaryRequestID() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(withRequestID(ctx), req)
+	}
+}
+
+// StreamRequestID is the streaming counterpart of UnaryRequestID
+func StreamRequestID() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &wrappedStream{ServerStream: ss, ctx: withRequestID(ss.Context())})
+	}
+}
+
+// RequestIDFromContext returns the request ID assigned by the request ID interceptors
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+func withRequestID(ctx context.Context) context.Context {
+	requestID := firstMetadata(ctx, RequestIDMetadataKey)
+	if requestID == "" || len(requestID) > 128 {
+		requestID = uuid.New().String()
+	}
+
+	_ = grpc.SetHeader(ctx, metadata.Pairs(RequestIDMetadataKey, requestID))
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
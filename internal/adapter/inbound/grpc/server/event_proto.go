@@ -0,0 +1,175 @@
+package server
+
+import (
+	"time"
+
+	eventstreamv1 "github.com/ebubekir/event-stream/api/gen/eventstream/v1"
+	"github.com/ebubekir/event-stream/internal/application/event"
+	"github.com/ebubekir/event-stream/internal/domain"
+	eventDomain "github.com/ebubekir/event-stream/internal/domain/event"
+)
+
+// toCommand converts a protobuf event to an application command and validates it
+func toCommand(msg *eventstreamv1.CreateEventRequest) (*event.CreateEventCommand, error) {
+	device := msg.GetDevice()
+	geo := msg.GetGeo()
+	appInfo := msg.GetAppInfo()
+
+	cmd := &event.CreateEventCommand{
+		Name:              msg.GetName(),
+		ChannelType:       domain.ChannelType(msg.GetChannelType()),
+		Timestamp:         msg.GetTimestamp(),
+		PreviousTimestamp: msg.GetPreviousTimestamp(),
+		Date:              msg.GetDate(),
+		EventParams:       toParamDTOs(msg.GetEventParams()),
+		UserID:            msg.GetUserId(),
+		UserPseudoID:      msg.GetUserPseudoId(),
+		UserParams:        toParamDTOs(msg.GetUserParams()),
+		Device: event.DeviceDTO{
+			Category:               device.GetCategory(),
+			MobileBrandName:        device.GetMobileBrandName(),
+			MobileModelName:        device.GetMobileModelName(),
+			OperatingSystem:        device.GetOperatingSystem(),
+			OperatingSystemVersion: device.GetOperatingSystemVersion(),
+			Language:               device.GetLanguage(),
+			BrowserName:            device.GetBrowserName(),
+			BrowserVersion:         device.GetBrowserVersion(),
+			Hostname:               device.GetHostname(),
+		},
+		Geo: event.GeoDTO{
+			Continent:    geo.GetContinent(),
+			SubContinent: geo.GetSubContinent(),
+			Country:      geo.GetCountry(),
+			Region:       geo.GetRegion(),
+			Metro:        geo.GetMetro(),
+			City:         geo.GetCity(),
+		},
+		AppInfo: event.AppInfoDTO{
+			ID:      appInfo.GetId(),
+			Version: appInfo.GetVersion(),
+		},
+		Items: toItemDTOs(msg.GetItems()),
+	}
+
+	if err := cmd.Validate(); err != nil {
+		return nil, err
+	}
+	return cmd, nil
+}
+
+func toParamDTOs(params []*eventstreamv1.Param) []event.ParamDTO {
+	dtos := make([]event.ParamDTO, len(params))
+	for i, p := range params {
+		dtos[i] = event.ParamDTO{
+			Key:          p.GetKey(),
+			StringValue:  p.GetStringValue(),
+			NumberValue:  p.GetNumberValue(),
+			BooleanValue: p.GetBooleanValue(),
+		}
+	}
+	return dtos
+}
+
+func toItemDTOs(items []*eventstreamv1.Item) []event.ItemDTO {
+	dtos := make([]event.ItemDTO, len(items))
+	for i, item := range items {
+		dtos[i] = event.ItemDTO{
+			ID:            item.GetId(),
+			Name:          item.GetName(),
+			Brand:         item.GetBrand(),
+			Variant:       item.GetVariant(),
+			PriceInUsd:    item.GetPriceInUsd(),
+			Quantity:      int(item.GetQuantity()),
+			RevenueInUsd:  item.GetRevenueInUsd(),
+			LocationId:    item.GetLocationId(),
+			ListId:        item.GetListId(),
+			ListName:      item.GetListName(),
+			PromotionId:   item.GetPromotionId(),
+			PromotionName: item.GetPromotionName(),
+			Params:        toParamDTOs(item.GetParams()),
+		}
+	}
+	return dtos
+}
+
+// toMetricsParams converts a protobuf metrics request to the application's metrics params
+func toMetricsParams(msg *eventstreamv1.GetMetricsRequest) *event.MetricsParams {
+	eventNames := msg.GetEventNames()
+	if msg.GetEventName() != "" {
+		eventNames = append([]string{msg.GetEventName()}, eventNames...)
+	}
+
+	return &event.MetricsParams{
+		EventNames:      eventNames,
+		From:            msg.GetFrom(),
+		To:              msg.GetTo(),
+		Timezone:        msg.GetTimezone(),
+		GroupBy:         msg.GetGroupBy(),
+		GroupLimit:      int(msg.GetGroupLimit()),
+		Fill:            msg.GetFill(),
+		Measures:        msg.GetMeasures(),
+		ResolveIdentity: msg.GetResolveIdentity(),
+		IngestEndpoint:  msg.GetIngestEndpoint(),
+		SDKName:         msg.GetSdkName(),
+		SDKVersion:      msg.GetSdkVersion(),
+		APIKeyID:        msg.GetApiKeyId(),
+		Filters:         msg.GetFilters(),
+	}
+}
+
+// fromMetricsResult converts the application result to the protobuf response
+func fromMetricsResult(result *event.MetricsResultDTO) *eventstreamv1.GetMetricsResponse {
+	groupedMetrics := make([]*eventstreamv1.GroupedMetric, len(result.GroupedMetrics))
+	for i, gm := range result.GroupedMetrics {
+		groupKeys := make(map[string]string, len(gm.GroupKeys))
+		for j, key := range gm.GroupKeys {
+			groupKeys[result.GroupBy[j]] = key
+		}
+
+		// Counts left null by fill=null read as zero, with Filled set
+		groupedMetrics[i] = &eventstreamv1.GroupedMetric{
+			GroupKey:  gm.GroupKey,
+			GroupKeys: groupKeys,
+			Measures:  measureMap(result.Measures, gm.MeasureValues),
+			Filled:    gm.Filled,
+		}
+		if !gm.Filled || result.Fill != string(eventDomain.FillNull) {
+			groupedMetrics[i].TotalCount = gm.TotalCount
+			groupedMetrics[i].UniqueUserCount = gm.UniqueUserCount
+		}
+	}
+
+	var eventName string
+	if len(result.EventNames) == 1 {
+		eventName = result.EventNames[0]
+	}
+
+	return &eventstreamv1.GetMetricsResponse{
+		EventNames:      result.EventNames,
+		EventName:       eventName,
+		GroupBy:         result.GroupBy,
+		Timezone:        result.Timezone,
+		Fill:            result.Fill,
+		Measures:        measureMap(result.Measures, result.MeasureValues),
+		From:            result.From.Format(time.RFC3339),
+		To:              result.To.Format(time.RFC3339),
+		TotalCount:      result.TotalCount,
+		UniqueUserCount: result.UniqueUserCount,
+		GroupedMetrics:  groupedMetrics,
+	}
+}
+
+// measureMap keys measure values by their measure, leaving out the measures
+// without values, which protobuf maps cannot hold
+func measureMap(measures []string, values []*float64) map[string]float64 {
+	m := make(map[string]float64, len(measures))
+	for i, measure := range measures {
+		if i < len(values) && values[i] != nil {
+			m[measure] = *values[i]
+		}
+	}
+	if len(m) == 0 {
+		return nil
+	}
+	return m
+}
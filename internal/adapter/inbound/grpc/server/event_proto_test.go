@@ -0,0 +1,81 @@
+package server
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	eventstreamv1 "github.com/ebubekir/event-stream/api/gen/eventstream/v1"
+	"github.com/ebubekir/event-stream/internal/application/event"
+)
+
+func TestToCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		msg     *eventstreamv1.CreateEventRequest
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			msg: &eventstreamv1.CreateEventRequest{
+				Name:        "purchase",
+				ChannelType: "web",
+				EventParams: []*eventstreamv1.Param{{Key: "value", NumberValue: 10}},
+				Items:       []*eventstreamv1.Item{{Id: "sku-1", Quantity: 2, Params: []*eventstreamv1.Param{{Key: "size"}}}},
+			},
+		},
+		{name: "missing name", msg: &eventstreamv1.CreateEventRequest{ChannelType: "web"}, wantErr: true},
+		{name: "unknown channel", msg: &eventstreamv1.CreateEventRequest{Name: "purchase", ChannelType: "fax"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, err := toCommand(tt.msg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("toCommand() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if !errors.Is(err, event.ErrInvalidEvent) {
+					t.Errorf("toCommand() error = %v, want ErrInvalidEvent", err)
+				}
+				return
+			}
+			if len(cmd.EventParams) != 1 || cmd.Items[0].Quantity != 2 || cmd.Items[0].Params[0].Key != "size" {
+				t.Errorf("toCommand() = %+v", cmd)
+			}
+		})
+	}
+}
+
+func TestFromMetricsResult(t *testing.T) {
+	value := 2.5
+	result := &event.MetricsResultDTO{
+		EventNames:    []string{"purchase"},
+		GroupBy:       []string{"daily"},
+		Measures:      []string{"avg:event_param.value", "sum:event_param.value"},
+		Fill:          "null",
+		From:          time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		To:            time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC),
+		TotalCount:    4,
+		MeasureValues: []*float64{&value, nil},
+		GroupedMetrics: []event.GroupedMetricDTO{
+			{GroupKey: "2024-01-01", GroupKeys: []string{"2024-01-01"}, TotalCount: 4, UniqueUserCount: 1, MeasureValues: []*float64{&value, nil}},
+			{GroupKey: "2024-01-02", GroupKeys: []string{"2024-01-02"}, TotalCount: 7, Filled: true},
+		},
+	}
+
+	resp := fromMetricsResult(result)
+
+	if resp.EventName != "purchase" || resp.From != "2024-01-01T00:00:00Z" {
+		t.Errorf("EventName = %q, From = %q", resp.EventName, resp.From)
+	}
+	if len(resp.Measures) != 1 || resp.Measures["avg:event_param.value"] != value {
+		t.Errorf("Measures = %v, want only the measure with a value", resp.Measures)
+	}
+	if got := resp.GroupedMetrics[0].GroupKeys["daily"]; got != "2024-01-01" {
+		t.Errorf("GroupKeys[daily] = %q", got)
+	}
+	if filled := resp.GroupedMetrics[1]; !filled.Filled || filled.TotalCount != 0 {
+		t.Errorf("filled bucket with fill=null = %+v, want zero counts", filled)
+	}
+}
@@ -0,0 +1,179 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	eventstreamv1 "github.com/ebubekir/event-stream/api/gen/eventstream/v1"
+	"github.com/ebubekir/event-stream/internal/adapter/inbound/grpc/interceptor"
+	"github.com/ebubekir/event-stream/internal/application/event"
+	eventDomain "github.com/ebubekir/event-stream/internal/domain/event"
+)
+
+const (
+	// SDKMetadataKey identifies the client SDK as "name/version", matching the HTTP X-Client-SDK header
+	SDKMetadataKey = "x-client-sdk"
+
+	// streamBatchSize is the number of streamed events persisted per batch
+	streamBatchSize = 500
+)
+
+// EventServer implements the eventstream.v1.EventService gRPC service
+type EventServer struct {
+	eventstreamv1.UnimplementedEventServiceServer
+	service *event.EventService
+}
+
+// NewEventServer creates a new EventServer
+func NewEventServer(service *event.EventService) *EventServer {
+	return &EventServer{
+		service: service,
+	}
+}
+
+// Register registers the event service on the given gRPC server
+func (s *EventServer) Register(srv *grpc.Server) {
+	eventstreamv1.RegisterEventServiceServer(srv, s)
+}
+
+// CreateEvent persists a single event
+func (s *EventServer) CreateEvent(ctx context.Context, msg *eventstreamv1.CreateEventRequest) (*eventstreamv1.CreateEventResponse, error) {
+	cmd, err := toCommand(msg)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	cmd.Ingestion = ingestionMetadata(ctx)
+	created, err := s.service.CreateEvent(ctx, cmd)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &eventstreamv1.CreateEventResponse{Id: created.ID, Quarantined: created.Quarantined}, nil
+}
+
+// StreamEvents persists streamed events in batches of streamBatchSize.
+// Batches saved before a failure stay saved; the error reports the index of
+// the first event that could not be stored.
+func (s *EventServer) StreamEvents(stream eventstreamv1.EventService_StreamEventsServer) error {
+	ctx := stream.Context()
+	ingestion := ingestionMetadata(ctx)
+
+	var ids, quarantinedIDs []string
+	batch := make([]*event.CreateEventCommand, 0, streamBatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		created, err := s.service.CreateEvents(ctx, batch)
+		if err != nil {
+			return toStatus(fmt.Errorf("event %d: %w", len(ids), err))
+		}
+		ids = append(ids, created.IDs...)
+		quarantinedIDs = append(quarantinedIDs, created.QuarantinedIDs...)
+		batch = batch[:0]
+		return nil
+	}
+
+	for {
+		msg, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		cmd, err := toCommand(msg)
+		if err != nil {
+			return status.Errorf(codes.InvalidArgument, "event %d: %v", len(ids)+len(batch), err)
+		}
+		cmd.Ingestion = ingestion
+		cmd.Ingestion.ReceivedAt = time.Now().UTC()
+		batch = append(batch, cmd)
+
+		if len(batch) == streamBatchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := flush(); err != nil {
+		return err
+	}
+
+	return stream.SendAndClose(&eventstreamv1.StreamEventsResponse{Ids: ids, QuarantinedIds: quarantinedIDs})
+}
+
+// GetMetrics retrieves aggregated metrics for events
+func (s *EventServer) GetMetrics(ctx context.Context, msg *eventstreamv1.GetMetricsRequest) (*eventstreamv1.GetMetricsResponse, error) {
+	query, err := toMetricsParams(msg).ToQuery()
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	result, err := s.service.GetMetrics(ctx, query)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return fromMetricsResult(result), nil
+}
+
+// toStatus maps service errors to gRPC status codes
+func toStatus(err error) error {
+	if errors.Is(err, eventDomain.ErrEventOutOfWindow) || errors.Is(err, eventDomain.ErrInvalidFilter) ||
+		errors.Is(err, eventDomain.ErrInvalidGroupBy) || errors.Is(err, eventDomain.ErrInvalidMeasure) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if errors.Is(err, context.Canceled) {
+		return status.Error(codes.Canceled, err.Error())
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+// ingestionMetadata captures how the call reached the server
+func ingestionMetadata(ctx context.Context) event.IngestionMetadataDTO {
+	var sdk string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(SDKMetadataKey); len(values) > 0 {
+			sdk = values[0]
+		}
+	}
+	sdkName, sdkVersion, _ := strings.Cut(sdk, "/")
+
+	var clientIP string
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		clientIP = p.Addr.String()
+		if host, _, err := net.SplitHostPort(clientIP); err == nil {
+			clientIP = host
+		}
+	}
+
+	endpoint, _ := grpc.Method(ctx)
+
+	return event.IngestionMetadataDTO{
+		ReceivedAt: time.Now().UTC(),
+		Endpoint:   endpoint,
+		RequestID:  interceptor.RequestIDFromContext(ctx),
+		ClientIP:   clientIP,
+		SDKName:    strings.TrimSpace(sdkName),
+		SDKVersion: strings.TrimSpace(sdkVersion),
+		APIKeyID:   interceptor.APIKeyIDFromContext(ctx),
+	}
+}
@@ -0,0 +1,201 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/ebubekir/event-stream/internal/application/event"
+)
+
+// GetEcommerceMetricsRequest represents the HTTP query parameters for ecommerce metrics
+type GetEcommerceMetricsRequest struct {
+	EventNames      []string `form:"event_name"` // purchase events, repeat for several; defaults to purchase
+	From            string   `form:"from"`       // RFC3339, or a local date or date-time in timezone
+	To              string   `form:"to"`         // RFC3339, or a local date (inclusive) or date-time in timezone
+	Timezone        string   `form:"timezone"`   // IANA name, e.g. Europe/Istanbul; defaults to UTC
+	Interval        string   `form:"interval" binding:"omitempty,oneof=minute 5min hourly daily weekly weekly_sunday monthly quarterly"`
+	ResolveIdentity bool     `form:"resolve_identity"` // count purchasing users by canonical identity
+	Filters         []string `form:"filter"`           // <field>:<operator>[:<value>], repeat to combine
+} // @name GetEcommerceMetricsRequest
+
+// ToQuery converts HTTP request to application query
+func (r *GetEcommerceMetricsRequest) ToQuery() (*event.GetEcommerceMetricsQuery, error) {
+	query := &event.GetEcommerceMetricsQuery{
+		EventNames:      r.EventNames,
+		Interval:        r.Interval,
+		ResolveIdentity: r.ResolveIdentity,
+	}
+
+	location, err := event.LoadTimezone(r.Timezone)
+	if err != nil {
+		return nil, err
+	}
+	query.Location = location
+
+	query.Filters, err = event.ParseFilters(r.Filters)
+	if err != nil {
+		return nil, err
+	}
+
+	query.From, query.To, err = event.ParseTimeRange(r.From, r.To, location)
+	if err != nil {
+		return nil, err
+	}
+
+	return query, nil
+}
+
+// GetTopItemsRequest represents the HTTP query parameters for top items
+type GetTopItemsRequest struct {
+	EventNames []string `form:"event_name"` // purchase events, repeat for several; defaults to purchase
+	From       string   `form:"from"`       // RFC3339, or a local date or date-time in timezone
+	To         string   `form:"to"`         // RFC3339, or a local date (inclusive) or date-time in timezone
+	Timezone   string   `form:"timezone"`   // IANA name, e.g. Europe/Istanbul; defaults to UTC
+	Interval   string   `form:"interval" binding:"omitempty,oneof=minute 5min hourly daily weekly weekly_sunday monthly quarterly"`
+	Filters    []string `form:"filter"`                                             // <field>:<operator>[:<value>], repeat to combine
+	By         string   `form:"by" binding:"omitempty,oneof=item brand variant"`    // defaults to item
+	SortBy     string   `form:"sort_by" binding:"omitempty,oneof=revenue quantity"` // defaults to revenue
+	Limit      int      `form:"limit" binding:"omitempty,min=1,max=100"`            // defaults to 10
+} // @name GetTopItemsRequest
+
+// ToQuery converts HTTP request to application query
+func (r *GetTopItemsRequest) ToQuery() (*event.GetTopItemsQuery, error) {
+	ecommerceRequest := GetEcommerceMetricsRequest{
+		EventNames: r.EventNames,
+		From:       r.From,
+		To:         r.To,
+		Timezone:   r.Timezone,
+		Interval:   r.Interval,
+		Filters:    r.Filters,
+	}
+	ecommerceQuery, err := ecommerceRequest.ToQuery()
+	if err != nil {
+		return nil, err
+	}
+
+	return &event.GetTopItemsQuery{
+		GetEcommerceMetricsQuery: *ecommerceQuery,
+		By:                       r.By,
+		SortBy:                   r.SortBy,
+		Limit:                    r.Limit,
+	}, nil
+}
+
+// EcommerceMetricsResponse represents ecommerce metrics in the response
+type EcommerceMetricsResponse struct {
+	Revenue           float64 `json:"revenue"` // sum of item revenue in USD
+	Orders            int64   `json:"orders"`  // purchase events
+	PurchasingUsers   int64   `json:"purchasing_users"`
+	AverageOrderValue float64 `json:"average_order_value"` // revenue per order in USD
+	UnitsSold         int64   `json:"units_sold"`          // sum of item quantities
+} // @name EcommerceMetricsResponse
+
+// EcommerceBucketResponse represents the ecommerce metrics of a time bucket in the response
+type EcommerceBucketResponse struct {
+	Bucket string `json:"bucket"` // first day, or start time for buckets shorter than a day
+	EcommerceMetricsResponse
+} // @name EcommerceBucketResponse
+
+// GetEcommerceMetricsResponse represents the HTTP response for ecommerce metrics
+type GetEcommerceMetricsResponse struct {
+	EventNames []string                  `json:"event_names"`
+	Interval   string                    `json:"interval,omitempty"`
+	Timezone   string                    `json:"timezone"`
+	From       string                    `json:"from"`
+	To         string                    `json:"to"`
+	Totals     EcommerceMetricsResponse  `json:"totals"`
+	Buckets    []EcommerceBucketResponse `json:"buckets,omitempty"` // buckets with orders, oldest first
+} // @name GetEcommerceMetricsResponse
+
+// FromEcommerceResultDTO converts application DTO to HTTP response
+func FromEcommerceResultDTO(dto *event.EcommerceResultDTO) *GetEcommerceMetricsResponse {
+	var buckets []EcommerceBucketResponse
+	for _, bucket := range dto.Buckets {
+		buckets = append(buckets, EcommerceBucketResponse{
+			Bucket:                   bucket.Bucket,
+			EcommerceMetricsResponse: fromEcommerceMetricsDTO(bucket.EcommerceMetricsDTO),
+		})
+	}
+
+	return &GetEcommerceMetricsResponse{
+		EventNames: dto.EventNames,
+		Interval:   dto.Interval,
+		Timezone:   dto.Timezone,
+		From:       dto.From.Format(time.RFC3339),
+		To:         dto.To.Format(time.RFC3339),
+		Totals:     fromEcommerceMetricsDTO(dto.Totals),
+		Buckets:    buckets,
+	}
+}
+
+func fromEcommerceMetricsDTO(dto event.EcommerceMetricsDTO) EcommerceMetricsResponse {
+	return EcommerceMetricsResponse{
+		Revenue:           dto.Revenue,
+		Orders:            dto.Orders,
+		PurchasingUsers:   dto.PurchasingUsers,
+		AverageOrderValue: dto.AverageOrderValue,
+		UnitsSold:         dto.UnitsSold,
+	}
+}
+
+// ItemBucketResponse represents the sales of an item in a time bucket in the response
+type ItemBucketResponse struct {
+	Bucket   string  `json:"bucket"`
+	Revenue  float64 `json:"revenue"`
+	Quantity int64   `json:"quantity"`
+} // @name ItemBucketResponse
+
+// ItemMetricsResponse represents the sales of an item, brand or variant in the response
+type ItemMetricsResponse struct {
+	Key      string               `json:"key"`            // item ID, brand or variant
+	Name     string               `json:"name,omitempty"` // item name, when ranking by item
+	Revenue  float64              `json:"revenue"`
+	Quantity int64                `json:"quantity"`
+	Orders   int64                `json:"orders"`
+	Series   []ItemBucketResponse `json:"series,omitempty"` // buckets with sales, oldest first
+} // @name ItemMetricsResponse
+
+// GetTopItemsResponse represents the HTTP response for top items
+type GetTopItemsResponse struct {
+	EventNames []string              `json:"event_names"`
+	By         string                `json:"by"`
+	SortBy     string                `json:"sort_by"`
+	Interval   string                `json:"interval,omitempty"`
+	Timezone   string                `json:"timezone"`
+	From       string                `json:"from"`
+	To         string                `json:"to"`
+	Items      []ItemMetricsResponse `json:"items"` // highest first
+} // @name GetTopItemsResponse
+
+// FromTopItemsResultDTO converts application DTO to HTTP response
+func FromTopItemsResultDTO(dto *event.TopItemsResultDTO) *GetTopItemsResponse {
+	items := make([]ItemMetricsResponse, len(dto.Items))
+	for i, item := range dto.Items {
+		var series []ItemBucketResponse
+		for _, bucket := range item.Series {
+			series = append(series, ItemBucketResponse{
+				Bucket:   bucket.Bucket,
+				Revenue:  bucket.Revenue,
+				Quantity: bucket.Quantity,
+			})
+		}
+		items[i] = ItemMetricsResponse{
+			Key:      item.Key,
+			Name:     item.Name,
+			Revenue:  item.Revenue,
+			Quantity: item.Quantity,
+			Orders:   item.Orders,
+			Series:   series,
+		}
+	}
+
+	return &GetTopItemsResponse{
+		EventNames: dto.EventNames,
+		By:         dto.By,
+		SortBy:     dto.SortBy,
+		Interval:   dto.Interval,
+		Timezone:   dto.Timezone,
+		From:       dto.From.Format(time.RFC3339),
+		To:         dto.To.Format(time.RFC3339),
+		Items:      items,
+	}
+}
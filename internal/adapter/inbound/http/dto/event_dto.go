@@ -1,6 +1,8 @@
 package dto
 
 import (
+	"time"
+
 	"github.com/ebubekir/event-stream/internal/application/event"
 	"github.com/ebubekir/event-stream/internal/domain"
 )
@@ -17,6 +19,7 @@ type CreateEventRequest struct {
 	UserPseudoID      string         `json:"user_pseudo_id"`
 	UserParams        []ParamRequest `json:"user_params"`
 	Device            DeviceRequest  `json:"device"`
+	Geo               GeoRequest     `json:"geo"`
 	AppInfo           AppInfoRequest `json:"app_info"`
 	Items             []ItemRequest  `json:"items"`
 } // @name CreateEventRequest
@@ -42,6 +45,16 @@ type DeviceRequest struct {
 	Hostname               string `json:"hostname"`
 } // @name DeviceRequest
 
+// GeoRequest represents geographic information in HTTP request
+type GeoRequest struct {
+	Continent    string `json:"continent"`
+	SubContinent string `json:"sub_continent"`
+	Country      string `json:"country"`
+	Region       string `json:"region"`
+	Metro        string `json:"metro"`
+	City         string `json:"city"`
+} // @name GeoRequest
+
 // AppInfoRequest represents app information in HTTP request
 type AppInfoRequest struct {
 	ID      string `json:"id"`
@@ -65,9 +78,16 @@ type ItemRequest struct {
 	Params        []ParamRequest `json:"params"`
 } // @name ItemRequest
 
+// Statuses of a created event
+const (
+	EventStatusStored      = "stored"
+	EventStatusQuarantined = "quarantined"
+)
+
 // CreateEventResponse represents the HTTP response after creating an event
 type CreateEventResponse struct {
-	ID string `json:"id"`
+	ID     string `json:"id"`
+	Status string `json:"status" enums:"stored,quarantined"` // quarantined events are held back for review and not stored
 } // @name CreateEventResponse
 
 // CreateEventBatchRequest represents batch event creation request
@@ -77,7 +97,8 @@ type CreateEventBatchRequest struct {
 
 // CreateEventBatchResponse represents batch event creation response
 type CreateEventBatchResponse struct {
-	IDs []string `json:"ids"`
+	IDs            []string `json:"ids"`
+	QuarantinedIDs []string `json:"quarantined_ids,omitempty"` // events held back for review instead of stored
 } // @name CreateEventBatchResponse
 
 // ToCommand converts HTTP DTO to application command
@@ -93,6 +114,7 @@ func (r *CreateEventRequest) ToCommand() *event.CreateEventCommand {
 		UserPseudoID:      r.UserPseudoID,
 		UserParams:        toParamDTOs(r.UserParams),
 		Device:            toDeviceDTO(r.Device),
+		Geo:               toGeoDTO(r.Geo),
 		AppInfo:           toAppInfoDTO(r.AppInfo),
 		Items:             toItemDTOs(r.Items),
 	}
@@ -125,6 +147,17 @@ func toDeviceDTO(req DeviceRequest) event.DeviceDTO {
 	}
 }
 
+func toGeoDTO(req GeoRequest) event.GeoDTO {
+	return event.GeoDTO{
+		Continent:    req.Continent,
+		SubContinent: req.SubContinent,
+		Country:      req.Country,
+		Region:       req.Region,
+		Metro:        req.Metro,
+		City:         req.City,
+	}
+}
+
 func toAppInfoDTO(req AppInfoRequest) event.AppInfoDTO {
 	return event.AppInfoDTO{
 		ID:      req.ID,
@@ -153,3 +186,248 @@ func toItemDTOs(requests []ItemRequest) []event.ItemDTO {
 	}
 	return items
 }
+
+// EventResponse represents a stored event in the response. It mirrors
+// CreateEventRequest, plus the event ID and how the event was ingested.
+type EventResponse struct {
+	ID                string            `json:"id"`
+	Name              string            `json:"name"`
+	ChannelType       string            `json:"channel_type"`
+	Timestamp         int64             `json:"timestamp"`
+	PreviousTimestamp int64             `json:"previous_timestamp"`
+	Date              string            `json:"date"`
+	EventParams       []ParamResponse   `json:"event_params"`
+	UserID            string            `json:"user_id"`
+	UserPseudoID      string            `json:"user_pseudo_id"`
+	UserParams        []ParamResponse   `json:"user_params"`
+	Device            DeviceResponse    `json:"device"`
+	Geo               GeoResponse       `json:"geo"`
+	AppInfo           AppInfoResponse   `json:"app_info"`
+	Items             []ItemResponse    `json:"items"`
+	Ingestion         IngestionResponse `json:"ingestion"`
+} // @name EventResponse
+
+// ParamResponse represents a parameter in the response
+type ParamResponse struct {
+	Key          string  `json:"key"`
+	StringValue  string  `json:"string_value"`
+	NumberValue  float64 `json:"number_value"`
+	BooleanValue bool    `json:"boolean_value"`
+} // @name ParamResponse
+
+// DeviceResponse represents device information in the response
+type DeviceResponse struct {
+	Category               string `json:"category"`
+	MobileBrandName        string `json:"mobile_brand_name"`
+	MobileModelName        string `json:"mobile_model_name"`
+	OperatingSystem        string `json:"operating_system"`
+	OperatingSystemVersion string `json:"operating_system_version"`
+	Language               string `json:"language"`
+	BrowserName            string `json:"browser_name"`
+	BrowserVersion         string `json:"browser_version"`
+	Hostname               string `json:"hostname"`
+} // @name DeviceResponse
+
+// GeoResponse represents geographic information in the response
+type GeoResponse struct {
+	Continent    string `json:"continent"`
+	SubContinent string `json:"sub_continent"`
+	Country      string `json:"country"`
+	Region       string `json:"region"`
+	Metro        string `json:"metro"`
+	City         string `json:"city"`
+} // @name GeoResponse
+
+// AppInfoResponse represents app information in the response
+type AppInfoResponse struct {
+	ID      string `json:"id"`
+	Version string `json:"version"`
+} // @name AppInfoResponse
+
+// ItemResponse represents an item in the response
+type ItemResponse struct {
+	ID            string          `json:"id"`
+	Name          string          `json:"name"`
+	Brand         string          `json:"brand"`
+	Variant       string          `json:"variant"`
+	PriceInUsd    float64         `json:"price_in_usd"`
+	Quantity      int             `json:"quantity"`
+	RevenueInUsd  float64         `json:"revenue_in_usd"`
+	LocationId    string          `json:"location_id"`
+	ListId        string          `json:"list_id"`
+	ListName      string          `json:"list_name"`
+	PromotionId   string          `json:"promotion_id"`
+	PromotionName string          `json:"promotion_name"`
+	Params        []ParamResponse `json:"params"`
+} // @name ItemResponse
+
+// IngestionResponse represents the server-side ingestion details in the response
+type IngestionResponse struct {
+	ReceivedAt   string `json:"received_at"`
+	Endpoint     string `json:"endpoint"`
+	RequestID    string `json:"request_id"`
+	ClientIPHash string `json:"client_ip_hash"`
+	SDKName      string `json:"sdk_name"`
+	SDKVersion   string `json:"sdk_version"`
+	APIKeyID     string `json:"api_key_id"`
+} // @name IngestionResponse
+
+// SearchEventsRequest represents the HTTP query parameters for searching events.
+// Empty filters match any value.
+type SearchEventsRequest struct {
+	EventNames      []string `form:"event_name"` // repeat to match any of several names
+	From            string   `form:"from"`       // RFC3339 format, inclusive
+	To              string   `form:"to"`         // RFC3339 format, exclusive
+	ChannelType     string   `form:"channel_type" binding:"omitempty,oneof=web mobile desktop tv console other"`
+	UserID          string   `form:"user_id"`
+	UserPseudoID    string   `form:"user_pseudo_id"`
+	DeviceCategory  string   `form:"device_category"`
+	OperatingSystem string   `form:"operating_system"`
+	BrowserName     string   `form:"browser_name"`
+	Language        string   `form:"language"`
+	AppID           string   `form:"app_id"`
+	AppVersion      string   `form:"app_version"`
+	ParamKey        string   `form:"param_key"`
+	ParamValue      string   `form:"param_value"`
+	Order           string   `form:"order" binding:"omitempty,oneof=asc desc"`
+	Cursor          string   `form:"cursor"`
+	Limit           int      `form:"limit" binding:"omitempty,min=1,max=1000"`
+} // @name SearchEventsRequest
+
+// ToQuery converts HTTP request to application query
+func (r *SearchEventsRequest) ToQuery() (*event.SearchEventsQuery, error) {
+	query := &event.SearchEventsQuery{
+		EventNames:      r.EventNames,
+		ChannelType:     r.ChannelType,
+		UserID:          r.UserID,
+		UserPseudoID:    r.UserPseudoID,
+		DeviceCategory:  r.DeviceCategory,
+		OperatingSystem: r.OperatingSystem,
+		BrowserName:     r.BrowserName,
+		Language:        r.Language,
+		AppID:           r.AppID,
+		AppVersion:      r.AppVersion,
+		ParamKey:        r.ParamKey,
+		ParamValue:      r.ParamValue,
+		Order:           r.Order,
+		Cursor:          r.Cursor,
+		Limit:           r.Limit,
+	}
+
+	if r.From != "" {
+		from, err := time.Parse(time.RFC3339, r.From)
+		if err != nil {
+			return nil, err
+		}
+		query.From = from
+	}
+	if r.To != "" {
+		to, err := time.Parse(time.RFC3339, r.To)
+		if err != nil {
+			return nil, err
+		}
+		query.To = to
+	}
+
+	return query, nil
+}
+
+// SearchEventsResponse represents a page of searched events
+type SearchEventsResponse struct {
+	Events []*EventResponse `json:"events"`
+	// NextCursor fetches the next page when passed as cursor; empty on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
+} // @name SearchEventsResponse
+
+// FromEventPageDTO converts application DTO to HTTP response
+func FromEventPageDTO(page *event.EventPageDTO) *SearchEventsResponse {
+	events := make([]*EventResponse, len(page.Events))
+	for i, e := range page.Events {
+		events[i] = FromEventDTO(e)
+	}
+	return &SearchEventsResponse{Events: events, NextCursor: page.NextCursor}
+}
+
+// FromEventDTO converts application DTO to HTTP response
+func FromEventDTO(e *event.EventDTO) *EventResponse {
+	return &EventResponse{
+		ID:                e.ID,
+		Name:              e.Name,
+		ChannelType:       e.ChannelType,
+		Timestamp:         e.Timestamp,
+		PreviousTimestamp: e.PreviousTimestamp,
+		Date:              e.Date,
+		EventParams:       fromParamDTOs(e.EventParams),
+		UserID:            e.UserID,
+		UserPseudoID:      e.UserPseudoID,
+		UserParams:        fromParamDTOs(e.UserParams),
+		Device: DeviceResponse{
+			Category:               e.Device.Category,
+			MobileBrandName:        e.Device.MobileBrandName,
+			MobileModelName:        e.Device.MobileModelName,
+			OperatingSystem:        e.Device.OperatingSystem,
+			OperatingSystemVersion: e.Device.OperatingSystemVersion,
+			Language:               e.Device.Language,
+			BrowserName:            e.Device.BrowserName,
+			BrowserVersion:         e.Device.BrowserVersion,
+			Hostname:               e.Device.Hostname,
+		},
+		Geo: GeoResponse{
+			Continent:    e.Geo.Continent,
+			SubContinent: e.Geo.SubContinent,
+			Country:      e.Geo.Country,
+			Region:       e.Geo.Region,
+			Metro:        e.Geo.Metro,
+			City:         e.Geo.City,
+		},
+		AppInfo: AppInfoResponse{
+			ID:      e.AppInfo.ID,
+			Version: e.AppInfo.Version,
+		},
+		Items: fromItemDTOs(e.Items),
+		Ingestion: IngestionResponse{
+			ReceivedAt:   formatOptionalTime(e.Ingestion.ReceivedAt),
+			Endpoint:     e.Ingestion.Endpoint,
+			RequestID:    e.Ingestion.RequestID,
+			ClientIPHash: e.Ingestion.ClientIPHash,
+			SDKName:      e.Ingestion.SDKName,
+			SDKVersion:   e.Ingestion.SDKVersion,
+			APIKeyID:     e.Ingestion.APIKeyID,
+		},
+	}
+}
+
+func fromParamDTOs(dtos []event.ParamDTO) []ParamResponse {
+	params := make([]ParamResponse, len(dtos))
+	for i, dto := range dtos {
+		params[i] = ParamResponse{
+			Key:          dto.Key,
+			StringValue:  dto.StringValue,
+			NumberValue:  dto.NumberValue,
+			BooleanValue: dto.BooleanValue,
+		}
+	}
+	return params
+}
+
+func fromItemDTOs(dtos []event.ItemDTO) []ItemResponse {
+	items := make([]ItemResponse, len(dtos))
+	for i, dto := range dtos {
+		items[i] = ItemResponse{
+			ID:            dto.ID,
+			Name:          dto.Name,
+			Brand:         dto.Brand,
+			Variant:       dto.Variant,
+			PriceInUsd:    dto.PriceInUsd,
+			Quantity:      dto.Quantity,
+			RevenueInUsd:  dto.RevenueInUsd,
+			LocationId:    dto.LocationId,
+			ListId:        dto.ListId,
+			ListName:      dto.ListName,
+			PromotionId:   dto.PromotionId,
+			PromotionName: dto.PromotionName,
+			Params:        fromParamDTOs(dto.Params),
+		}
+	}
+	return items
+}
@@ -0,0 +1,168 @@
+package dto
+
+import (
+	eventstreamv1 "github.com/ebubekir/event-stream/api/gen/eventstream/v1"
+)
+
+// FromProtoCreateEventRequest converts a protobuf event into the HTTP DTO so
+// binary payloads go through the same validation and command mapping as JSON
+func FromProtoCreateEventRequest(msg *eventstreamv1.CreateEventRequest) CreateEventRequest {
+	device := msg.GetDevice()
+	geo := msg.GetGeo()
+	appInfo := msg.GetAppInfo()
+
+	return CreateEventRequest{
+		Name:              msg.GetName(),
+		ChannelType:       msg.GetChannelType(),
+		Timestamp:         msg.GetTimestamp(),
+		PreviousTimestamp: msg.GetPreviousTimestamp(),
+		Date:              msg.GetDate(),
+		EventParams:       fromProtoParams(msg.GetEventParams()),
+		UserID:            msg.GetUserId(),
+		UserPseudoID:      msg.GetUserPseudoId(),
+		UserParams:        fromProtoParams(msg.GetUserParams()),
+		Device: DeviceRequest{
+			Category:               device.GetCategory(),
+			MobileBrandName:        device.GetMobileBrandName(),
+			MobileModelName:        device.GetMobileModelName(),
+			OperatingSystem:        device.GetOperatingSystem(),
+			OperatingSystemVersion: device.GetOperatingSystemVersion(),
+			Language:               device.GetLanguage(),
+			BrowserName:            device.GetBrowserName(),
+			BrowserVersion:         device.GetBrowserVersion(),
+			Hostname:               device.GetHostname(),
+		},
+		Geo: GeoRequest{
+			Continent:    geo.GetContinent(),
+			SubContinent: geo.GetSubContinent(),
+			Country:      geo.GetCountry(),
+			Region:       geo.GetRegion(),
+			Metro:        geo.GetMetro(),
+			City:         geo.GetCity(),
+		},
+		AppInfo: AppInfoRequest{
+			ID:      appInfo.GetId(),
+			Version: appInfo.GetVersion(),
+		},
+		Items: fromProtoItems(msg.GetItems()),
+	}
+}
+
+// FromProtoCreateEventBatchRequest converts a protobuf batch into the HTTP DTO
+func FromProtoCreateEventBatchRequest(msg *eventstreamv1.CreateEventBatchRequest) CreateEventBatchRequest {
+	events := make([]CreateEventRequest, len(msg.GetEvents()))
+	for i, e := range msg.GetEvents() {
+		events[i] = FromProtoCreateEventRequest(e)
+	}
+	return CreateEventBatchRequest{Events: events}
+}
+
+func fromProtoParams(params []*eventstreamv1.Param) []ParamRequest {
+	requests := make([]ParamRequest, len(params))
+	for i, p := range params {
+		requests[i] = ParamRequest{
+			Key:          p.GetKey(),
+			StringValue:  p.GetStringValue(),
+			NumberValue:  p.GetNumberValue(),
+			BooleanValue: p.GetBooleanValue(),
+		}
+	}
+	return requests
+}
+
+func fromProtoItems(items []*eventstreamv1.Item) []ItemRequest {
+	requests := make([]ItemRequest, len(items))
+	for i, item := range items {
+		requests[i] = ItemRequest{
+			ID:            item.GetId(),
+			Name:          item.GetName(),
+			Brand:         item.GetBrand(),
+			Variant:       item.GetVariant(),
+			PriceInUsd:    item.GetPriceInUsd(),
+			Quantity:      int(item.GetQuantity()),
+			RevenueInUsd:  item.GetRevenueInUsd(),
+			LocationId:    item.GetLocationId(),
+			ListId:        item.GetListId(),
+			ListName:      item.GetListName(),
+			PromotionId:   item.GetPromotionId(),
+			PromotionName: item.GetPromotionName(),
+			Params:        fromProtoParams(item.GetParams()),
+		}
+	}
+	return requests
+}
+
+// ToProtoCreateEventRequest converts the HTTP DTO into a protobuf event, for
+// tools sending the same events over gRPC
+func ToProtoCreateEventRequest(req *CreateEventRequest) *eventstreamv1.CreateEventRequest {
+	return &eventstreamv1.CreateEventRequest{
+		Name:              req.Name,
+		ChannelType:       req.ChannelType,
+		Timestamp:         req.Timestamp,
+		PreviousTimestamp: req.PreviousTimestamp,
+		Date:              req.Date,
+		EventParams:       toProtoParams(req.EventParams),
+		UserId:            req.UserID,
+		UserPseudoId:      req.UserPseudoID,
+		UserParams:        toProtoParams(req.UserParams),
+		Device: &eventstreamv1.Device{
+			Category:               req.Device.Category,
+			MobileBrandName:        req.Device.MobileBrandName,
+			MobileModelName:        req.Device.MobileModelName,
+			OperatingSystem:        req.Device.OperatingSystem,
+			OperatingSystemVersion: req.Device.OperatingSystemVersion,
+			Language:               req.Device.Language,
+			BrowserName:            req.Device.BrowserName,
+			BrowserVersion:         req.Device.BrowserVersion,
+			Hostname:               req.Device.Hostname,
+		},
+		Geo: &eventstreamv1.Geo{
+			Continent:    req.Geo.Continent,
+			SubContinent: req.Geo.SubContinent,
+			Country:      req.Geo.Country,
+			Region:       req.Geo.Region,
+			Metro:        req.Geo.Metro,
+			City:         req.Geo.City,
+		},
+		AppInfo: &eventstreamv1.AppInfo{
+			Id:      req.AppInfo.ID,
+			Version: req.AppInfo.Version,
+		},
+		Items: toProtoItems(req.Items),
+	}
+}
+
+func toProtoParams(params []ParamRequest) []*eventstreamv1.Param {
+	msgs := make([]*eventstreamv1.Param, len(params))
+	for i, p := range params {
+		msgs[i] = &eventstreamv1.Param{
+			Key:          p.Key,
+			StringValue:  p.StringValue,
+			NumberValue:  p.NumberValue,
+			BooleanValue: p.BooleanValue,
+		}
+	}
+	return msgs
+}
+
+func toProtoItems(items []ItemRequest) []*eventstreamv1.Item {
+	msgs := make([]*eventstreamv1.Item, len(items))
+	for i, item := range items {
+		msgs[i] = &eventstreamv1.Item{
+			Id:            item.ID,
+			Name:          item.Name,
+			Brand:         item.Brand,
+			Variant:       item.Variant,
+			PriceInUsd:    item.PriceInUsd,
+			Quantity:      int64(item.Quantity),
+			RevenueInUsd:  item.RevenueInUsd,
+			LocationId:    item.LocationId,
+			ListId:        item.ListId,
+			ListName:      item.ListName,
+			PromotionId:   item.PromotionId,
+			PromotionName: item.PromotionName,
+			Params:        toProtoParams(item.Params),
+		}
+	}
+	return msgs
+}
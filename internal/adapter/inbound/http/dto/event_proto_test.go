@@ -0,0 +1,154 @@
+package dto
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/ugorji/go/codec"
+	"google.golang.org/protobuf/proto"
+
+	eventstreamv1 "github.com/ebubekir/event-stream/api/gen/eventstream/v1"
+)
+
+// benchBatchSize is the number of events in the batch payloads
+const benchBatchSize = 100
+
+// BenchmarkDecodeEvent compares the cost of decoding a single event as JSON,
+// MessagePack and protobuf with the gin bindings the HTTP handlers use:
+//
+//	go test -bench Decode -benchmem ./internal/adapter/inbound/http/dto
+func BenchmarkDecodeEvent(b *testing.B) {
+	event := sampleEvent(0)
+	req := FromProtoCreateEventRequest(event)
+
+	b.Run("json", func(b *testing.B) {
+		benchmarkDecode(b, mustJSON(b, req), func(body []byte) error {
+			var req CreateEventRequest
+			return binding.JSON.BindBody(body, &req)
+		})
+	})
+	b.Run("msgpack", func(b *testing.B) {
+		benchmarkDecode(b, mustMsgPack(b, req), func(body []byte) error {
+			var req CreateEventRequest
+			return binding.MsgPack.BindBody(body, &req)
+		})
+	})
+	b.Run("protobuf", func(b *testing.B) {
+		benchmarkDecode(b, mustProto(b, event), func(body []byte) error {
+			var msg eventstreamv1.CreateEventRequest
+			if err := binding.ProtoBuf.BindBody(body, &msg); err != nil {
+				return err
+			}
+			req := FromProtoCreateEventRequest(&msg)
+			return binding.Validator.ValidateStruct(&req)
+		})
+	})
+}
+
+// BenchmarkDecodeEventBatch compares the formats on a batch of benchBatchSize events
+func BenchmarkDecodeEventBatch(b *testing.B) {
+	batch := &eventstreamv1.CreateEventBatchRequest{}
+	for i := range benchBatchSize {
+		batch.Events = append(batch.Events, sampleEvent(i))
+	}
+	req := FromProtoCreateEventBatchRequest(batch)
+
+	b.Run("json", func(b *testing.B) {
+		benchmarkDecode(b, mustJSON(b, req), func(body []byte) error {
+			var req CreateEventBatchRequest
+			return binding.JSON.BindBody(body, &req)
+		})
+	})
+	b.Run("msgpack", func(b *testing.B) {
+		benchmarkDecode(b, mustMsgPack(b, req), func(body []byte) error {
+			var req CreateEventBatchRequest
+			return binding.MsgPack.BindBody(body, &req)
+		})
+	})
+	b.Run("protobuf", func(b *testing.B) {
+		benchmarkDecode(b, mustProto(b, batch), func(body []byte) error {
+			var msg eventstreamv1.CreateEventBatchRequest
+			if err := binding.ProtoBuf.BindBody(body, &msg); err != nil {
+				return err
+			}
+			req := FromProtoCreateEventBatchRequest(&msg)
+			return binding.Validator.ValidateStruct(&req)
+		})
+	})
+}
+
+func benchmarkDecode(b *testing.B, body []byte, decode func([]byte) error) {
+	if err := decode(body); err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(body)))
+	for b.Loop() {
+		if err := decode(body); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func sampleEvent(i int) *eventstreamv1.CreateEventRequest {
+	return &eventstreamv1.CreateEventRequest{
+		Name:         "purchase",
+		ChannelType:  "web",
+		Timestamp:    1705314600000000,
+		Date:         "2024-01-15T10:30:00Z",
+		UserId:       fmt.Sprintf("user-%d", i),
+		UserPseudoId: fmt.Sprintf("pseudo-%d", i),
+		EventParams: []*eventstreamv1.Param{
+			{Key: "page_location", StringValue: "https://example.com/checkout"},
+			{Key: "engagement_time_msec", NumberValue: 1200},
+			{Key: "session_engaged", BooleanValue: true},
+		},
+		UserParams: []*eventstreamv1.Param{
+			{Key: "plan", StringValue: "pro"},
+		},
+		Device: &eventstreamv1.Device{
+			Category:        "desktop",
+			OperatingSystem: "macOS",
+			Language:        "en-us",
+			BrowserName:     "Chrome",
+			BrowserVersion:  "120.0",
+			Hostname:        "example.com",
+		},
+		AppInfo: &eventstreamv1.AppInfo{Id: "web", Version: "1.4.2"},
+		Items: []*eventstreamv1.Item{
+			{Id: "sku-1", Name: "T-Shirt", Brand: "Acme", PriceInUsd: 19.99, Quantity: 2, RevenueInUsd: 39.98},
+			{Id: "sku-2", Name: "Mug", Brand: "Acme", PriceInUsd: 9.5, Quantity: 1, RevenueInUsd: 9.5},
+		},
+	}
+}
+
+func mustJSON(b *testing.B, v any) []byte {
+	b.Helper()
+	body, err := json.Marshal(v)
+	if err != nil {
+		b.Fatal(err)
+	}
+	return body
+}
+
+func mustMsgPack(b *testing.B, v any) []byte {
+	b.Helper()
+	var buf bytes.Buffer
+	if err := codec.NewEncoder(&buf, new(codec.MsgpackHandle)).Encode(v); err != nil {
+		b.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func mustProto(b *testing.B, m proto.Message) []byte {
+	b.Helper()
+	body, err := proto.Marshal(m)
+	if err != nil {
+		b.Fatal(err)
+	}
+	return body
+}
@@ -0,0 +1,120 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/ebubekir/event-stream/internal/application/export"
+)
+
+// CreateExportRequest represents the HTTP request body for starting an export job
+type CreateExportRequest struct {
+	Format       string   `json:"format" binding:"required,oneof=ndjson csv parquet"`
+	From         string   `json:"from" binding:"required"` // RFC3339 format, inclusive
+	To           string   `json:"to"`                      // RFC3339 format, exclusive; defaults to now
+	EventNames   []string `json:"event_names"`             // empty matches every event
+	ChannelType  string   `json:"channel_type"`
+	UserID       string   `json:"user_id"`
+	UserPseudoID string   `json:"user_pseudo_id"`
+	AppID        string   `json:"app_id"`
+	EventParams  []string `json:"event_params"` // CSV only: event param keys flattened into columns
+	UserParams   []string `json:"user_params"`  // CSV only: user param keys flattened into columns
+} // @name CreateExportRequest
+
+// ToCommand converts HTTP request to application command
+func (r *CreateExportRequest) ToCommand() (*export.ExportCommand, error) {
+	cmd := &export.ExportCommand{
+		Format:       r.Format,
+		EventNames:   r.EventNames,
+		ChannelType:  r.ChannelType,
+		UserID:       r.UserID,
+		UserPseudoID: r.UserPseudoID,
+		AppID:        r.AppID,
+		EventParams:  r.EventParams,
+		UserParams:   r.UserParams,
+	}
+
+	from, err := time.Parse(time.RFC3339, r.From)
+	if err != nil {
+		return nil, err
+	}
+	cmd.From = from
+
+	if r.To != "" {
+		to, err := time.Parse(time.RFC3339, r.To)
+		if err != nil {
+			return nil, err
+		}
+		cmd.To = to
+	} else {
+		cmd.To = time.Now()
+	}
+
+	return cmd, nil
+}
+
+// ExportJobResponse represents an export job in HTTP response
+type ExportJobResponse struct {
+	ID           string   `json:"id"`
+	Format       string   `json:"format"`
+	From         string   `json:"from"`
+	To           string   `json:"to"`
+	EventNames   []string `json:"event_names"`
+	ChannelType  string   `json:"channel_type,omitempty"`
+	UserID       string   `json:"user_id,omitempty"`
+	UserPseudoID string   `json:"user_pseudo_id,omitempty"`
+	AppID        string   `json:"app_id,omitempty"`
+	Status       string   `json:"status"` // pending, running, succeeded, failed
+	EventCount   int64    `json:"event_count"`
+	Error        string   `json:"error,omitempty"`
+	CreatedAt    string   `json:"created_at"`
+	StartedAt    string   `json:"started_at,omitempty"`
+	FinishedAt   string   `json:"finished_at,omitempty"`
+} // @name ExportJobResponse
+
+// ListExportsResponse represents the HTTP response for listing export jobs
+type ListExportsResponse struct {
+	Exports []ExportJobResponse `json:"exports"`
+} // @name ListExportsResponse
+
+// FromExportJobDTO converts application DTO to HTTP response
+func FromExportJobDTO(job *export.ExportJobDTO) ExportJobResponse {
+	eventNames := job.EventNames
+	if eventNames == nil {
+		eventNames = []string{}
+	}
+
+	return ExportJobResponse{
+		ID:           job.ID,
+		Format:       job.Format,
+		From:         job.From.Format(time.RFC3339),
+		To:           job.To.Format(time.RFC3339),
+		EventNames:   eventNames,
+		ChannelType:  job.ChannelType,
+		UserID:       job.UserID,
+		UserPseudoID: job.UserPseudoID,
+		AppID:        job.AppID,
+		Status:       job.Status,
+		EventCount:   job.EventCount,
+		Error:        job.Error,
+		CreatedAt:    job.CreatedAt.Format(time.RFC3339),
+		StartedAt:    formatOptionalTime(job.StartedAt),
+		FinishedAt:   formatOptionalTime(job.FinishedAt),
+	}
+}
+
+// FromExportJobDTOs converts application DTOs to HTTP response
+func FromExportJobDTOs(dtos []*export.ExportJobDTO) *ListExportsResponse {
+	exports := make([]ExportJobResponse, len(dtos))
+	for i, job := range dtos {
+		exports[i] = FromExportJobDTO(job)
+	}
+	return &ListExportsResponse{Exports: exports}
+}
+
+// formatOptionalTime formats t as RFC3339, or returns "" for the zero time
+func formatOptionalTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,19 @@
+package dto
+
+import (
+	"github.com/ebubekir/event-stream/internal/application/event"
+)
+
+// IdentifyRequest represents the HTTP request body for linking an anonymous user to a known user
+type IdentifyRequest struct {
+	UserPseudoID string `json:"user_pseudo_id" binding:"required"`
+	UserID       string `json:"user_id" binding:"required"`
+} // @name IdentifyRequest
+
+// ToCommand converts HTTP DTO to application command
+func (r *IdentifyRequest) ToCommand() *event.IdentifyCommand {
+	return &event.IdentifyCommand{
+		UserPseudoID: r.UserPseudoID,
+		UserID:       r.UserID,
+	}
+}
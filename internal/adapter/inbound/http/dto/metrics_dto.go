@@ -4,61 +4,70 @@ import (
 	"time"
 
 	"github.com/ebubekir/event-stream/internal/application/event"
+	eventDomain "github.com/ebubekir/event-stream/internal/domain/event"
 )
 
 // GetMetricsRequest represents the HTTP query parameters for metrics
 type GetMetricsRequest struct {
-	EventName   string `form:"event_name" binding:"required"`
-	From        string `form:"from"`                                                    // RFC3339 format
-	To          string `form:"to"`                                                      // RFC3339 format
-	Aggregation string `form:"group_by" binding:"omitempty,oneof=channel daily hourly"` // channel, daily, hourly
+	EventNames      []string `form:"event_name"`                                     // repeat for several events; none counts every event
+	From            string   `form:"from"`                                           // RFC3339, or a local date or date-time in timezone
+	To              string   `form:"to"`                                             // RFC3339, or a local date (inclusive) or date-time in timezone
+	Timezone        string   `form:"timezone"`                                       // IANA name, e.g. Europe/Istanbul; defaults to UTC
+	GroupBy         string   `form:"group_by"`                                       // comma-separated dimensions, e.g. daily,device.browser_name
+	GroupLimit      int      `form:"group_limit" binding:"omitempty,min=1,max=1000"` // keep the most frequent groups, counting the rest as Other
+	Fill            string   `form:"fill" binding:"omitempty,oneof=zero null none"`  // time buckets without events; defaults to zero with one time dimension
+	Measures        string   `form:"measures"`                                       // comma-separated <function>:<field>, e.g. avg:event_param.load_time_ms
+	ResolveIdentity bool     `form:"resolve_identity"`                               // count unique users by canonical identity
+	IngestEndpoint  string   `form:"ingest_endpoint"`
+	SDKName         string   `form:"sdk_name"`
+	SDKVersion      string   `form:"sdk_version"`
+	APIKeyID        string   `form:"api_key_id"`
+	Filters         []string `form:"filter"` // <field>:<operator>[:<value>], repeat to combine
 } // @name GetMetricsRequest
 
 // ToQuery converts HTTP request to application query
 func (r *GetMetricsRequest) ToQuery() (*event.GetMetricsQuery, error) {
-	query := &event.GetMetricsQuery{
-		EventName:   r.EventName,
-		Aggregation: r.Aggregation,
+	params := event.MetricsParams{
+		EventNames:      r.EventNames,
+		From:            r.From,
+		To:              r.To,
+		Timezone:        r.Timezone,
+		GroupBy:         r.GroupBy,
+		GroupLimit:      r.GroupLimit,
+		Fill:            r.Fill,
+		Measures:        r.Measures,
+		ResolveIdentity: r.ResolveIdentity,
+		IngestEndpoint:  r.IngestEndpoint,
+		SDKName:         r.SDKName,
+		SDKVersion:      r.SDKVersion,
+		APIKeyID:        r.APIKeyID,
+		Filters:         r.Filters,
 	}
-
-	// Parse 'from' timestamp
-	if r.From != "" {
-		from, err := time.Parse(time.RFC3339, r.From)
-		if err != nil {
-			return nil, err
-		}
-		query.From = from
-	}
-
-	// Parse 'to' timestamp
-	if r.To != "" {
-		to, err := time.Parse(time.RFC3339, r.To)
-		if err != nil {
-			return nil, err
-		}
-		query.To = to
-	} else {
-		// Default to now if not provided
-		query.To = time.Now()
-	}
-
-	return query, nil
+	return params.ToQuery()
 }
 
 // GroupedMetricResponse represents a grouped metric in the response
 type GroupedMetricResponse struct {
-	GroupKey        string `json:"group_key"`
-	TotalCount      int64  `json:"total_count"`
-	UniqueUserCount int64  `json:"unique_user_count"`
+	GroupKey        string              `json:"group_key"`          // group_keys values joined by "|"
+	GroupKeys       map[string]string   `json:"group_keys"`         // value per group_by dimension
+	TotalCount      *int64              `json:"total_count"`        // null for filled buckets with fill=null
+	UniqueUserCount *int64              `json:"unique_user_count"`  // null for filled buckets with fill=null
+	Measures        map[string]*float64 `json:"measures,omitempty"` // value per measure, null without values
+	Filled          bool                `json:"filled,omitempty"`   // a time bucket without events
 } // @name GroupedMetricResponse
 
 // GetMetricsResponse represents the HTTP response for metrics
 type GetMetricsResponse struct {
-	EventName       string                  `json:"event_name"`
+	EventNames      []string                `json:"event_names"` // empty when every event was counted
+	EventName       string                  `json:"event_name"`  // the queried event when exactly one was given, else empty
+	GroupBy         []string                `json:"group_by,omitempty"`
+	Fill            string                  `json:"fill,omitempty"`
+	Timezone        string                  `json:"timezone,omitempty"`
 	From            string                  `json:"from"`
 	To              string                  `json:"to"`
 	TotalCount      int64                   `json:"total_count"`
 	UniqueUserCount int64                   `json:"unique_user_count"`
+	Measures        map[string]*float64     `json:"measures,omitempty"` // value per measure, null without values
 	GroupedMetrics  []GroupedMetricResponse `json:"grouped_metrics,omitempty"`
 } // @name GetMetricsResponse
 
@@ -66,19 +75,58 @@ type GetMetricsResponse struct {
 func FromMetricsResultDTO(dto *event.MetricsResultDTO) *GetMetricsResponse {
 	groupedMetrics := make([]GroupedMetricResponse, len(dto.GroupedMetrics))
 	for i, gm := range dto.GroupedMetrics {
+		groupKeys := make(map[string]string, len(gm.GroupKeys))
+		for j, key := range gm.GroupKeys {
+			groupKeys[dto.GroupBy[j]] = key
+		}
 		groupedMetrics[i] = GroupedMetricResponse{
-			GroupKey:        gm.GroupKey,
-			TotalCount:      gm.TotalCount,
-			UniqueUserCount: gm.UniqueUserCount,
+			GroupKey:  gm.GroupKey,
+			GroupKeys: groupKeys,
+			Measures:  measureMap(dto.Measures, gm.MeasureValues),
+			Filled:    gm.Filled,
+		}
+		if !gm.Filled || dto.Fill != string(eventDomain.FillNull) {
+			groupedMetrics[i].TotalCount = &gm.TotalCount
+			groupedMetrics[i].UniqueUserCount = &gm.UniqueUserCount
 		}
 	}
 
+	eventNames := dto.EventNames
+	if eventNames == nil {
+		eventNames = []string{}
+	}
+	var eventName string
+	if len(eventNames) == 1 {
+		eventName = eventNames[0]
+	}
+
 	return &GetMetricsResponse{
-		EventName:       dto.EventName,
+		EventNames:      eventNames,
+		EventName:       eventName,
+		GroupBy:         dto.GroupBy,
+		Fill:            dto.Fill,
+		Timezone:        dto.Timezone,
 		From:            dto.From.Format(time.RFC3339),
 		To:              dto.To.Format(time.RFC3339),
 		TotalCount:      dto.TotalCount,
 		UniqueUserCount: dto.UniqueUserCount,
+		Measures:        measureMap(dto.Measures, dto.MeasureValues),
 		GroupedMetrics:  groupedMetrics,
 	}
 }
+
+// measureMap keys measure values by their measure, with null for missing values
+func measureMap(measures []string, values []*float64) map[string]*float64 {
+	if len(measures) == 0 {
+		return nil
+	}
+	m := make(map[string]*float64, len(measures))
+	for i, measure := range measures {
+		var value *float64
+		if i < len(values) {
+			value = values[i]
+		}
+		m[measure] = value
+	}
+	return m
+}
@@ -0,0 +1,47 @@
+package dto
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ebubekir/event-stream/internal/application/event"
+)
+
+func TestFromMetricsResultDTOEventName(t *testing.T) {
+	tests := []struct {
+		name       string
+		eventNames []string
+		want       string
+	}{
+		{name: "single event", eventNames: []string{"purchase"}, want: "purchase"},
+		{name: "several events", eventNames: []string{"add_to_cart", "purchase"}, want: ""},
+		{name: "every event", eventNames: nil, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := FromMetricsResultDTO(&event.MetricsResultDTO{EventNames: tt.eventNames, From: time.Unix(0, 0), To: time.Unix(0, 0)})
+
+			body, err := json.Marshal(resp)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var fields map[string]any
+			if err := json.Unmarshal(body, &fields); err != nil {
+				t.Fatal(err)
+			}
+
+			eventName, ok := fields["event_name"]
+			if !ok {
+				t.Fatalf("response %s has no event_name", body)
+			}
+			if eventName != tt.want {
+				t.Errorf("event_name = %v, want %q", eventName, tt.want)
+			}
+			if _, ok := fields["event_names"].([]any); !ok {
+				t.Errorf("event_names = %v, want a list", fields["event_names"])
+			}
+		})
+	}
+}
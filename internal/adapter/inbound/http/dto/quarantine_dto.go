@@ -0,0 +1,89 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/ebubekir/event-stream/internal/application/event"
+)
+
+// defaultQuarantinePageSize is used when no limit is given
+const defaultQuarantinePageSize = 100
+
+// ListQuarantineRequest represents the HTTP query parameters for listing quarantined events
+type ListQuarantineRequest struct {
+	Limit  int `form:"limit" binding:"omitempty,min=1,max=1000"`
+	Offset int `form:"offset" binding:"omitempty,min=0"`
+} // @name ListQuarantineRequest
+
+// ToQuery converts HTTP request to application query
+func (r *ListQuarantineRequest) ToQuery() *event.ListQuarantineQuery {
+	limit := r.Limit
+	if limit == 0 {
+		limit = defaultQuarantinePageSize
+	}
+
+	return &event.ListQuarantineQuery{
+		Limit:  limit,
+		Offset: r.Offset,
+	}
+}
+
+// QuarantinedEventResponse represents a quarantined event in the response
+type QuarantinedEventResponse struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	ChannelType   string `json:"channel_type"`
+	Date          string `json:"date"`
+	UserID        string `json:"user_id"`
+	UserPseudoID  string `json:"user_pseudo_id"`
+	Reason        string `json:"reason"`
+	QuarantinedAt string `json:"quarantined_at"`
+} // @name QuarantinedEventResponse
+
+// ListQuarantineResponse represents the HTTP response for listing quarantined events
+type ListQuarantineResponse struct {
+	Events []QuarantinedEventResponse `json:"events"`
+} // @name ListQuarantineResponse
+
+// FromQuarantinedEventDTOs converts application DTOs to HTTP response
+func FromQuarantinedEventDTOs(dtos []event.QuarantinedEventDTO) *ListQuarantineResponse {
+	events := make([]QuarantinedEventResponse, len(dtos))
+	for i, q := range dtos {
+		events[i] = QuarantinedEventResponse{
+			ID:            q.ID,
+			Name:          q.Name,
+			ChannelType:   q.ChannelType,
+			Date:          q.Date,
+			UserID:        q.UserID,
+			UserPseudoID:  q.UserPseudoID,
+			Reason:        q.Reason,
+			QuarantinedAt: q.QuarantinedAt.Format(time.RFC3339),
+		}
+	}
+
+	return &ListQuarantineResponse{Events: events}
+}
+
+// ReleaseQuarantineRequest represents the HTTP request body for releasing quarantined events
+type ReleaseQuarantineRequest struct {
+	IDs   []string `json:"ids" binding:"required,min=1"`
+	Clamp bool     `json:"clamp"` // move dates into the current acceptance window
+} // @name ReleaseQuarantineRequest
+
+// ToCommand converts HTTP DTO to application command
+func (r *ReleaseQuarantineRequest) ToCommand() *event.ReleaseQuarantineCommand {
+	return &event.ReleaseQuarantineCommand{
+		IDs:   r.IDs,
+		Clamp: r.Clamp,
+	}
+}
+
+// ReleaseQuarantineResponse represents the HTTP response after releasing quarantined events
+type ReleaseQuarantineResponse struct {
+	Released int `json:"released"`
+} // @name ReleaseQuarantineResponse
+
+// DiscardQuarantineRequest represents the HTTP request body for discarding quarantined events
+type DiscardQuarantineRequest struct {
+	IDs []string `json:"ids" binding:"required,min=1"`
+} // @name DiscardQuarantineRequest
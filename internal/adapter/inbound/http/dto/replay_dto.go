@@ -0,0 +1,136 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/ebubekir/event-stream/internal/application/replay"
+)
+
+// CreateReplayRequest represents the HTTP request body for starting a replay job
+type CreateReplayRequest struct {
+	Source       string   `json:"source" binding:"required"` // archive, quarantine, or the other database type: postgres, clickhouse
+	From         string   `json:"from" binding:"required"`   // RFC3339 format, inclusive
+	To           string   `json:"to" binding:"required"`     // RFC3339 format, exclusive
+	EventNames   []string `json:"event_names"`               // empty matches every event
+	ChannelType  string   `json:"channel_type"`
+	UserID       string   `json:"user_id"`
+	UserPseudoID string   `json:"user_pseudo_id"`
+	AppID        string   `json:"app_id"`
+	Reprocess    bool     `json:"reprocess"`                      // run events through the current ingestion pipeline
+	DeleteFirst  bool     `json:"delete_first"`                   // delete the matching events in each chunk before replaying it
+	Rate         float64  `json:"rate" binding:"omitempty,gte=0"` // events per second; 0 is unlimited
+	ChunkSize    string   `json:"chunk_size"`                     // Go duration replayed between checkpoints, e.g. "1h" (default)
+} // @name CreateReplayRequest
+
+// ToCommand converts HTTP request to application command
+func (r *CreateReplayRequest) ToCommand() (*replay.ReplayCommand, error) {
+	cmd := &replay.ReplayCommand{
+		Source:       r.Source,
+		EventNames:   r.EventNames,
+		ChannelType:  r.ChannelType,
+		UserID:       r.UserID,
+		UserPseudoID: r.UserPseudoID,
+		AppID:        r.AppID,
+		Reprocess:    r.Reprocess,
+		DeleteFirst:  r.DeleteFirst,
+		Rate:         r.Rate,
+	}
+
+	from, err := time.Parse(time.RFC3339, r.From)
+	if err != nil {
+		return nil, err
+	}
+	cmd.From = from
+
+	to, err := time.Parse(time.RFC3339, r.To)
+	if err != nil {
+		return nil, err
+	}
+	cmd.To = to
+
+	if r.ChunkSize != "" {
+		chunkSize, err := time.ParseDuration(r.ChunkSize)
+		if err != nil {
+			return nil, err
+		}
+		cmd.ChunkSize = chunkSize
+	}
+
+	return cmd, nil
+}
+
+// UpdateReplayRequest represents the HTTP request body for throttling a running replay
+type UpdateReplayRequest struct {
+	Rate *float64 `json:"rate" binding:"required,gte=0"` // events per second; 0 is unlimited
+} // @name UpdateReplayRequest
+
+// ReplayJobResponse represents a replay job in HTTP response
+type ReplayJobResponse struct {
+	ID           string   `json:"id"`
+	Source       string   `json:"source"`
+	From         string   `json:"from"`
+	To           string   `json:"to"`
+	EventNames   []string `json:"event_names"`
+	ChannelType  string   `json:"channel_type,omitempty"`
+	UserID       string   `json:"user_id,omitempty"`
+	UserPseudoID string   `json:"user_pseudo_id,omitempty"`
+	AppID        string   `json:"app_id,omitempty"`
+	Reprocess    bool     `json:"reprocess"`
+	DeleteFirst  bool     `json:"delete_first"`
+	Rate         float64  `json:"rate"`
+	ChunkSize    string   `json:"chunk_size"`
+	Checkpoint   string   `json:"checkpoint"` // events before this time have been replayed
+	Runs         int      `json:"runs"`
+	Status       string   `json:"status"` // pending, running, succeeded, failed, cancelled
+	EventCount   int64    `json:"event_count"`
+	Error        string   `json:"error,omitempty"`
+	CreatedAt    string   `json:"created_at"`
+	StartedAt    string   `json:"started_at,omitempty"`
+	FinishedAt   string   `json:"finished_at,omitempty"`
+} // @name ReplayJobResponse
+
+// ListReplaysResponse represents the HTTP response for listing replay jobs
+type ListReplaysResponse struct {
+	Replays []ReplayJobResponse `json:"replays"`
+} // @name ListReplaysResponse
+
+// FromReplayJobDTO converts application DTO to HTTP response
+func FromReplayJobDTO(job *replay.ReplayJobDTO) ReplayJobResponse {
+	eventNames := job.EventNames
+	if eventNames == nil {
+		eventNames = []string{}
+	}
+
+	return ReplayJobResponse{
+		ID:           job.ID,
+		Source:       job.Source,
+		From:         job.From.Format(time.RFC3339),
+		To:           job.To.Format(time.RFC3339),
+		EventNames:   eventNames,
+		ChannelType:  job.ChannelType,
+		UserID:       job.UserID,
+		UserPseudoID: job.UserPseudoID,
+		AppID:        job.AppID,
+		Reprocess:    job.Reprocess,
+		DeleteFirst:  job.DeleteFirst,
+		Rate:         job.Rate,
+		ChunkSize:    job.ChunkSize.String(),
+		Checkpoint:   job.Checkpoint.Format(time.RFC3339),
+		Runs:         job.Runs,
+		Status:       job.Status,
+		EventCount:   job.EventCount,
+		Error:        job.Error,
+		CreatedAt:    job.CreatedAt.Format(time.RFC3339),
+		StartedAt:    formatOptionalTime(job.StartedAt),
+		FinishedAt:   formatOptionalTime(job.FinishedAt),
+	}
+}
+
+// FromReplayJobDTOs converts application DTOs to HTTP response
+func FromReplayJobDTOs(dtos []*replay.ReplayJobDTO) *ListReplaysResponse {
+	replays := make([]ReplayJobResponse, len(dtos))
+	for i, job := range dtos {
+		replays[i] = FromReplayJobDTO(job)
+	}
+	return &ListReplaysResponse{Replays: replays}
+}
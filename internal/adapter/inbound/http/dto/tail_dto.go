@@ -0,0 +1,50 @@
+package dto
+
+import (
+	"github.com/ebubekir/event-stream/internal/adapter/eventcodec"
+	"github.com/ebubekir/event-stream/internal/application/event"
+	"github.com/ebubekir/event-stream/internal/domain"
+)
+
+// Live tail message types
+const (
+	TailMessageEvent   = "event"   // a newly persisted event
+	TailMessageDropped = "dropped" // events were dropped because the client fell behind
+)
+
+// TailEventsRequest represents the HTTP query parameters for a live event tail.
+// Empty filters match any value.
+type TailEventsRequest struct {
+	EventName    string `form:"event_name"`
+	UserPseudoID string `form:"user_pseudo_id"`
+	ChannelType  string `form:"channel_type" binding:"omitempty,oneof=web mobile desktop tv console other"`
+	AppID        string `form:"app_id"`
+} // @name TailEventsRequest
+
+// ToQuery converts HTTP request to application query
+func (r *TailEventsRequest) ToQuery() *event.TailEventsQuery {
+	return &event.TailEventsQuery{
+		EventName:    r.EventName,
+		UserPseudoID: r.UserPseudoID,
+		ChannelType:  r.ChannelType,
+		AppID:        r.AppID,
+	}
+}
+
+// TailMessage is a single live tail message. Over SSE the message type is also the SSE event name.
+type TailMessage struct {
+	Type    string             `json:"type"`              // event, dropped
+	Event   *eventcodec.Record `json:"event,omitempty"`   // set for event messages
+	Dropped uint64             `json:"dropped,omitempty"` // total dropped since the tail started, set for dropped messages
+} // @name TailMessage
+
+// NewTailEventMessage wraps a persisted event in a tail message
+func NewTailEventMessage(e *domain.Event) TailMessage {
+	record := eventcodec.FromEvent(e)
+	return TailMessage{Type: TailMessageEvent, Event: &record}
+}
+
+// NewTailDroppedMessage reports the total number of dropped events
+func NewTailDroppedMessage(dropped uint64) TailMessage {
+	return TailMessage{Type: TailMessageDropped, Dropped: dropped}
+}
@@ -0,0 +1,56 @@
+package dto
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ebubekir/event-stream/internal/application/event"
+)
+
+// UserTimelineRequest represents the HTTP query parameters for a user's event timeline
+type UserTimelineRequest struct {
+	IDType     string   `form:"id_type" binding:"omitempty,oneof=user_id user_pseudo_id"` // defaults to user_id
+	EventNames []string `form:"event_name"`                                               // repeat to match any of several names
+	From       string   `form:"from"`                                                     // RFC3339 format, inclusive
+	To         string   `form:"to"`                                                       // RFC3339 format, exclusive
+	Order      string   `form:"order" binding:"omitempty,oneof=asc desc"`
+	Cursor     string   `form:"cursor"`
+	Limit      int      `form:"limit" binding:"omitempty,min=1,max=1000"`
+} // @name UserTimelineRequest
+
+// ToQuery converts HTTP request to application query for the user with the given ID
+func (r *UserTimelineRequest) ToQuery(userID string) (*event.UserTimelineQuery, error) {
+	// An empty ID would turn the timeline into a search over every user
+	if userID == "" {
+		return nil, errors.New("user ID is required")
+	}
+
+	query := &event.UserTimelineQuery{
+		UserID:     userID,
+		IDType:     r.IDType,
+		EventNames: r.EventNames,
+		Order:      r.Order,
+		Cursor:     r.Cursor,
+		Limit:      r.Limit,
+	}
+	if query.IDType == "" {
+		query.IDType = event.UserIDTypeUserID
+	}
+
+	if r.From != "" {
+		from, err := time.Parse(time.RFC3339, r.From)
+		if err != nil {
+			return nil, err
+		}
+		query.From = from
+	}
+	if r.To != "" {
+		to, err := time.Parse(time.RFC3339, r.To)
+		if err != nil {
+			return nil, err
+		}
+		query.To = to
+	}
+
+	return query, nil
+}
@@ -0,0 +1,174 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/ebubekir/event-stream/internal/application/webhook"
+)
+
+// defaultWebhookDeliveryPageSize is used when no limit is given
+const defaultWebhookDeliveryPageSize = 100
+
+// WebhookParamFilterRequest represents a param filter in HTTP request
+type WebhookParamFilterRequest struct {
+	Key   string `json:"key" binding:"required"`
+	Value string `json:"value"` // compared with the param's string, number or boolean value as text
+} // @name WebhookParamFilterRequest
+
+// CreateWebhookRequest represents the HTTP request body for creating a webhook subscription
+type CreateWebhookRequest struct {
+	URL          string                      `json:"url" binding:"required,http_url"`
+	Secret       string                      `json:"secret" binding:"omitempty,min=16"` // generated when empty
+	EventNames   []string                    `json:"event_names"`                       // empty matches every event
+	ParamFilters []WebhookParamFilterRequest `json:"param_filters" binding:"dive"`
+} // @name CreateWebhookRequest
+
+// UpdateWebhookRequest represents the HTTP request body for replacing a webhook subscription
+type UpdateWebhookRequest struct {
+	URL          string                      `json:"url" binding:"required,http_url"`
+	Secret       string                      `json:"secret" binding:"omitempty,min=16"` // rotates the secret when set
+	EventNames   []string                    `json:"event_names"`
+	ParamFilters []WebhookParamFilterRequest `json:"param_filters" binding:"dive"`
+	Enabled      bool                        `json:"enabled"` // re-enabling resets the failure count
+} // @name UpdateWebhookRequest
+
+// ListWebhookDeliveriesRequest represents the HTTP query parameters for listing delivery attempts
+type ListWebhookDeliveriesRequest struct {
+	Limit  int `form:"limit" binding:"omitempty,min=1,max=1000"`
+	Offset int `form:"offset" binding:"omitempty,min=0"`
+} // @name ListWebhookDeliveriesRequest
+
+// WebhookResponse represents a webhook subscription in the response
+type WebhookResponse struct {
+	ID                  string                      `json:"id"`
+	URL                 string                      `json:"url"`
+	Secret              string                      `json:"secret,omitempty"` // only returned when created or rotated
+	EventNames          []string                    `json:"event_names"`
+	ParamFilters        []WebhookParamFilterRequest `json:"param_filters"`
+	Enabled             bool                        `json:"enabled"`
+	ConsecutiveFailures int                         `json:"consecutive_failures"`
+	DisabledReason      string                      `json:"disabled_reason,omitempty"`
+	CreatedAt           string                      `json:"created_at"`
+	UpdatedAt           string                      `json:"updated_at"`
+} // @name WebhookResponse
+
+// ListWebhooksResponse represents the HTTP response for listing webhook subscriptions
+type ListWebhooksResponse struct {
+	Webhooks []WebhookResponse `json:"webhooks"`
+} // @name ListWebhooksResponse
+
+// WebhookDeliveryResponse represents a delivery attempt in the response
+type WebhookDeliveryResponse struct {
+	ID          string `json:"id"`
+	EventID     string `json:"event_id"`
+	EventName   string `json:"event_name"`
+	Attempt     int    `json:"attempt"`
+	StatusCode  int    `json:"status_code"`
+	Error       string `json:"error,omitempty"`
+	Succeeded   bool   `json:"succeeded"`
+	DurationMs  int64  `json:"duration_ms"`
+	AttemptedAt string `json:"attempted_at"`
+} // @name WebhookDeliveryResponse
+
+// ListWebhookDeliveriesResponse represents the HTTP response for listing delivery attempts
+type ListWebhookDeliveriesResponse struct {
+	Deliveries []WebhookDeliveryResponse `json:"deliveries"`
+} // @name ListWebhookDeliveriesResponse
+
+// ToCommand converts HTTP DTO to application command
+func (r *CreateWebhookRequest) ToCommand() *webhook.CreateSubscriptionCommand {
+	return &webhook.CreateSubscriptionCommand{
+		URL:          r.URL,
+		Secret:       r.Secret,
+		EventNames:   r.EventNames,
+		ParamFilters: toParamFilterDTOs(r.ParamFilters),
+	}
+}
+
+// ToCommand converts HTTP DTO to application command
+func (r *UpdateWebhookRequest) ToCommand(id string) *webhook.UpdateSubscriptionCommand {
+	return &webhook.UpdateSubscriptionCommand{
+		ID:           id,
+		URL:          r.URL,
+		Secret:       r.Secret,
+		EventNames:   r.EventNames,
+		ParamFilters: toParamFilterDTOs(r.ParamFilters),
+		Enabled:      r.Enabled,
+	}
+}
+
+// ToQuery converts HTTP request to application query
+func (r *ListWebhookDeliveriesRequest) ToQuery(subscriptionID string) *webhook.ListDeliveriesQuery {
+	limit := r.Limit
+	if limit == 0 {
+		limit = defaultWebhookDeliveryPageSize
+	}
+
+	return &webhook.ListDeliveriesQuery{
+		SubscriptionID: subscriptionID,
+		Limit:          limit,
+		Offset:         r.Offset,
+	}
+}
+
+// FromSubscriptionDTO converts application DTO to HTTP response
+func FromSubscriptionDTO(s *webhook.SubscriptionDTO) WebhookResponse {
+	filters := make([]WebhookParamFilterRequest, len(s.ParamFilters))
+	for i, f := range s.ParamFilters {
+		filters[i] = WebhookParamFilterRequest{Key: f.Key, Value: f.Value}
+	}
+
+	eventNames := s.EventNames
+	if eventNames == nil {
+		eventNames = []string{}
+	}
+
+	return WebhookResponse{
+		ID:                  s.ID,
+		URL:                 s.URL,
+		Secret:              s.Secret,
+		EventNames:          eventNames,
+		ParamFilters:        filters,
+		Enabled:             s.Enabled,
+		ConsecutiveFailures: s.ConsecutiveFailures,
+		DisabledReason:      s.DisabledReason,
+		CreatedAt:           s.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:           s.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// FromSubscriptionDTOs converts application DTOs to HTTP response
+func FromSubscriptionDTOs(dtos []*webhook.SubscriptionDTO) *ListWebhooksResponse {
+	webhooks := make([]WebhookResponse, len(dtos))
+	for i, s := range dtos {
+		webhooks[i] = FromSubscriptionDTO(s)
+	}
+	return &ListWebhooksResponse{Webhooks: webhooks}
+}
+
+// FromDeliveryDTOs converts application DTOs to HTTP response
+func FromDeliveryDTOs(dtos []webhook.DeliveryDTO) *ListWebhookDeliveriesResponse {
+	deliveries := make([]WebhookDeliveryResponse, len(dtos))
+	for i, d := range dtos {
+		deliveries[i] = WebhookDeliveryResponse{
+			ID:          d.ID,
+			EventID:     d.EventID,
+			EventName:   d.EventName,
+			Attempt:     d.Attempt,
+			StatusCode:  d.StatusCode,
+			Error:       d.Error,
+			Succeeded:   d.Succeeded,
+			DurationMs:  d.Duration.Milliseconds(),
+			AttemptedAt: d.AttemptedAt.Format(time.RFC3339),
+		}
+	}
+	return &ListWebhookDeliveriesResponse{Deliveries: deliveries}
+}
+
+func toParamFilterDTOs(requests []WebhookParamFilterRequest) []webhook.ParamFilterDTO {
+	filters := make([]webhook.ParamFilterDTO, len(requests))
+	for i, req := range requests {
+		filters[i] = webhook.ParamFilterDTO{Key: req.Key, Value: req.Value}
+	}
+	return filters
+}
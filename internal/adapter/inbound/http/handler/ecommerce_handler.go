@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/ebubekir/event-stream/internal/adapter/inbound/http/dto"
+	"github.com/ebubekir/event-stream/internal/application/event"
+	eventDomain "github.com/ebubekir/event-stream/internal/domain/event"
+	"github.com/ebubekir/event-stream/pkg/response"
+)
+
+// EcommerceHandler handles HTTP requests for ecommerce metrics
+type EcommerceHandler struct {
+	service *event.EventService
+}
+
+// NewEcommerceHandler creates a new EcommerceHandler
+func NewEcommerceHandler(service *event.EventService) *EcommerceHandler {
+	return &EcommerceHandler{
+		service: service,
+	}
+}
+
+// GetEcommerceMetrics
+// @ID GetEcommerceMetrics
+// @Summary Get ecommerce metrics
+// @Description Retrieves revenue, orders, purchasing users, average order value and units sold of purchase events.
+// @Description Each purchase event is one order; its revenue and units are the sums of its items' revenue_in_usd and quantity.
+// @Tags ecommerce
+// @Param event_name query []string false "Purchase event names (default purchase)" collectionFormat(multi)
+// @Param from query string false "Start timestamp: RFC3339, or a date or date-time without offset in timezone"
+// @Param to query string false "End timestamp: RFC3339, or a date (inclusive) or date-time without offset in timezone"
+// @Param timezone query string false "IANA time zone of time buckets and local from/to, e.g. Europe/Istanbul (default UTC)"
+// @Param interval query string false "Break the metrics down by time bucket" Enums(minute, 5min, hourly, daily, weekly, weekly_sunday, monthly, quarterly)
+// @Param resolve_identity query bool false "Count purchasing users by canonical identity (user_pseudo_id resolved to user_id)"
+// @Param filter query []string false "Field filter <field>:<operator>[:<value>], e.g. device.category:eq:mobile; repeat to combine" collectionFormat(multi)
+// @Success 200 {object} dto.GetEcommerceMetricsResponse
+// @Failure default {object} response.ApiError
+// @Router /ecommerce/metrics [get]
+func (h *EcommerceHandler) GetEcommerceMetrics(c *gin.Context) {
+	var req dto.GetEcommerceMetricsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	query, err := req.ToQuery()
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	result, err := h.service.GetEcommerceMetrics(c.Request.Context(), query)
+	if errors.Is(err, eventDomain.ErrInvalidFilter) || errors.Is(err, eventDomain.ErrInvalidGroupBy) {
+		response.BadRequest(c, err)
+		return
+	}
+	if err != nil {
+		response.SystemError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.FromEcommerceResultDTO(result))
+}
+
+// GetTopItems
+// @ID GetTopItems
+// @Summary Get top items
+// @Description Ranks the items, brands or variants of purchase events by revenue or quantity sold.
+// @Description With an interval, each ranked item also has its sales per time bucket.
+// @Tags ecommerce
+// @Param event_name query []string false "Purchase event names (default purchase)" collectionFormat(multi)
+// @Param from query string false "Start timestamp: RFC3339, or a date or date-time without offset in timezone"
+// @Param to query string false "End timestamp: RFC3339, or a date (inclusive) or date-time without offset in timezone"
+// @Param timezone query string false "IANA time zone of time buckets and local from/to, e.g. Europe/Istanbul (default UTC)"
+// @Param interval query string false "Break each item's sales down by time bucket" Enums(minute, 5min, hourly, daily, weekly, weekly_sunday, monthly, quarterly)
+// @Param filter query []string false "Field filter <field>:<operator>[:<value>], e.g. device.category:eq:mobile; repeat to combine" collectionFormat(multi)
+// @Param by query string false "Item field to rank: item ID (name for items without one), brand or variant" Enums(item, brand, variant) default(item)
+// @Param sort_by query string false "Value to rank by" Enums(revenue, quantity) default(revenue)
+// @Param limit query int false "Number of items" minimum(1) maximum(100) default(10)
+// @Success 200 {object} dto.GetTopItemsResponse
+// @Failure default {object} response.ApiError
+// @Router /ecommerce/items [get]
+func (h *EcommerceHandler) GetTopItems(c *gin.Context) {
+	var req dto.GetTopItemsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	query, err := req.ToQuery()
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	result, err := h.service.GetTopItems(c.Request.Context(), query)
+	if errors.Is(err, eventDomain.ErrInvalidFilter) || errors.Is(err, eventDomain.ErrInvalidGroupBy) {
+		response.BadRequest(c, err)
+		return
+	}
+	if err != nil {
+		response.SystemError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.FromTopItemsResultDTO(result))
+}
+
+// RegisterRoutes registers ecommerce routes on the given router group
+func (h *EcommerceHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	ecommerce := rg.Group("/ecommerce")
+	{
+		ecommerce.GET("/metrics", h.GetEcommerceMetrics)
+		ecommerce.GET("/items", h.GetTopItems)
+	}
+}
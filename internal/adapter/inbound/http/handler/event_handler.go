@@ -1,15 +1,25 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 
+	eventstreamv1 "github.com/ebubekir/event-stream/api/gen/eventstream/v1"
 	"github.com/ebubekir/event-stream/internal/adapter/inbound/http/dto"
+	"github.com/ebubekir/event-stream/internal/adapter/inbound/http/middleware"
 	"github.com/ebubekir/event-stream/internal/application/event"
+	eventDomain "github.com/ebubekir/event-stream/internal/domain/event"
 	"github.com/ebubekir/event-stream/pkg/response"
 )
 
+// SDKHeader identifies the client SDK as "name/version", e.g. "event-stream-go/1.2.0"
+const SDKHeader = "X-Client-SDK"
+
 // EventHandler handles HTTP requests for events
 type EventHandler struct {
 	service *event.EventService
@@ -25,68 +35,193 @@ func NewEventHandler(service *event.EventService) *EventHandler {
 // CreateEvent
 // @ID CreateEvent
 // @Summary Create a new event
-// @Description Creates a new event and persists it to the configured database
+// @Description Creates a new event and persists it to the configured database.
+// @Description The body may be JSON, protobuf (eventstream.v1.CreateEventRequest) or MessagePack, selected by Content-Type.
 // @Tags events
+// @Accept json,application/x-protobuf,application/msgpack
 // @Param event body dto.CreateEventRequest true "Event data"
 // @Success 201 {object} dto.CreateEventResponse
+// @Success 202 {object} dto.CreateEventResponse "The event was quarantined by the acceptance window instead of stored"
 // @Failure default {object} response.ApiError
 // @Router /events [post]
 func (h *EventHandler) CreateEvent(c *gin.Context) {
 	var req dto.CreateEventRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	if err := bindEventBody(c, &req, func() error {
+		var msg eventstreamv1.CreateEventRequest
+		if err := c.ShouldBindWith(&msg, binding.ProtoBuf); err != nil {
+			return err
+		}
+		req = dto.FromProtoCreateEventRequest(&msg)
+		return nil
+	}); err != nil {
 		response.BadRequest(c, err)
 		return
 	}
 
 	cmd := req.ToCommand()
-	id, err := h.service.CreateEvent(c.Request.Context(), cmd)
+	cmd.Ingestion = ingestionMetadata(c)
+	created, err := h.service.CreateEvent(c.Request.Context(), cmd)
+	if errors.Is(err, eventDomain.ErrEventOutOfWindow) {
+		response.ValidationError(c, err)
+		return
+	}
 	if err != nil {
 		response.SystemError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, dto.CreateEventResponse{ID: id})
+	if created.Quarantined {
+		c.JSON(http.StatusAccepted, dto.CreateEventResponse{ID: created.ID, Status: dto.EventStatusQuarantined})
+		return
+	}
+	c.JSON(http.StatusCreated, dto.CreateEventResponse{ID: created.ID, Status: dto.EventStatusStored})
 }
 
 // CreateEventBatch
 // @ID CreateEventBatch
 // @Summary Create multiple events
-// @Description Creates multiple events in a single batch operation
+// @Description Creates multiple events in a single batch operation.
+// @Description The body may be JSON, protobuf (eventstream.v1.CreateEventBatchRequest) or MessagePack, selected by Content-Type.
 // @Tags events
+// @Accept json,application/x-protobuf,application/msgpack
 // @Param events body dto.CreateEventBatchRequest true "Events data"
 // @Success 201 {object} dto.CreateEventBatchResponse
+// @Success 202 {object} dto.CreateEventBatchResponse "Some events were quarantined by the acceptance window; see quarantined_ids"
 // @Failure default {object} response.ApiError
 // @Router /events/batch [post]
 func (h *EventHandler) CreateEventBatch(c *gin.Context) {
 	var req dto.CreateEventBatchRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	if err := bindEventBody(c, &req, func() error {
+		var msg eventstreamv1.CreateEventBatchRequest
+		if err := c.ShouldBindWith(&msg, binding.ProtoBuf); err != nil {
+			return err
+		}
+		req = dto.FromProtoCreateEventBatchRequest(&msg)
+		return nil
+	}); err != nil {
 		response.BadRequest(c, err)
 		return
 	}
 
+	metadata := ingestionMetadata(c)
 	cmds := make([]*event.CreateEventCommand, len(req.Events))
 	for i, eventReq := range req.Events {
 		cmds[i] = eventReq.ToCommand()
+		cmds[i].Ingestion = metadata
+	}
+
+	created, err := h.service.CreateEvents(c.Request.Context(), cmds)
+	if errors.Is(err, eventDomain.ErrEventOutOfWindow) {
+		response.ValidationError(c, err)
+		return
+	}
+	if err != nil {
+		response.SystemError(c, err)
+		return
+	}
+
+	// Accepted rather than Created when some events were held back for review
+	status := http.StatusCreated
+	if len(created.QuarantinedIDs) > 0 {
+		status = http.StatusAccepted
+	}
+	c.JSON(status, dto.CreateEventBatchResponse{IDs: created.IDs, QuarantinedIDs: created.QuarantinedIDs})
+}
+
+// SearchEvents
+// @ID SearchEvents
+// @Summary Search events
+// @Description Returns stored events matching the filters, newest first by default.
+// @Description Pass next_cursor from a response as cursor to fetch the next page; pages are not shifted by events ingested in the meantime.
+// @Tags events
+// @Param event_name query []string false "Event names to match" collectionFormat(multi)
+// @Param from query string false "Start timestamp, inclusive (RFC3339 format)"
+// @Param to query string false "End timestamp, exclusive (RFC3339 format)"
+// @Param channel_type query string false "Channel type" Enums(web, mobile, desktop, tv, console, other)
+// @Param user_id query string false "User ID"
+// @Param user_pseudo_id query string false "User pseudo ID"
+// @Param device_category query string false "Device category"
+// @Param operating_system query string false "Device operating system"
+// @Param browser_name query string false "Device browser name"
+// @Param language query string false "Device language"
+// @Param app_id query string false "App ID"
+// @Param app_version query string false "App version"
+// @Param param_key query string false "Only events with this event param"
+// @Param param_value query string false "Value of param_key: its string value, number value, or true for a boolean"
+// @Param order query string false "Sort order by event time" Enums(asc, desc) default(desc)
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param limit query int false "Page size" minimum(1) maximum(1000) default(100)
+// @Success 200 {object} dto.SearchEventsResponse
+// @Failure default {object} response.ApiError
+// @Router /events [get]
+func (h *EventHandler) SearchEvents(c *gin.Context) {
+	var req dto.SearchEventsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	query, err := req.ToQuery()
+	if err != nil {
+		response.BadRequest(c, err)
+		return
 	}
 
-	ids, err := h.service.CreateEvents(c.Request.Context(), cmds)
+	page, err := h.service.SearchEvents(c.Request.Context(), query)
+	if errors.Is(err, event.ErrInvalidCursor) {
+		response.BadRequest(c, err)
+		return
+	}
+	if err != nil {
+		response.SystemError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.FromEventPageDTO(page))
+}
+
+// GetEvent
+// @ID GetEvent
+// @Summary Get an event
+// @Description Returns a stored event by the ID CreateEvent returned, with its ingestion metadata.
+// @Tags events
+// @Param id path string true "Event ID"
+// @Success 200 {object} dto.EventResponse
+// @Failure default {object} response.ApiError
+// @Router /events/{id} [get]
+func (h *EventHandler) GetEvent(c *gin.Context) {
+	result, err := h.service.GetEvent(c.Request.Context(), c.Param("id"))
+	if errors.Is(err, eventDomain.ErrEventNotFound) {
+		response.NotFoundError(c, err)
+		return
+	}
 	if err != nil {
 		response.SystemError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, dto.CreateEventBatchResponse{IDs: ids})
+	c.JSON(http.StatusOK, dto.FromEventDTO(result))
 }
 
 // GetMetrics
 // @ID GetMetrics
 // @Summary Get event metrics
-// @Description Retrieves aggregated metrics for events with optional grouping
+// @Description Retrieves aggregated metrics for one, several or all events with optional grouping
 // @Tags events
-// @Param event_name query string true "Event name to filter by"
-// @Param from query string false "Start timestamp (RFC3339 format)"
-// @Param to query string false "End timestamp (RFC3339 format)"
-// @Param group_by query string false "Aggregation type: channel, daily, hourly"
+// @Param event_name query []string false "Event names to filter by; none counts every event" collectionFormat(multi)
+// @Param from query string false "Start timestamp: RFC3339, or a date or date-time without offset in timezone"
+// @Param to query string false "End timestamp: RFC3339, or a date (inclusive) or date-time without offset in timezone"
+// @Param timezone query string false "IANA time zone of time buckets and local from/to, e.g. Europe/Istanbul (default UTC)"
+// @Param group_by query string false "Comma-separated dimensions: minute, 5min, hourly, daily, weekly, weekly_sunday, monthly, quarterly, channel, event_name, device.<field>, app_info.id, app_info.version, channel_type, event_param.<key> or user_param.<key>"
+// @Param group_limit query int false "Keep the most frequent groups of the non-time dimensions and count the rest as Other (max 1000)"
+// @Param fill query string false "Time buckets without events, with a single time dimension: zero (default), null or none" Enums(zero, null, none)
+// @Param measures query string false "Comma-separated measures <function>:<field> over event_param.<key> or user_param.<key>, e.g. avg:event_param.load_time_ms; functions: sum, avg, min, max, p50, p90, p95, p99, count_distinct"
+// @Param resolve_identity query bool false "Count unique users by canonical identity (user_pseudo_id resolved to user_id)"
+// @Param ingest_endpoint query string false "Only count events received on this endpoint, e.g. /v1/events/batch"
+// @Param sdk_name query string false "Only count events sent by this SDK"
+// @Param sdk_version query string false "Only count events sent by this SDK version"
+// @Param api_key_id query string false "Only count events sent with this API key ID"
+// @Param filter query []string false "Field filter <field>:<operator>[:<value>], e.g. event_param.value:gt:100; repeat to combine" collectionFormat(multi)
 // @Success 200 {object} dto.GetMetricsResponse
 // @Failure default {object} response.ApiError
 // @Router /events/metrics [get]
@@ -104,6 +239,11 @@ func (h *EventHandler) GetMetrics(c *gin.Context) {
 	}
 
 	result, err := h.service.GetMetrics(c.Request.Context(), query)
+	if errors.Is(err, eventDomain.ErrInvalidFilter) || errors.Is(err, eventDomain.ErrInvalidGroupBy) ||
+		errors.Is(err, eventDomain.ErrInvalidMeasure) {
+		response.BadRequest(c, err)
+		return
+	}
 	if err != nil {
 		response.SystemError(c, err)
 		return
@@ -117,7 +257,42 @@ func (h *EventHandler) RegisterRoutes(rg *gin.RouterGroup) {
 	events := rg.Group("/events")
 	{
 		events.POST("", h.CreateEvent)
+		events.GET("", h.SearchEvents)
 		events.POST("/batch", h.CreateEventBatch)
 		events.GET("/metrics", h.GetMetrics)
+		events.GET("/:id", h.GetEvent)
+	}
+}
+
+// bindEventBody decodes an ingest payload according to its Content-Type.
+// Protobuf messages are decoded by decodeProto into req and then validated
+// against the same binding rules as JSON; MessagePack decodes straight into req
+// using its json tags. Anything else is treated as JSON.
+func bindEventBody(c *gin.Context, req any, decodeProto func() error) error {
+	switch c.ContentType() {
+	case binding.MIMEPROTOBUF:
+		if err := decodeProto(); err != nil {
+			return err
+		}
+		return binding.Validator.ValidateStruct(req)
+	case binding.MIMEMSGPACK, binding.MIMEMSGPACK2:
+		return c.ShouldBindWith(req, binding.MsgPack)
+	default:
+		return c.ShouldBindJSON(req)
+	}
+}
+
+// ingestionMetadata captures how the request reached the server
+func ingestionMetadata(c *gin.Context) event.IngestionMetadataDTO {
+	sdkName, sdkVersion, _ := strings.Cut(c.GetHeader(SDKHeader), "/")
+
+	return event.IngestionMetadataDTO{
+		ReceivedAt: time.Now().UTC(),
+		Endpoint:   c.FullPath(),
+		RequestID:  middleware.GetRequestID(c),
+		ClientIP:   c.ClientIP(),
+		SDKName:    strings.TrimSpace(sdkName),
+		SDKVersion: strings.TrimSpace(sdkVersion),
+		APIKeyID:   middleware.GetAPIKeyID(c),
 	}
 }
@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/ebubekir/event-stream/internal/adapter/inbound/http/dto"
+	"github.com/ebubekir/event-stream/internal/application/export"
+	eventDomain "github.com/ebubekir/event-stream/internal/domain/event"
+	"github.com/ebubekir/event-stream/pkg/logger"
+	"github.com/ebubekir/event-stream/pkg/response"
+)
+
+// exportContentTypes maps export formats to their download content type
+var exportContentTypes = map[string]string{
+	"ndjson":  "application/x-ndjson",
+	"csv":     "text/csv",
+	"parquet": "application/vnd.apache.parquet",
+}
+
+// ExportHandler handles HTTP requests for raw event export jobs
+type ExportHandler struct {
+	service *export.ExportService
+}
+
+// NewExportHandler creates a new ExportHandler
+func NewExportHandler(service *export.ExportService) *ExportHandler {
+	return &ExportHandler{
+		service: service,
+	}
+}
+
+// CreateExport
+// @ID CreateExport
+// @Summary Start an export job
+// @Description Starts exporting the raw events in [from, to) that match the filters. NDJSON and Parquet keep params and items nested; CSV flattens the requested params, or every param key in the range, into columns. Poll the job and download its output once it has succeeded.
+// @Tags exports
+// @Param export body dto.CreateExportRequest true "Export"
+// @Success 202 {object} dto.ExportJobResponse
+// @Failure default {object} response.ApiError
+// @Router /exports [post]
+func (h *ExportHandler) CreateExport(c *gin.Context) {
+	var req dto.CreateExportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	cmd, err := req.ToCommand()
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	job, err := h.service.CreateJob(c.Request.Context(), cmd)
+	if err != nil {
+		exportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, dto.FromExportJobDTO(job))
+}
+
+// ListExports
+// @ID ListExports
+// @Summary List export jobs
+// @Description Lists all export jobs, most recent first
+// @Tags exports
+// @Success 200 {object} dto.ListExportsResponse
+// @Failure default {object} response.ApiError
+// @Router /exports [get]
+func (h *ExportHandler) ListExports(c *gin.Context) {
+	jobs, err := h.service.ListJobs(c.Request.Context())
+	if err != nil {
+		response.SystemError(c, err)
+		return
+	}
+
+	response.Success(c, dto.FromExportJobDTOs(jobs))
+}
+
+// GetExport
+// @ID GetExport
+// @Summary Get an export job
+// @Tags exports
+// @Param id path string true "Export ID"
+// @Success 200 {object} dto.ExportJobResponse
+// @Failure default {object} response.ApiError
+// @Router /exports/{id} [get]
+func (h *ExportHandler) GetExport(c *gin.Context) {
+	job, err := h.service.GetJob(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		exportError(c, err)
+		return
+	}
+
+	response.Success(c, dto.FromExportJobDTO(job))
+}
+
+// DownloadExport
+// @ID DownloadExport
+// @Summary Download an export
+// @Description Streams the output of a succeeded export job. Returns 409 while the job is pending or running, or when it failed.
+// @Tags exports
+// @Produce application/x-ndjson,text/csv,application/vnd.apache.parquet
+// @Param id path string true "Export ID"
+// @Success 200 {file} file
+// @Failure default {object} response.ApiError
+// @Router /exports/{id}/download [get]
+func (h *ExportHandler) DownloadExport(c *gin.Context) {
+	output, job, err := h.service.OpenJobOutput(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		exportError(c, err)
+		return
+	}
+	defer output.Close()
+
+	c.Header("Content-Type", exportContentTypes[job.Format])
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="events-%s.%s"`, job.ID, job.Format))
+	c.Status(http.StatusOK)
+
+	if _, err := io.Copy(c.Writer, output); err != nil {
+		// Headers are sent already; the client sees a truncated body
+		logger.Warn("export download interrupted", zap.String("export_id", job.ID), zap.Error(err))
+	}
+}
+
+// RegisterRoutes registers export routes on the given router group
+func (h *ExportHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	exports := rg.Group("/exports")
+	{
+		exports.POST("", h.CreateExport)
+		exports.GET("", h.ListExports)
+		exports.GET("/:id", h.GetExport)
+		exports.GET("/:id/download", h.DownloadExport)
+	}
+}
+
+func exportError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, eventDomain.ErrExportNotFound):
+		response.NotFoundError(c, err)
+	case errors.Is(err, eventDomain.ErrExportNotReady):
+		response.ConflictError(c, err)
+	case errors.Is(err, export.ErrUnsupportedFormat):
+		response.BadRequest(c, err)
+	default:
+		response.SystemError(c, err)
+	}
+}
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/ebubekir/event-stream/internal/adapter/inbound/http/dto"
+	"github.com/ebubekir/event-stream/internal/application/event"
+	"github.com/ebubekir/event-stream/pkg/response"
+)
+
+// IdentityHandler handles HTTP requests for identity stitching
+type IdentityHandler struct {
+	service *event.EventService
+}
+
+// NewIdentityHandler creates a new IdentityHandler
+func NewIdentityHandler(service *event.EventService) *IdentityHandler {
+	return &IdentityHandler{
+		service: service,
+	}
+}
+
+// Identify
+// @ID Identify
+// @Summary Link an anonymous user to a known user
+// @Description Aliases a user_pseudo_id to a user_id so earlier anonymous events are attributed to that user
+// @Tags identity
+// @Param identity body dto.IdentifyRequest true "Identity link"
+// @Success 200 {object} response.StatusResponse
+// @Failure default {object} response.ApiError
+// @Router /identify [post]
+func (h *IdentityHandler) Identify(c *gin.Context) {
+	var req dto.IdentifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	if err := h.service.Identify(c.Request.Context(), req.ToCommand()); err != nil {
+		response.SystemError(c, err)
+		return
+	}
+
+	response.Status(c, "Identity linked")
+}
+
+// RegisterRoutes registers identity routes on the given router group
+func (h *IdentityHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.POST("/identify", h.Identify)
+}
@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/ebubekir/event-stream/internal/adapter/inbound/http/dto"
+	"github.com/ebubekir/event-stream/internal/application/event"
+	"github.com/ebubekir/event-stream/pkg/response"
+)
+
+// QuarantineHandler handles admin HTTP requests for reviewing quarantined events
+type QuarantineHandler struct {
+	service *event.EventService
+}
+
+// NewQuarantineHandler creates a new QuarantineHandler
+func NewQuarantineHandler(service *event.EventService) *QuarantineHandler {
+	return &QuarantineHandler{
+		service: service,
+	}
+}
+
+// ListQuarantined
+// @ID ListQuarantined
+// @Summary List quarantined events
+// @Description Lists events held back for review, most recently quarantined first
+// @Tags admin
+// @Param limit query int false "Page size (default 100, max 1000)"
+// @Param offset query int false "Number of events to skip"
+// @Success 200 {object} dto.ListQuarantineResponse
+// @Failure default {object} response.ApiError
+// @Router /admin/quarantine [get]
+func (h *QuarantineHandler) ListQuarantined(c *gin.Context) {
+	var req dto.ListQuarantineRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	events, err := h.service.ListQuarantined(c.Request.Context(), req.ToQuery())
+	if errors.Is(err, event.ErrQuarantineDisabled) {
+		response.NotFoundError(c, err)
+		return
+	}
+	if err != nil {
+		response.SystemError(c, err)
+		return
+	}
+
+	response.Success(c, dto.FromQuarantinedEventDTOs(events))
+}
+
+// ReleaseQuarantined
+// @ID ReleaseQuarantined
+// @Summary Release quarantined events
+// @Description Moves the given quarantined events into the events table, optionally clamping their dates into the acceptance window
+// @Tags admin
+// @Param release body dto.ReleaseQuarantineRequest true "Events to release"
+// @Success 200 {object} dto.ReleaseQuarantineResponse
+// @Failure default {object} response.ApiError
+// @Router /admin/quarantine/release [post]
+func (h *QuarantineHandler) ReleaseQuarantined(c *gin.Context) {
+	var req dto.ReleaseQuarantineRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	released, err := h.service.ReleaseQuarantined(c.Request.Context(), req.ToCommand())
+	if errors.Is(err, event.ErrQuarantineDisabled) {
+		response.NotFoundError(c, err)
+		return
+	}
+	if err != nil {
+		response.SystemError(c, err)
+		return
+	}
+
+	response.Success(c, dto.ReleaseQuarantineResponse{Released: released})
+}
+
+// DiscardQuarantined
+// @ID DiscardQuarantined
+// @Summary Discard quarantined events
+// @Description Permanently deletes the given events from quarantine
+// @Tags admin
+// @Param discard body dto.DiscardQuarantineRequest true "Events to discard"
+// @Success 200 {object} response.StatusResponse
+// @Failure default {object} response.ApiError
+// @Router /admin/quarantine/discard [post]
+func (h *QuarantineHandler) DiscardQuarantined(c *gin.Context) {
+	var req dto.DiscardQuarantineRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	err := h.service.DiscardQuarantined(c.Request.Context(), req.IDs)
+	if errors.Is(err, event.ErrQuarantineDisabled) {
+		response.NotFoundError(c, err)
+		return
+	}
+	if err != nil {
+		response.SystemError(c, err)
+		return
+	}
+
+	response.Status(c, "Quarantined events discarded")
+}
+
+// RegisterRoutes registers quarantine admin routes on the given router group
+func (h *QuarantineHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	quarantine := rg.Group("/admin/quarantine")
+	{
+		quarantine.GET("", h.ListQuarantined)
+		quarantine.POST("/release", h.ReleaseQuarantined)
+		quarantine.POST("/discard", h.DiscardQuarantined)
+	}
+}
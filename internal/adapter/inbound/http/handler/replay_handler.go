@@ -0,0 +1,184 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/ebubekir/event-stream/internal/adapter/inbound/http/dto"
+	"github.com/ebubekir/event-stream/internal/application/replay"
+	eventDomain "github.com/ebubekir/event-stream/internal/domain/event"
+	"github.com/ebubekir/event-stream/pkg/response"
+)
+
+// ReplayHandler handles admin HTTP requests for replaying events
+type ReplayHandler struct {
+	service *replay.ReplayService
+}
+
+// NewReplayHandler creates a new ReplayHandler
+func NewReplayHandler(service *replay.ReplayService) *ReplayHandler {
+	return &ReplayHandler{
+		service: service,
+	}
+}
+
+// CreateReplay
+// @ID CreateReplay
+// @Summary Start a replay job
+// @Description Re-ingests the events in [from, to) that match the filters from a source: the raw event archive, the quarantine, or the other database type. The window is replayed in chunks and checkpointed after each, so a cancelled or interrupted replay resumes where it stopped. With delete_first the matching stored events of each chunk are deleted before it is replayed; with reprocess events run through the current ingestion pipeline again. Quarantined events are removed from the quarantine once replayed.
+// @Tags replays
+// @Param replay body dto.CreateReplayRequest true "Replay"
+// @Success 202 {object} dto.ReplayJobResponse
+// @Failure default {object} response.ApiError
+// @Router /admin/replays [post]
+func (h *ReplayHandler) CreateReplay(c *gin.Context) {
+	var req dto.CreateReplayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	cmd, err := req.ToCommand()
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	job, err := h.service.StartJob(c.Request.Context(), cmd)
+	if err != nil {
+		replayError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, dto.FromReplayJobDTO(job))
+}
+
+// ListReplays
+// @ID ListReplays
+// @Summary List replay jobs
+// @Description Lists all replay jobs, most recent first
+// @Tags replays
+// @Success 200 {object} dto.ListReplaysResponse
+// @Failure default {object} response.ApiError
+// @Router /admin/replays [get]
+func (h *ReplayHandler) ListReplays(c *gin.Context) {
+	jobs, err := h.service.ListJobs(c.Request.Context())
+	if err != nil {
+		response.SystemError(c, err)
+		return
+	}
+
+	response.Success(c, dto.FromReplayJobDTOs(jobs))
+}
+
+// GetReplay
+// @ID GetReplay
+// @Summary Get a replay job
+// @Tags replays
+// @Param id path string true "Replay ID"
+// @Success 200 {object} dto.ReplayJobResponse
+// @Failure default {object} response.ApiError
+// @Router /admin/replays/{id} [get]
+func (h *ReplayHandler) GetReplay(c *gin.Context) {
+	job, err := h.service.GetJob(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		replayError(c, err)
+		return
+	}
+
+	response.Success(c, dto.FromReplayJobDTO(job))
+}
+
+// UpdateReplay
+// @ID UpdateReplay
+// @Summary Throttle a running replay
+// @Description Changes the events per second limit of a running replay from its next batch on. Returns 409 when the replay is not running.
+// @Tags replays
+// @Param id path string true "Replay ID"
+// @Param replay body dto.UpdateReplayRequest true "Rate"
+// @Success 200 {object} dto.ReplayJobResponse
+// @Failure default {object} response.ApiError
+// @Router /admin/replays/{id} [patch]
+func (h *ReplayHandler) UpdateReplay(c *gin.Context) {
+	var req dto.UpdateReplayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	job, err := h.service.SetRate(c.Request.Context(), c.Param("id"), *req.Rate)
+	if err != nil {
+		replayError(c, err)
+		return
+	}
+
+	response.Success(c, dto.FromReplayJobDTO(job))
+}
+
+// CancelReplay
+// @ID CancelReplay
+// @Summary Cancel a running replay
+// @Description Stops a running replay after its current batch. It can be resumed from its last checkpoint.
+// @Tags replays
+// @Param id path string true "Replay ID"
+// @Success 200 {object} dto.ReplayJobResponse
+// @Failure default {object} response.ApiError
+// @Router /admin/replays/{id}/cancel [post]
+func (h *ReplayHandler) CancelReplay(c *gin.Context) {
+	job, err := h.service.CancelJob(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		replayError(c, err)
+		return
+	}
+
+	response.Success(c, dto.FromReplayJobDTO(job))
+}
+
+// ResumeReplay
+// @ID ResumeReplay
+// @Summary Resume a replay
+// @Description Continues a cancelled or failed replay from its last checkpoint. Returns 409 when the replay is running or has succeeded.
+// @Tags replays
+// @Param id path string true "Replay ID"
+// @Success 202 {object} dto.ReplayJobResponse
+// @Failure default {object} response.ApiError
+// @Router /admin/replays/{id}/resume [post]
+func (h *ReplayHandler) ResumeReplay(c *gin.Context) {
+	job, err := h.service.ResumeJob(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		replayError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, dto.FromReplayJobDTO(job))
+}
+
+// RegisterRoutes registers replay admin routes on the given router group
+func (h *ReplayHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	replays := rg.Group("/admin/replays")
+	{
+		replays.POST("", h.CreateReplay)
+		replays.GET("", h.ListReplays)
+		replays.GET("/:id", h.GetReplay)
+		replays.PATCH("/:id", h.UpdateReplay)
+		replays.POST("/:id/cancel", h.CancelReplay)
+		replays.POST("/:id/resume", h.ResumeReplay)
+	}
+}
+
+func replayError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, eventDomain.ErrReplayNotFound):
+		response.NotFoundError(c, err)
+	case errors.Is(err, eventDomain.ErrReplayNotRunning),
+		errors.Is(err, eventDomain.ErrReplayRunning),
+		errors.Is(err, eventDomain.ErrReplaySucceeded):
+		response.ConflictError(c, err)
+	case errors.Is(err, replay.ErrInvalidReplay):
+		response.BadRequest(c, err)
+	default:
+		response.SystemError(c, err)
+	}
+}
@@ -0,0 +1,187 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+
+	"github.com/ebubekir/event-stream/internal/adapter/inbound/http/dto"
+	"github.com/ebubekir/event-stream/internal/application/event"
+	"github.com/ebubekir/event-stream/pkg/logger"
+	"github.com/ebubekir/event-stream/pkg/response"
+)
+
+const (
+	// tailHeartbeat keeps idle tails alive through proxies and reports dropped events
+	tailHeartbeat = 15 * time.Second
+	// tailWriteTimeout bounds a single WebSocket write to a stalled client
+	tailWriteTimeout = 10 * time.Second
+)
+
+// TailHandler handles HTTP requests for live event tails
+type TailHandler struct {
+	service  *event.EventService
+	upgrader websocket.Upgrader
+}
+
+// NewTailHandler creates a new TailHandler
+func NewTailHandler(service *event.EventService) *TailHandler {
+	return &TailHandler{
+		service: service,
+		upgrader: websocket.Upgrader{
+			// Tails are authorized by API key rather than cookies, so any origin may connect
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+	}
+}
+
+// TailEvents
+// @ID TailEvents
+// @Summary Stream newly ingested events
+// @Description Streams events as they are persisted, as Server-Sent Events. Each SSE event is named after the message type
+// @Description and carries a TailMessage: "event" messages hold an event, "dropped" messages report how many events were
+// @Description skipped because the client fell behind. Only events ingested after connecting are sent.
+// @Tags events
+// @Produce text/event-stream
+// @Param event_name query string false "Event name"
+// @Param user_pseudo_id query string false "User pseudo ID"
+// @Param channel_type query string false "Channel type" Enums(web, mobile, desktop, tv, console, other)
+// @Param app_id query string false "App ID"
+// @Success 200 {object} dto.TailMessage
+// @Failure default {object} response.ApiError
+// @Router /events/tail [get]
+func (h *TailHandler) TailEvents(c *gin.Context) {
+	var req dto.TailEventsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	sub := h.service.TailEvents(req.ToQuery())
+	defer sub.Close()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(tailHeartbeat)
+	defer heartbeat.Stop()
+
+	var reported uint64
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case e, ok := <-sub.Events():
+			if !ok {
+				return false
+			}
+			c.SSEvent(dto.TailMessageEvent, dto.NewTailEventMessage(e))
+		case <-heartbeat.C:
+			if dropped := sub.Dropped(); dropped != reported {
+				reported = dropped
+				c.SSEvent(dto.TailMessageDropped, dto.NewTailDroppedMessage(dropped))
+			} else {
+				// SSE comment line; ignored by clients
+				_, _ = io.WriteString(w, ": ping\n\n")
+			}
+		}
+		return true
+	})
+}
+
+// TailEventsWebSocket
+// @ID TailEventsWebSocket
+// @Summary Stream newly ingested events over WebSocket
+// @Description Upgrades to a WebSocket and sends one JSON TailMessage per text frame, with the same filters and messages
+// @Description as the Server-Sent Events tail. Messages sent by the client are ignored.
+// @Tags events
+// @Param event_name query string false "Event name"
+// @Param user_pseudo_id query string false "User pseudo ID"
+// @Param channel_type query string false "Channel type" Enums(web, mobile, desktop, tv, console, other)
+// @Param app_id query string false "App ID"
+// @Success 101 {object} dto.TailMessage
+// @Failure default {object} response.ApiError
+// @Router /events/tail/ws [get]
+func (h *TailHandler) TailEventsWebSocket(c *gin.Context) {
+	var req dto.TailEventsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	// Upgrade writes its own error response on failure
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	sub := h.service.TailEvents(req.ToQuery())
+	defer sub.Close()
+
+	// Read until the client goes away; this also processes control frames
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	heartbeat := time.NewTicker(tailHeartbeat)
+	defer heartbeat.Stop()
+
+	var reported uint64
+	for {
+		var err error
+		select {
+		case <-closed:
+			return
+		case e, ok := <-sub.Events():
+			if !ok {
+				_ = conn.WriteControl(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down"),
+					time.Now().Add(tailWriteTimeout))
+				return
+			}
+			err = writeTailMessage(conn, dto.NewTailEventMessage(e))
+		case <-heartbeat.C:
+			if dropped := sub.Dropped(); dropped != reported {
+				reported = dropped
+				err = writeTailMessage(conn, dto.NewTailDroppedMessage(dropped))
+			} else {
+				err = conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(tailWriteTimeout))
+			}
+		}
+		if err != nil {
+			logger.Debug("live tail connection closed", zap.Error(err))
+			return
+		}
+	}
+}
+
+func writeTailMessage(conn *websocket.Conn, msg dto.TailMessage) error {
+	if err := conn.SetWriteDeadline(time.Now().Add(tailWriteTimeout)); err != nil {
+		return err
+	}
+	return conn.WriteJSON(msg)
+}
+
+// RegisterRoutes registers live tail routes on the given router group
+func (h *TailHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	events := rg.Group("/events")
+	{
+		events.GET("/tail", h.TailEvents)
+		events.GET("/tail/ws", h.TailEventsWebSocket)
+	}
+}
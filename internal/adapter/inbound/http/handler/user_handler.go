@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/ebubekir/event-stream/internal/adapter/inbound/http/dto"
+	"github.com/ebubekir/event-stream/internal/application/event"
+	"github.com/ebubekir/event-stream/pkg/response"
+)
+
+// UserHandler handles HTTP requests for per-user views
+type UserHandler struct {
+	service *event.EventService
+}
+
+// NewUserHandler creates a new UserHandler
+func NewUserHandler(service *event.EventService) *UserHandler {
+	return &UserHandler{
+		service: service,
+	}
+}
+
+// GetUserTimeline
+// @ID GetUserTimeline
+// @Summary Get a user's event timeline
+// @Description Returns the events of a user_id, or of a user_pseudo_id with id_type=user_pseudo_id, oldest first by default.
+// @Description Pass next_cursor from a response as cursor to fetch the next page.
+// @Tags users
+// @Param id path string true "User ID or user pseudo ID"
+// @Param id_type query string false "Which ID the path holds" Enums(user_id, user_pseudo_id) default(user_id)
+// @Param event_name query []string false "Event names to match" collectionFormat(multi)
+// @Param from query string false "Start timestamp, inclusive (RFC3339 format)"
+// @Param to query string false "End timestamp, exclusive (RFC3339 format)"
+// @Param order query string false "Sort order by event time" Enums(asc, desc) default(asc)
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param limit query int false "Page size" minimum(1) maximum(1000) default(100)
+// @Success 200 {object} dto.SearchEventsResponse
+// @Failure default {object} response.ApiError
+// @Router /users/{id}/events [get]
+func (h *UserHandler) GetUserTimeline(c *gin.Context) {
+	var req dto.UserTimelineRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	query, err := req.ToQuery(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	page, err := h.service.GetUserTimeline(c.Request.Context(), query)
+	if errors.Is(err, event.ErrInvalidCursor) {
+		response.BadRequest(c, err)
+		return
+	}
+	if err != nil {
+		response.SystemError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.FromEventPageDTO(page))
+}
+
+// RegisterRoutes registers user routes on the given router group
+func (h *UserHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	users := rg.Group("/users")
+	{
+		users.GET("/:id/events", h.GetUserTimeline)
+	}
+}
@@ -0,0 +1,178 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/ebubekir/event-stream/internal/adapter/inbound/http/dto"
+	"github.com/ebubekir/event-stream/internal/application/webhook"
+	eventDomain "github.com/ebubekir/event-stream/internal/domain/event"
+	"github.com/ebubekir/event-stream/pkg/response"
+)
+
+// WebhookHandler handles admin HTTP requests for managing webhook subscriptions
+type WebhookHandler struct {
+	service *webhook.WebhookService
+}
+
+// NewWebhookHandler creates a new WebhookHandler
+func NewWebhookHandler(service *webhook.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		service: service,
+	}
+}
+
+// CreateWebhook
+// @ID CreateWebhook
+// @Summary Create a webhook subscription
+// @Description Registers an endpoint that receives a signed POST for every stored event matching its filters. The signing secret is only returned here.
+// @Tags webhooks
+// @Param webhook body dto.CreateWebhookRequest true "Subscription"
+// @Success 201 {object} dto.WebhookResponse
+// @Failure default {object} response.ApiError
+// @Router /admin/webhooks [post]
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	var req dto.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	subscription, err := h.service.CreateSubscription(c.Request.Context(), req.ToCommand())
+	if err != nil {
+		response.SystemError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.FromSubscriptionDTO(subscription))
+}
+
+// ListWebhooks
+// @ID ListWebhooks
+// @Summary List webhook subscriptions
+// @Description Lists all webhook subscriptions with their delivery health
+// @Tags webhooks
+// @Success 200 {object} dto.ListWebhooksResponse
+// @Failure default {object} response.ApiError
+// @Router /admin/webhooks [get]
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+	subscriptions, err := h.service.ListSubscriptions(c.Request.Context())
+	if err != nil {
+		response.SystemError(c, err)
+		return
+	}
+
+	response.Success(c, dto.FromSubscriptionDTOs(subscriptions))
+}
+
+// GetWebhook
+// @ID GetWebhook
+// @Summary Get a webhook subscription
+// @Tags webhooks
+// @Param id path string true "Subscription ID"
+// @Success 200 {object} dto.WebhookResponse
+// @Failure default {object} response.ApiError
+// @Router /admin/webhooks/{id} [get]
+func (h *WebhookHandler) GetWebhook(c *gin.Context) {
+	subscription, err := h.service.GetSubscription(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		webhookError(c, err)
+		return
+	}
+
+	response.Success(c, dto.FromSubscriptionDTO(subscription))
+}
+
+// UpdateWebhook
+// @ID UpdateWebhook
+// @Summary Update a webhook subscription
+// @Description Replaces a subscription's URL, filters and enabled flag, and optionally rotates its secret
+// @Tags webhooks
+// @Param id path string true "Subscription ID"
+// @Param webhook body dto.UpdateWebhookRequest true "Subscription"
+// @Success 200 {object} dto.WebhookResponse
+// @Failure default {object} response.ApiError
+// @Router /admin/webhooks/{id} [put]
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	var req dto.UpdateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	subscription, err := h.service.UpdateSubscription(c.Request.Context(), req.ToCommand(c.Param("id")))
+	if err != nil {
+		webhookError(c, err)
+		return
+	}
+
+	response.Success(c, dto.FromSubscriptionDTO(subscription))
+}
+
+// DeleteWebhook
+// @ID DeleteWebhook
+// @Summary Delete a webhook subscription
+// @Description Deletes a subscription and its delivery history
+// @Tags webhooks
+// @Param id path string true "Subscription ID"
+// @Success 200 {object} response.StatusResponse
+// @Failure default {object} response.ApiError
+// @Router /admin/webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	if err := h.service.DeleteSubscription(c.Request.Context(), c.Param("id")); err != nil {
+		webhookError(c, err)
+		return
+	}
+
+	response.Status(c, "Webhook deleted")
+}
+
+// ListWebhookDeliveries
+// @ID ListWebhookDeliveries
+// @Summary List webhook delivery attempts
+// @Description Lists a subscription's delivery attempts, most recent first
+// @Tags webhooks
+// @Param id path string true "Subscription ID"
+// @Param limit query int false "Page size (default 100, max 1000)"
+// @Param offset query int false "Number of attempts to skip"
+// @Success 200 {object} dto.ListWebhookDeliveriesResponse
+// @Failure default {object} response.ApiError
+// @Router /admin/webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) ListWebhookDeliveries(c *gin.Context) {
+	var req dto.ListWebhookDeliveriesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	deliveries, err := h.service.ListDeliveries(c.Request.Context(), req.ToQuery(c.Param("id")))
+	if err != nil {
+		webhookError(c, err)
+		return
+	}
+
+	response.Success(c, dto.FromDeliveryDTOs(deliveries))
+}
+
+// RegisterRoutes registers webhook admin routes on the given router group
+func (h *WebhookHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	webhooks := rg.Group("/admin/webhooks")
+	{
+		webhooks.POST("", h.CreateWebhook)
+		webhooks.GET("", h.ListWebhooks)
+		webhooks.GET("/:id", h.GetWebhook)
+		webhooks.PUT("/:id", h.UpdateWebhook)
+		webhooks.DELETE("/:id", h.DeleteWebhook)
+		webhooks.GET("/:id/deliveries", h.ListWebhookDeliveries)
+	}
+}
+
+func webhookError(c *gin.Context, err error) {
+	if errors.Is(err, eventDomain.ErrWebhookNotFound) {
+		response.NotFoundError(c, err)
+		return
+	}
+	response.SystemError(c, err)
+}
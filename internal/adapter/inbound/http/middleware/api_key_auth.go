@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/ebubekir/event-stream/pkg/auth"
+	"github.com/ebubekir/event-stream/pkg/response"
+)
+
+const (
+	// APIKeyHeader carries the caller's API key
+	APIKeyHeader = "X-API-Key"
+
+	apiKeyIDKey = "api_key_id"
+)
+
+// APIKeyAuth rejects requests without a valid X-API-Key header.
+// Authentication is disabled when no keys are configured.
+func APIKeyAuth(keys []auth.APIKey) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if len(keys) == 0 {
+			c.Next()
+			return
+		}
+
+		id, ok := auth.Match(keys, c.GetHeader(APIKeyHeader))
+		if !ok {
+			response.UnauthorizedError(c, errors.New("missing or invalid API key"))
+			return
+		}
+
+		c.Set(apiKeyIDKey, id)
+		c.Next()
+	}
+}
+
+// GetAPIKeyID returns the ID of the API key that authenticated the request
+func GetAPIKeyID(c *gin.Context) string {
+	return c.GetString(apiKeyIDKey)
+}
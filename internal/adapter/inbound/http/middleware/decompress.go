@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/ebubekir/event-stream/pkg/response"
+)
+
+// maxDecompressedBodySize bounds gzip request bodies once inflated
+const maxDecompressedBodySize = 64 << 20
+
+// Decompress inflates gzip request bodies sent with Content-Encoding: gzip.
+// Other content encodings are rejected with 415 Unsupported Media Type.
+func Decompress() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		encoding := strings.ToLower(strings.TrimSpace(c.GetHeader("Content-Encoding")))
+		switch encoding {
+		case "", "identity":
+			c.Next()
+			return
+		case "gzip", "x-gzip":
+		default:
+			response.ErrorWithStatusCodeAndMessage(c, http.StatusUnsupportedMediaType, "unsupported content encoding: "+encoding)
+			return
+		}
+
+		gz, err := gzip.NewReader(c.Request.Body)
+		if err != nil {
+			response.BadRequest(c, errors.New("invalid gzip request body"))
+			return
+		}
+
+		c.Request.Body = http.MaxBytesReader(c.Writer, &gzipBody{Reader: gz, body: c.Request.Body}, maxDecompressedBodySize)
+		c.Request.Header.Del("Content-Encoding")
+		c.Request.Header.Del("Content-Length")
+		c.Request.ContentLength = -1
+		c.Next()
+	}
+}
+
+// gzipBody closes both the gzip reader and the underlying request body
+type gzipBody struct {
+	*gzip.Reader
+	body io.Closer
+}
+
+func (b *gzipBody) Close() error {
+	return errors.Join(b.Reader.Close(), b.body.Close())
+}
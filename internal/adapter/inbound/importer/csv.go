@@ -0,0 +1,163 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ebubekir/event-stream/internal/adapter/inbound/http/dto"
+)
+
+// CSVSource reads CSV files with a header row, turning each data row into a
+// request according to a Mapping. Offsets are data row numbers.
+type CSVSource struct {
+	file    *os.File
+	reader  *csv.Reader
+	mapping *Mapping
+	index   map[string]int
+	offset  int64
+}
+
+// OpenCSV opens a CSV file and checks its header against mapping
+func OpenCSV(path string, mapping *Mapping) (*CSVSource, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	index := make(map[string]int, len(header))
+	for i, column := range header {
+		index[strings.TrimSpace(column)] = i
+	}
+
+	var columns []string
+	for _, column := range mapping.Columns {
+		columns = append(columns, column)
+	}
+	for _, params := range [][]ParamMapping{mapping.EventParams, mapping.UserParams} {
+		for _, p := range params {
+			columns = append(columns, p.Column)
+		}
+	}
+	for _, column := range columns {
+		if _, ok := index[column]; !ok {
+			_ = file.Close()
+			return nil, fmt.Errorf("CSV header has no column %q", column)
+		}
+	}
+
+	return &CSVSource{file: file, reader: reader, mapping: mapping, index: index}, nil
+}
+
+// Next returns the next data row
+func (s *CSVSource) Next() (Row, error) {
+	record, err := s.reader.Read()
+	if err == io.EOF {
+		return Row{}, io.EOF
+	}
+	s.offset++
+
+	row := Row{Offset: s.offset}
+	if err != nil {
+		// Malformed rows are reported; anything else, e.g. an I/O error, stops the import
+		var parseErr *csv.ParseError
+		if !errors.As(err, &parseErr) {
+			return Row{}, err
+		}
+		row.Err = err
+		return row, nil
+	}
+
+	row.Raw = strings.Join(record, ",")
+	req, err := s.toRequest(record)
+	if err != nil {
+		row.Err = err
+	} else {
+		row.Request = req
+	}
+	return row, nil
+}
+
+func (s *CSVSource) toRequest(record []string) (*Request, error) {
+	value := func(column string) string {
+		i := s.index[column]
+		if i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	req := &Request{}
+	for field, v := range s.mapping.Defaults {
+		if err := fieldSetters[field](req, v); err != nil {
+			return nil, fmt.Errorf("default %s: %w", field, err)
+		}
+	}
+	for field, column := range s.mapping.Columns {
+		v := value(column)
+		if v == "" {
+			continue
+		}
+		if err := fieldSetters[field](req, v); err != nil {
+			return nil, fmt.Errorf("column %s: %w", column, err)
+		}
+	}
+
+	var err error
+	if req.EventParams, err = toParams(s.mapping.EventParams, value); err != nil {
+		return nil, err
+	}
+	if req.UserParams, err = toParams(s.mapping.UserParams, value); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// toParams converts mapped columns to params, skipping empty cells
+func toParams(mappings []ParamMapping, value func(string) string) ([]dto.ParamRequest, error) {
+	params := make([]dto.ParamRequest, 0, len(mappings))
+	for _, m := range mappings {
+		v := value(m.Column)
+		if v == "" {
+			continue
+		}
+
+		param := dto.ParamRequest{Key: m.Key}
+		switch m.Type {
+		case ParamTypeNumber:
+			n, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return nil, fmt.Errorf("column %s: %w", m.Column, err)
+			}
+			param.NumberValue = n
+		case ParamTypeBoolean:
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, fmt.Errorf("column %s: %w", m.Column, err)
+			}
+			param.BooleanValue = b
+		default:
+			param.StringValue = v
+		}
+		params = append(params, param)
+	}
+	return params, nil
+}
+
+// Close closes the file
+func (s *CSVSource) Close() error {
+	return s.file.Close()
+}
@@ -0,0 +1,436 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ebubekir/event-stream/internal/adapter/inbound/http/dto"
+)
+
+// ga4ParamPrefix prefixes the keys of params that carry GA4 export fields
+// with no dedicated event field, so nothing in the export is dropped
+const ga4ParamPrefix = "ga4."
+
+// maxExactFloat is the largest integer magnitude a float64 holds exactly
+const maxExactFloat = 1 << 53
+
+// GA4JSONSource reads a Google Analytics 4 BigQuery export in newline-delimited
+// JSON, one GA4 event per line. Offsets are line numbers; blank lines are skipped.
+type GA4JSONSource struct {
+	file    *os.File
+	scanner *bufio.Scanner
+	line    int64
+}
+
+// OpenGA4JSON opens a GA4 JSON export file, decompressing it when the name ends in .gz
+func OpenGA4JSON(path string) (*GA4JSONSource, error) {
+	file, scanner, err := openLines(path)
+	if err != nil {
+		return nil, err
+	}
+	return &GA4JSONSource{file: file, scanner: scanner}, nil
+}
+
+// Next returns the next GA4 event
+func (s *GA4JSONSource) Next() (Row, error) {
+	for s.scanner.Scan() {
+		s.line++
+		raw := s.scanner.Text()
+		if strings.TrimSpace(raw) == "" {
+			continue
+		}
+
+		row := Row{Offset: s.line, Raw: raw}
+		// Numbers are kept as written so large integers are not rounded
+		decoder := json.NewDecoder(strings.NewReader(raw))
+		decoder.UseNumber()
+		var record map[string]any
+		if err := decoder.Decode(&record); err != nil {
+			row.Err = err
+		} else {
+			row.Request, row.Err = mapGA4(record)
+		}
+		return row, nil
+	}
+
+	if err := s.scanner.Err(); err != nil {
+		return Row{}, err
+	}
+	return Row{}, io.EOF
+}
+
+// Close closes the file
+func (s *GA4JSONSource) Close() error {
+	return s.file.Close()
+}
+
+// mapGA4 converts one GA4 export row to a Request. Fields with a place in the
+// event schema are mapped to it; every other non-null field is kept as an
+// event param keyed by its path under ga4ParamPrefix, e.g. "ga4.event_date"
+// or "ga4.traffic_source.medium".
+func mapGA4(record map[string]any) (*Request, error) {
+	var req Request
+
+	name, ok := ga4String(take(record, "event_name"))
+	if !ok || name == "" {
+		return nil, fmt.Errorf("event_name is required")
+	}
+	req.Name = name
+
+	timestamp, ok := ga4Int(take(record, "event_timestamp"))
+	if !ok {
+		return nil, fmt.Errorf("event_timestamp is required")
+	}
+	req.Timestamp = timestamp
+	req.Date = time.UnixMicro(timestamp).UTC().Format(time.RFC3339)
+	if previous, ok := ga4Int(take(record, "event_previous_timestamp")); ok {
+		req.PreviousTimestamp = previous
+	}
+
+	// platform is kept as a param too, since channels are coarser than platforms
+	platform, _ := ga4String(record["platform"])
+	req.ChannelType = ga4ChannelType(platform)
+
+	req.UserID, _ = ga4String(take(record, "user_id"))
+	req.UserPseudoID, _ = ga4String(take(record, "user_pseudo_id"))
+
+	var extra []dto.ParamRequest
+	req.EventParams = mapGA4Params(take(record, "event_params"))
+
+	for _, prop := range ga4List(take(record, "user_properties")) {
+		param, ok := mapGA4Param(prop)
+		if !ok {
+			continue
+		}
+		req.UserParams = append(req.UserParams, param)
+		if micros, ok := ga4Int(ga4Map(ga4Map(prop)["value"])["set_timestamp_micros"]); ok {
+			extra = flattenGA4(extra, "user_properties."+param.Key+".set_timestamp_micros", micros)
+		}
+	}
+
+	if device := ga4Map(take(record, "device")); device != nil {
+		req.Device = mapGA4Device(device)
+		extra = flattenGA4(extra, "device", device)
+	}
+
+	if geo := ga4Map(take(record, "geo")); geo != nil {
+		req.Geo = dto.GeoRequest{
+			Continent:    takeString(geo, "continent"),
+			SubContinent: takeString(geo, "sub_continent"),
+			Country:      takeString(geo, "country"),
+			Region:       takeString(geo, "region"),
+			Metro:        takeString(geo, "metro"),
+			City:         takeString(geo, "city"),
+		}
+		extra = flattenGA4(extra, "geo", geo)
+	}
+
+	if appInfo := ga4Map(take(record, "app_info")); appInfo != nil {
+		req.AppInfo = dto.AppInfoRequest{
+			ID:      takeString(appInfo, "id"),
+			Version: takeString(appInfo, "version"),
+		}
+		extra = flattenGA4(extra, "app_info", appInfo)
+	}
+
+	for _, value := range ga4List(take(record, "items")) {
+		if item := ga4Map(value); item != nil {
+			req.Items = append(req.Items, mapGA4Item(item))
+		}
+	}
+
+	for _, key := range sortedKeys(record) {
+		extra = flattenGA4(extra, key, record[key])
+	}
+	req.EventParams = append(req.EventParams, extra...)
+
+	return &req, nil
+}
+
+// ga4ChannelType maps a GA4 platform to a channel type
+func ga4ChannelType(platform string) string {
+	switch strings.ToUpper(platform) {
+	case "WEB":
+		return "web"
+	case "IOS", "ANDROID":
+		return "mobile"
+	default:
+		return "other"
+	}
+}
+
+// mapGA4Device takes the device fields with a place in the event schema out
+// of device. Web browser details live under device.web_info in current
+// exports and directly on device in older ones.
+func mapGA4Device(device map[string]any) dto.DeviceRequest {
+	req := dto.DeviceRequest{
+		Category:               takeString(device, "category"),
+		MobileBrandName:        takeString(device, "mobile_brand_name"),
+		MobileModelName:        takeString(device, "mobile_model_name"),
+		OperatingSystem:        takeString(device, "operating_system"),
+		OperatingSystemVersion: takeString(device, "operating_system_version"),
+		Language:               takeString(device, "language"),
+	}
+
+	if webInfo := ga4Map(device["web_info"]); webInfo != nil {
+		req.BrowserName = takeString(webInfo, "browser")
+		req.BrowserVersion = takeString(webInfo, "browser_version")
+		req.Hostname = takeString(webInfo, "hostname")
+	}
+	if req.BrowserName == "" {
+		req.BrowserName = takeString(device, "browser")
+	}
+	if req.BrowserVersion == "" {
+		req.BrowserVersion = takeString(device, "browser_version")
+	}
+	return req
+}
+
+// mapGA4Item converts a GA4 item; fields with no item field become item params
+func mapGA4Item(item map[string]any) dto.ItemRequest {
+	req := dto.ItemRequest{
+		ID:            takeString(item, "item_id"),
+		Name:          takeString(item, "item_name"),
+		Brand:         takeString(item, "item_brand"),
+		Variant:       takeString(item, "item_variant"),
+		LocationId:    takeString(item, "location_id"),
+		ListId:        takeString(item, "item_list_id"),
+		ListName:      takeString(item, "item_list_name"),
+		PromotionId:   takeString(item, "promotion_id"),
+		PromotionName: takeString(item, "promotion_name"),
+		Params:        mapGA4Params(take(item, "item_params")),
+	}
+	if price, ok := ga4Float(take(item, "price_in_usd")); ok {
+		req.PriceInUsd = price
+	}
+	if revenue, ok := ga4Float(take(item, "item_revenue_in_usd")); ok {
+		req.RevenueInUsd = revenue
+	}
+	if quantity, ok := ga4Int(item["quantity"]); ok && quantity >= math.MinInt32 && quantity <= math.MaxInt32 {
+		req.Quantity = int(quantity)
+		delete(item, "quantity")
+	}
+
+	for _, key := range sortedKeys(item) {
+		req.Params = flattenGA4(req.Params, key, item[key])
+	}
+	return req
+}
+
+// mapGA4Params converts a GA4 key/value param array
+func mapGA4Params(value any) []dto.ParamRequest {
+	var params []dto.ParamRequest
+	for _, entry := range ga4List(value) {
+		if param, ok := mapGA4Param(entry); ok {
+			params = append(params, param)
+		}
+	}
+	return params
+}
+
+// mapGA4Param converts a GA4 {key, value: {string_value, int_value,
+// float_value, double_value}} entry. GA4 sets at most one of the values.
+func mapGA4Param(entry any) (dto.ParamRequest, bool) {
+	fields := ga4Map(entry)
+	key, ok := ga4String(fields["key"])
+	if !ok || key == "" {
+		return dto.ParamRequest{}, false
+	}
+
+	param := dto.ParamRequest{Key: key}
+	value := ga4Map(fields["value"])
+	if s, ok := ga4String(value["string_value"]); ok {
+		param.StringValue = s
+	}
+	if n, ok := ga4Int(value["int_value"]); ok {
+		param.NumberValue = float64(n)
+		if !exactFloat(n) {
+			param.StringValue = strconv.FormatInt(n, 10)
+		}
+	}
+	for _, field := range []string{"float_value", "double_value"} {
+		if f, ok := ga4Float(value[field]); ok {
+			param.NumberValue = f
+		}
+	}
+	return param, true
+}
+
+// ga4IntegerFields lists the INT64 export fields, by path, that have no
+// dedicated event field. The JSON export writes INT64 values as strings, so
+// these are parsed to keep params the same for JSON and Avro exports.
+var ga4IntegerFields = map[string]bool{
+	"event_bundle_sequence_id":        true,
+	"event_server_timestamp_offset":   true,
+	"user_first_touch_timestamp":      true,
+	"batch_event_index":               true,
+	"batch_ordering_id":               true,
+	"batch_page_id":                   true,
+	"device.time_zone_offset_seconds": true,
+	"ecommerce.total_item_quantity":   true,
+	"ecommerce.unique_items":          true,
+	"quantity":                        true,
+}
+
+// flattenGA4 appends value as params keyed by its path under ga4ParamPrefix.
+// Objects are walked field by field; arrays are stored as JSON strings.
+// Nulls are skipped because GA4 uses them for fields that were not collected.
+func flattenGA4(params []dto.ParamRequest, path string, value any) []dto.ParamRequest {
+	key := ga4ParamPrefix + path
+	if s, ok := value.(string); ok && ga4IntegerFields[path] {
+		if n, ok := ga4Int(s); ok {
+			value = n
+		}
+	}
+	switch v := value.(type) {
+	case nil:
+		return params
+	case map[string]any:
+		for _, field := range sortedKeys(v) {
+			params = flattenGA4(params, path+"."+field, v[field])
+		}
+		return params
+	case []any:
+		if len(v) == 0 {
+			return params
+		}
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return params
+		}
+		return append(params, dto.ParamRequest{Key: key, StringValue: string(encoded)})
+	case bool:
+		return append(params, dto.ParamRequest{Key: key, BooleanValue: v})
+	case string:
+		return append(params, dto.ParamRequest{Key: key, StringValue: v})
+	case []byte:
+		return append(params, dto.ParamRequest{Key: key, StringValue: string(v)})
+	}
+
+	if n, ok := ga4Int(value); ok {
+		param := dto.ParamRequest{Key: key, NumberValue: float64(n)}
+		if !exactFloat(n) {
+			param.StringValue = strconv.FormatInt(n, 10)
+		}
+		return append(params, param)
+	}
+	if f, ok := ga4Float(value); ok {
+		return append(params, dto.ParamRequest{Key: key, NumberValue: f})
+	}
+	return append(params, dto.ParamRequest{Key: key, StringValue: fmt.Sprint(value)})
+}
+
+// take removes and returns a field
+func take(fields map[string]any, key string) any {
+	value := fields[key]
+	delete(fields, key)
+	return value
+}
+
+// takeString removes a string field and returns it. Fields of another type
+// are left in place so they are still flattened into params.
+func takeString(fields map[string]any, key string) string {
+	s, ok := ga4String(fields[key])
+	if !ok {
+		return ""
+	}
+	delete(fields, key)
+	return s
+}
+
+func sortedKeys(fields map[string]any) []string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func exactFloat(n int64) bool {
+	return n >= -maxExactFloat && n <= maxExactFloat
+}
+
+// The helpers below read GA4 values decoded from either export format.
+// BigQuery writes INT64 values as JSON strings and Avro decodes them as int64.
+
+func ga4Map(value any) map[string]any {
+	fields, _ := value.(map[string]any)
+	return fields
+}
+
+func ga4List(value any) []any {
+	list, _ := value.([]any)
+	return list
+}
+
+func ga4String(value any) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case []byte:
+		return string(v), true
+	}
+	return "", false
+}
+
+func ga4Int(value any) (int64, bool) {
+	switch v := value.(type) {
+	case int64:
+		return v, true
+	case int32:
+		return int64(v), true
+	case int:
+		return int64(v), true
+	case json.Number:
+		n, err := v.Int64()
+		return n, err == nil
+	case string:
+		n, err := strconv.ParseInt(v, 10, 64)
+		return n, err == nil
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) <= maxExactFloat {
+			return int64(v), true
+		}
+	}
+	return 0, false
+}
+
+func ga4Float(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
+	}
+	if n, ok := ga4Int(value); ok {
+		return float64(n), true
+	}
+	return 0, false
+}
+
+// marshalGA4 encodes a decoded record for the error report
+func marshalGA4(record map[string]any) string {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(record); err != nil {
+		return fmt.Sprint(record)
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
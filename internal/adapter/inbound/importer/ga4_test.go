@@ -0,0 +1,375 @@
+package importer
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ebubekir/event-stream/internal/adapter/inbound/http/dto"
+)
+
+// decodeGA4 decodes a GA4 JSON export line like GA4JSONSource does
+func decodeGA4(t *testing.T, line string) map[string]any {
+	t.Helper()
+
+	decoder := json.NewDecoder(strings.NewReader(line))
+	decoder.UseNumber()
+	var record map[string]any
+	if err := decoder.Decode(&record); err != nil {
+		t.Fatal(err)
+	}
+	return record
+}
+
+func TestGA4Int(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  any
+		want   int64
+		wantOK bool
+	}{
+		{name: "JSON string", value: "1705312800000000", want: 1705312800000000, wantOK: true},
+		{name: "JSON number", value: json.Number("42"), want: 42, wantOK: true},
+		{name: "Avro int64", value: int64(-7), want: -7, wantOK: true},
+		{name: "Avro int32", value: int32(7), want: 7, wantOK: true},
+		{name: "above 2^53", value: "9007199254740993", want: 9007199254740993, wantOK: true},
+		{name: "whole float", value: 3.0, want: 3, wantOK: true},
+		{name: "fractional float", value: 3.5},
+		{name: "float beyond 2^53", value: 1e17},
+		{name: "decimal string", value: "1.5"},
+		{name: "null", value: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ga4Int(tt.value)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("ga4Int(%v) = %d, %v, want %d, %v", tt.value, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestMapGA4Param(t *testing.T) {
+	tests := []struct {
+		name   string
+		entry  string
+		want   dto.ParamRequest
+		wantOK bool
+	}{
+		{
+			name:   "string value",
+			entry:  `{"key": "page_title", "value": {"string_value": "Home", "int_value": null, "float_value": null, "double_value": null}}`,
+			want:   dto.ParamRequest{Key: "page_title", StringValue: "Home"},
+			wantOK: true,
+		},
+		{
+			name:   "int value encoded as a string",
+			entry:  `{"key": "ga_session_id", "value": {"string_value": null, "int_value": "1705312800", "float_value": null, "double_value": null}}`,
+			want:   dto.ParamRequest{Key: "ga_session_id", NumberValue: 1705312800},
+			wantOK: true,
+		},
+		{
+			name:   "int value encoded as a number",
+			entry:  `{"key": "engagement_time_msec", "value": {"int_value": 1250}}`,
+			want:   dto.ParamRequest{Key: "engagement_time_msec", NumberValue: 1250},
+			wantOK: true,
+		},
+		{
+			name:   "int value above 2^53 keeps its digits",
+			entry:  `{"key": "order_id", "value": {"int_value": "9007199254740993"}}`,
+			want:   dto.ParamRequest{Key: "order_id", NumberValue: 9007199254740992, StringValue: "9007199254740993"},
+			wantOK: true,
+		},
+		{
+			name:   "int value at 2^53 is exact",
+			entry:  `{"key": "order_id", "value": {"int_value": 9007199254740992}}`,
+			want:   dto.ParamRequest{Key: "order_id", NumberValue: 9007199254740992},
+			wantOK: true,
+		},
+		{
+			name:   "double value",
+			entry:  `{"key": "value", "value": {"string_value": null, "int_value": null, "float_value": null, "double_value": 59.9}}`,
+			want:   dto.ParamRequest{Key: "value", NumberValue: 59.9},
+			wantOK: true,
+		},
+		{
+			name:   "all values null",
+			entry:  `{"key": "campaign", "value": {"string_value": null, "int_value": null, "float_value": null, "double_value": null}}`,
+			want:   dto.ParamRequest{Key: "campaign"},
+			wantOK: true,
+		},
+		{
+			name:  "missing key",
+			entry: `{"value": {"string_value": "x"}}`,
+		},
+		{
+			name:  "null key",
+			entry: `{"key": null, "value": {"string_value": "x"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := mapGA4Param(decodeGA4(t, tt.entry))
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("mapGA4Param() = %+v, %v, want %+v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestFlattenGA4(t *testing.T) {
+	tests := []struct {
+		name  string
+		path  string
+		value string
+		want  []dto.ParamRequest
+	}{
+		{
+			name:  "nested fields",
+			path:  "traffic_source",
+			value: `{"source": "google", "medium": "organic"}`,
+			want: []dto.ParamRequest{
+				{Key: "ga4.traffic_source.medium", StringValue: "organic"},
+				{Key: "ga4.traffic_source.source", StringValue: "google"},
+			},
+		},
+		{
+			name:  "null fields are skipped",
+			path:  "traffic_source",
+			value: `{"source": "google", "medium": null, "name": null}`,
+			want:  []dto.ParamRequest{{Key: "ga4.traffic_source.source", StringValue: "google"}},
+		},
+		{
+			name:  "integer field encoded as a string",
+			path:  "event_bundle_sequence_id",
+			value: `"1234"`,
+			want:  []dto.ParamRequest{{Key: "ga4.event_bundle_sequence_id", NumberValue: 1234}},
+		},
+		{
+			name:  "integer field above 2^53",
+			path:  "batch_ordering_id",
+			value: `"9007199254740993"`,
+			want:  []dto.ParamRequest{{Key: "ga4.batch_ordering_id", NumberValue: 9007199254740992, StringValue: "9007199254740993"}},
+		},
+		{
+			name:  "other strings stay strings",
+			path:  "event_date",
+			value: `"20240115"`,
+			want:  []dto.ParamRequest{{Key: "ga4.event_date", StringValue: "20240115"}},
+		},
+		{
+			name:  "arrays are JSON",
+			path:  "privacy_info.consents",
+			value: `["ads", "analytics"]`,
+			want:  []dto.ParamRequest{{Key: "ga4.privacy_info.consents", StringValue: `["ads","analytics"]`}},
+		},
+		{
+			name:  "empty arrays are skipped",
+			path:  "privacy_info.consents",
+			value: `[]`,
+		},
+		{
+			name:  "booleans",
+			path:  "is_active_user",
+			value: `true`,
+			want:  []dto.ParamRequest{{Key: "ga4.is_active_user", BooleanValue: true}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value := decodeGA4(t, `{"v": `+tt.value+`}`)["v"]
+			if got := flattenGA4(nil, tt.path, value); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("flattenGA4() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMapGA4(t *testing.T) {
+	const base = `"event_name": "purchase", "event_timestamp": "1705312800000000", "platform": "WEB"`
+
+	tests := []struct {
+		name  string
+		line  string
+		check func(t *testing.T, req *Request)
+	}{
+		{
+			name: "event fields",
+			line: `{` + base + `, "event_previous_timestamp": null, "user_id": "user-1", "user_pseudo_id": "pseudo-1"}`,
+			check: func(t *testing.T, req *Request) {
+				if req.Name != "purchase" || req.Timestamp != 1705312800000000 || req.Date != "2024-01-15T10:00:00Z" || req.ChannelType != "web" {
+					t.Errorf("event = %+v", req.CreateEventRequest)
+				}
+				if req.PreviousTimestamp != 0 || req.UserID != "user-1" || req.UserPseudoID != "pseudo-1" {
+					t.Errorf("event = %+v", req.CreateEventRequest)
+				}
+				want := []dto.ParamRequest{{Key: "ga4.platform", StringValue: "WEB"}}
+				if !reflect.DeepEqual(req.EventParams, want) {
+					t.Errorf("event params = %+v, want %+v", req.EventParams, want)
+				}
+			},
+		},
+		{
+			name: "user properties",
+			line: `{` + base + `, "user_properties": [
+				{"key": "plan", "value": {"string_value": "pro", "int_value": null, "float_value": null, "double_value": null, "set_timestamp_micros": "1705312700000000"}},
+				{"key": "logins", "value": {"string_value": null, "int_value": "12", "set_timestamp_micros": null}},
+				{"key": null, "value": {"string_value": "dropped"}}
+			]}`,
+			check: func(t *testing.T, req *Request) {
+				wantUser := []dto.ParamRequest{
+					{Key: "plan", StringValue: "pro"},
+					{Key: "logins", NumberValue: 12},
+				}
+				if !reflect.DeepEqual(req.UserParams, wantUser) {
+					t.Errorf("user params = %+v, want %+v", req.UserParams, wantUser)
+				}
+				want := dto.ParamRequest{Key: "ga4.user_properties.plan.set_timestamp_micros", NumberValue: 1705312700000000}
+				if !slices.Contains(req.EventParams, want) {
+					t.Errorf("event params = %+v, want %+v", req.EventParams, want)
+				}
+				for _, param := range req.EventParams {
+					if strings.HasPrefix(param.Key, "ga4.user_properties.logins") {
+						t.Errorf("unexpected %+v for a null set_timestamp_micros", param)
+					}
+				}
+			},
+		},
+		{
+			name: "browser from web_info",
+			line: `{` + base + `, "device": {"category": "desktop", "language": "en-us", "web_info": {"browser": "Safari", "browser_version": "17.2", "hostname": "shop.example.com"}, "browser": null, "time_zone_offset_seconds": "3600"}}`,
+			check: func(t *testing.T, req *Request) {
+				want := dto.DeviceRequest{Category: "desktop", Language: "en-us", BrowserName: "Safari", BrowserVersion: "17.2", Hostname: "shop.example.com"}
+				if req.Device != want {
+					t.Errorf("device = %+v, want %+v", req.Device, want)
+				}
+				wantParams := []dto.ParamRequest{
+					{Key: "ga4.device.time_zone_offset_seconds", NumberValue: 3600},
+					{Key: "ga4.platform", StringValue: "WEB"},
+				}
+				if !reflect.DeepEqual(req.EventParams, wantParams) {
+					t.Errorf("event params = %+v, want %+v", req.EventParams, wantParams)
+				}
+			},
+		},
+		{
+			name: "browser from device in older exports",
+			line: `{` + base + `, "device": {"category": "mobile", "browser": "Chrome", "browser_version": "120.0", "web_info": null}}`,
+			check: func(t *testing.T, req *Request) {
+				want := dto.DeviceRequest{Category: "mobile", BrowserName: "Chrome", BrowserVersion: "120.0"}
+				if req.Device != want {
+					t.Errorf("device = %+v, want %+v", req.Device, want)
+				}
+			},
+		},
+		{
+			name: "browser version from device when web_info lacks it",
+			line: `{` + base + `, "device": {"browser": "Chrome", "browser_version": "120.0", "web_info": {"browser": "Safari", "browser_version": null}}}`,
+			check: func(t *testing.T, req *Request) {
+				if req.Device.BrowserName != "Safari" || req.Device.BrowserVersion != "120.0" {
+					t.Errorf("device = %+v, want Safari 120.0", req.Device)
+				}
+				want := dto.ParamRequest{Key: "ga4.device.browser", StringValue: "Chrome"}
+				if !slices.Contains(req.EventParams, want) {
+					t.Errorf("event params = %+v, want the unused device browser %+v", req.EventParams, want)
+				}
+			},
+		},
+		{
+			name: "app info",
+			line: `{"event_name": "screen_view", "event_timestamp": "1705312800000000", "platform": "IOS", "app_info": {"id": "com.example.shop", "version": "2.1.0", "install_source": "manual_install", "firebase_app_id": null}}`,
+			check: func(t *testing.T, req *Request) {
+				want := dto.AppInfoRequest{ID: "com.example.shop", Version: "2.1.0"}
+				if req.AppInfo != want || req.ChannelType != "mobile" {
+					t.Errorf("app info = %+v, channel %s, want %+v, mobile", req.AppInfo, req.ChannelType, want)
+				}
+				wantParams := []dto.ParamRequest{
+					{Key: "ga4.app_info.install_source", StringValue: "manual_install"},
+					{Key: "ga4.platform", StringValue: "IOS"},
+				}
+				if !reflect.DeepEqual(req.EventParams, wantParams) {
+					t.Errorf("event params = %+v, want %+v", req.EventParams, wantParams)
+				}
+			},
+		},
+		{
+			name: "app info of a web event",
+			line: `{` + base + `, "app_info": null}`,
+			check: func(t *testing.T, req *Request) {
+				if req.AppInfo != (dto.AppInfoRequest{}) {
+					t.Errorf("app info = %+v, want none", req.AppInfo)
+				}
+			},
+		},
+		{
+			name: "items",
+			line: `{` + base + `, "items": [{
+				"item_id": "sku-1", "item_name": "Shoe", "item_brand": "Acme", "item_variant": null,
+				"price_in_usd": 29.95, "quantity": "2", "item_revenue_in_usd": 59.9,
+				"item_category": "Footwear", "item_category2": null, "coupon": "WINTER",
+				"item_params": [{"key": "size", "value": {"int_value": "42"}}]
+			}, null]}`,
+			check: func(t *testing.T, req *Request) {
+				want := []dto.ItemRequest{{
+					ID: "sku-1", Name: "Shoe", Brand: "Acme", PriceInUsd: 29.95, Quantity: 2, RevenueInUsd: 59.9,
+					Params: []dto.ParamRequest{
+						{Key: "size", NumberValue: 42},
+						{Key: "ga4.coupon", StringValue: "WINTER"},
+						{Key: "ga4.item_category", StringValue: "Footwear"},
+					},
+				}}
+				if !reflect.DeepEqual(req.Items, want) {
+					t.Errorf("items = %+v, want %+v", req.Items, want)
+				}
+			},
+		},
+		{
+			name: "item quantity beyond int32 is kept as a param",
+			line: `{` + base + `, "items": [{"item_id": "sku-1", "quantity": "9007199254740993"}]}`,
+			check: func(t *testing.T, req *Request) {
+				want := []dto.ItemRequest{{
+					ID:     "sku-1",
+					Params: []dto.ParamRequest{{Key: "ga4.quantity", NumberValue: 9007199254740992, StringValue: "9007199254740993"}},
+				}}
+				if !reflect.DeepEqual(req.Items, want) {
+					t.Errorf("items = %+v, want %+v", req.Items, want)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := mapGA4(decodeGA4(t, tt.line))
+			if err != nil {
+				t.Fatalf("mapGA4() error = %v", err)
+			}
+			tt.check(t, req)
+		})
+	}
+}
+
+func TestMapGA4Required(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+	}{
+		{name: "no event name", line: `{"event_timestamp": "1705312800000000"}`},
+		{name: "null event name", line: `{"event_name": null, "event_timestamp": "1705312800000000"}`},
+		{name: "no timestamp", line: `{"event_name": "purchase"}`},
+		{name: "timestamp not an integer", line: `{"event_name": "purchase", "event_timestamp": "yesterday"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := mapGA4(decodeGA4(t, tt.line)); err == nil {
+				t.Error("mapGA4() error = nil, want an error")
+			}
+		})
+	}
+}
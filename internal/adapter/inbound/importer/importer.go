@@ -0,0 +1,322 @@
+package importer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/ebubekir/event-stream/internal/application/event"
+	eventDomain "github.com/ebubekir/event-stream/internal/domain/event"
+	"github.com/ebubekir/event-stream/pkg/logger"
+)
+
+const (
+	defaultBatchSize = 1000
+	defaultWorkers   = 4
+	retryBackoff     = time.Second
+	progressInterval = 10 * time.Second
+)
+
+// Config tunes an import run
+type Config struct {
+	// Source names the input in event IDs, dedup tokens and ingestion metadata
+	Source string
+	// BatchSize is the number of records stored per SaveBatch call
+	BatchSize int
+	// Workers is the number of batches stored concurrently
+	Workers int
+	// Skip skips records up to and including this offset, to resume an earlier run
+	Skip int64
+	// DryRun validates records without storing them
+	DryRun bool
+	// MaxRetries is the number of retries for a batch that failed to store
+	MaxRetries int
+	// ErrorReport receives one JSON line per invalid record; nil discards them
+	ErrorReport io.Writer
+}
+
+// Summary is the outcome of an import run
+type Summary struct {
+	Read     int64 // records read after the skipped ones
+	Skipped  int64 // records skipped by Config.Skip
+	Imported int64 // records stored, or valid records in a dry run
+	Invalid  int64 // records written to the error report
+	// Committed is the offset up to which every record was stored or reported.
+	// A failed run resumes without gaps or duplicates with Config.Skip set to it.
+	Committed int64
+}
+
+// ErrorRecord is a line of the error report
+type ErrorRecord struct {
+	Offset int64  `json:"offset"`
+	Error  string `json:"error"`
+	Record string `json:"record,omitempty"`
+}
+
+// Importer validates input records like the HTTP API and stores them through
+// EventService.ImportEvents, which writes with EventRepository.SaveBatch.
+//
+// Events without an ID get one derived from the source name and offset, and
+// batches are written with eventDomain.WithSkipStored, so importing the same
+// file twice does not duplicate events. Batches cover offset ranges aligned
+// to BatchSize and also carry a matching dedup token.
+type Importer struct {
+	service *event.EventService
+	config  Config
+}
+
+// NewImporter creates a new Importer
+func NewImporter(service *event.EventService, config Config) *Importer {
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaultBatchSize
+	}
+	if config.Workers <= 0 {
+		config.Workers = defaultWorkers
+	}
+
+	return &Importer{
+		service: service,
+		config:  config,
+	}
+}
+
+// batch is a run of records whose offsets fall in the same BatchSize-aligned range
+type batch struct {
+	seq   int
+	key   int64
+	first int64
+	last  int64
+	cmds  []*event.CreateEventCommand
+}
+
+// progress tracks completed batches to compute the committed offset
+type progress struct {
+	mu        sync.Mutex
+	done      map[int]int64
+	next      int
+	committed int64
+	imported  int64
+}
+
+func (p *progress) complete(b *batch) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.imported += int64(len(b.cmds))
+	p.done[b.seq] = b.last
+	for {
+		last, ok := p.done[p.next]
+		if !ok {
+			return
+		}
+		delete(p.done, p.next)
+		p.committed = last
+		p.next++
+	}
+}
+
+func (p *progress) snapshot() (committed, imported int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.committed, p.imported
+}
+
+// Run imports every record of src. It stops at the first batch that cannot be
+// stored after retries; the returned summary is valid either way.
+func (i *Importer) Run(parent context.Context, src Source) (*Summary, error) {
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+
+	summary := &Summary{}
+	prog := &progress{done: make(map[int]int64), committed: i.config.Skip}
+
+	var (
+		errOnce  sync.Once
+		storeErr error
+	)
+	fail := func(err error) {
+		errOnce.Do(func() {
+			storeErr = err
+			cancel()
+		})
+	}
+
+	batches := make(chan *batch, i.config.Workers)
+	var workers sync.WaitGroup
+	for range i.config.Workers {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for b := range batches {
+				if err := i.store(ctx, b); err != nil {
+					fail(err)
+					continue
+				}
+				prog.complete(b)
+			}
+		}()
+	}
+
+	ticker := time.NewTicker(progressInterval)
+	defer ticker.Stop()
+
+	readErr := i.read(ctx, src, summary, batches, ticker.C, prog)
+	close(batches)
+	workers.Wait()
+
+	summary.Committed, summary.Imported = prog.snapshot()
+
+	switch {
+	case storeErr != nil:
+		return summary, storeErr
+	case readErr != nil:
+		return summary, readErr
+	case parent.Err() != nil:
+		return summary, parent.Err()
+	default:
+		return summary, nil
+	}
+}
+
+// read decodes records into batches and hands them to the workers
+func (i *Importer) read(ctx context.Context, src Source, summary *Summary, batches chan<- *batch, tick <-chan time.Time, prog *progress) error {
+	var current *batch
+	seq := 0
+
+	dispatch := func() bool {
+		if current == nil {
+			return true
+		}
+		select {
+		case batches <- current:
+			current = nil
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	for {
+		select {
+		case <-tick:
+			committed, imported := prog.snapshot()
+			logger.Info("import progress", zap.Int64("read", summary.Read), zap.Int64("imported", imported),
+				zap.Int64("invalid", summary.Invalid), zap.Int64("committed_offset", committed))
+		default:
+		}
+
+		row, err := src.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			dispatch()
+			return fmt.Errorf("failed to read input after offset %d: %w", summary.Read+summary.Skipped, err)
+		}
+
+		if row.Offset <= i.config.Skip {
+			summary.Skipped++
+			continue
+		}
+		summary.Read++
+
+		key := (row.Offset - 1) / int64(i.config.BatchSize)
+		if current != nil && current.key != key {
+			if !dispatch() {
+				return nil
+			}
+		}
+		if current == nil {
+			current = &batch{seq: seq, key: key, first: row.Offset}
+			seq++
+		}
+		current.last = row.Offset
+
+		cmd, err := i.toCommand(row)
+		if err != nil {
+			summary.Invalid++
+			if err := i.report(row, err); err != nil {
+				return err
+			}
+			continue
+		}
+		current.cmds = append(current.cmds, cmd)
+	}
+
+	dispatch()
+	return nil
+}
+
+// toCommand validates a row with the HTTP binding rules and converts it
+func (i *Importer) toCommand(row Row) (*event.CreateEventCommand, error) {
+	if row.Err != nil {
+		return nil, row.Err
+	}
+	if err := binding.Validator.ValidateStruct(&row.Request.CreateEventRequest); err != nil {
+		return nil, err
+	}
+
+	cmd := row.Request.ToCommand()
+	cmd.ID = row.Request.ID
+	if cmd.ID == "" {
+		cmd.ID = uuid.NewSHA1(uuid.NameSpaceURL, []byte(fmt.Sprintf("%s/%d", i.sourceURI(), row.Offset))).String()
+	}
+	cmd.Ingestion = event.IngestionMetadataDTO{
+		ReceivedAt: time.Now().UTC(),
+		Endpoint:   i.sourceURI(),
+	}
+	return cmd, nil
+}
+
+// store saves a batch, retrying failures with backoff
+func (i *Importer) store(ctx context.Context, b *batch) error {
+	if i.config.DryRun || len(b.cmds) == 0 {
+		return nil
+	}
+
+	token := fmt.Sprintf("%s/%d-%d", i.sourceURI(), b.first, b.last)
+	backoff := retryBackoff
+	for attempt := 0; ; attempt++ {
+		_, err := i.service.ImportEvents(eventDomain.WithSkipStored(eventDomain.WithDedupToken(ctx, token)), b.cmds)
+		if err == nil {
+			return nil
+		}
+		if attempt >= i.config.MaxRetries || ctx.Err() != nil {
+			return fmt.Errorf("failed to import records %d-%d: %w", b.first, b.last, err)
+		}
+
+		logger.Warn("import batch failed, retrying", zap.Int64("first", b.first), zap.Int64("last", b.last), zap.Error(err))
+		select {
+		case <-time.After(backoff):
+			backoff *= 2
+		case <-ctx.Done():
+			return fmt.Errorf("failed to import records %d-%d: %w", b.first, b.last, err)
+		}
+	}
+}
+
+func (i *Importer) report(row Row, err error) error {
+	if i.config.ErrorReport == nil {
+		return nil
+	}
+
+	line, marshalErr := json.Marshal(ErrorRecord{Offset: row.Offset, Error: err.Error(), Record: row.Raw})
+	if marshalErr != nil {
+		return marshalErr
+	}
+	if _, err := i.config.ErrorReport.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write error report: %w", err)
+	}
+	return nil
+}
+
+func (i *Importer) sourceURI() string {
+	return "import://" + i.config.Source
+}
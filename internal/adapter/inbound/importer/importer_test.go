@@ -0,0 +1,243 @@
+package importer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"slices"
+	"sync"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/ebubekir/event-stream/internal/adapter/inbound/http/dto"
+	"github.com/ebubekir/event-stream/internal/application/event"
+	"github.com/ebubekir/event-stream/internal/domain"
+	eventDomain "github.com/ebubekir/event-stream/internal/domain/event"
+	"github.com/ebubekir/event-stream/pkg/logger"
+)
+
+func init() {
+	logger.Log = zap.NewNop()
+}
+
+// rowSource returns rows in order, then io.EOF
+type rowSource struct {
+	rows []Row
+}
+
+func (s *rowSource) Next() (Row, error) {
+	if len(s.rows) == 0 {
+		return Row{}, io.EOF
+	}
+	row := s.rows[0]
+	s.rows = s.rows[1:]
+	return row, nil
+}
+
+func (s *rowSource) Close() error { return nil }
+
+// eventStore keeps saved events by ID and, like the real stores, drops the
+// ones already stored from a write marked WithSkipStored
+type eventStore struct {
+	eventDomain.EventRepository
+
+	mu     sync.Mutex
+	events []*domain.Event
+}
+
+func (s *eventStore) SaveBatch(ctx context.Context, events []*domain.Event) ([]*domain.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var saved []*domain.Event
+	for _, event := range events {
+		if eventDomain.SkipStored(ctx) && slices.ContainsFunc(s.events, func(e *domain.Event) bool { return e.ID == event.ID }) {
+			continue
+		}
+		saved = append(saved, event)
+	}
+	s.events = append(s.events, saved...)
+	return saved, nil
+}
+
+func validRow(offset int64) Row {
+	return Row{Offset: offset, Request: &Request{CreateEventRequest: dto.CreateEventRequest{Name: "page_view", ChannelType: "web"}}}
+}
+
+func invalidRow(offset int64) Row {
+	return Row{Offset: offset, Raw: "{", Err: errors.New("unexpected end of JSON input")}
+}
+
+func TestImporterReadBatches(t *testing.T) {
+	tests := []struct {
+		name        string
+		skip        int64
+		rows        []Row
+		wantRanges  [][2]int64
+		wantCmds    []int
+		wantSkipped int64
+		wantInvalid int64
+	}{
+		{
+			name:       "aligned to batch size",
+			rows:       []Row{validRow(1), validRow(2), validRow(3), validRow(4), validRow(5), validRow(6), validRow(7)},
+			wantRanges: [][2]int64{{1, 3}, {4, 6}, {7, 7}},
+			wantCmds:   []int{3, 3, 1},
+		},
+		{
+			name:       "offset gaps keep ranges",
+			rows:       []Row{validRow(1), validRow(2), validRow(5), validRow(9)},
+			wantRanges: [][2]int64{{1, 2}, {5, 5}, {9, 9}},
+			wantCmds:   []int{2, 1, 1},
+		},
+		{
+			name:        "invalid records stay in their range",
+			rows:        []Row{validRow(1), invalidRow(2), invalidRow(3), validRow(4)},
+			wantRanges:  [][2]int64{{1, 3}, {4, 4}},
+			wantCmds:    []int{1, 1},
+			wantInvalid: 2,
+		},
+		{
+			name:        "resume mid batch",
+			skip:        4,
+			rows:        []Row{validRow(1), validRow(2), validRow(3), validRow(4), validRow(5), validRow(6), validRow(7)},
+			wantRanges:  [][2]int64{{5, 6}, {7, 7}},
+			wantCmds:    []int{2, 1},
+			wantSkipped: 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var report bytes.Buffer
+			imp := NewImporter(nil, Config{Source: "test", BatchSize: 3, Skip: tt.skip, ErrorReport: &report})
+
+			batches := make(chan *batch, len(tt.rows))
+			summary := &Summary{}
+			prog := &progress{done: make(map[int]int64)}
+			if err := imp.read(context.Background(), &rowSource{rows: tt.rows}, summary, batches, nil, prog); err != nil {
+				t.Fatal(err)
+			}
+			close(batches)
+
+			var ranges [][2]int64
+			var cmds []int
+			for b := range batches {
+				if b.seq != len(ranges) {
+					t.Errorf("batch %d-%d has seq %d, want %d", b.first, b.last, b.seq, len(ranges))
+				}
+				ranges = append(ranges, [2]int64{b.first, b.last})
+				cmds = append(cmds, len(b.cmds))
+			}
+			if !slices.Equal(ranges, tt.wantRanges) || !slices.Equal(cmds, tt.wantCmds) {
+				t.Errorf("batches = %v with %v commands, want %v with %v", ranges, cmds, tt.wantRanges, tt.wantCmds)
+			}
+			if summary.Skipped != tt.wantSkipped || summary.Invalid != tt.wantInvalid {
+				t.Errorf("summary = %+v, want %d skipped and %d invalid", summary, tt.wantSkipped, tt.wantInvalid)
+			}
+			if lines := bytes.Count(report.Bytes(), []byte("\n")); int64(lines) != tt.wantInvalid {
+				t.Errorf("error report has %d lines, want %d", lines, tt.wantInvalid)
+			}
+		})
+	}
+}
+
+func TestImporterDerivesStableIDs(t *testing.T) {
+	imp := NewImporter(nil, Config{Source: "events.ndjson"})
+
+	first, err := imp.toCommand(validRow(7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := imp.toCommand(validRow(7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := imp.toCommand(validRow(8))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if first.ID == "" || first.ID != again.ID || first.ID == other.ID {
+		t.Errorf("IDs = %q, %q, %q, want the same ID for the same offset only", first.ID, again.ID, other.ID)
+	}
+
+	row := validRow(9)
+	row.Request.ID = "kept"
+	if cmd, err := imp.toCommand(row); err != nil || cmd.ID != "kept" {
+		t.Errorf("toCommand() = %v, %v, want the record's own ID", cmd, err)
+	}
+}
+
+func TestProgressComplete(t *testing.T) {
+	tests := []struct {
+		name          string
+		order         []int
+		wantCommitted []int64
+	}{
+		{name: "in order", order: []int{0, 1, 2}, wantCommitted: []int64{3, 6, 9}},
+		{name: "out of order", order: []int{1, 2, 0}, wantCommitted: []int64{0, 0, 9}},
+		{name: "gap", order: []int{0, 2, 1}, wantCommitted: []int64{3, 3, 9}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			batches := []*batch{
+				{seq: 0, last: 3, cmds: make([]*event.CreateEventCommand, 2)},
+				{seq: 1, last: 6, cmds: make([]*event.CreateEventCommand, 3)},
+				{seq: 2, last: 9, cmds: make([]*event.CreateEventCommand, 1)},
+			}
+			prog := &progress{done: make(map[int]int64)}
+
+			for i, seq := range tt.order {
+				prog.complete(batches[seq])
+				if committed, _ := prog.snapshot(); committed != tt.wantCommitted[i] {
+					t.Errorf("after batch %d committed = %d, want %d", seq, committed, tt.wantCommitted[i])
+				}
+			}
+			if _, imported := prog.snapshot(); imported != 6 {
+				t.Errorf("imported = %d, want 6", imported)
+			}
+		})
+	}
+}
+
+func TestImporterRunDryRun(t *testing.T) {
+	var report bytes.Buffer
+	imp := NewImporter(nil, Config{Source: "test", BatchSize: 2, DryRun: true, ErrorReport: &report})
+
+	summary, err := imp.Run(context.Background(), &rowSource{rows: []Row{validRow(1), invalidRow(2), validRow(3), validRow(4), validRow(5)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Summary{Read: 5, Imported: 4, Invalid: 1, Committed: 5}
+	if *summary != want {
+		t.Errorf("Run() = %+v, want %+v", *summary, want)
+	}
+
+	var record ErrorRecord
+	if err := json.Unmarshal(bytes.TrimSpace(report.Bytes()), &record); err != nil || record.Offset != 2 || record.Record != "{" {
+		t.Errorf("error report = %q, want the record at offset 2", report.String())
+	}
+}
+
+func TestImporterRerunDoesNotDuplicate(t *testing.T) {
+	store := &eventStore{}
+	service := event.NewEventService(store, nil, nil)
+
+	// The second run rereads the file cut into different batches
+	for _, batchSize := range []int{2, 3} {
+		imp := NewImporter(service, Config{Source: "test", BatchSize: batchSize})
+		if _, err := imp.Run(context.Background(), &rowSource{rows: []Row{validRow(1), validRow(2), validRow(3), validRow(4), validRow(5)}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(store.events) != 5 {
+		t.Errorf("stored %d events, want 5", len(store.events))
+	}
+}
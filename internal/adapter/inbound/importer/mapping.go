@@ -0,0 +1,134 @@
+package importer
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Param value types in a mapping
+const (
+	ParamTypeString  = "string"
+	ParamTypeNumber  = "number"
+	ParamTypeBoolean = "boolean"
+)
+
+// Mapping describes how CSV columns become CreateEventRequest fields:
+//
+//	columns:            # field: CSV column; nested fields use dots
+//	  name: event_name
+//	  user_pseudo_id: anonymous_id
+//	  device.operating_system: os
+//	defaults:           # fixed values for fields without a column
+//	  channel_type: web
+//	event_params:       # columns stored as event params
+//	  - column: page_title
+//	  - column: price
+//	    key: value      # param key, defaults to the column name
+//	    type: number    # string (default), number, boolean
+//	user_params:
+//	  - column: plan
+//
+// Items cannot be mapped; each CSV row is a single event.
+type Mapping struct {
+	Columns     map[string]string `yaml:"columns"`
+	Defaults    map[string]string `yaml:"defaults"`
+	EventParams []ParamMapping    `yaml:"event_params"`
+	UserParams  []ParamMapping    `yaml:"user_params"`
+}
+
+// ParamMapping maps a CSV column to a param
+type ParamMapping struct {
+	Column string `yaml:"column"`
+	Key    string `yaml:"key"`
+	Type   string `yaml:"type"`
+}
+
+// fieldSetters assign a CSV value to a request field, keyed by mapping field name
+var fieldSetters = map[string]func(r *Request, v string) error{
+	"id":                 func(r *Request, v string) error { r.ID = v; return nil },
+	"name":               func(r *Request, v string) error { r.Name = v; return nil },
+	"channel_type":       func(r *Request, v string) error { r.ChannelType = v; return nil },
+	"timestamp":          func(r *Request, v string) error { return parseInt(v, &r.Timestamp) },
+	"previous_timestamp": func(r *Request, v string) error { return parseInt(v, &r.PreviousTimestamp) },
+	"date":               func(r *Request, v string) error { r.Date = v; return nil },
+	"user_id":            func(r *Request, v string) error { r.UserID = v; return nil },
+	"user_pseudo_id":     func(r *Request, v string) error { r.UserPseudoID = v; return nil },
+
+	"device.category":                 func(r *Request, v string) error { r.Device.Category = v; return nil },
+	"device.mobile_brand_name":        func(r *Request, v string) error { r.Device.MobileBrandName = v; return nil },
+	"device.mobile_model_name":        func(r *Request, v string) error { r.Device.MobileModelName = v; return nil },
+	"device.operating_system":         func(r *Request, v string) error { r.Device.OperatingSystem = v; return nil },
+	"device.operating_system_version": func(r *Request, v string) error { r.Device.OperatingSystemVersion = v; return nil },
+	"device.language":                 func(r *Request, v string) error { r.Device.Language = v; return nil },
+	"device.browser_name":             func(r *Request, v string) error { r.Device.BrowserName = v; return nil },
+	"device.browser_version":          func(r *Request, v string) error { r.Device.BrowserVersion = v; return nil },
+	"device.hostname":                 func(r *Request, v string) error { r.Device.Hostname = v; return nil },
+
+	"geo.continent":     func(r *Request, v string) error { r.Geo.Continent = v; return nil },
+	"geo.sub_continent": func(r *Request, v string) error { r.Geo.SubContinent = v; return nil },
+	"geo.country":       func(r *Request, v string) error { r.Geo.Country = v; return nil },
+	"geo.region":        func(r *Request, v string) error { r.Geo.Region = v; return nil },
+	"geo.metro":         func(r *Request, v string) error { r.Geo.Metro = v; return nil },
+	"geo.city":          func(r *Request, v string) error { r.Geo.City = v; return nil },
+
+	"app_info.id":      func(r *Request, v string) error { r.AppInfo.ID = v; return nil },
+	"app_info.version": func(r *Request, v string) error { r.AppInfo.Version = v; return nil },
+}
+
+func parseInt(v string, dst *int64) error {
+	if v == "" {
+		return nil
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return err
+	}
+	*dst = n
+	return nil
+}
+
+// LoadMapping reads and checks a YAML mapping file
+func LoadMapping(path string) (*Mapping, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var mapping Mapping
+	if err := yaml.Unmarshal(data, &mapping); err != nil {
+		return nil, fmt.Errorf("invalid mapping file: %w", err)
+	}
+
+	for field := range mapping.Columns {
+		if _, ok := fieldSetters[field]; !ok {
+			return nil, fmt.Errorf("invalid mapping file: unknown field %q", field)
+		}
+	}
+	for field := range mapping.Defaults {
+		if _, ok := fieldSetters[field]; !ok {
+			return nil, fmt.Errorf("invalid mapping file: unknown default field %q", field)
+		}
+	}
+	for _, params := range [][]ParamMapping{mapping.EventParams, mapping.UserParams} {
+		for i := range params {
+			if params[i].Column == "" {
+				return nil, fmt.Errorf("invalid mapping file: param without column")
+			}
+			if params[i].Key == "" {
+				params[i].Key = params[i].Column
+			}
+			switch params[i].Type {
+			case "":
+				params[i].Type = ParamTypeString
+			case ParamTypeString, ParamTypeNumber, ParamTypeBoolean:
+			default:
+				return nil, fmt.Errorf("invalid mapping file: unknown param type %q", params[i].Type)
+			}
+		}
+	}
+
+	return &mapping, nil
+}
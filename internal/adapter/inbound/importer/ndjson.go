@@ -0,0 +1,82 @@
+package importer
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+)
+
+// maxLineSize bounds a single NDJSON record
+const maxLineSize = 16 << 20
+
+// NDJSONSource reads one CreateEventRequest JSON object per line, such as the
+// files of the raw event archive. Offsets are line numbers; blank lines are skipped.
+type NDJSONSource struct {
+	file    *os.File
+	scanner *bufio.Scanner
+	line    int64
+}
+
+// OpenNDJSON opens an NDJSON file, decompressing it when the name ends in .gz
+func OpenNDJSON(path string) (*NDJSONSource, error) {
+	file, scanner, err := openLines(path)
+	if err != nil {
+		return nil, err
+	}
+	return &NDJSONSource{file: file, scanner: scanner}, nil
+}
+
+// openLines opens a line-oriented file, decompressing it when the name ends in .gz
+func openLines(path string) (*os.File, *bufio.Scanner, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var r io.Reader = file
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			_ = file.Close()
+			return nil, nil, err
+		}
+		r = gz
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64<<10), maxLineSize)
+	return file, scanner, nil
+}
+
+// Next returns the next non-blank line
+func (s *NDJSONSource) Next() (Row, error) {
+	for s.scanner.Scan() {
+		s.line++
+		raw := s.scanner.Text()
+		if strings.TrimSpace(raw) == "" {
+			continue
+		}
+
+		row := Row{Offset: s.line, Raw: raw}
+		var req Request
+		if err := json.Unmarshal([]byte(raw), &req); err != nil {
+			row.Err = err
+		} else {
+			row.Request = &req
+		}
+		return row, nil
+	}
+
+	if err := s.scanner.Err(); err != nil {
+		return Row{}, err
+	}
+	return Row{}, io.EOF
+}
+
+// Close closes the file
+func (s *NDJSONSource) Close() error {
+	return s.file.Close()
+}
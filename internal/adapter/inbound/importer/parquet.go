@@ -0,0 +1,84 @@
+package importer
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/xitongsys/parquet-go-source/local"
+	"github.com/xitongsys/parquet-go/reader"
+	"github.com/xitongsys/parquet-go/source"
+
+	"github.com/ebubekir/event-stream/internal/adapter/eventcodec"
+)
+
+const parquetReadSize = 1000
+
+// ParquetSource reads files with the eventcodec.ParquetRecord schema, as
+// written by the export command. Offsets are row numbers.
+type ParquetSource struct {
+	file   source.ParquetFile
+	reader *reader.ParquetReader
+	rows   []eventcodec.ParquetRecord
+	next   int
+	offset int64
+	remain int64
+}
+
+// OpenParquet opens a Parquet file
+func OpenParquet(path string) (*ParquetSource, error) {
+	file, err := local.NewLocalFileReader(path)
+	if err != nil {
+		return nil, err
+	}
+
+	pr, err := reader.NewParquetReader(file, new(eventcodec.ParquetRecord), 4)
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+
+	return &ParquetSource{file: file, reader: pr, remain: pr.GetNumRows()}, nil
+}
+
+// Next returns the next row
+func (s *ParquetSource) Next() (Row, error) {
+	if s.next == len(s.rows) {
+		if s.remain == 0 {
+			return Row{}, io.EOF
+		}
+
+		s.rows = make([]eventcodec.ParquetRecord, min(s.remain, parquetReadSize))
+		if err := s.reader.Read(&s.rows); err != nil {
+			return Row{}, err
+		}
+		if len(s.rows) == 0 {
+			return Row{}, io.EOF
+		}
+		s.remain -= int64(len(s.rows))
+		s.next = 0
+	}
+
+	record := s.rows[s.next].Record()
+	s.next++
+	s.offset++
+
+	raw, err := json.Marshal(record)
+	if err != nil {
+		return Row{Offset: s.offset, Err: err}, nil
+	}
+
+	row := Row{Offset: s.offset, Raw: string(raw)}
+	var req Request
+	if err := json.Unmarshal(raw, &req); err != nil {
+		row.Err = err
+	} else {
+		row.Request = &req
+	}
+	return row, nil
+}
+
+// Close closes the file
+func (s *ParquetSource) Close() error {
+	s.reader.ReadStop()
+	return s.file.Close()
+}
@@ -0,0 +1,67 @@
+package importer
+
+import (
+	"fmt"
+
+	"github.com/ebubekir/event-stream/internal/adapter/inbound/http/dto"
+)
+
+// Supported input formats
+const (
+	FormatNDJSON  = "ndjson"
+	FormatCSV     = "csv"
+	FormatParquet = "parquet"
+	// GA4 BigQuery exports, as newline-delimited JSON or Avro
+	FormatGA4     = "ga4"
+	FormatGA4Avro = "ga4-avro"
+)
+
+// Request is a decoded input record: a CreateEventRequest plus an optional
+// event ID, so files exported from this service keep their IDs
+type Request struct {
+	ID string `json:"id"`
+	dto.CreateEventRequest
+}
+
+// Row is one input record
+type Row struct {
+	// Offset is the 1-based position of the record in the input
+	Offset int64
+	// Raw is the record as read, for the error report
+	Raw string
+	// Request is the decoded record; nil when Err is set
+	Request *Request
+	// Err is set when the record could not be decoded
+	Err error
+}
+
+// Source reads input records in order. Next returns io.EOF after the last record.
+type Source interface {
+	Next() (Row, error)
+	Close() error
+}
+
+// Open opens path in the given format. mappingPath is required for CSV input.
+func Open(format, path, mappingPath string) (Source, error) {
+	switch format {
+	case FormatNDJSON:
+		return OpenNDJSON(path)
+	case FormatCSV:
+		if mappingPath == "" {
+			return nil, fmt.Errorf("a column mapping file is required for CSV input")
+		}
+		mapping, err := LoadMapping(mappingPath)
+		if err != nil {
+			return nil, err
+		}
+		return OpenCSV(path, mapping)
+	case FormatParquet:
+		return OpenParquet(path)
+	case FormatGA4:
+		return OpenGA4JSON(path)
+	case FormatGA4Avro:
+		return OpenGA4Avro(path)
+	default:
+		return nil, fmt.Errorf("unsupported input format: %s", format)
+	}
+}
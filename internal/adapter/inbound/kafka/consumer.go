@@ -0,0 +1,284 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	kafkago "github.com/segmentio/kafka-go"
+	"go.uber.org/zap"
+
+	"github.com/ebubekir/event-stream/internal/adapter/inbound/http/dto"
+	"github.com/ebubekir/event-stream/internal/application/event"
+	eventDomain "github.com/ebubekir/event-stream/internal/domain/event"
+	"github.com/ebubekir/event-stream/pkg/logger"
+)
+
+const (
+	// RequestIDHeader and SDKHeader are optional message headers recorded as ingestion metadata
+	RequestIDHeader = "x-request-id"
+	SDKHeader       = "x-client-sdk"
+
+	defaultBatchSize     = 500
+	defaultFlushInterval = time.Second
+	defaultRetryBackoff  = time.Second
+	maxRetryBackoff      = 30 * time.Second
+)
+
+// MessageReader is the part of *kafka.Reader used by the consumer.
+// Tests and local setups can substitute an in-memory stand-in for the broker.
+type MessageReader interface {
+	FetchMessage(ctx context.Context) (kafkago.Message, error)
+	CommitMessages(ctx context.Context, msgs ...kafkago.Message) error
+}
+
+// Config tunes how messages are batched
+type Config struct {
+	// BatchSize is the maximum number of messages per partition stored at once
+	BatchSize int
+	// FlushInterval bounds how long a partial batch waits for more messages
+	FlushInterval time.Duration
+	// RetryBackoff is the initial wait before retrying a failed batch; it doubles up to 30s
+	RetryBackoff time.Duration
+}
+
+// Consumer reads CreateEventRequest JSON messages and stores them through the EventService.
+//
+// Delivery is at-least-once: offsets are committed only after a batch is saved.
+// Each message gets an event ID derived from its topic, partition and offset,
+// and batches are written with eventDomain.WithSkipStored, so the event and
+// quarantine stores drop a redelivered message instead of storing it twice,
+// and only the events stored now reach webhooks, sinks and live tails. This
+// holds however the redelivered range is cut into batches, e.g. by the flush
+// interval. A message redelivered while its first write is still in flight
+// may still be published twice.
+type Consumer struct {
+	reader  MessageReader
+	service *event.EventService
+	config  Config
+}
+
+// NewConsumer creates a new Consumer
+func NewConsumer(reader MessageReader, service *event.EventService, config Config) *Consumer {
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaultBatchSize
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = defaultFlushInterval
+	}
+	if config.RetryBackoff <= 0 {
+		config.RetryBackoff = defaultRetryBackoff
+	}
+
+	return &Consumer{
+		reader:  reader,
+		service: service,
+		config:  config,
+	}
+}
+
+// NewReader creates a consumer-group reader for topic that leaves committing to the Consumer
+func NewReader(brokers []string, topic, groupID string) *kafkago.Reader {
+	return kafkago.NewReader(kafkago.ReaderConfig{
+		Brokers:     brokers,
+		Topic:       topic,
+		GroupID:     groupID,
+		StartOffset: kafkago.FirstOffset,
+		MaxWait:     500 * time.Millisecond,
+	})
+}
+
+// Run consumes messages until ctx is cancelled. Messages that were fetched but
+// not committed when Run returns are redelivered to the next consumer.
+func (c *Consumer) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	messages := make(chan kafkago.Message)
+	fetchErr := make(chan error, 1)
+	go func() {
+		for {
+			msg, err := c.reader.FetchMessage(ctx)
+			if err != nil {
+				fetchErr <- err
+				return
+			}
+			select {
+			case messages <- msg:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(c.config.FlushInterval)
+	defer ticker.Stop()
+
+	pending := make(map[string][]kafkago.Message)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case err := <-fetchErr:
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to fetch message: %w", err)
+
+		case msg := <-messages:
+			key := partitionKey(msg)
+			pending[key] = append(pending[key], msg)
+			if len(pending[key]) >= c.config.BatchSize {
+				if err := c.flush(ctx, pending[key]); err != nil {
+					return err
+				}
+				delete(pending, key)
+			}
+
+		case <-ticker.C:
+			for key, batch := range pending {
+				if err := c.flush(ctx, batch); err != nil {
+					return err
+				}
+				delete(pending, key)
+			}
+		}
+	}
+}
+
+// flush stores a batch from a single partition, retrying until it succeeds or
+// ctx is cancelled, then commits its last offset
+func (c *Consumer) flush(ctx context.Context, batch []kafkago.Message) error {
+	backoff := c.config.RetryBackoff
+	for {
+		err := c.store(ctx, batch)
+		if err == nil {
+			break
+		}
+
+		first, last := batch[0], batch[len(batch)-1]
+		logger.Error("failed to store kafka batch, retrying",
+			zap.String("topic", first.Topic),
+			zap.Int("partition", first.Partition),
+			zap.Int64("first_offset", first.Offset),
+			zap.Int64("last_offset", last.Offset),
+			zap.Duration("backoff", backoff),
+			zap.Error(err),
+		)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxRetryBackoff)
+	}
+
+	if err := c.reader.CommitMessages(ctx, batch[len(batch)-1]); err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return fmt.Errorf("failed to commit offsets: %w", err)
+	}
+
+	return nil
+}
+
+// store decodes and saves a batch. Messages that cannot be decoded, and events
+// rejected by the acceptance window, are logged and skipped so they do not
+// block the partition.
+func (c *Consumer) store(ctx context.Context, batch []kafkago.Message) error {
+	cmds := make([]*event.CreateEventCommand, 0, len(batch))
+	offsets := make([]kafkago.Message, 0, len(batch))
+	for _, msg := range batch {
+		cmd, err := toCommand(msg)
+		if err != nil {
+			logger.Warn("skipping undecodable kafka message", messageFields(msg, err)...)
+			continue
+		}
+		cmds = append(cmds, cmd)
+		offsets = append(offsets, msg)
+	}
+
+	if len(cmds) == 0 {
+		return nil
+	}
+
+	ctx = eventDomain.WithSkipStored(ctx)
+	_, err := c.service.CreateEvents(ctx, cmds)
+	if !errors.Is(err, eventDomain.ErrEventOutOfWindow) {
+		return err
+	}
+
+	// Some events are out of window and the window rejects them:
+	// store the rest one by one so a single bad event does not stall the batch
+	for i, cmd := range cmds {
+		msg := offsets[i]
+		_, err := c.service.CreateEvents(ctx, []*event.CreateEventCommand{cmd})
+		if errors.Is(err, eventDomain.ErrEventOutOfWindow) {
+			logger.Warn("skipping out-of-window kafka message", messageFields(msg, err)...)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// toCommand decodes a message into a command with a deterministic event ID
+func toCommand(msg kafkago.Message) (*event.CreateEventCommand, error) {
+	var req dto.CreateEventRequest
+	if err := json.Unmarshal(msg.Value, &req); err != nil {
+		return nil, err
+	}
+
+	cmd := req.ToCommand()
+	if err := cmd.Validate(); err != nil {
+		return nil, err
+	}
+
+	sdkName, sdkVersion, _ := strings.Cut(header(msg, SDKHeader), "/")
+	cmd.ID = uuid.NewSHA1(uuid.NameSpaceURL, []byte(messageURI(msg))).String()
+	cmd.Ingestion = event.IngestionMetadataDTO{
+		ReceivedAt: time.Now().UTC(),
+		Endpoint:   "kafka://" + msg.Topic,
+		RequestID:  header(msg, RequestIDHeader),
+		SDKName:    strings.TrimSpace(sdkName),
+		SDKVersion: strings.TrimSpace(sdkVersion),
+	}
+
+	return cmd, nil
+}
+
+func messageURI(msg kafkago.Message) string {
+	return fmt.Sprintf("kafka://%s/%d/%d", msg.Topic, msg.Partition, msg.Offset)
+}
+
+func partitionKey(msg kafkago.Message) string {
+	return fmt.Sprintf("%s/%d", msg.Topic, msg.Partition)
+}
+
+func header(msg kafkago.Message, key string) string {
+	for _, h := range msg.Headers {
+		if strings.EqualFold(h.Key, key) {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+func messageFields(msg kafkago.Message, err error) []zap.Field {
+	return []zap.Field{
+		zap.String("topic", msg.Topic),
+		zap.Int("partition", msg.Partition),
+		zap.Int64("offset", msg.Offset),
+		zap.Error(err),
+	}
+}
@@ -0,0 +1,240 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	kafkago "github.com/segmentio/kafka-go"
+	"go.uber.org/zap"
+
+	"github.com/ebubekir/event-stream/internal/application/event"
+	"github.com/ebubekir/event-stream/internal/domain"
+	eventDomain "github.com/ebubekir/event-stream/internal/domain/event"
+	"github.com/ebubekir/event-stream/pkg/logger"
+)
+
+func init() {
+	logger.Log = zap.NewNop()
+}
+
+// messageReader is an in-memory stand-in for a partition. After delivering
+// an offset listed in waitCommit it holds further messages until that offset
+// is committed, so the flush interval cuts the batch there.
+type messageReader struct {
+	mu         sync.Mutex
+	messages   []kafkago.Message
+	next       int
+	waitCommit map[int64]bool
+	committed  int64
+	changed    chan struct{}
+}
+
+func newMessageReader(messages []kafkago.Message, waitCommit ...int64) *messageReader {
+	r := &messageReader{
+		messages:   messages,
+		waitCommit: make(map[int64]bool),
+		committed:  -1,
+		changed:    make(chan struct{}),
+	}
+	for _, offset := range waitCommit {
+		r.waitCommit[offset] = true
+	}
+	return r
+}
+
+func (r *messageReader) FetchMessage(ctx context.Context) (kafkago.Message, error) {
+	for {
+		r.mu.Lock()
+		ready := r.next < len(r.messages)
+		if ready && r.next > 0 {
+			previous := r.messages[r.next-1].Offset
+			ready = !r.waitCommit[previous] || r.committed >= previous
+		}
+		if ready {
+			msg := r.messages[r.next]
+			r.next++
+			r.mu.Unlock()
+			return msg, nil
+		}
+		changed := r.changed
+		r.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return kafkago.Message{}, ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+func (r *messageReader) CommitMessages(ctx context.Context, msgs ...kafkago.Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, msg := range msgs {
+		r.committed = max(r.committed, msg.Offset)
+	}
+	close(r.changed)
+	r.changed = make(chan struct{})
+	return nil
+}
+
+func (r *messageReader) lastCommitted() int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.committed
+}
+
+// eventStore is an in-memory EventRepository that, like the real stores,
+// drops events already stored when the write is marked WithSkipStored
+type eventStore struct {
+	eventDomain.EventRepository
+
+	mu      sync.Mutex
+	rows    []string
+	batches [][]string
+}
+
+func (s *eventStore) SaveBatch(ctx context.Context, events []*domain.Event) ([]*domain.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var batch []string
+	var saved []*domain.Event
+	for _, e := range events {
+		batch = append(batch, e.ID)
+		if eventDomain.SkipStored(ctx) && s.stored(e.ID) {
+			continue
+		}
+		s.rows = append(s.rows, e.ID)
+		saved = append(saved, e)
+	}
+	s.batches = append(s.batches, batch)
+	return saved, nil
+}
+
+func (s *eventStore) stored(id string) bool {
+	for _, row := range s.rows {
+		if row == id {
+			return true
+		}
+	}
+	return false
+}
+
+// recordingSink counts the events written to it by ID
+type recordingSink struct {
+	mu     sync.Mutex
+	writes map[string]int
+}
+
+func (s *recordingSink) Write(ctx context.Context, events []*domain.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range events {
+		s.writes[e.ID]++
+	}
+	return nil
+}
+
+func (s *recordingSink) Close(ctx context.Context) error {
+	return nil
+}
+
+func testMessages(t *testing.T, n int) []kafkago.Message {
+	t.Helper()
+
+	value, err := json.Marshal(map[string]any{"name": "page_view", "channel_type": "web", "date": "2024-01-01T00:00:00Z"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	messages := make([]kafkago.Message, n)
+	for i := range messages {
+		messages[i] = kafkago.Message{Topic: "events", Partition: 0, Offset: int64(i), Value: value}
+	}
+	return messages
+}
+
+// consume runs a consumer over reader until lastOffset is committed
+func consume(t *testing.T, reader *messageReader, service *event.EventService, lastOffset int64) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	consumer := NewConsumer(reader, service, Config{BatchSize: 5, FlushInterval: 10 * time.Millisecond})
+	done := make(chan error, 1)
+	go func() { done <- consumer.Run(ctx) }()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for reader.lastCommitted() < lastOffset {
+		if time.Now().After(deadline) {
+			t.Fatalf("offset %d not committed, last committed %d", lastOffset, reader.lastCommitted())
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+}
+
+func TestConsumerRedeliveryAfterTickerFlush(t *testing.T) {
+	store := &eventStore{}
+	sink := &recordingSink{writes: make(map[string]int)}
+	service := event.NewEventService(store, nil, nil, event.WithSinks(sink))
+	messages := testMessages(t, 7)
+
+	// The flush interval cuts the first delivery into [0-2] and [3-6]
+	consume(t, newMessageReader(messages, 2), service, 6)
+
+	// The commits are lost and the range is redelivered at once, so it is
+	// cut by the batch size into [0-4] and [5-6] instead
+	consume(t, newMessageReader(messages), service, 6)
+
+	// Only the redelivery holds a batch of 5
+	full := slices.ContainsFunc(store.batches, func(batch []string) bool { return len(batch) == 5 })
+	if len(store.batches[0]) != 3 || !full {
+		t.Fatalf("batches = %v, want the deliveries cut at different offsets", store.batches)
+	}
+	if len(store.rows) != len(messages) {
+		t.Errorf("stored %d events, want %d", len(store.rows), len(messages))
+	}
+	if len(sink.writes) != len(messages) {
+		t.Errorf("sink received %d events, want %d", len(sink.writes), len(messages))
+	}
+	for id, writes := range sink.writes {
+		if writes != 1 {
+			t.Errorf("event %s written to the sink %d times, want once", id, writes)
+		}
+	}
+}
+
+func TestToCommandDeterministicID(t *testing.T) {
+	messages := testMessages(t, 2)
+
+	first, err := toCommand(messages[0])
+	if err != nil {
+		t.Fatalf("toCommand() error = %v", err)
+	}
+	again, err := toCommand(messages[0])
+	if err != nil {
+		t.Fatalf("toCommand() error = %v", err)
+	}
+	other, err := toCommand(messages[1])
+	if err != nil {
+		t.Fatalf("toCommand() error = %v", err)
+	}
+
+	if first.ID != again.ID {
+		t.Errorf("IDs of the same message differ: %s and %s", first.ID, again.ID)
+	}
+	if first.ID == other.ID {
+		t.Errorf("IDs of different offsets are equal: %s", first.ID)
+	}
+}
@@ -0,0 +1,157 @@
+package archive
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ebubekir/event-stream/internal/adapter/eventcodec"
+	"github.com/ebubekir/event-stream/internal/domain"
+	eventDomain "github.com/ebubekir/event-stream/internal/domain/event"
+)
+
+// maxLineSize bounds a single archived event; larger lines fail the read
+const maxLineSize = 16 << 20
+
+// Reader implements domain/event.EventSource over archive files in a local
+// directory, such as one written by LocalStore or a synced copy of a bucket.
+// Both gzip-compressed and plain NDJSON files are read.
+//
+// Archives are partitioned by the hour events were received, not by their
+// date, so partitions are only skipped when they lie more than slack outside
+// the filter's time range. Late events received after that are missed; a
+// slack of 0 reads every partition.
+type Reader struct {
+	dir   string
+	slack time.Duration
+}
+
+// NewReader creates a Reader for the archive rooted at dir
+func NewReader(dir string, slack time.Duration) *Reader {
+	return &Reader{dir: dir, slack: slack}
+}
+
+// Stream calls fn for each archived event matching filter, file by file in
+// partition order. Events are not ordered by date.
+func (r *Reader) Stream(ctx context.Context, filter eventDomain.EventFilter, fn func(*domain.Event) error) error {
+	if _, err := os.Stat(r.dir); err != nil {
+		return fmt.Errorf("failed to open archive: %w", err)
+	}
+
+	return filepath.WalkDir(r.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		name := d.Name()
+		if d.IsDir() {
+			if path != r.dir && r.skipPartition(path, filter) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		// Hidden files are uploads in progress
+		if strings.HasPrefix(name, ".") || !(strings.HasSuffix(name, ".ndjson") || strings.HasSuffix(name, fileSuffix)) {
+			return nil
+		}
+
+		return r.readFile(path, filter, fn)
+	})
+}
+
+// skipPartition reports whether the dt= or hour= directory at path cannot
+// hold events in the filter's time range
+func (r *Reader) skipPartition(path string, filter eventDomain.EventFilter) bool {
+	if r.slack <= 0 || (filter.From.IsZero() && filter.To.IsZero()) {
+		return false
+	}
+
+	start, length, ok := partitionRange(path)
+	if !ok {
+		return false
+	}
+	if !filter.To.IsZero() && !start.Before(filter.To.Add(r.slack)) {
+		return true
+	}
+	if !filter.From.IsZero() && !start.Add(length).After(filter.From.Add(-r.slack)) {
+		return true
+	}
+	return false
+}
+
+// partitionRange returns the time span of a dt=YYYY-MM-DD or
+// dt=YYYY-MM-DD/hour=HH directory
+func partitionRange(path string) (time.Time, time.Duration, bool) {
+	name := filepath.Base(path)
+	if day, ok := strings.CutPrefix(name, "dt="); ok {
+		start, err := time.Parse(time.DateOnly, day)
+		return start, 24 * time.Hour, err == nil
+	}
+
+	hour, ok := strings.CutPrefix(name, "hour=")
+	if !ok {
+		return time.Time{}, 0, false
+	}
+	day, ok := strings.CutPrefix(filepath.Base(filepath.Dir(path)), "dt=")
+	if !ok {
+		return time.Time{}, 0, false
+	}
+	start, err := time.Parse(time.DateOnly+" 15", day+" "+hour)
+	return start, time.Hour, err == nil
+}
+
+// readFile calls fn for each event in one archive file matching filter
+func (r *Reader) readFile(path string, filter eventDomain.EventFilter, fn func(*domain.Event) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open archive file: %w", err)
+	}
+	defer file.Close()
+
+	var input io.Reader = file
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		defer gz.Close()
+		input = gz
+	}
+
+	scanner := bufio.NewScanner(input)
+	scanner.Buffer(make([]byte, 0, 64<<10), maxLineSize)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+
+		var record eventcodec.Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		event := record.ToEvent()
+		if !filter.Matches(event) {
+			continue
+		}
+		if err := fn(event); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return nil
+}
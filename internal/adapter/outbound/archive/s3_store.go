@@ -0,0 +1,59 @@
+package archive
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// S3Config describes an S3-compatible bucket, e.g. AWS S3 or MinIO
+type S3Config struct {
+	Endpoint  string // host[:port] without scheme, e.g. "s3.amazonaws.com" or "localhost:9000"
+	Region    string
+	Bucket    string
+	Prefix    string // optional key prefix inside the bucket
+	AccessKey string
+	SecretKey string
+	UseSSL    bool
+}
+
+// S3Store uploads archive files to an S3-compatible bucket
+type S3Store struct {
+	client *minio.Client
+	bucket string
+	prefix string
+}
+
+// NewS3Store creates an S3Store for the configured bucket
+func NewS3Store(cfg S3Config) (*S3Store, error) {
+	client, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
+		Secure: cfg.UseSSL,
+		Region: cfg.Region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 client: %w", err)
+	}
+
+	return &S3Store{
+		client: client,
+		bucket: cfg.Bucket,
+		prefix: cfg.Prefix,
+	}, nil
+}
+
+// Put uploads the file under prefix/key
+func (s *S3Store) Put(ctx context.Context, key string, r io.Reader, size int64) error {
+	_, err := s.client.PutObject(ctx, s.bucket, path.Join(s.prefix, key), r, size, minio.PutObjectOptions{
+		ContentType: "application/gzip",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload archive file: %w", err)
+	}
+
+	return nil
+}
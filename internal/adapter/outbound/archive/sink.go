@@ -0,0 +1,450 @@
+package archive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/ebubekir/event-stream/internal/adapter/eventcodec"
+	"github.com/ebubekir/event-stream/internal/domain"
+	"github.com/ebubekir/event-stream/pkg/logger"
+)
+
+const (
+	fileSuffix    = ".ndjson.gz"
+	partialSuffix = ".partial"
+
+	defaultMaxFileSize = 256 << 20 // uncompressed bytes
+	rollCheckInterval  = time.Minute
+	uploadRetryDelay   = 30 * time.Second
+	uploadTimeout      = 5 * time.Minute
+)
+
+// Config tunes how archive files are written
+type Config struct {
+	// StagingDir holds files being written and files waiting to be uploaded
+	StagingDir string
+	// MaxFileSize rolls the current file once this many uncompressed bytes were written
+	MaxFileSize int64
+}
+
+// Sink implements domain/event.EventSink by writing events as gzip-compressed
+// NDJSON files, one eventcodec.Record per line.
+//
+// Files are rolled every UTC hour and whenever they reach MaxFileSize, then
+// uploaded to the store under a date-partitioned key:
+//
+//	dt=2024-01-15/hour=10/events-20240115T100000Z-<host>-<nanos>.ndjson.gz
+//
+// Files are written in a staging directory first and removed once uploaded.
+// Files left behind by a crash or failed upload are uploaded on the next start.
+type Sink struct {
+	store    Store
+	config   Config
+	hostname string
+
+	mu      sync.Mutex
+	file    *os.File
+	gz      *gzip.Writer
+	path    string
+	hour    time.Time
+	written int64
+
+	// queue holds finished files waiting to be uploaded. It has its own lock
+	// and is unbounded, so rolling a file never waits for the uploader.
+	queueMu sync.Mutex
+	queue   []string
+	closed  bool
+	queued  chan struct{}
+
+	stop         chan struct{}
+	roller       sync.WaitGroup
+	uploaderDone chan struct{}
+}
+
+// NewSink creates a Sink that uploads to store and starts its background uploader
+func NewSink(store Store, config Config) (*Sink, error) {
+	if config.StagingDir == "" {
+		config.StagingDir = filepath.Join(os.TempDir(), "event-stream-archive")
+	}
+	if config.MaxFileSize <= 0 {
+		config.MaxFileSize = defaultMaxFileSize
+	}
+
+	if err := os.MkdirAll(config.StagingDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create archive staging directory: %w", err)
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "unknown"
+	}
+
+	pending, err := recoverStaged(config.StagingDir)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Sink{
+		store:        store,
+		config:       config,
+		hostname:     hostname,
+		queue:        pending,
+		queued:       make(chan struct{}, 1),
+		stop:         make(chan struct{}),
+		uploaderDone: make(chan struct{}),
+	}
+
+	go s.upload()
+	s.roller.Add(1)
+	go s.rollHourly()
+
+	return s, nil
+}
+
+// Write appends events to the current file
+func (s *Sink) Write(ctx context.Context, events []*domain.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hour := time.Now().UTC().Truncate(time.Hour)
+	if s.file != nil && !hour.Equal(s.hour) {
+		if err := s.roll(); err != nil {
+			return err
+		}
+	}
+	if s.file == nil {
+		if err := s.open(hour); err != nil {
+			return err
+		}
+	}
+
+	for _, event := range events {
+		line, err := json.Marshal(eventcodec.FromEvent(event))
+		if err != nil {
+			return fmt.Errorf("failed to encode event %s: %w", event.ID, err)
+		}
+		line = append(line, '\n')
+
+		n, err := s.gz.Write(line)
+		s.written += int64(n)
+		if err != nil {
+			return fmt.Errorf("failed to write archive file: %w", err)
+		}
+	}
+
+	// Flush so a crash loses at most the events being written
+	if err := s.gz.Flush(); err != nil {
+		return fmt.Errorf("failed to flush archive file: %w", err)
+	}
+
+	if s.written >= s.config.MaxFileSize {
+		return s.roll()
+	}
+
+	return nil
+}
+
+// Close rolls the current file and waits for pending uploads, or for ctx to expire.
+// Files that could not be uploaded stay in the staging directory for the next start.
+func (s *Sink) Close(ctx context.Context) error {
+	close(s.stop)
+	s.roller.Wait()
+
+	s.mu.Lock()
+	err := s.roll()
+	s.mu.Unlock()
+
+	s.queueMu.Lock()
+	s.closed = true
+	s.queueMu.Unlock()
+	s.notify()
+
+	if err != nil {
+		return err
+	}
+
+	select {
+	case <-s.uploaderDone:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// open starts a new staging file for hour
+func (s *Sink) open(hour time.Time) error {
+	key := fmt.Sprintf("dt=%s/hour=%02d/events-%s-%s-%d%s",
+		hour.Format("2006-01-02"), hour.Hour(), hour.Format("20060102T150405Z"),
+		s.hostname, time.Now().UnixNano(), fileSuffix)
+	path := filepath.Join(s.config.StagingDir, filepath.FromSlash(key)) + partialSuffix
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create archive staging directory: %w", err)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create archive file: %w", err)
+	}
+
+	s.file = file
+	s.gz = gzip.NewWriter(file)
+	s.path = path
+	s.hour = hour
+	s.written = 0
+	return nil
+}
+
+// roll finishes the current file and queues it for upload. Callers hold s.mu.
+func (s *Sink) roll() error {
+	if s.file == nil {
+		return nil
+	}
+
+	file, gz, path := s.file, s.gz, s.path
+	s.file, s.gz, s.path = nil, nil, ""
+
+	if err := gz.Close(); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to finish archive file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to close archive file: %w", err)
+	}
+
+	finished := strings.TrimSuffix(path, partialSuffix)
+	if err := os.Rename(path, finished); err != nil {
+		return fmt.Errorf("failed to finish archive file: %w", err)
+	}
+
+	s.enqueue(finished)
+	return nil
+}
+
+// enqueue queues a finished file for upload without waiting for the uploader
+func (s *Sink) enqueue(path string) {
+	s.queueMu.Lock()
+	s.queue = append(s.queue, path)
+	s.queueMu.Unlock()
+	s.notify()
+}
+
+// notify wakes the uploader; a wake-up already pending covers this one
+func (s *Sink) notify() {
+	select {
+	case s.queued <- struct{}{}:
+	default:
+	}
+}
+
+// next waits for the next file to upload. It returns false once the sink is
+// closed and every queued file was taken.
+func (s *Sink) next() (string, bool) {
+	for {
+		s.queueMu.Lock()
+		if len(s.queue) > 0 {
+			path := s.queue[0]
+			s.queue = s.queue[1:]
+			s.queueMu.Unlock()
+			return path, true
+		}
+		closed := s.closed
+		s.queueMu.Unlock()
+
+		if closed {
+			return "", false
+		}
+		<-s.queued
+	}
+}
+
+// rollHourly closes the current file once its hour is over, even without new writes
+func (s *Sink) rollHourly() {
+	defer s.roller.Done()
+
+	ticker := time.NewTicker(rollCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			s.mu.Lock()
+			if s.file != nil && !time.Now().UTC().Truncate(time.Hour).Equal(s.hour) {
+				if err := s.roll(); err != nil {
+					logger.Error("failed to roll archive file", zap.Error(err))
+				}
+			}
+			s.mu.Unlock()
+		}
+	}
+}
+
+// upload sends finished files to the store in order, retrying failures
+func (s *Sink) upload() {
+	defer close(s.uploaderDone)
+
+	for {
+		path, ok := s.next()
+		if !ok {
+			return
+		}
+
+		for {
+			err := s.put(path)
+			if err == nil {
+				break
+			}
+
+			logger.Error("failed to upload archive file, retrying", zap.String("file", path), zap.Error(err))
+			select {
+			case <-time.After(uploadRetryDelay):
+			case <-s.stop:
+				// Shutting down: leave the file for the next start
+				return
+			}
+		}
+	}
+}
+
+func (s *Sink) put(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	key, err := filepath.Rel(s.config.StagingDir, path)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), uploadTimeout)
+	defer cancel()
+
+	if err := s.store.Put(ctx, filepath.ToSlash(key), file, info.Size()); err != nil {
+		return err
+	}
+
+	return os.Remove(path)
+}
+
+// recoverStaged returns files left in the staging directory by a previous run.
+// Partial files were flushed after every write but never closed, so their
+// gzip stream has no trailer; recoverPartial rewrites them as complete files.
+func recoverStaged(dir string) ([]string, error) {
+	var pending []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		switch {
+		case strings.HasSuffix(path, fileSuffix):
+			pending = append(pending, path)
+		case strings.HasSuffix(path, fileSuffix+partialSuffix):
+			finished, ok, err := recoverPartial(path)
+			if err != nil {
+				return err
+			}
+			if ok {
+				pending = append(pending, finished)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to recover staged archive files: %w", err)
+	}
+
+	return pending, nil
+}
+
+// recoverPartial re-encodes the complete lines readable from a partial file
+// into a finished file and removes the partial one. It returns false when
+// the partial file holds no complete line.
+func recoverPartial(path string) (string, bool, error) {
+	partial, err := os.Open(path)
+	if err != nil {
+		return "", false, err
+	}
+	defer partial.Close()
+
+	finished := strings.TrimSuffix(path, partialSuffix)
+	file, err := os.Create(finished)
+	if err != nil {
+		return "", false, err
+	}
+	gz := gzip.NewWriter(file)
+
+	lines, err := copyCompleteLines(gz, partial)
+	if err == nil {
+		err = gz.Close()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && lines == 0 {
+		err = os.Remove(finished)
+	}
+	if err != nil {
+		_ = os.Remove(finished)
+		return "", false, fmt.Errorf("failed to recover %s: %w", path, err)
+	}
+
+	if err := os.Remove(path); err != nil {
+		return "", false, err
+	}
+	return finished, lines > 0, nil
+}
+
+// copyCompleteLines decompresses r up to the end of its readable prefix and
+// writes the complete lines to w. A write cut short by a crash leaves a
+// truncated stream and possibly a half line, which are dropped.
+func copyCompleteLines(w io.Writer, r io.Reader) (int, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		// Not even the header was flushed
+		return 0, nil
+	}
+
+	var lines int
+	var tail []byte
+	buf := make([]byte, 32<<10)
+	for {
+		n, readErr := zr.Read(buf)
+		if n > 0 {
+			chunk := append(tail, buf[:n]...)
+			if end := bytes.LastIndexByte(chunk, '\n'); end >= 0 {
+				if _, err := w.Write(chunk[:end+1]); err != nil {
+					return lines, err
+				}
+				lines += bytes.Count(chunk[:end+1], []byte{'\n'})
+				chunk = chunk[end+1:]
+			}
+			tail = append(tail[:0], chunk...)
+		}
+		if readErr != nil {
+			// io.EOF for a file closed before the crash, otherwise the end of the flushed prefix
+			return lines, nil
+		}
+	}
+}
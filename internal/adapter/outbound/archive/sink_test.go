@@ -0,0 +1,257 @@
+package archive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/ebubekir/event-stream/internal/domain"
+	"github.com/ebubekir/event-stream/pkg/logger"
+)
+
+func init() {
+	logger.Log = zap.NewNop()
+}
+
+// memoryStore keeps uploaded files in memory, or fails every upload with err
+type memoryStore struct {
+	mu    sync.Mutex
+	files map[string][]byte
+	err   error
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{files: make(map[string][]byte)}
+}
+
+func (s *memoryStore) Put(ctx context.Context, key string, r io.Reader, size int64) error {
+	if s.err != nil {
+		return s.err
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.files[key] = data
+	return nil
+}
+
+func (s *memoryStore) snapshot() map[string][]byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	files := make(map[string][]byte, len(s.files))
+	for key, data := range s.files {
+		files[key] = data
+	}
+	return files
+}
+
+func testEvents(n int) []*domain.Event {
+	events := make([]*domain.Event, n)
+	for i := range events {
+		events[i] = &domain.Event{ID: "event-" + string(rune('a'+i)), Name: "page_view", ChannelType: "web"}
+	}
+	return events
+}
+
+// gunzipLines reads a complete gzip file and returns its lines
+func gunzipLines(t *testing.T, data []byte) []string {
+	t.Helper()
+
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("gzip.NewReader() error = %v", err)
+	}
+	content, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("reading archive file: %v", err)
+	}
+	return strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+}
+
+// stagedFiles lists the files left in the staging directory
+func stagedFiles(t *testing.T, dir string) []string {
+	t.Helper()
+
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			files = append(files, path)
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+func TestSinkRecoversFileAfterCrash(t *testing.T) {
+	dir := t.TempDir()
+
+	// The first sink writes and crashes: its file is flushed but never closed
+	crashed, err := NewSink(newMemoryStore(), Config{StagingDir: dir})
+	if err != nil {
+		t.Fatalf("NewSink() error = %v", err)
+	}
+	if err := crashed.Write(context.Background(), testEvents(3)); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	store := newMemoryStore()
+	sink, err := NewSink(store, Config{StagingDir: dir})
+	if err != nil {
+		t.Fatalf("NewSink() error = %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := sink.Close(ctx); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	files := store.snapshot()
+	if len(files) != 1 {
+		t.Fatalf("uploaded %d files, want 1", len(files))
+	}
+	for key, data := range files {
+		if !strings.HasSuffix(key, fileSuffix) {
+			t.Errorf("key = %s, want suffix %s", key, fileSuffix)
+		}
+		if lines := gunzipLines(t, data); len(lines) != 3 {
+			t.Errorf("recovered %d lines, want 3", len(lines))
+		}
+	}
+	if left := stagedFiles(t, dir); len(left) != 0 {
+		t.Errorf("staging directory holds %v, want none", left)
+	}
+}
+
+func TestRecoverPartial(t *testing.T) {
+	tests := []struct {
+		name      string
+		write     func(w io.Writer)
+		wantLines []string
+	}{
+		{
+			name:  "empty file",
+			write: func(w io.Writer) {},
+		},
+		{
+			name: "header only",
+			write: func(w io.Writer) {
+				gz := gzip.NewWriter(w)
+				_ = gz.Flush()
+			},
+		},
+		{
+			name: "flushed lines",
+			write: func(w io.Writer) {
+				gz := gzip.NewWriter(w)
+				_, _ = gz.Write([]byte("a\nb\n"))
+				_ = gz.Flush()
+			},
+			wantLines: []string{"a", "b"},
+		},
+		{
+			name: "half line",
+			write: func(w io.Writer) {
+				gz := gzip.NewWriter(w)
+				_, _ = gz.Write([]byte("a\nb\nhal"))
+				_ = gz.Flush()
+			},
+			wantLines: []string{"a", "b"},
+		},
+		{
+			name: "closed before rename",
+			write: func(w io.Writer) {
+				gz := gzip.NewWriter(w)
+				_, _ = gz.Write([]byte("a\n"))
+				_ = gz.Close()
+			},
+			wantLines: []string{"a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "events"+fileSuffix+partialSuffix)
+			var buf bytes.Buffer
+			tt.write(&buf)
+			if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			finished, ok, err := recoverPartial(path)
+			if err != nil {
+				t.Fatalf("recoverPartial() error = %v", err)
+			}
+			if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+				t.Errorf("partial file still exists")
+			}
+			if ok != (len(tt.wantLines) > 0) {
+				t.Fatalf("recoverPartial() ok = %v, want %v", ok, len(tt.wantLines) > 0)
+			}
+			if !ok {
+				if left := stagedFiles(t, filepath.Dir(path)); len(left) != 0 {
+					t.Errorf("staging directory holds %v, want none", left)
+				}
+				return
+			}
+
+			data, err := os.ReadFile(finished)
+			if err != nil {
+				t.Fatal(err)
+			}
+			lines := gunzipLines(t, data)
+			if strings.Join(lines, ",") != strings.Join(tt.wantLines, ",") {
+				t.Errorf("lines = %v, want %v", lines, tt.wantLines)
+			}
+		})
+	}
+}
+
+func TestSinkWriteDoesNotWaitForFailingStore(t *testing.T) {
+	store := newMemoryStore()
+	store.err = errors.New("store unavailable")
+
+	// Every write rolls a file the uploader cannot upload
+	sink, err := NewSink(store, Config{StagingDir: t.TempDir(), MaxFileSize: 1})
+	if err != nil {
+		t.Fatalf("NewSink() error = %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		for range 200 {
+			if err := sink.Write(context.Background(), testEvents(1)); err != nil {
+				done <- err
+				return
+			}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		done <- sink.Close(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Write or Close blocked while the store was failing")
+	}
+}
@@ -0,0 +1,225 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	eventDomain "github.com/ebubekir/event-stream/internal/domain/event"
+)
+
+// LocalStore implements domain/event.ExportStore on a local directory.
+// Each job is kept as "<id>.json" next to its output "<id>.<format>".
+type LocalStore struct {
+	dir string
+}
+
+// NewLocalStore creates a LocalStore rooted at dir, creating the directory if needed
+func NewLocalStore(dir string) (*LocalStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create export directory: %w", err)
+	}
+	return &LocalStore{dir: dir}, nil
+}
+
+// jobModel is the stored representation of an export job
+type jobModel struct {
+	ID           string    `json:"id"`
+	Format       string    `json:"format"`
+	From         time.Time `json:"from"`
+	To           time.Time `json:"to"`
+	EventNames   []string  `json:"event_names,omitempty"`
+	ChannelType  string    `json:"channel_type,omitempty"`
+	UserID       string    `json:"user_id,omitempty"`
+	UserPseudoID string    `json:"user_pseudo_id,omitempty"`
+	AppID        string    `json:"app_id,omitempty"`
+	EventParams  []string  `json:"event_params,omitempty"`
+	UserParams   []string  `json:"user_params,omitempty"`
+	Status       string    `json:"status"`
+	EventCount   int64     `json:"event_count"`
+	Error        string    `json:"error,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	StartedAt    time.Time `json:"started_at"`
+	FinishedAt   time.Time `json:"finished_at"`
+}
+
+// SaveJob writes the job file atomically
+func (s *LocalStore) SaveJob(ctx context.Context, job *eventDomain.ExportJob) error {
+	data, err := json.Marshal(toJobModel(job))
+	if err != nil {
+		return fmt.Errorf("failed to encode export job: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(s.dir, ".job-*")
+	if err != nil {
+		return fmt.Errorf("failed to save export job: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to save export job: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save export job: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.jobPath(job.ID)); err != nil {
+		return fmt.Errorf("failed to save export job: %w", err)
+	}
+	return nil
+}
+
+// FindJob returns the job with the given ID or ErrExportNotFound
+func (s *LocalStore) FindJob(ctx context.Context, id string) (*eventDomain.ExportJob, error) {
+	// IDs become file names, so anything but a UUID cannot be a job
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, eventDomain.ErrExportNotFound
+	}
+	return s.readJob(s.jobPath(id))
+}
+
+// ListJobs returns all jobs, most recent first
+func (s *LocalStore) ListJobs(ctx context.Context) ([]*eventDomain.ExportJob, error) {
+	paths, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list export jobs: %w", err)
+	}
+
+	jobs := make([]*eventDomain.ExportJob, 0, len(paths))
+	for _, path := range paths {
+		job, err := s.readJob(path)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].CreatedAt.After(jobs[j].CreatedAt)
+	})
+	return jobs, nil
+}
+
+// Create returns a writer for the job's output. The output is written to a
+// ".partial" file and moved into place on Close.
+func (s *LocalStore) Create(ctx context.Context, job *eventDomain.ExportJob) (eventDomain.ExportFile, error) {
+	path := s.outputPath(job)
+	file, err := os.Create(path + ".partial")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create export file: %w", err)
+	}
+	return &localFile{File: file, path: path}, nil
+}
+
+// Open returns the job's output
+func (s *LocalStore) Open(ctx context.Context, job *eventDomain.ExportJob) (io.ReadCloser, error) {
+	file, err := os.Open(s.outputPath(job))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open export file: %w", err)
+	}
+	return file, nil
+}
+
+func (s *LocalStore) readJob(path string) (*eventDomain.ExportJob, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, eventDomain.ErrExportNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read export job: %w", err)
+	}
+
+	var model jobModel
+	if err := json.Unmarshal(data, &model); err != nil {
+		return nil, fmt.Errorf("failed to decode export job %s: %w", filepath.Base(path), err)
+	}
+	return fromJobModel(&model), nil
+}
+
+func (s *LocalStore) jobPath(id string) string {
+	return filepath.Join(s.dir, id+".json")
+}
+
+func (s *LocalStore) outputPath(job *eventDomain.ExportJob) string {
+	return filepath.Join(s.dir, job.ID+"."+string(job.Format))
+}
+
+// localFile is an export output being written to a local file
+type localFile struct {
+	*os.File
+	path string
+}
+
+// Close closes the file and moves it into place
+func (f *localFile) Close() error {
+	if err := f.File.Close(); err != nil {
+		return fmt.Errorf("failed to write export file: %w", err)
+	}
+	if err := os.Rename(f.Name(), f.path); err != nil {
+		return fmt.Errorf("failed to move export file into place: %w", err)
+	}
+	return nil
+}
+
+// Abort closes and removes the partial file
+func (f *localFile) Abort() error {
+	_ = f.File.Close()
+	return os.Remove(f.Name())
+}
+
+func toJobModel(job *eventDomain.ExportJob) *jobModel {
+	return &jobModel{
+		ID:           job.ID,
+		Format:       string(job.Format),
+		From:         job.Filter.From,
+		To:           job.Filter.To,
+		EventNames:   job.Filter.EventNames,
+		ChannelType:  job.Filter.ChannelType,
+		UserID:       job.Filter.UserID,
+		UserPseudoID: job.Filter.UserPseudoID,
+		AppID:        job.Filter.AppID,
+		EventParams:  job.Columns.EventParams,
+		UserParams:   job.Columns.UserParams,
+		Status:       string(job.Status),
+		EventCount:   job.EventCount,
+		Error:        job.Error,
+		CreatedAt:    job.CreatedAt,
+		StartedAt:    job.StartedAt,
+		FinishedAt:   job.FinishedAt,
+	}
+}
+
+func fromJobModel(model *jobModel) *eventDomain.ExportJob {
+	return &eventDomain.ExportJob{
+		ID:     model.ID,
+		Format: eventDomain.ExportFormat(model.Format),
+		Filter: eventDomain.EventFilter{
+			From:         model.From,
+			To:           model.To,
+			EventNames:   model.EventNames,
+			ChannelType:  model.ChannelType,
+			UserID:       model.UserID,
+			UserPseudoID: model.UserPseudoID,
+			AppID:        model.AppID,
+		},
+		Columns: eventDomain.ParamKeys{
+			EventParams: model.EventParams,
+			UserParams:  model.UserParams,
+		},
+		Status:     eventDomain.ExportStatus(model.Status),
+		EventCount: model.EventCount,
+		Error:      model.Error,
+		CreatedAt:  model.CreatedAt,
+		StartedAt:  model.StartedAt,
+		FinishedAt: model.FinishedAt,
+	}
+}
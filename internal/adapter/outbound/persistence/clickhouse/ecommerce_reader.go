@@ -0,0 +1,214 @@
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+
+	eventDomain "github.com/ebubekir/event-stream/internal/domain/event"
+	"github.com/ebubekir/event-stream/pkg/clickhouse"
+)
+
+// ecommerceRow represents a row from the ecommerce metrics queries
+type ecommerceRow struct {
+	Bucket          string  `db:"bucket"`
+	Revenue         float64 `db:"revenue"`
+	Orders          int64   `db:"orders"`
+	PurchasingUsers int64   `db:"purchasing_users"`
+	UnitsSold       int64   `db:"units_sold"`
+}
+
+// topItemRow represents a row from the top items query
+type topItemRow struct {
+	Key      string  `db:"item_key"`
+	Name     string  `db:"name"`
+	Revenue  float64 `db:"revenue"`
+	Quantity int64   `db:"quantity"`
+	Orders   int64   `db:"orders"`
+}
+
+// itemBucketRow represents a row from the top items series query
+type itemBucketRow struct {
+	Bucket   string  `db:"bucket"`
+	Key      string  `db:"item_key"`
+	Revenue  float64 `db:"revenue"`
+	Quantity int64   `db:"quantity"`
+}
+
+// itemKeyExprs are the expressions the item at item_index is ranked by
+var itemKeyExprs = map[eventDomain.ItemDimension]string{
+	eventDomain.ItemDimensionItem:    "if(item_ids[item_index] != '', item_ids[item_index], item_names[item_index])",
+	eventDomain.ItemDimensionBrand:   "item_brands[item_index]",
+	eventDomain.ItemDimensionVariant: "item_variants[item_index]",
+}
+
+// GetEcommerceMetrics retrieves the revenue metrics of the purchase events matching the query
+func (r *MetricsReader) GetEcommerceMetrics(ctx context.Context, query *eventDomain.EcommerceQuery) (*eventDomain.EcommerceResult, error) {
+	result := &eventDomain.EcommerceResult{
+		EventNames: query.EventNames,
+		Interval:   query.Interval,
+		Location:   query.Location,
+		From:       query.From,
+		To:         query.To,
+	}
+
+	metricsQuery := query.MetricsQuery()
+	whereClause, args := metricsWhereClause(metricsQuery)
+
+	// Each event is one order; its revenue and units are the sums over its items
+	columns := fmt.Sprintf(`
+			sum(arraySum(item_revenues_in_usd)) AS revenue,
+			count() AS orders,
+			uniqExact(%s) AS purchasing_users,
+			sum(arraySum(item_quantities)) AS units_sold`, uniqueUserExpr(metricsQuery))
+
+	totalsQuery := fmt.Sprintf(`
+		SELECT %s
+		FROM %s
+		%s
+	`, columns, fromClause(metricsQuery), whereClause)
+
+	var totals ecommerceRow
+	if err := clickhouse.GetWithContext(ctx, r.db, &totals, totalsQuery, args...); err != nil {
+		return nil, fmt.Errorf("failed to query ecommerce totals: %w", err)
+	}
+	result.Totals = totals.metrics()
+
+	if query.Interval == "" {
+		return result, nil
+	}
+
+	// Bucket keys sort as their times do
+	bucketsQuery := fmt.Sprintf(`
+		SELECT
+			toString(%s) AS bucket,%s
+		FROM %s
+		%s
+		GROUP BY bucket
+		ORDER BY bucket
+	`, fmt.Sprintf(timeBucketExprs[query.Interval], "date"), columns, fromClause(metricsQuery), whereClause)
+
+	var rows []ecommerceRow
+	if err := clickhouse.SelectWithContext(ctx, r.db, &rows, bucketsQuery, append([]interface{}{query.TimeZone()}, args...)...); err != nil {
+		return nil, fmt.Errorf("failed to query ecommerce buckets: %w", err)
+	}
+
+	result.Buckets = make([]eventDomain.EcommerceBucket, len(rows))
+	for i, row := range rows {
+		result.Buckets[i] = eventDomain.EcommerceBucket{Bucket: row.Bucket, EcommerceMetrics: row.metrics()}
+	}
+
+	return result, nil
+}
+
+// metrics returns the row's ecommerce metrics
+func (row ecommerceRow) metrics() eventDomain.EcommerceMetrics {
+	return eventDomain.EcommerceMetrics{
+		Revenue:         row.Revenue,
+		Orders:          row.Orders,
+		PurchasingUsers: row.PurchasingUsers,
+		UnitsSold:       row.UnitsSold,
+	}
+}
+
+// GetTopItems ranks the items of the purchase events matching the query.
+// purchased_items holds one row per item of each event, unrolled with arrayJoin.
+func (r *MetricsReader) GetTopItems(ctx context.Context, query *eventDomain.TopItemsQuery) (*eventDomain.TopItemsResult, error) {
+	result := &eventDomain.TopItemsResult{
+		EventNames: query.EventNames,
+		By:         query.By,
+		SortBy:     query.SortBy,
+		Interval:   query.Interval,
+		Location:   query.Location,
+		From:       query.From,
+		To:         query.To,
+	}
+
+	whereClause, args := metricsWhereClause(query.MetricsQuery())
+
+	// Output columns are named apart from the item columns, which they would shadow
+	purchasedItems := fmt.Sprintf(`
+		WITH purchased_items AS (
+			SELECT
+				id AS order_id,
+				date,
+				arrayJoin(arrayEnumerate(item_ids)) AS item_index,
+				%s AS item_key,
+				item_names[item_index] AS item_name,
+				item_revenues_in_usd[item_index] AS item_revenue,
+				item_quantities[item_index] AS item_quantity
+			FROM events
+			%s
+		)`, itemKeyExprs[query.By], whereClause)
+
+	// An item sold under several names reports the smallest, as in PostgreSQL
+	topQuery := fmt.Sprintf(`%s
+		SELECT
+			item_key,
+			min(item_name) AS name,
+			sum(item_revenue) AS revenue,
+			sum(item_quantity) AS quantity,
+			uniqExact(order_id) AS orders
+		FROM purchased_items
+		WHERE item_key != ''
+		GROUP BY item_key
+		ORDER BY %s DESC, item_key
+		LIMIT %d
+	`, purchasedItems, query.SortBy, query.Limit)
+
+	var rows []topItemRow
+	if err := clickhouse.SelectWithContext(ctx, r.db, &rows, topQuery, args...); err != nil {
+		return nil, fmt.Errorf("failed to query top items: %w", err)
+	}
+
+	result.Items = make([]eventDomain.ItemMetrics, len(rows))
+	keys := make([]string, len(rows))
+	index := make(map[string]int, len(rows))
+	for i, row := range rows {
+		result.Items[i] = eventDomain.ItemMetrics{
+			Key:      row.Key,
+			Revenue:  row.Revenue,
+			Quantity: row.Quantity,
+			Orders:   row.Orders,
+		}
+		if query.By == eventDomain.ItemDimensionItem {
+			result.Items[i].Name = row.Name
+		}
+		keys[i] = row.Key
+		index[row.Key] = i
+	}
+
+	if query.Interval == "" || len(rows) == 0 {
+		return result, nil
+	}
+
+	seriesQuery := fmt.Sprintf(`%s
+		SELECT
+			toString(%s) AS bucket,
+			item_key,
+			sum(item_revenue) AS revenue,
+			sum(item_quantity) AS quantity
+		FROM purchased_items
+		WHERE has(?, item_key)
+		GROUP BY bucket, item_key
+		ORDER BY bucket, item_key
+	`, purchasedItems, fmt.Sprintf(timeBucketExprs[query.Interval], "date"))
+
+	// Arguments follow the text: the WHERE clause, the time zone, then the keys
+	seriesArgs := append(append(args, query.TimeZone()), keys)
+
+	var buckets []itemBucketRow
+	if err := clickhouse.SelectWithContext(ctx, r.db, &buckets, seriesQuery, seriesArgs...); err != nil {
+		return nil, fmt.Errorf("failed to query top items series: %w", err)
+	}
+
+	for _, bucket := range buckets {
+		item := &result.Items[index[bucket.Key]]
+		item.Series = append(item.Series, eventDomain.ItemBucket{
+			Bucket:   bucket.Bucket,
+			Revenue:  bucket.Revenue,
+			Quantity: bucket.Quantity,
+		})
+	}
+
+	return result, nil
+}
@@ -3,8 +3,12 @@ package clickhouse
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/ebubekir/event-stream/internal/domain"
+	eventDomain "github.com/ebubekir/event-stream/internal/domain/event"
 	"github.com/ebubekir/event-stream/pkg/clickhouse"
 )
 
@@ -49,60 +53,117 @@ type eventModel struct {
 	DeviceBrowserName            string `db:"device_browser_name"`
 	DeviceBrowserVersion         string `db:"device_browser_version"`
 	DeviceHostname               string `db:"device_hostname"`
+	// Geo fields flattened
+	GeoContinent    string `db:"geo_continent"`
+	GeoSubContinent string `db:"geo_sub_continent"`
+	GeoCountry      string `db:"geo_country"`
+	GeoRegion       string `db:"geo_region"`
+	GeoMetro        string `db:"geo_metro"`
+	GeoCity         string `db:"geo_city"`
 	// AppInfo fields flattened
 	AppInfoID      string `db:"app_info_id"`
 	AppInfoVersion string `db:"app_info_version"`
 	// Items as parallel arrays
-	ItemIDs           []string  `db:"item_ids"`
-	ItemNames         []string  `db:"item_names"`
-	ItemBrands        []string  `db:"item_brands"`
-	ItemVariants      []string  `db:"item_variants"`
-	ItemPricesInUsd   []float64 `db:"item_prices_in_usd"`
-	ItemQuantities    []int32   `db:"item_quantities"`
-	ItemRevenuesInUsd []float64 `db:"item_revenues_in_usd"`
+	ItemIDs            []string  `db:"item_ids"`
+	ItemNames          []string  `db:"item_names"`
+	ItemBrands         []string  `db:"item_brands"`
+	ItemVariants       []string  `db:"item_variants"`
+	ItemPricesInUsd    []float64 `db:"item_prices_in_usd"`
+	ItemQuantities     []int32   `db:"item_quantities"`
+	ItemRevenuesInUsd  []float64 `db:"item_revenues_in_usd"`
+	ItemLocationIDs    []string  `db:"item_location_ids"`
+	ItemListIDs        []string  `db:"item_list_ids"`
+	ItemListNames      []string  `db:"item_list_names"`
+	ItemPromotionIDs   []string  `db:"item_promotion_ids"`
+	ItemPromotionNames []string  `db:"item_promotion_names"`
+	// Item params as one set of parallel arrays per item
+	ItemParamKeys          [][]string  `db:"item_param_keys"`
+	ItemParamStringValues  [][]string  `db:"item_param_string_values"`
+	ItemParamNumberValues  [][]float64 `db:"item_param_number_values"`
+	ItemParamBooleanValues [][]uint8   `db:"item_param_boolean_values"`
+	// Ingestion metadata populated server-side
+	ReceivedAt     time.Time `db:"received_at"`
+	IngestEndpoint string    `db:"ingest_endpoint"`
+	RequestID      string    `db:"request_id"`
+	ClientIPHash   string    `db:"client_ip_hash"`
+	SDKName        string    `db:"sdk_name"`
+	SDKVersion     string    `db:"sdk_version"`
+	APIKeyID       string    `db:"api_key_id"`
 }
 
+// eventColumns lists the columns written for every event, in insert order
+const eventColumns = `
+	id, name, channel_type, timestamp, previous_timestamp, date,
+	user_id, user_pseudo_id,
+	event_param_keys, event_param_string_values, event_param_number_values, event_param_boolean_values,
+	user_param_keys, user_param_string_values, user_param_number_values, user_param_boolean_values,
+	device_category, device_mobile_brand_name, device_mobile_model_name,
+	device_operating_system, device_operating_system_version,
+	device_language, device_browser_name, device_browser_version, device_hostname,
+	geo_continent, geo_sub_continent, geo_country, geo_region, geo_metro, geo_city,
+	app_info_id, app_info_version,
+	item_ids, item_names, item_brands, item_variants,
+	item_prices_in_usd, item_quantities, item_revenues_in_usd,
+	item_location_ids, item_list_ids, item_list_names, item_promotion_ids, item_promotion_names,
+	item_param_keys, item_param_string_values, item_param_number_values, item_param_boolean_values,
+	received_at, ingest_endpoint, request_id, client_ip_hash,
+	sdk_name, sdk_version, api_key_id`
+
+// eventValues lists the named parameters matching eventColumns
+const eventValues = `
+	:id, :name, :channel_type, :timestamp, :previous_timestamp, :date,
+	:user_id, :user_pseudo_id,
+	:event_param_keys, :event_param_string_values, :event_param_number_values, :event_param_boolean_values,
+	:user_param_keys, :user_param_string_values, :user_param_number_values, :user_param_boolean_values,
+	:device_category, :device_mobile_brand_name, :device_mobile_model_name,
+	:device_operating_system, :device_operating_system_version,
+	:device_language, :device_browser_name, :device_browser_version, :device_hostname,
+	:geo_continent, :geo_sub_continent, :geo_country, :geo_region, :geo_metro, :geo_city,
+	:app_info_id, :app_info_version,
+	:item_ids, :item_names, :item_brands, :item_variants,
+	:item_prices_in_usd, :item_quantities, :item_revenues_in_usd,
+	:item_location_ids, :item_list_ids, :item_list_names, :item_promotion_ids, :item_promotion_names,
+	:item_param_keys, :item_param_string_values, :item_param_number_values, :item_param_boolean_values,
+	:received_at, :ingest_endpoint, :request_id, :client_ip_hash,
+	:sdk_name, :sdk_version, :api_key_id`
+
+// insertEventQuery is the named INSERT statement for the events table
+var insertEventQuery = fmt.Sprintf("INSERT INTO events (%s\n) VALUES (%s\n)", eventColumns, eventValues)
+
+// selectEventColumns lists eventColumns for reading. DateTime does not scan
+// into a string, so date is formatted as RFC3339 like incoming events.
+var selectEventColumns = strings.Replace(eventColumns, " date,", " formatDateTime(date, '%Y-%m-%dT%H:%i:%SZ', 'UTC') AS date,", 1)
+
 // Save persists a single event to ClickHouse
 func (r *EventRepository) Save(ctx context.Context, event *domain.Event) error {
 	model := toModel(event)
 
-	query := `
-		INSERT INTO events (
-			id, name, channel_type, timestamp, previous_timestamp, date,
-			user_id, user_pseudo_id,
-			event_param_keys, event_param_string_values, event_param_number_values, event_param_boolean_values,
-			user_param_keys, user_param_string_values, user_param_number_values, user_param_boolean_values,
-			device_category, device_mobile_brand_name, device_mobile_model_name,
-			device_operating_system, device_operating_system_version,
-			device_language, device_browser_name, device_browser_version, device_hostname,
-			app_info_id, app_info_version,
-			item_ids, item_names, item_brands, item_variants,
-			item_prices_in_usd, item_quantities, item_revenues_in_usd
-		) VALUES (
-			:id, :name, :channel_type, :timestamp, :previous_timestamp, :date,
-			:user_id, :user_pseudo_id,
-			:event_param_keys, :event_param_string_values, :event_param_number_values, :event_param_boolean_values,
-			:user_param_keys, :user_param_string_values, :user_param_number_values, :user_param_boolean_values,
-			:device_category, :device_mobile_brand_name, :device_mobile_model_name,
-			:device_operating_system, :device_operating_system_version,
-			:device_language, :device_browser_name, :device_browser_version, :device_hostname,
-			:app_info_id, :app_info_version,
-			:item_ids, :item_names, :item_brands, :item_variants,
-			:item_prices_in_usd, :item_quantities, :item_revenues_in_usd
-		)
-	`
-
-	if err := clickhouse.NamedExec(r.db, query, model); err != nil {
+	if err := clickhouse.NamedExec(r.db, insertEventQuery, model); err != nil {
 		return fmt.Errorf("failed to insert event: %w", err)
 	}
 
 	return nil
 }
 
-// SaveBatch persists multiple events using ClickHouse batch insert
-func (r *EventRepository) SaveBatch(ctx context.Context, events []*domain.Event) error {
+// SaveBatch persists multiple events using ClickHouse batch insert. A batch
+// dropped by insert_deduplication_token is not detected and is still returned.
+func (r *EventRepository) SaveBatch(ctx context.Context, events []*domain.Event) ([]*domain.Event, error) {
 	if len(events) == 0 {
-		return nil
+		return nil, nil
+	}
+
+	if eventDomain.SkipStored(ctx) {
+		// MergeTree keeps duplicate rows, so a repeated write has to be
+		// filtered before it is inserted; the idx_id bloom filter keeps the
+		// lookup to a few granules
+		stored, err := storedIDs(ctx, r.db, "events", eventIDs(events))
+		if err != nil {
+			return nil, err
+		}
+		events = slices.DeleteFunc(slices.Clone(events), func(event *domain.Event) bool { return stored[event.ID] })
+		if len(events) == 0 {
+			return nil, nil
+		}
 	}
 
 	models := make([]eventModel, len(events))
@@ -110,68 +171,227 @@ func (r *EventRepository) SaveBatch(ctx context.Context, events []*domain.Event)
 		models[i] = *toModel(event)
 	}
 
-	query := `
-		INSERT INTO events (
-			id, name, channel_type, timestamp, previous_timestamp, date,
-			user_id, user_pseudo_id,
-			event_param_keys, event_param_string_values, event_param_number_values, event_param_boolean_values,
-			user_param_keys, user_param_string_values, user_param_number_values, user_param_boolean_values,
-			device_category, device_mobile_brand_name, device_mobile_model_name,
-			device_operating_system, device_operating_system_version,
-			device_language, device_browser_name, device_browser_version, device_hostname,
-			app_info_id, app_info_version,
-			item_ids, item_names, item_brands, item_variants,
-			item_prices_in_usd, item_quantities, item_revenues_in_usd
-		) VALUES (
-			:id, :name, :channel_type, :timestamp, :previous_timestamp, :date,
-			:user_id, :user_pseudo_id,
-			:event_param_keys, :event_param_string_values, :event_param_number_values, :event_param_boolean_values,
-			:user_param_keys, :user_param_string_values, :user_param_number_values, :user_param_boolean_values,
-			:device_category, :device_mobile_brand_name, :device_mobile_model_name,
-			:device_operating_system, :device_operating_system_version,
-			:device_language, :device_browser_name, :device_browser_version, :device_hostname,
-			:app_info_id, :app_info_version,
-			:item_ids, :item_names, :item_brands, :item_variants,
-			:item_prices_in_usd, :item_quantities, :item_revenues_in_usd
-		)
-	`
-
-	if err := clickhouse.BatchInsert(r.db, query, models); err != nil {
-		return fmt.Errorf("failed to batch insert events: %w", err)
+	if err := batchInsert(ctx, r.db, insertEventQuery, models); err != nil {
+		return nil, fmt.Errorf("failed to batch insert events: %w", err)
 	}
 
-	return nil
+	return events, nil
 }
 
-func toModel(event *domain.Event) *eventModel {
-	// Convert EventParams to parallel arrays
-	eventParamKeys := make([]string, len(event.EventParams))
-	eventParamStringValues := make([]string, len(event.EventParams))
-	eventParamNumberValues := make([]float64, len(event.EventParams))
-	eventParamBooleanValues := make([]uint8, len(event.EventParams))
-	for i, p := range event.EventParams {
-		eventParamKeys[i] = p.Key
-		eventParamStringValues[i] = p.StringValue
-		eventParamNumberValues[i] = p.NumberValue
-		if p.BooleanValue {
-			eventParamBooleanValues[i] = 1
+// storedIDs returns which of ids are stored in table
+func storedIDs(ctx context.Context, db *clickhouse.ClickHouseDb, table string, ids []string) (map[string]bool, error) {
+	var stored []string
+	if err := clickhouse.SelectWithContext(ctx, db, &stored, fmt.Sprintf("SELECT DISTINCT id FROM %s WHERE has(?, id)", table), ids); err != nil {
+		return nil, fmt.Errorf("failed to look up stored events: %w", err)
+	}
+
+	storedIDs := make(map[string]bool, len(stored))
+	for _, id := range stored {
+		storedIDs[id] = true
+	}
+	return storedIDs, nil
+}
+
+// eventIDs returns the IDs of events
+func eventIDs(events []*domain.Event) []string {
+	ids := make([]string, len(events))
+	for i, event := range events {
+		ids[i] = event.ID
+	}
+	return ids
+}
+
+// FindByID returns the event with the given ID. The ID is not part of the
+// sorting key, so the lookup relies on the idx_id bloom filter to skip granules.
+func (r *EventRepository) FindByID(ctx context.Context, id string) (*domain.Event, error) {
+	query := fmt.Sprintf("SELECT %s\nFROM events\nWHERE id = ?\nLIMIT 1", selectEventColumns)
+
+	var rows []eventModel
+	if err := clickhouse.SelectWithContext(ctx, r.db, &rows, query, id); err != nil {
+		return nil, fmt.Errorf("failed to find event: %w", err)
+	}
+
+	if len(rows) == 0 {
+		return nil, eventDomain.ErrEventNotFound
+	}
+
+	return fromModel(&rows[0]), nil
+}
+
+// Search returns a page of events matching the query. Pages continue after
+// the cursor's date and ID rather than at an offset, so they are not shifted
+// by concurrent inserts and stay cheap deep into the result. User timelines
+// filter on user_id or user_pseudo_id, which the idx_user_id and
+// idx_user_pseudo_id bloom filters narrow down to a few granules.
+func (r *EventRepository) Search(ctx context.Context, query *eventDomain.EventSearchQuery) ([]*domain.Event, error) {
+	where, args := filterConditions(query.Filter)
+
+	direction, comparison := "ASC", ">"
+	if query.Order == eventDomain.SortDescending {
+		direction, comparison = "DESC", "<"
+	}
+	if query.After != nil {
+		condition := fmt.Sprintf("(date %[1]s ? OR (date = ? AND id %[1]s ?))", comparison)
+		if where == "" {
+			where = "WHERE " + condition
+		} else {
+			where += " AND " + condition
 		}
+		args = append(args, query.After.Date, query.After.Date, query.After.ID)
 	}
 
-	// Convert UserParams to parallel arrays
-	userParamKeys := make([]string, len(event.UserParams))
-	userParamStringValues := make([]string, len(event.UserParams))
-	userParamNumberValues := make([]float64, len(event.UserParams))
-	userParamBooleanValues := make([]uint8, len(event.UserParams))
-	for i, p := range event.UserParams {
-		userParamKeys[i] = p.Key
-		userParamStringValues[i] = p.StringValue
-		userParamNumberValues[i] = p.NumberValue
-		if p.BooleanValue {
-			userParamBooleanValues[i] = 1
+	sql := fmt.Sprintf("SELECT %s\nFROM events\n%s\nORDER BY date %[3]s, id %[3]s\nLIMIT %[4]d",
+		selectEventColumns, where, direction, query.Limit)
+
+	var rows []eventModel
+	if err := clickhouse.SelectWithContext(ctx, r.db, &rows, sql, args...); err != nil {
+		return nil, fmt.Errorf("failed to search events: %w", err)
+	}
+
+	events := make([]*domain.Event, len(rows))
+	for i := range rows {
+		events[i] = fromModel(&rows[i])
+	}
+	return events, nil
+}
+
+// Stream calls fn for each event matching filter, ordered by date and ID
+func (r *EventRepository) Stream(ctx context.Context, filter eventDomain.EventFilter, fn func(*domain.Event) error) error {
+	where, args := filterConditions(filter)
+	query := fmt.Sprintf("SELECT %s\nFROM events\n%s\nORDER BY date, id", selectEventColumns, where)
+
+	rows, err := clickhouse.QueryWithContext(ctx, r.db, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query events: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var model eventModel
+		if err := rows.StructScan(&model); err != nil {
+			return fmt.Errorf("failed to scan event: %w", err)
+		}
+		if err := fn(fromModel(&model)); err != nil {
+			return err
 		}
 	}
 
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read events: %w", err)
+	}
+	return nil
+}
+
+// paramKeysRow represents the distinct param keys of a set of events
+type paramKeysRow struct {
+	EventParams []string `db:"event_params"`
+	UserParams  []string `db:"user_params"`
+}
+
+// ParamKeys returns the sorted distinct event and user param keys of the events matching filter
+func (r *EventRepository) ParamKeys(ctx context.Context, filter eventDomain.EventFilter) (*eventDomain.ParamKeys, error) {
+	where, args := filterConditions(filter)
+	query := fmt.Sprintf(`
+		SELECT
+			arraySort(groupUniqArrayArray(event_param_keys)) AS event_params,
+			arraySort(groupUniqArrayArray(user_param_keys)) AS user_params
+		FROM events
+		%s
+	`, where)
+
+	var rows []paramKeysRow
+	if err := clickhouse.SelectWithContext(ctx, r.db, &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to query param keys: %w", err)
+	}
+
+	keys := &eventDomain.ParamKeys{}
+	if len(rows) > 0 {
+		keys.EventParams = rows[0].EventParams
+		keys.UserParams = rows[0].UserParams
+	}
+	return keys, nil
+}
+
+// DeleteRange removes the events matching filter. The lightweight delete
+// hides the rows at once; they are purged on the next merge.
+func (r *EventRepository) DeleteRange(ctx context.Context, filter eventDomain.EventFilter) error {
+	if filter.From.IsZero() || filter.To.IsZero() {
+		return eventDomain.ErrUnboundedDelete
+	}
+
+	where, args := filterConditions(filter)
+	if err := clickhouse.ExecWithContext(ctx, r.db, "DELETE FROM events "+where, args...); err != nil {
+		return fmt.Errorf("failed to delete events: %w", err)
+	}
+	return nil
+}
+
+// filterConditions returns the WHERE clause and arguments for filter
+func filterConditions(filter eventDomain.EventFilter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	if !filter.From.IsZero() {
+		conditions = append(conditions, "date >= ?")
+		args = append(args, filter.From)
+	}
+	if !filter.To.IsZero() {
+		conditions = append(conditions, "date < ?")
+		args = append(args, filter.To)
+	}
+	if len(filter.EventNames) > 0 {
+		conditions = append(conditions, "has(?, name)")
+		args = append(args, filter.EventNames)
+	}
+
+	candidates := []columnCondition{
+		{column: "channel_type", value: filter.ChannelType},
+		{column: "user_id", value: filter.UserID},
+		{column: "user_pseudo_id", value: filter.UserPseudoID},
+		{column: "app_info_id", value: filter.AppID},
+		{column: "app_info_version", value: filter.AppVersion},
+		{column: "device_category", value: filter.DeviceCategory},
+		{column: "device_operating_system", value: filter.OperatingSystem},
+		{column: "device_browser_name", value: filter.BrowserName},
+		{column: "device_language", value: filter.Language},
+	}
+	for _, c := range candidates {
+		if c.value != "" {
+			conditions = append(conditions, c.column+" = ?")
+			args = append(args, c.value)
+		}
+	}
+
+	if paramFilter, ok := filter.ParamFilter(); ok {
+		condition, paramArgs := fieldFilterCondition(paramFilter)
+		conditions = append(conditions, condition)
+		args = append(args, paramArgs...)
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// batchInsert inserts models as a single block tagged with the context's dedup
+// token, so ClickHouse drops a redelivered batch it has already stored.
+// Without a token it falls back to a plain batch insert.
+func batchInsert[T any](ctx context.Context, db *clickhouse.ClickHouseDb, query string, models []T) error {
+	token := eventDomain.DedupToken(ctx)
+	if token == "" {
+		return clickhouse.BatchInsert(db, query, models)
+	}
+
+	ctx = clickhouse.WithSettings(ctx, map[string]any{"insert_deduplication_token": token})
+	return clickhouse.BatchInsertWithContext(ctx, db, query, models)
+}
+
+func toModel(event *domain.Event) *eventModel {
+	// Convert EventParams and UserParams to parallel arrays
+	eventParamKeys, eventParamStringValues, eventParamNumberValues, eventParamBooleanValues := toParamArrays(event.EventParams)
+	userParamKeys, userParamStringValues, userParamNumberValues, userParamBooleanValues := toParamArrays(event.UserParams)
+
 	// Convert Items to parallel arrays
 	itemIDs := make([]string, len(event.Items))
 	itemNames := make([]string, len(event.Items))
@@ -180,6 +400,15 @@ func toModel(event *domain.Event) *eventModel {
 	itemPricesInUsd := make([]float64, len(event.Items))
 	itemQuantities := make([]int32, len(event.Items))
 	itemRevenuesInUsd := make([]float64, len(event.Items))
+	itemLocationIDs := make([]string, len(event.Items))
+	itemListIDs := make([]string, len(event.Items))
+	itemListNames := make([]string, len(event.Items))
+	itemPromotionIDs := make([]string, len(event.Items))
+	itemPromotionNames := make([]string, len(event.Items))
+	itemParamKeys := make([][]string, len(event.Items))
+	itemParamStringValues := make([][]string, len(event.Items))
+	itemParamNumberValues := make([][]float64, len(event.Items))
+	itemParamBooleanValues := make([][]uint8, len(event.Items))
 	for i, item := range event.Items {
 		itemIDs[i] = item.ID
 		itemNames[i] = item.Name
@@ -188,6 +417,12 @@ func toModel(event *domain.Event) *eventModel {
 		itemPricesInUsd[i] = item.PriceInUsd
 		itemQuantities[i] = int32(item.Quantity)
 		itemRevenuesInUsd[i] = item.RevenueInUsd
+		itemLocationIDs[i] = item.LocationId
+		itemListIDs[i] = item.ListId
+		itemListNames[i] = item.ListName
+		itemPromotionIDs[i] = item.PromotionId
+		itemPromotionNames[i] = item.PromotionName
+		itemParamKeys[i], itemParamStringValues[i], itemParamNumberValues[i], itemParamBooleanValues[i] = toParamArrays(item.Params)
 	}
 
 	return &eventModel{
@@ -216,6 +451,12 @@ func toModel(event *domain.Event) *eventModel {
 		DeviceBrowserName:            event.Device.BrowserName,
 		DeviceBrowserVersion:         event.Device.BrowserVersion,
 		DeviceHostname:               event.Device.Hostname,
+		GeoContinent:                 event.Geo.Continent,
+		GeoSubContinent:              event.Geo.SubContinent,
+		GeoCountry:                   event.Geo.Country,
+		GeoRegion:                    event.Geo.Region,
+		GeoMetro:                     event.Geo.Metro,
+		GeoCity:                      event.Geo.City,
 		AppInfoID:                    event.AppInfo.ID,
 		AppInfoVersion:               event.AppInfo.Version,
 		ItemIDs:                      itemIDs,
@@ -225,5 +466,137 @@ func toModel(event *domain.Event) *eventModel {
 		ItemPricesInUsd:              itemPricesInUsd,
 		ItemQuantities:               itemQuantities,
 		ItemRevenuesInUsd:            itemRevenuesInUsd,
+		ItemLocationIDs:              itemLocationIDs,
+		ItemListIDs:                  itemListIDs,
+		ItemListNames:                itemListNames,
+		ItemPromotionIDs:             itemPromotionIDs,
+		ItemPromotionNames:           itemPromotionNames,
+		ItemParamKeys:                itemParamKeys,
+		ItemParamStringValues:        itemParamStringValues,
+		ItemParamNumberValues:        itemParamNumberValues,
+		ItemParamBooleanValues:       itemParamBooleanValues,
+		ReceivedAt:                   event.Ingestion.ReceivedAt,
+		IngestEndpoint:               event.Ingestion.Endpoint,
+		RequestID:                    event.Ingestion.RequestID,
+		ClientIPHash:                 event.Ingestion.ClientIPHash,
+		SDKName:                      event.Ingestion.SDKName,
+		SDKVersion:                   event.Ingestion.SDKVersion,
+		APIKeyID:                     event.Ingestion.APIKeyID,
+	}
+}
+
+// toParamArrays converts params to the parallel arrays stored in ClickHouse
+func toParamArrays(params []domain.Param) ([]string, []string, []float64, []uint8) {
+	keys := make([]string, len(params))
+	stringValues := make([]string, len(params))
+	numberValues := make([]float64, len(params))
+	booleanValues := make([]uint8, len(params))
+	for i, p := range params {
+		keys[i] = p.Key
+		stringValues[i] = p.StringValue
+		numberValues[i] = p.NumberValue
+		if p.BooleanValue {
+			booleanValues[i] = 1
+		}
+	}
+	return keys, stringValues, numberValues, booleanValues
+}
+
+// fromParamArrays converts stored parallel arrays back to params
+func fromParamArrays(keys, stringValues []string, numberValues []float64, booleanValues []uint8) []domain.Param {
+	params := make([]domain.Param, len(keys))
+	for i, key := range keys {
+		params[i] = domain.Param{
+			Key:          key,
+			StringValue:  stringValues[i],
+			NumberValue:  numberValues[i],
+			BooleanValue: booleanValues[i] == 1,
+		}
+	}
+	return params
+}
+
+// at returns the element at i, or the zero value for rows written before
+// the column existed
+func at[T any](values []T, i int) T {
+	var zero T
+	if i >= len(values) {
+		return zero
+	}
+	return values[i]
+}
+
+func fromModel(model *eventModel) *domain.Event {
+	eventParams := fromParamArrays(model.EventParamKeys, model.EventParamStringValues, model.EventParamNumberValues, model.EventParamBooleanValues)
+	userParams := fromParamArrays(model.UserParamKeys, model.UserParamStringValues, model.UserParamNumberValues, model.UserParamBooleanValues)
+
+	items := make([]domain.Item, len(model.ItemIDs))
+	for i, id := range model.ItemIDs {
+		items[i] = domain.Item{
+			ID:            id,
+			Name:          model.ItemNames[i],
+			Brand:         model.ItemBrands[i],
+			Variant:       model.ItemVariants[i],
+			PriceInUsd:    model.ItemPricesInUsd[i],
+			Quantity:      int(model.ItemQuantities[i]),
+			RevenueInUsd:  model.ItemRevenuesInUsd[i],
+			LocationId:    at(model.ItemLocationIDs, i),
+			ListId:        at(model.ItemListIDs, i),
+			ListName:      at(model.ItemListNames, i),
+			PromotionId:   at(model.ItemPromotionIDs, i),
+			PromotionName: at(model.ItemPromotionNames, i),
+			Params: fromParamArrays(
+				at(model.ItemParamKeys, i),
+				at(model.ItemParamStringValues, i),
+				at(model.ItemParamNumberValues, i),
+				at(model.ItemParamBooleanValues, i),
+			),
+		}
+	}
+
+	return &domain.Event{
+		ID:                model.ID,
+		Name:              model.Name,
+		ChannelType:       domain.ChannelType(model.ChannelType),
+		Timestamp:         model.Timestamp,
+		PreviousTimestamp: model.PreviousTimestamp,
+		Date:              model.Date,
+		EventParams:       eventParams,
+		UserID:            model.UserID,
+		UserPseudoID:      model.UserPseudoID,
+		UserParams:        userParams,
+		Device: domain.Device{
+			Category:               model.DeviceCategory,
+			MobileBrandName:        model.DeviceMobileBrandName,
+			MobileModelName:        model.DeviceMobileModelName,
+			OperatingSystem:        model.DeviceOperatingSystem,
+			OperatingSystemVersion: model.DeviceOperatingSystemVersion,
+			Language:               model.DeviceLanguage,
+			BrowserName:            model.DeviceBrowserName,
+			BrowserVersion:         model.DeviceBrowserVersion,
+			Hostname:               model.DeviceHostname,
+		},
+		Geo: domain.Geo{
+			Continent:    model.GeoContinent,
+			SubContinent: model.GeoSubContinent,
+			Country:      model.GeoCountry,
+			Region:       model.GeoRegion,
+			Metro:        model.GeoMetro,
+			City:         model.GeoCity,
+		},
+		AppInfo: domain.AppInfo{
+			ID:      model.AppInfoID,
+			Version: model.AppInfoVersion,
+		},
+		Items: items,
+		Ingestion: domain.IngestionMetadata{
+			ReceivedAt:   model.ReceivedAt,
+			Endpoint:     model.IngestEndpoint,
+			RequestID:    model.RequestID,
+			ClientIPHash: model.ClientIPHash,
+			SDKName:      model.SDKName,
+			SDKVersion:   model.SDKVersion,
+			APIKeyID:     model.APIKeyID,
+		},
 	}
 }
@@ -0,0 +1,125 @@
+package clickhouse
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	eventDomain "github.com/ebubekir/event-stream/internal/domain/event"
+)
+
+// paramArrays lists the parallel key, string, number and boolean value
+// arrays each param scope is stored in
+var paramArrays = map[eventDomain.FieldScope][4]string{
+	eventDomain.FieldScopeEventParam: {"event_param_keys", "event_param_string_values", "event_param_number_values", "event_param_boolean_values"},
+	eventDomain.FieldScopeUserParam:  {"user_param_keys", "user_param_string_values", "user_param_number_values", "user_param_boolean_values"},
+}
+
+// fieldColumn returns the column a device, app info or channel field is stored in
+func fieldColumn(field eventDomain.Field) string {
+	switch field.Scope {
+	case eventDomain.FieldScopeDevice:
+		return "device_" + field.Name
+	case eventDomain.FieldScopeAppInfo:
+		return "app_info_" + field.Name
+	default:
+		return "channel_type"
+	}
+}
+
+// fieldFilterCondition compiles a validated filter into a condition and its
+// arguments. Field names come from a fixed set, so only values are bound.
+func fieldFilterCondition(filter eventDomain.FieldFilter) (string, []interface{}) {
+	if filter.Field.IsParam() {
+		return paramFilterCondition(filter)
+	}
+
+	column := fieldColumn(filter.Field)
+	switch filter.Operator {
+	case eventDomain.FilterNotEquals:
+		return column + " != ?", []interface{}{filter.Values[0]}
+	case eventDomain.FilterIn:
+		return fmt.Sprintf("has(?, %s)", column), []interface{}{filter.Values}
+	case eventDomain.FilterContains:
+		return fmt.Sprintf("position(%s, ?) > 0", column), []interface{}{filter.Values[0]}
+	case eventDomain.FilterRegex:
+		return fmt.Sprintf("match(%s, ?)", column), []interface{}{filter.Values[0]}
+	case eventDomain.FilterExists:
+		return column + " != ''", nil
+	default:
+		return column + " = ?", []interface{}{filter.Values[0]}
+	}
+}
+
+// paramFilterCondition matches events with a param of the filter's key whose
+// value passes the filter, looking at the param arrays element by element
+func paramFilterCondition(filter eventDomain.FieldFilter) (string, []interface{}) {
+	arrays := paramArrays[filter.Field.Scope]
+	if filter.Operator == eventDomain.FilterExists {
+		return fmt.Sprintf("has(%s, ?)", arrays[0]), []interface{}{filter.Field.Name}
+	}
+
+	match, matchArgs := paramValueCondition(filter)
+	condition := fmt.Sprintf("arrayExists((k, s, n, b) -> k = ? AND %s, %s)", match, strings.Join(arrays[:], ", "))
+	if filter.Operator == eventDomain.FilterNotEquals {
+		condition = "NOT " + condition
+	}
+	return condition, append([]interface{}{filter.Field.Name}, matchArgs...)
+}
+
+// paramValueCondition compares a param's string (s), number (n) and boolean (b) value
+func paramValueCondition(filter eventDomain.FieldFilter) (string, []interface{}) {
+	switch filter.Operator {
+	case eventDomain.FilterIn:
+		var matches []string
+		var args []interface{}
+		for _, value := range filter.Values {
+			match, matchArgs := paramEquals(value)
+			matches = append(matches, match)
+			args = append(args, matchArgs...)
+		}
+		return "(" + strings.Join(matches, " OR ") + ")", args
+	case eventDomain.FilterContains:
+		return "position(s, ?) > 0", []interface{}{filter.Values[0]}
+	case eventDomain.FilterRegex:
+		return "match(s, ?)", []interface{}{filter.Values[0]}
+	case eventDomain.FilterGreater, eventDomain.FilterGreaterOrEqual, eventDomain.FilterLess, eventDomain.FilterLessOrEqual:
+		number, _ := filter.Number()
+		return fmt.Sprintf("(s = '' AND n %s ?)", numericOperators[filter.Operator]), []interface{}{number}
+	default:
+		// eq, and neq negated by the caller
+		return paramEquals(filter.Values[0])
+	}
+}
+
+// paramEquals matches a param value by its string value, its number value
+// when value is a number, or a true boolean value when value is "true"
+func paramEquals(value string) (string, []interface{}) {
+	matches := []string{"s = ?"}
+	args := []interface{}{value}
+	if number, err := strconv.ParseFloat(value, 64); err == nil {
+		matches = append(matches, "(s = '' AND n = ?)")
+		args = append(args, number)
+	}
+	if value == "true" {
+		matches = append(matches, "b = 1")
+	}
+	return "(" + strings.Join(matches, " OR ") + ")", args
+}
+
+// numericOperators maps the numeric filter operators to SQL
+var numericOperators = map[eventDomain.FilterOperator]string{
+	eventDomain.FilterGreater:        ">",
+	eventDomain.FilterGreaterOrEqual: ">=",
+	eventDomain.FilterLess:           "<",
+	eventDomain.FilterLessOrEqual:    "<=",
+}
+
+// paramValueExpr returns the value of the field's param as a string: its
+// string value, "true" for a true boolean value, or else its number value.
+// It is empty when the event has no such param and binds the param key.
+func paramValueExpr(field eventDomain.Field) string {
+	arrays := paramArrays[field.Scope]
+	return fmt.Sprintf("arrayFirst((v, k) -> k = ?, arrayMap((s, n, b) -> multiIf(s != '', s, b = 1, 'true', toString(n)), %s, %s, %s), %s)",
+		arrays[1], arrays[2], arrays[3], arrays[0])
+}
@@ -0,0 +1,111 @@
+package clickhouse
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	eventDomain "github.com/ebubekir/event-stream/internal/domain/event"
+)
+
+func TestFieldFilterCondition(t *testing.T) {
+	device := eventDomain.Field{Scope: eventDomain.FieldScopeDevice, Name: "category"}
+	channel := eventDomain.Field{Scope: eventDomain.FieldScopeChannel}
+	param := eventDomain.Field{Scope: eventDomain.FieldScopeEventParam, Name: "value"}
+	userParam := eventDomain.Field{Scope: eventDomain.FieldScopeUserParam, Name: "plan"}
+	paramArrays := "event_param_keys, event_param_string_values, event_param_number_values, event_param_boolean_values"
+
+	tests := []struct {
+		name     string
+		filter   eventDomain.FieldFilter
+		want     string
+		wantArgs []interface{}
+	}{
+		{
+			name:     "eq on device",
+			filter:   eventDomain.FieldFilter{Field: device, Operator: eventDomain.FilterEquals, Values: []string{"mobile"}},
+			want:     "device_category = ?",
+			wantArgs: []interface{}{"mobile"},
+		},
+		{
+			name:     "neq on channel",
+			filter:   eventDomain.FieldFilter{Field: channel, Operator: eventDomain.FilterNotEquals, Values: []string{"web"}},
+			want:     "channel_type != ?",
+			wantArgs: []interface{}{"web"},
+		},
+		{
+			name:     "in on device",
+			filter:   eventDomain.FieldFilter{Field: device, Operator: eventDomain.FilterIn, Values: []string{"mobile", "tablet"}},
+			want:     "has(?, device_category)",
+			wantArgs: []interface{}{[]string{"mobile", "tablet"}},
+		},
+		{
+			name:     "contains on app info",
+			filter:   eventDomain.FieldFilter{Field: eventDomain.Field{Scope: eventDomain.FieldScopeAppInfo, Name: "version"}, Operator: eventDomain.FilterContains, Values: []string{"beta"}},
+			want:     "position(app_info_version, ?) > 0",
+			wantArgs: []interface{}{"beta"},
+		},
+		{
+			name:     "regex on device",
+			filter:   eventDomain.FieldFilter{Field: device, Operator: eventDomain.FilterRegex, Values: []string{"^mob"}},
+			want:     "match(device_category, ?)",
+			wantArgs: []interface{}{"^mob"},
+		},
+		{
+			name:   "exists on device",
+			filter: eventDomain.FieldFilter{Field: device, Operator: eventDomain.FilterExists},
+			want:   "device_category != ''",
+		},
+		{
+			name:     "exists on param",
+			filter:   eventDomain.FieldFilter{Field: userParam, Operator: eventDomain.FilterExists},
+			want:     "has(user_param_keys, ?)",
+			wantArgs: []interface{}{"plan"},
+		},
+		{
+			name:     "eq on param with text",
+			filter:   eventDomain.FieldFilter{Field: param, Operator: eventDomain.FilterEquals, Values: []string{"gold"}},
+			want:     "arrayExists((k, s, n, b) -> k = ? AND (s = ?), " + paramArrays + ")",
+			wantArgs: []interface{}{"value", "gold"},
+		},
+		{
+			name:     "neq on param with number",
+			filter:   eventDomain.FieldFilter{Field: param, Operator: eventDomain.FilterNotEquals, Values: []string{"10"}},
+			want:     "NOT arrayExists((k, s, n, b) -> k = ? AND (s = ? OR (s = '' AND n = ?)), " + paramArrays + ")",
+			wantArgs: []interface{}{"value", "10", 10.0},
+		},
+		{
+			name:     "in on param",
+			filter:   eventDomain.FieldFilter{Field: param, Operator: eventDomain.FilterIn, Values: []string{"true", "2"}},
+			want:     "arrayExists((k, s, n, b) -> k = ? AND ((s = ? OR b = 1) OR (s = ? OR (s = '' AND n = ?))), " + paramArrays + ")",
+			wantArgs: []interface{}{"value", "true", "2", 2.0},
+		},
+		{
+			name:     "gte on param",
+			filter:   eventDomain.FieldFilter{Field: param, Operator: eventDomain.FilterGreaterOrEqual, Values: []string{"1.5"}},
+			want:     "arrayExists((k, s, n, b) -> k = ? AND (s = '' AND n >= ?), " + paramArrays + ")",
+			wantArgs: []interface{}{"value", 1.5},
+		},
+		{
+			name:     "regex on param",
+			filter:   eventDomain.FieldFilter{Field: param, Operator: eventDomain.FilterRegex, Values: []string{"^g"}},
+			want:     "arrayExists((k, s, n, b) -> k = ? AND match(s, ?), " + paramArrays + ")",
+			wantArgs: []interface{}{"value", "^g"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, args := fieldFilterCondition(tt.filter)
+			if got != tt.want {
+				t.Errorf("fieldFilterCondition() = %s, want %s", got, tt.want)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("fieldFilterCondition() args = %#v, want %#v", args, tt.wantArgs)
+			}
+			if placeholders := strings.Count(got, "?"); placeholders != len(args) {
+				t.Errorf("fieldFilterCondition() has %d placeholders for %d args", placeholders, len(args))
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ebubekir/event-stream/internal/domain"
+	"github.com/ebubekir/event-stream/pkg/clickhouse"
+)
+
+// IdentityRepository implements domain/event.IdentityRepository for ClickHouse
+type IdentityRepository struct {
+	db *clickhouse.ClickHouseDb
+}
+
+// NewIdentityRepository creates a new ClickHouse identity repository
+func NewIdentityRepository(db *clickhouse.ClickHouseDb) *IdentityRepository {
+	return &IdentityRepository{db: db}
+}
+
+// identityModel is the database model for identity links in ClickHouse
+type identityModel struct {
+	UserPseudoID string    `db:"user_pseudo_id"`
+	UserID       string    `db:"user_id"`
+	LinkedAt     time.Time `db:"linked_at"`
+}
+
+// Link persists identity links. The table is a ReplacingMergeTree keyed by
+// user_pseudo_id, so the latest link replaces older ones on merge.
+func (r *IdentityRepository) Link(ctx context.Context, links []domain.IdentityLink) error {
+	if len(links) == 0 {
+		return nil
+	}
+
+	models := make([]identityModel, len(links))
+	for i, link := range links {
+		models[i] = identityModel{
+			UserPseudoID: link.UserPseudoID,
+			UserID:       link.UserID,
+			LinkedAt:     link.LinkedAt,
+		}
+	}
+
+	query := `
+		INSERT INTO user_identities (user_pseudo_id, user_id, linked_at)
+		VALUES (:user_pseudo_id, :user_id, :linked_at)
+	`
+
+	if err := clickhouse.BatchInsert(r.db, query, models); err != nil {
+		return fmt.Errorf("failed to insert identity links: %w", err)
+	}
+
+	return nil
+}
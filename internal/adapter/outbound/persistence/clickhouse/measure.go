@@ -0,0 +1,82 @@
+package clickhouse
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	eventDomain "github.com/ebubekir/event-stream/internal/domain/event"
+)
+
+// measureAggregates are the aggregates of the non-percentile measures over
+// their values, %s
+var measureAggregates = map[eventDomain.MeasureFunction]string{
+	eventDomain.MeasureSum:           "sum(%s)",
+	eventDomain.MeasureAvg:           "avg(%s)",
+	eventDomain.MeasureMin:           "min(%s)",
+	eventDomain.MeasureMax:           "max(%s)",
+	eventDomain.MeasureCountDistinct: "uniqExact(%s)",
+}
+
+// percentileLevels are the levels of every percentile function, so that the
+// percentiles of a value share one quantiles state
+var percentileLevels = func() []string {
+	var levels []string
+	for _, function := range eventDomain.MeasureFunctions {
+		if level, ok := eventDomain.Percentiles[function]; ok {
+			levels = append(levels, strconv.FormatFloat(level, 'f', -1, 64))
+		}
+	}
+	return levels
+}()
+
+// measureValueExpr returns the value of the measure's param an event
+// contributes, NULL when it has none, and binds the param key: its number
+// value, or its value as text for count_distinct
+func measureValueExpr(measure eventDomain.Measure) (string, []interface{}) {
+	if measure.Function == eventDomain.MeasureCountDistinct {
+		return fmt.Sprintf("nullIf(%s, '')", paramValueExpr(measure.Field)), []interface{}{measure.Field.Name}
+	}
+
+	arrays := paramArrays[measure.Field.Scope]
+	return fmt.Sprintf("arrayFirst((v, k) -> k = ?, arrayMap((s, n) -> if(s = '', toNullable(n), NULL), %s, %s), %s)",
+		arrays[1], arrays[2], arrays[0]), []interface{}{measure.Field.Name}
+}
+
+// measureAggregateExpr returns the aggregate of a measure over the column
+// holding its values, NULL when no event has a value
+func measureAggregateExpr(measure eventDomain.Measure, column string) string {
+	aggregate, ok := measureAggregates[measure.Function]
+	if !ok {
+		// quantiles leaves no NULL for an empty set, so count the values first
+		aggregate = fmt.Sprintf("if(count(%%[1]s) > 0, quantiles(%s)(%%[1]s)[%d], NULL)",
+			strings.Join(percentileLevels, ", "), percentileIndex(measure.Function))
+	}
+	return fmt.Sprintf("CAST(%s AS Nullable(Float64))", fmt.Sprintf(aggregate, column))
+}
+
+// percentileIndex returns the 1-based position of a percentile function's
+// level in percentileLevels
+func percentileIndex(function eventDomain.MeasureFunction) int {
+	level := strconv.FormatFloat(eventDomain.Percentiles[function], 'f', -1, 64)
+	for i, l := range percentileLevels {
+		if l == level {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// measureColumns returns the value columns and aggregates of the query's
+// measures, and the arguments of the values. Values are named
+// measure_value_<i>, so that they may be used in a WITH clause or subquery.
+func measureColumns(measures []eventDomain.Measure) (values, aggregates []string, args []interface{}) {
+	for i, measure := range measures {
+		value, valueArgs := measureValueExpr(measure)
+		column := fmt.Sprintf("measure_value_%d", i)
+		values = append(values, fmt.Sprintf("%s AS %s", value, column))
+		aggregates = append(aggregates, measureAggregateExpr(measure, column))
+		args = append(args, valueArgs...)
+	}
+	return values, aggregates, args
+}
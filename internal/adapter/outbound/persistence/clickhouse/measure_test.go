@@ -0,0 +1,55 @@
+package clickhouse
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	eventDomain "github.com/ebubekir/event-stream/internal/domain/event"
+)
+
+func TestMeasureAggregateExpr(t *testing.T) {
+	tests := []struct {
+		function eventDomain.MeasureFunction
+		want     string
+	}{
+		{function: eventDomain.MeasureSum, want: "CAST(sum(v) AS Nullable(Float64))"},
+		{function: eventDomain.MeasureCountDistinct, want: "CAST(uniqExact(v) AS Nullable(Float64))"},
+		{function: eventDomain.MeasureP50, want: "CAST(if(count(v) > 0, quantiles(0.5, 0.9, 0.95, 0.99)(v)[1], NULL) AS Nullable(Float64))"},
+		{function: eventDomain.MeasureP99, want: "CAST(if(count(v) > 0, quantiles(0.5, 0.9, 0.95, 0.99)(v)[4], NULL) AS Nullable(Float64))"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.function), func(t *testing.T) {
+			measure := eventDomain.Measure{Function: tt.function, Field: eventDomain.Field{Scope: eventDomain.FieldScopeEventParam, Name: "load_time_ms"}}
+			if got := measureAggregateExpr(measure, "v"); got != tt.want {
+				t.Errorf("measureAggregateExpr() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMeasureColumns(t *testing.T) {
+	measures := []eventDomain.Measure{
+		{Function: eventDomain.MeasureAvg, Field: eventDomain.Field{Scope: eventDomain.FieldScopeEventParam, Name: "load_time_ms"}},
+		{Function: eventDomain.MeasureCountDistinct, Field: eventDomain.Field{Scope: eventDomain.FieldScopeUserParam, Name: "plan"}},
+	}
+
+	values, aggregates, args := measureColumns(measures)
+
+	if len(values) != 2 || !strings.HasSuffix(values[0], " AS measure_value_0") || !strings.HasSuffix(values[1], " AS measure_value_1") {
+		t.Fatalf("measureColumns() values = %v", values)
+	}
+	if !strings.Contains(values[0], "event_param_number_values") || !strings.Contains(values[1], "nullIf(") || !strings.Contains(values[1], "user_param_keys") {
+		t.Errorf("measureColumns() values = %v, want the number value then the text value", values)
+	}
+	if want := []string{"CAST(avg(measure_value_0) AS Nullable(Float64))", "CAST(uniqExact(measure_value_1) AS Nullable(Float64))"}; !reflect.DeepEqual(aggregates, want) {
+		t.Errorf("measureColumns() aggregates = %v, want %v", aggregates, want)
+	}
+	if want := []interface{}{"load_time_ms", "plan"}; !reflect.DeepEqual(args, want) {
+		t.Errorf("measureColumns() args = %v, want %v", args, want)
+	}
+	if placeholders := strings.Count(strings.Join(values, ", "), "?"); placeholders != len(args) {
+		t.Errorf("measureColumns() has %d placeholders for %d args", placeholders, len(args))
+	}
+}
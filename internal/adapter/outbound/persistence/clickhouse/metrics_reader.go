@@ -3,6 +3,7 @@ package clickhouse
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	eventDomain "github.com/ebubekir/event-stream/internal/domain/event"
 	"github.com/ebubekir/event-stream/pkg/clickhouse"
@@ -20,58 +21,61 @@ func NewMetricsReader(db *clickhouse.ClickHouseDb) *MetricsReader {
 
 // metricsRow represents a row from the metrics query
 type metricsRow struct {
-	TotalCount      int64 `db:"total_count"`
-	UniqueUserCount int64 `db:"unique_user_count"`
+	TotalCount      int64      `db:"total_count"`
+	UniqueUserCount int64      `db:"unique_user_count"`
+	Measures        []*float64 `db:"measures"`
 }
 
 // groupedMetricsRow represents a row from the grouped metrics query
 type groupedMetricsRow struct {
-	GroupKey        string `db:"group_key"`
-	TotalCount      int64  `db:"total_count"`
-	UniqueUserCount int64  `db:"unique_user_count"`
+	GroupKeys       []string   `db:"group_keys"`
+	TotalCount      int64      `db:"total_count"`
+	UniqueUserCount int64      `db:"unique_user_count"`
+	Measures        []*float64 `db:"measures"`
+	Filled          bool       `db:"filled"`
 }
 
 // GetMetrics retrieves aggregated metrics for events matching the query
 func (r *MetricsReader) GetMetrics(ctx context.Context, query *eventDomain.MetricsQuery) (*eventDomain.MetricsResult, error) {
 	result := &eventDomain.MetricsResult{
-		EventName: query.EventName,
-		From:      query.From,
-		To:        query.To,
+		EventNames: query.EventNames,
+		GroupBy:    query.GroupBy,
+		Measures:   query.Measures,
+		Fill:       query.Fill,
+		Location:   query.Location,
+		From:       query.From,
+		To:         query.To,
 	}
 
-	// Build base WHERE clause
-	whereClause := "WHERE name = ?"
-	args := []interface{}{query.EventName}
+	whereClause, args := metricsWhereClause(query)
 
-	if !query.From.IsZero() {
-		whereClause += " AND date >= ?"
-		args = append(args, query.From)
-	}
-
-	if !query.To.IsZero() {
-		whereClause += " AND date <= ?"
-		args = append(args, query.To)
+	// Get totals, with the measures' values as WITH expressions
+	measureValues, measureAggregates, measureArgs := measureColumns(query.Measures)
+	var with, measures string
+	if len(query.Measures) > 0 {
+		with = "WITH " + strings.Join(measureValues, ",\n\t\t\t")
+		measures = fmt.Sprintf(",\n\t\t\t[%s] AS measures", strings.Join(measureAggregates, ", "))
 	}
-
-	// Get totals
 	totalsQuery := fmt.Sprintf(`
+		%s
 		SELECT 
 			count() AS total_count,
-			uniqExact(user_id) AS unique_user_count
-		FROM events
+			uniqExact(%s) AS unique_user_count%s
+		FROM %s
 		%s
-	`, whereClause)
+	`, with, uniqueUserExpr(query), measures, fromClause(query), whereClause)
 
 	var totals metricsRow
-	if err := clickhouse.Get(r.db, &totals, totalsQuery, args...); err != nil {
+	if err := clickhouse.GetWithContext(ctx, r.db, &totals, totalsQuery, append(measureArgs, args...)...); err != nil {
 		return nil, fmt.Errorf("failed to query totals: %w", err)
 	}
 
 	result.TotalCount = totals.TotalCount
 	result.UniqueUserCount = totals.UniqueUserCount
+	result.MeasureValues = totals.Measures
 
-	// Get grouped metrics if aggregation is specified
-	if query.Aggregation != "" {
+	// Get grouped metrics if dimensions are specified
+	if len(query.GroupBy) > 0 {
 		groupedMetrics, err := r.getGroupedMetrics(ctx, query, whereClause, args)
 		if err != nil {
 			return nil, err
@@ -82,49 +86,305 @@ func (r *MetricsReader) GetMetrics(ctx context.Context, query *eventDomain.Metri
 	return result, nil
 }
 
-// getGroupedMetrics retrieves metrics grouped by the specified aggregation
-func (r *MetricsReader) getGroupedMetrics(ctx context.Context, query *eventDomain.MetricsQuery, whereClause string, args []interface{}) ([]eventDomain.GroupedMetric, error) {
-	var groupByExpr string
-	var selectExpr string
+// metricsWhereClause returns the WHERE clause selecting the events the
+// query counts, and its arguments
+func metricsWhereClause(query *eventDomain.MetricsQuery) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
 
-	switch query.Aggregation {
-	case eventDomain.AggregationByChannel:
-		groupByExpr = "channel_type"
-		selectExpr = "channel_type AS group_key"
-	case eventDomain.AggregationByDaily:
-		groupByExpr = "toDate(date)"
-		selectExpr = "toString(toDate(date)) AS group_key"
-	case eventDomain.AggregationByHourly:
-		groupByExpr = "toStartOfHour(date)"
-		selectExpr = "toString(toStartOfHour(date)) AS group_key"
+	switch len(query.EventNames) {
+	case 0:
+		// Every event
+	case 1:
+		conditions = append(conditions, "name = ?")
+		args = append(args, query.EventNames[0])
 	default:
-		return nil, nil
+		conditions = append(conditions, "has(?, name)")
+		args = append(args, query.EventNames)
+	}
+
+	if !query.From.IsZero() {
+		conditions = append(conditions, "date >= ?")
+		args = append(args, query.From)
+	}
+
+	if !query.To.IsZero() {
+		conditions = append(conditions, "date <= ?")
+		args = append(args, query.To)
+	}
+
+	// Narrow to events that arrived through a specific ingestion path
+	for _, cond := range ingestionConditions(query.Ingestion) {
+		conditions = append(conditions, cond.column+" = ?")
+		args = append(args, cond.value)
+	}
+
+	for _, filter := range query.Filters {
+		condition, filterArgs := fieldFilterCondition(filter)
+		conditions = append(conditions, condition)
+		args = append(args, filterArgs...)
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// getGroupedMetrics retrieves metrics grouped by the query's dimensions.
+// grouped_events holds each event's key for every dimension; with a group
+// limit, top_groups holds the most frequent combinations of the non-time
+// keys and the keys of every other event become OtherGroupKey. Filling adds
+// the missing time buckets of each combination of the other keys. Measures
+// are aggregated over the measure_value columns of grouped_events.
+func (r *MetricsReader) getGroupedMetrics(ctx context.Context, query *eventDomain.MetricsQuery, whereClause string, args []interface{}) ([]eventDomain.GroupedMetric, error) {
+	var dimensionColumns, limitedColumns []string
+	var dimensionArgs []interface{}
+	for i, dimension := range query.GroupBy {
+		expr, exprArgs := dimensionExpr(dimension, query.TimeZone())
+		if !dimension.IsTime() {
+			// Time buckets stay dates and times to be filled and ordered
+			expr = fmt.Sprintf("toString(%s)", expr)
+			limitedColumns = append(limitedColumns, fmt.Sprintf("dimension_%d", i))
+		}
+		dimensionColumns = append(dimensionColumns, fmt.Sprintf("%s AS dimension_%d", expr, i))
+		dimensionArgs = append(dimensionArgs, exprArgs...)
+	}
+	limited := query.GroupLimit > 0 && len(limitedColumns) > 0
+
+	// Filled rows leave the measures NULL
+	measureValues, measureAggregates, measureArgs := measureColumns(query.Measures)
+	eventColumns := append(dimensionColumns, measureValues...)
+	metricColumns := []string{"count() AS total_count", "uniqExact(user_key) AS unique_user_count"}
+	measures := make([]string, len(query.Measures))
+	for i, aggregate := range measureAggregates {
+		measures[i] = fmt.Sprintf("measure_%d", i)
+		metricColumns = append(metricColumns, fmt.Sprintf("%s AS %s", aggregate, measures[i]))
+	}
+	var measuresColumn string
+	if len(measures) > 0 {
+		measuresColumn = fmt.Sprintf(",\n\t\t\t[%s] AS measures", strings.Join(measures, ", "))
+	}
+
+	var topGroups string
+	if limited {
+		topGroups = fmt.Sprintf(`,
+		top_groups AS (
+			SELECT %[1]s
+			FROM grouped_events
+			GROUP BY %[1]s
+			ORDER BY count() DESC, %[1]s
+			LIMIT %[2]d
+		)`, strings.Join(limitedColumns, ", "), query.GroupLimit)
+	}
+
+	keyColumns := make([]string, len(query.GroupBy))
+	keys := make([]string, len(query.GroupBy))
+	groupKeys := make([]string, len(query.GroupBy))
+	var timeKeys, otherKeys []string
+	for i, dimension := range query.GroupBy {
+		keys[i] = fmt.Sprintf("key_%d", i)
+		keyColumns[i] = fmt.Sprintf("dimension_%d AS key_%d", i, i)
+		groupKeys[i] = keys[i]
+		if dimension.IsTime() {
+			timeKeys = append(timeKeys, keys[i])
+			groupKeys[i] = fmt.Sprintf("toString(%s)", keys[i])
+			continue
+		}
+		otherKeys = append(otherKeys, keys[i])
+		if limited {
+			keyColumns[i] = fmt.Sprintf("if((%s) IN (SELECT * FROM top_groups), dimension_%d, '%s') AS key_%d",
+				strings.Join(limitedColumns, ", "), i, eventDomain.OtherGroupKey, i)
+		}
+	}
+
+	// Time buckets first, then the most frequent groups with Other last
+	orderBy := keys
+	if limited {
+		orderBy = append(timeKeys, fmt.Sprintf("%s = '%s'", otherKeys[0], eventDomain.OtherGroupKey), "total_count DESC")
+		orderBy = append(orderBy, otherKeys...)
+	}
+
+	// Rows added by WITH FILL have the defaults of the columns not filled,
+	// so zero counts and no present flag. The other keys come first, so
+	// each of their combinations is filled on its own.
+	var fill string
+	var fillArgs []interface{}
+	if query.Fill == eventDomain.FillZero || query.Fill == eventDomain.FillNull {
+		fill, fillArgs = fillClause(query, append(otherKeys, timeKeys[0]))
 	}
 
 	groupedQuery := fmt.Sprintf(`
+		WITH grouped_events AS (
+			SELECT 
+				%s,
+				%s AS user_key
+			FROM %s
+			%s
+		)%s
 		SELECT 
-			%s,
-			count() AS total_count,
-			uniqExact(user_id) AS unique_user_count
-		FROM events
-		%s
-		GROUP BY %s
+			[%s] AS group_keys,
+			total_count,
+			unique_user_count%s,
+			present = 0 AS filled
+		FROM (
+			SELECT 
+				%s,
+				%s,
+				1 AS present
+			FROM grouped_events
+			GROUP BY %s
+			%s
+		) AS grouped_metrics
 		ORDER BY %s
-	`, selectExpr, whereClause, groupByExpr, groupByExpr)
+	`, strings.Join(eventColumns, ",\n\t\t\t\t"), uniqueUserExpr(query), fromClause(query), whereClause, topGroups,
+		strings.Join(groupKeys, ", "), measuresColumn, strings.Join(keyColumns, ",\n\t\t\t\t"), strings.Join(metricColumns, ",\n\t\t\t\t"),
+		strings.Join(keys, ", "), fill, strings.Join(orderBy, ", "))
+
+	// Arguments follow the text: dimensions, measures, the WHERE clause, then the fill range
+	queryArgs := append(append(append(dimensionArgs, measureArgs...), args...), fillArgs...)
 
 	var rows []groupedMetricsRow
-	if err := clickhouse.SelectWithContext(ctx, r.db, &rows, groupedQuery, args...); err != nil {
+	if err := clickhouse.SelectWithContext(ctx, r.db, &rows, groupedQuery, queryArgs...); err != nil {
 		return nil, fmt.Errorf("failed to query grouped metrics: %w", err)
 	}
 
 	groupedMetrics := make([]eventDomain.GroupedMetric, len(rows))
 	for i, row := range rows {
 		groupedMetrics[i] = eventDomain.GroupedMetric{
-			GroupKey:        row.GroupKey,
+			GroupKeys:       row.GroupKeys,
 			TotalCount:      row.TotalCount,
 			UniqueUserCount: row.UniqueUserCount,
+			MeasureValues:   row.Measures,
+			Filled:          row.Filled,
 		}
 	}
 
 	return groupedMetrics, nil
 }
+
+// fillClause returns the ORDER BY ... WITH FILL clause that fills the time
+// bucket, the last of orderBy, from the bucket of the query's from to the
+// bucket of its to, and its arguments. Without from or to, filling starts
+// or ends at the first or last bucket with events.
+func fillClause(query *eventDomain.MetricsQuery, orderBy []string) (string, []interface{}) {
+	var timeDimension eventDomain.Dimension
+	for _, dimension := range query.GroupBy {
+		if dimension.IsTime() {
+			timeDimension = dimension
+		}
+	}
+	bucket := timeBucketExprs[timeDimension.Aggregation]
+	step := timeBucketSteps[timeDimension.Aggregation]
+
+	clause := "ORDER BY " + strings.Join(orderBy, ", ") + " WITH FILL"
+	var args []interface{}
+	if !query.From.IsZero() {
+		clause += " FROM " + fmt.Sprintf(bucket, "?")
+		args = append(args, query.From, query.TimeZone())
+	}
+	if !query.To.IsZero() {
+		// TO is exclusive
+		clause += fmt.Sprintf(" TO %s + %s", fmt.Sprintf(bucket, "?"), step)
+		args = append(args, query.To, query.TimeZone())
+	}
+	return clause + " STEP " + step, args
+}
+
+// timeBucketExprs are the expressions a time, %s, is bucketed by, each
+// taking the time zone as its argument. Buckets of a day or longer are
+// dates, shorter ones start times.
+var timeBucketExprs = map[eventDomain.AggregationType]string{
+	eventDomain.AggregationByMinute:       "toStartOfMinute(%s, ?)",
+	eventDomain.AggregationByFiveMinutes:  "toStartOfFiveMinutes(%s, ?)",
+	eventDomain.AggregationByHourly:       "toStartOfHour(%s, ?)",
+	eventDomain.AggregationByDaily:        "toDate(%s, ?)",
+	eventDomain.AggregationByWeekly:       "toMonday(%s, ?)",
+	eventDomain.AggregationByWeeklySunday: "toStartOfWeek(%s, 0, ?)",
+	eventDomain.AggregationByMonthly:      "toStartOfMonth(%s, ?)",
+	eventDomain.AggregationByQuarterly:    "toStartOfQuarter(%s, ?)",
+}
+
+// timeBucketSteps are the lengths of the time buckets
+var timeBucketSteps = map[eventDomain.AggregationType]string{
+	eventDomain.AggregationByMinute:       "INTERVAL 1 MINUTE",
+	eventDomain.AggregationByFiveMinutes:  "INTERVAL 5 MINUTE",
+	eventDomain.AggregationByHourly:       "INTERVAL 1 HOUR",
+	eventDomain.AggregationByDaily:        "INTERVAL 1 DAY",
+	eventDomain.AggregationByWeekly:       "INTERVAL 1 WEEK",
+	eventDomain.AggregationByWeeklySunday: "INTERVAL 1 WEEK",
+	eventDomain.AggregationByMonthly:      "INTERVAL 1 MONTH",
+	eventDomain.AggregationByQuarterly:    "INTERVAL 1 QUARTER",
+}
+
+// dimensionExpr returns the expression a dimension's key is read with, and its
+// arguments. Time buckets are computed in timeZone.
+func dimensionExpr(dimension eventDomain.Dimension, timeZone string) (string, []interface{}) {
+	switch dimension.Aggregation {
+	case eventDomain.AggregationByChannel:
+		return "channel_type", nil
+	case eventDomain.AggregationByEventName:
+		return "name", nil
+	}
+
+	if dimension.IsTime() {
+		return fmt.Sprintf(timeBucketExprs[dimension.Aggregation], "date"), []interface{}{timeZone}
+	}
+
+	if dimension.Field.IsParam() {
+		return paramValueExpr(dimension.Field), []interface{}{dimension.Field.Name}
+	}
+	return fieldColumn(dimension.Field), nil
+}
+
+// fromClause returns the events source, joined with the latest identity link
+// per user_pseudo_id when identity resolution is requested
+func fromClause(query *eventDomain.MetricsQuery) string {
+	if !query.ResolveIdentity {
+		return "events"
+	}
+
+	return `events
+		LEFT JOIN (
+			SELECT 
+				user_pseudo_id AS identity_pseudo_id,
+				argMax(user_id, linked_at) AS canonical_user_id
+			FROM user_identities
+			GROUP BY user_pseudo_id
+		) AS identities ON events.user_pseudo_id = identities.identity_pseudo_id`
+}
+
+// uniqueUserExpr returns the expression unique users are counted by.
+// With identity resolution, anonymous events are attributed to their linked
+// user_id and fall back to the user_pseudo_id when no link exists.
+func uniqueUserExpr(query *eventDomain.MetricsQuery) string {
+	if !query.ResolveIdentity {
+		return "user_id"
+	}
+
+	return "if(events.user_id != '', events.user_id, if(identities.canonical_user_id != '', identities.canonical_user_id, events.user_pseudo_id))"
+}
+
+// columnCondition is an equality condition on a single column
+type columnCondition struct {
+	column string
+	value  string
+}
+
+// ingestionConditions returns the equality conditions for the ingestion filter fields that are set
+func ingestionConditions(filter eventDomain.IngestionFilter) []columnCondition {
+	candidates := []columnCondition{
+		{column: "ingest_endpoint", value: filter.Endpoint},
+		{column: "sdk_name", value: filter.SDKName},
+		{column: "sdk_version", value: filter.SDKVersion},
+		{column: "api_key_id", value: filter.APIKeyID},
+	}
+
+	var conditions []columnCondition
+	for _, c := range candidates {
+		if c.value != "" {
+			conditions = append(conditions, c)
+		}
+	}
+	return conditions
+}
@@ -0,0 +1,140 @@
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/ebubekir/event-stream/internal/domain"
+	eventDomain "github.com/ebubekir/event-stream/internal/domain/event"
+	"github.com/ebubekir/event-stream/pkg/clickhouse"
+)
+
+// QuarantineRepository implements domain/event.QuarantineRepository for ClickHouse
+type QuarantineRepository struct {
+	db *clickhouse.ClickHouseDb
+}
+
+// NewQuarantineRepository creates a new ClickHouse quarantine repository
+func NewQuarantineRepository(db *clickhouse.ClickHouseDb) *QuarantineRepository {
+	return &QuarantineRepository{db: db}
+}
+
+// quarantinedEventModel is the database model for quarantined events.
+// It shares the events table layout plus the quarantine bookkeeping columns.
+type quarantinedEventModel struct {
+	eventModel
+	QuarantineReason string    `db:"quarantine_reason"`
+	QuarantinedAt    time.Time `db:"quarantined_at"`
+}
+
+// SaveBatch persists quarantined events using ClickHouse batch insert. A
+// write marked WithSkipStored leaves out the events already quarantined.
+func (r *QuarantineRepository) SaveBatch(ctx context.Context, events []*domain.QuarantinedEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	if eventDomain.SkipStored(ctx) {
+		ids := make([]string, len(events))
+		for i, event := range events {
+			ids[i] = event.Event.ID
+		}
+		stored, err := storedIDs(ctx, r.db, "quarantined_events", ids)
+		if err != nil {
+			return err
+		}
+		events = slices.DeleteFunc(slices.Clone(events), func(event *domain.QuarantinedEvent) bool { return stored[event.Event.ID] })
+		if len(events) == 0 {
+			return nil
+		}
+	}
+
+	models := make([]quarantinedEventModel, len(events))
+	for i, event := range events {
+		models[i] = quarantinedEventModel{
+			eventModel:       *toModel(event.Event),
+			QuarantineReason: event.Reason,
+			QuarantinedAt:    event.QuarantinedAt,
+		}
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO quarantined_events (%s,
+			quarantine_reason, quarantined_at
+		) VALUES (%s,
+			:quarantine_reason, :quarantined_at
+		)
+	`, eventColumns, eventValues)
+
+	if err := batchInsert(ctx, r.db, query, models); err != nil {
+		return fmt.Errorf("failed to batch insert quarantined events: %w", err)
+	}
+
+	return nil
+}
+
+// List returns quarantined events, most recently quarantined first
+func (r *QuarantineRepository) List(ctx context.Context, limit, offset int) ([]*domain.QuarantinedEvent, error) {
+	query := fmt.Sprintf(`
+		SELECT %s,
+			quarantine_reason, quarantined_at
+		FROM quarantined_events
+		ORDER BY quarantined_at DESC, id
+		LIMIT ? OFFSET ?
+	`, selectEventColumns)
+
+	var rows []quarantinedEventModel
+	if err := clickhouse.SelectWithContext(ctx, r.db, &rows, query, limit, offset); err != nil {
+		return nil, fmt.Errorf("failed to list quarantined events: %w", err)
+	}
+
+	return fromQuarantinedModels(rows), nil
+}
+
+// FindByIDs returns the quarantined events with the given event IDs
+func (r *QuarantineRepository) FindByIDs(ctx context.Context, ids []string) ([]*domain.QuarantinedEvent, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s,
+			quarantine_reason, quarantined_at
+		FROM quarantined_events
+		WHERE has(?, id)
+	`, selectEventColumns)
+
+	var rows []quarantinedEventModel
+	if err := clickhouse.SelectWithContext(ctx, r.db, &rows, query, ids); err != nil {
+		return nil, fmt.Errorf("failed to find quarantined events: %w", err)
+	}
+
+	return fromQuarantinedModels(rows), nil
+}
+
+// Delete removes the quarantined events with the given event IDs
+func (r *QuarantineRepository) Delete(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	if err := clickhouse.ExecWithContext(ctx, r.db, "DELETE FROM quarantined_events WHERE has(?, id)", ids); err != nil {
+		return fmt.Errorf("failed to delete quarantined events: %w", err)
+	}
+
+	return nil
+}
+
+func fromQuarantinedModels(rows []quarantinedEventModel) []*domain.QuarantinedEvent {
+	events := make([]*domain.QuarantinedEvent, len(rows))
+	for i := range rows {
+		events[i] = &domain.QuarantinedEvent{
+			Event:         fromModel(&rows[i].eventModel),
+			Reason:        rows[i].QuarantineReason,
+			QuarantinedAt: rows[i].QuarantinedAt,
+		}
+	}
+	return events
+}
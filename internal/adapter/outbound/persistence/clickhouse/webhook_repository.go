@@ -0,0 +1,270 @@
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ebubekir/event-stream/internal/domain"
+	eventDomain "github.com/ebubekir/event-stream/internal/domain/event"
+	"github.com/ebubekir/event-stream/pkg/clickhouse"
+)
+
+// WebhookRepository implements domain/event.WebhookRepository for ClickHouse
+type WebhookRepository struct {
+	db *clickhouse.ClickHouseDb
+}
+
+// NewWebhookRepository creates a new ClickHouse webhook repository
+func NewWebhookRepository(db *clickhouse.ClickHouseDb) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+// webhookSubscriptionModel is the database model for webhook subscriptions.
+// Param filters are stored as parallel arrays (ClickHouse pattern).
+type webhookSubscriptionModel struct {
+	ID                  string    `db:"id"`
+	URL                 string    `db:"url"`
+	Secret              string    `db:"secret"`
+	EventNames          []string  `db:"event_names"`
+	ParamFilterKeys     []string  `db:"param_filter_keys"`
+	ParamFilterValues   []string  `db:"param_filter_values"`
+	Enabled             uint8     `db:"enabled"`
+	ConsecutiveFailures uint32    `db:"consecutive_failures"`
+	DisabledReason      string    `db:"disabled_reason"`
+	CreatedAt           time.Time `db:"created_at"`
+	UpdatedAt           time.Time `db:"updated_at"`
+	Deleted             uint8     `db:"deleted"`
+}
+
+// webhookDeliveryModel is the database model for webhook delivery attempts
+type webhookDeliveryModel struct {
+	ID             string    `db:"id"`
+	SubscriptionID string    `db:"subscription_id"`
+	EventID        string    `db:"event_id"`
+	EventName      string    `db:"event_name"`
+	Attempt        uint16    `db:"attempt"`
+	StatusCode     uint16    `db:"status_code"`
+	Error          string    `db:"error"`
+	Succeeded      uint8     `db:"succeeded"`
+	DurationMs     uint32    `db:"duration_ms"`
+	AttemptedAt    time.Time `db:"attempted_at"`
+}
+
+const webhookSubscriptionColumns = `
+	id, url, secret, event_names, param_filter_keys, param_filter_values, enabled,
+	consecutive_failures, disabled_reason, created_at, updated_at, deleted`
+
+var insertWebhookSubscriptionQuery = fmt.Sprintf(`
+	INSERT INTO webhook_subscriptions (%s)
+	VALUES (
+		:id, :url, :secret, :event_names, :param_filter_keys, :param_filter_values, :enabled,
+		:consecutive_failures, :disabled_reason, :created_at, :updated_at, :deleted
+	)
+`, webhookSubscriptionColumns)
+
+// Create persists a new subscription
+func (r *WebhookRepository) Create(ctx context.Context, subscription *domain.WebhookSubscription) error {
+	if err := clickhouse.NamedExec(r.db, insertWebhookSubscriptionQuery, toWebhookSubscriptionModel(subscription)); err != nil {
+		return fmt.Errorf("failed to insert webhook subscription: %w", err)
+	}
+
+	return nil
+}
+
+// Update writes a new version of the subscription; the ReplacingMergeTree keeps the latest
+func (r *WebhookRepository) Update(ctx context.Context, subscription *domain.WebhookSubscription) error {
+	if err := clickhouse.NamedExec(r.db, insertWebhookSubscriptionQuery, toWebhookSubscriptionModel(subscription)); err != nil {
+		return fmt.Errorf("failed to update webhook subscription: %w", err)
+	}
+
+	return nil
+}
+
+// Delete writes a deleted version of the subscription and removes its delivery history
+func (r *WebhookRepository) Delete(ctx context.Context, id string) error {
+	subscription, err := r.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	model := toWebhookSubscriptionModel(subscription)
+	model.UpdatedAt = time.Now().UTC()
+	model.Deleted = 1
+
+	if err := clickhouse.NamedExec(r.db, insertWebhookSubscriptionQuery, model); err != nil {
+		return fmt.Errorf("failed to delete webhook subscription: %w", err)
+	}
+
+	if err := clickhouse.ExecWithContext(ctx, r.db, "DELETE FROM webhook_deliveries WHERE subscription_id = ?", id); err != nil {
+		return fmt.Errorf("failed to delete webhook deliveries: %w", err)
+	}
+
+	return nil
+}
+
+// FindByID returns the subscription with the given ID
+func (r *WebhookRepository) FindByID(ctx context.Context, id string) (*domain.WebhookSubscription, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM webhook_subscriptions FINAL
+		WHERE id = ? AND deleted = 0
+	`, webhookSubscriptionColumns)
+
+	var rows []webhookSubscriptionModel
+	if err := clickhouse.SelectWithContext(ctx, r.db, &rows, query, id); err != nil {
+		return nil, fmt.Errorf("failed to find webhook subscription: %w", err)
+	}
+
+	if len(rows) == 0 {
+		return nil, eventDomain.ErrWebhookNotFound
+	}
+
+	return fromWebhookSubscriptionModel(&rows[0]), nil
+}
+
+// List returns all subscriptions, oldest first
+func (r *WebhookRepository) List(ctx context.Context) ([]*domain.WebhookSubscription, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM webhook_subscriptions FINAL
+		WHERE deleted = 0
+		ORDER BY created_at, id
+	`, webhookSubscriptionColumns)
+
+	var rows []webhookSubscriptionModel
+	if err := clickhouse.SelectWithContext(ctx, r.db, &rows, query); err != nil {
+		return nil, fmt.Errorf("failed to list webhook subscriptions: %w", err)
+	}
+
+	subscriptions := make([]*domain.WebhookSubscription, len(rows))
+	for i := range rows {
+		subscriptions[i] = fromWebhookSubscriptionModel(&rows[i])
+	}
+	return subscriptions, nil
+}
+
+// SaveDelivery records a delivery attempt
+func (r *WebhookRepository) SaveDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	query := `
+		INSERT INTO webhook_deliveries (
+			id, subscription_id, event_id, event_name, attempt,
+			status_code, error, succeeded, duration_ms, attempted_at
+		) VALUES (
+			:id, :subscription_id, :event_id, :event_name, :attempt,
+			:status_code, :error, :succeeded, :duration_ms, :attempted_at
+		)
+	`
+
+	model := webhookDeliveryModel{
+		ID:             delivery.ID,
+		SubscriptionID: delivery.SubscriptionID,
+		EventID:        delivery.EventID,
+		EventName:      delivery.EventName,
+		Attempt:        uint16(delivery.Attempt),
+		StatusCode:     uint16(delivery.StatusCode),
+		Error:          delivery.Error,
+		Succeeded:      boolToUint8(delivery.Succeeded),
+		DurationMs:     uint32(delivery.Duration.Milliseconds()),
+		AttemptedAt:    delivery.AttemptedAt,
+	}
+
+	if err := clickhouse.NamedExec(r.db, query, model); err != nil {
+		return fmt.Errorf("failed to insert webhook delivery: %w", err)
+	}
+
+	return nil
+}
+
+// ListDeliveries returns a subscription's delivery attempts, most recent first
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, subscriptionID string, limit, offset int) ([]*domain.WebhookDelivery, error) {
+	query := `
+		SELECT
+			id, subscription_id, event_id, event_name, attempt,
+			status_code, error, succeeded, duration_ms, attempted_at
+		FROM webhook_deliveries
+		WHERE subscription_id = ?
+		ORDER BY attempted_at DESC, id
+		LIMIT ? OFFSET ?
+	`
+
+	var rows []webhookDeliveryModel
+	if err := clickhouse.SelectWithContext(ctx, r.db, &rows, query, subscriptionID, limit, offset); err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+
+	deliveries := make([]*domain.WebhookDelivery, len(rows))
+	for i, row := range rows {
+		deliveries[i] = &domain.WebhookDelivery{
+			ID:             row.ID,
+			SubscriptionID: row.SubscriptionID,
+			EventID:        row.EventID,
+			EventName:      row.EventName,
+			Attempt:        int(row.Attempt),
+			StatusCode:     int(row.StatusCode),
+			Error:          row.Error,
+			Succeeded:      row.Succeeded == 1,
+			Duration:       time.Duration(row.DurationMs) * time.Millisecond,
+			AttemptedAt:    row.AttemptedAt,
+		}
+	}
+	return deliveries, nil
+}
+
+func toWebhookSubscriptionModel(s *domain.WebhookSubscription) *webhookSubscriptionModel {
+	keys := make([]string, len(s.ParamFilters))
+	values := make([]string, len(s.ParamFilters))
+	for i, f := range s.ParamFilters {
+		keys[i] = f.Key
+		values[i] = f.Value
+	}
+
+	eventNames := s.EventNames
+	if eventNames == nil {
+		eventNames = []string{}
+	}
+
+	return &webhookSubscriptionModel{
+		ID:                  s.ID,
+		URL:                 s.URL,
+		Secret:              s.Secret,
+		EventNames:          eventNames,
+		ParamFilterKeys:     keys,
+		ParamFilterValues:   values,
+		Enabled:             boolToUint8(s.Enabled),
+		ConsecutiveFailures: uint32(s.ConsecutiveFailures),
+		DisabledReason:      s.DisabledReason,
+		CreatedAt:           s.CreatedAt,
+		UpdatedAt:           s.UpdatedAt,
+	}
+}
+
+func fromWebhookSubscriptionModel(m *webhookSubscriptionModel) *domain.WebhookSubscription {
+	filters := make([]domain.WebhookParamFilter, len(m.ParamFilterKeys))
+	for i := range m.ParamFilterKeys {
+		filters[i] = domain.WebhookParamFilter{Key: m.ParamFilterKeys[i]}
+		if i < len(m.ParamFilterValues) {
+			filters[i].Value = m.ParamFilterValues[i]
+		}
+	}
+
+	return &domain.WebhookSubscription{
+		ID:                  m.ID,
+		URL:                 m.URL,
+		Secret:              m.Secret,
+		EventNames:          m.EventNames,
+		ParamFilters:        filters,
+		Enabled:             m.Enabled == 1,
+		ConsecutiveFailures: int(m.ConsecutiveFailures),
+		DisabledReason:      m.DisabledReason,
+		CreatedAt:           m.CreatedAt,
+		UpdatedAt:           m.UpdatedAt,
+	}
+}
+
+func boolToUint8(b bool) uint8 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,229 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	eventDomain "github.com/ebubekir/event-stream/internal/domain/event"
+	"github.com/ebubekir/event-stream/pkg/postgresql"
+)
+
+// ecommerceRow represents a row from the ecommerce metrics queries
+type ecommerceRow struct {
+	Bucket          string  `db:"bucket"`
+	Revenue         float64 `db:"revenue"`
+	Orders          int64   `db:"orders"`
+	PurchasingUsers int64   `db:"purchasing_users"`
+	UnitsSold       int64   `db:"units_sold"`
+}
+
+// topItemRow represents a row from the top items query
+type topItemRow struct {
+	Key      string  `db:"item_key"`
+	Name     string  `db:"name"`
+	Revenue  float64 `db:"revenue"`
+	Quantity int64   `db:"quantity"`
+	Orders   int64   `db:"orders"`
+}
+
+// itemBucketRow represents a row from the top items series query
+type itemBucketRow struct {
+	Bucket   string  `db:"bucket"`
+	Key      string  `db:"item_key"`
+	Revenue  float64 `db:"revenue"`
+	Quantity int64   `db:"quantity"`
+}
+
+// itemsExpr is the items of an event as an array; events stored without
+// items hold JSON null, which jsonb_array_elements rejects
+const itemsExpr = "CASE WHEN jsonb_typeof(events.items) = 'array' THEN events.items ELSE '[]'::jsonb END"
+
+// itemKeyExprs are the expressions an item, stored as JSON of domain.Item,
+// is ranked by
+var itemKeyExprs = map[eventDomain.ItemDimension]string{
+	eventDomain.ItemDimensionItem:    "COALESCE(NULLIF(item->>'ID', ''), item->>'Name', '')",
+	eventDomain.ItemDimensionBrand:   "COALESCE(item->>'Brand', '')",
+	eventDomain.ItemDimensionVariant: "COALESCE(item->>'Variant', '')",
+}
+
+// GetEcommerceMetrics retrieves the revenue metrics of the purchase events matching the query
+func (r *MetricsReader) GetEcommerceMetrics(ctx context.Context, query *eventDomain.EcommerceQuery) (*eventDomain.EcommerceResult, error) {
+	result := &eventDomain.EcommerceResult{
+		EventNames: query.EventNames,
+		Interval:   query.Interval,
+		Location:   query.Location,
+		From:       query.From,
+		To:         query.To,
+	}
+
+	metricsQuery := query.MetricsQuery()
+	whereClause, args := metricsWhereClause(metricsQuery)
+
+	// Each event is one order; its revenue and units are the sums over its items
+	columns := fmt.Sprintf(`
+			COALESCE(SUM(order_items.revenue), 0) AS revenue,
+			COUNT(*) AS orders,
+			COUNT(DISTINCT %s) AS purchasing_users,
+			COALESCE(SUM(order_items.units), 0)::bigint AS units_sold`, uniqueUserExpr(metricsQuery))
+	from := fromClause(metricsQuery) + `
+		CROSS JOIN LATERAL (
+			SELECT
+				SUM((item->>'RevenueInUsd')::float8) AS revenue,
+				SUM((item->>'Quantity')::bigint) AS units
+			FROM jsonb_array_elements(` + itemsExpr + `) AS item
+		) AS order_items`
+
+	totalsQuery := fmt.Sprintf(`
+		SELECT %s
+		FROM %s
+		%s
+	`, columns, from, whereClause)
+
+	var totals ecommerceRow
+	if err := postgresql.Get(r.db, &totals, totalsQuery, args...); err != nil {
+		return nil, fmt.Errorf("failed to query ecommerce totals: %w", err)
+	}
+	result.Totals = totals.metrics()
+
+	if query.Interval == "" {
+		return result, nil
+	}
+
+	// Bucket keys sort as their times do
+	interval := eventDomain.Dimension{Aggregation: query.Interval}
+	bound := filterArgs(args)
+	bucketsQuery := fmt.Sprintf(`
+		SELECT
+			TO_CHAR(%s, '%s') AS bucket,%s
+		FROM %s
+		%s
+		GROUP BY bucket
+		ORDER BY bucket
+	`, dimensionExpr(interval, query.TimeZone(), &bound), timeBucketFormat(interval), columns, from, whereClause)
+
+	var rows []ecommerceRow
+	if err := postgresql.Select(r.db, &rows, bucketsQuery, bound...); err != nil {
+		return nil, fmt.Errorf("failed to query ecommerce buckets: %w", err)
+	}
+
+	result.Buckets = make([]eventDomain.EcommerceBucket, len(rows))
+	for i, row := range rows {
+		result.Buckets[i] = eventDomain.EcommerceBucket{Bucket: row.Bucket, EcommerceMetrics: row.metrics()}
+	}
+
+	return result, nil
+}
+
+// metrics returns the row's ecommerce metrics
+func (row ecommerceRow) metrics() eventDomain.EcommerceMetrics {
+	return eventDomain.EcommerceMetrics{
+		Revenue:         row.Revenue,
+		Orders:          row.Orders,
+		PurchasingUsers: row.PurchasingUsers,
+		UnitsSold:       row.UnitsSold,
+	}
+}
+
+// GetTopItems ranks the items of the purchase events matching the query.
+// purchased_items holds one row per item of each event, unrolled with
+// jsonb_array_elements.
+func (r *MetricsReader) GetTopItems(ctx context.Context, query *eventDomain.TopItemsQuery) (*eventDomain.TopItemsResult, error) {
+	result := &eventDomain.TopItemsResult{
+		EventNames: query.EventNames,
+		By:         query.By,
+		SortBy:     query.SortBy,
+		Interval:   query.Interval,
+		Location:   query.Location,
+		From:       query.From,
+		To:         query.To,
+	}
+
+	whereClause, args := metricsWhereClause(query.MetricsQuery())
+
+	purchasedItems := fmt.Sprintf(`
+		WITH purchased_items AS (
+			SELECT
+				events.id AS order_id,
+				events.date,
+				%s AS item_key,
+				item->>'Name' AS item_name,
+				(item->>'RevenueInUsd')::float8 AS item_revenue,
+				(item->>'Quantity')::bigint AS item_quantity
+			FROM events
+			CROSS JOIN LATERAL jsonb_array_elements(%s) AS item
+			%s
+		)`, itemKeyExprs[query.By], itemsExpr, whereClause)
+
+	topQuery := fmt.Sprintf(`%s
+		SELECT
+			item_key,
+			COALESCE(MIN(item_name), '') AS name,
+			COALESCE(SUM(item_revenue), 0) AS revenue,
+			COALESCE(SUM(item_quantity), 0)::bigint AS quantity,
+			COUNT(DISTINCT order_id) AS orders
+		FROM purchased_items
+		WHERE item_key <> ''
+		GROUP BY item_key
+		ORDER BY %s DESC, item_key
+		LIMIT %d
+	`, purchasedItems, query.SortBy, query.Limit)
+
+	var rows []topItemRow
+	if err := postgresql.Select(r.db, &rows, topQuery, args...); err != nil {
+		return nil, fmt.Errorf("failed to query top items: %w", err)
+	}
+
+	result.Items = make([]eventDomain.ItemMetrics, len(rows))
+	keys := make([]string, len(rows))
+	index := make(map[string]int, len(rows))
+	for i, row := range rows {
+		result.Items[i] = eventDomain.ItemMetrics{
+			Key:      row.Key,
+			Revenue:  row.Revenue,
+			Quantity: row.Quantity,
+			Orders:   row.Orders,
+		}
+		if query.By == eventDomain.ItemDimensionItem {
+			result.Items[i].Name = row.Name
+		}
+		keys[i] = row.Key
+		index[row.Key] = i
+	}
+
+	if query.Interval == "" || len(rows) == 0 {
+		return result, nil
+	}
+
+	interval := eventDomain.Dimension{Aggregation: query.Interval}
+	bound := filterArgs(args)
+	localTime := fmt.Sprintf("(date AT TIME ZONE %s)", bound.bind(query.TimeZone()))
+	seriesQuery := fmt.Sprintf(`%s
+		SELECT
+			TO_CHAR(%s, '%s') AS bucket,
+			item_key,
+			COALESCE(SUM(item_revenue), 0) AS revenue,
+			COALESCE(SUM(item_quantity), 0)::bigint AS quantity
+		FROM purchased_items
+		WHERE item_key = ANY(%s)
+		GROUP BY bucket, item_key
+		ORDER BY bucket, item_key
+	`, purchasedItems, fmt.Sprintf(timeBucketExprs[query.Interval], localTime), timeBucketFormat(interval), bound.bind(pq.Array(keys)))
+
+	var buckets []itemBucketRow
+	if err := postgresql.Select(r.db, &buckets, seriesQuery, bound...); err != nil {
+		return nil, fmt.Errorf("failed to query top items series: %w", err)
+	}
+
+	for _, bucket := range buckets {
+		item := &result.Items[index[bucket.Key]]
+		item.Series = append(item.Series, eventDomain.ItemBucket{
+			Bucket:   bucket.Bucket,
+			Revenue:  bucket.Revenue,
+			Quantity: bucket.Quantity,
+		})
+	}
+
+	return result, nil
+}
@@ -4,10 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 
 	"github.com/ebubekir/event-stream/internal/domain"
+	eventDomain "github.com/ebubekir/event-stream/internal/domain/event"
 	"github.com/ebubekir/event-stream/pkg/postgresql"
 )
 
@@ -34,10 +38,39 @@ type eventModel struct {
 	UserPseudoID      string `db:"user_pseudo_id"`
 	UserParams        string `db:"user_params"` // JSON
 	Device            string `db:"device"`      // JSON
+	Geo               string `db:"geo"`         // JSON
 	AppInfo           string `db:"app_info"`    // JSON
 	Items             string `db:"items"`       // JSON
+	// Ingestion metadata populated server-side
+	ReceivedAt     time.Time `db:"received_at"`
+	IngestEndpoint string    `db:"ingest_endpoint"`
+	RequestID      string    `db:"request_id"`
+	ClientIPHash   string    `db:"client_ip_hash"`
+	SDKName        string    `db:"sdk_name"`
+	SDKVersion     string    `db:"sdk_version"`
+	APIKeyID       string    `db:"api_key_id"`
 }
 
+// eventColumns lists the columns written for every event, in insert order
+const eventColumns = `
+	id, name, channel_type, timestamp, previous_timestamp, date,
+	event_params, user_id, user_pseudo_id, user_params,
+	device, geo, app_info, items,
+	received_at, ingest_endpoint, request_id, client_ip_hash,
+	sdk_name, sdk_version, api_key_id`
+
+// eventValues lists the named parameters matching eventColumns
+const eventValues = `
+	:id, :name, :channel_type, :timestamp, :previous_timestamp, :date,
+	:event_params, :user_id, :user_pseudo_id, :user_params,
+	:device, :geo, :app_info, :items,
+	:received_at, :ingest_endpoint, :request_id, :client_ip_hash,
+	:sdk_name, :sdk_version, :api_key_id`
+
+// insertEventQuery is the named INSERT statement for the events table.
+// Re-inserting an existing ID is a no-op so redelivered events are not stored twice.
+var insertEventQuery = fmt.Sprintf("INSERT INTO events (%s\n) VALUES (%s\n) ON CONFLICT (id) DO NOTHING", eventColumns, eventValues)
+
 // Save persists a single event to PostgreSQL
 func (r *EventRepository) Save(ctx context.Context, event *domain.Event) error {
 	model, err := toModel(event)
@@ -45,57 +78,231 @@ func (r *EventRepository) Save(ctx context.Context, event *domain.Event) error {
 		return fmt.Errorf("failed to convert event to model: %w", err)
 	}
 
-	query := `
-		INSERT INTO events (
-			id, name, channel_type, timestamp, previous_timestamp, date,
-			event_params, user_id, user_pseudo_id, user_params,
-			device, app_info, items
-		) VALUES (
-			:id, :name, :channel_type, :timestamp, :previous_timestamp, :date,
-			:event_params, :user_id, :user_pseudo_id, :user_params,
-			:device, :app_info, :items
-		)
-	`
-
-	if err := postgresql.NamedExec(r.db, query, model); err != nil {
+	if err := postgresql.NamedExec(r.db, insertEventQuery, model); err != nil {
 		return fmt.Errorf("failed to insert event: %w", err)
 	}
 
 	return nil
 }
 
-// SaveBatch persists multiple events in a single transaction
-func (r *EventRepository) SaveBatch(ctx context.Context, events []*domain.Event) error {
+// SaveBatch persists multiple events in a single transaction. Events whose
+// ID is already stored are left out of the result, whether or not the write
+// is marked WithSkipStored.
+func (r *EventRepository) SaveBatch(ctx context.Context, events []*domain.Event) ([]*domain.Event, error) {
 	if len(events) == 0 {
-		return nil
+		return nil, nil
 	}
 
-	return postgresql.Transaction(r.db, func(tx *sqlx.Tx) error {
-		query := `
-			INSERT INTO events (
-				id, name, channel_type, timestamp, previous_timestamp, date,
-				event_params, user_id, user_pseudo_id, user_params,
-				device, app_info, items
-			) VALUES (
-				:id, :name, :channel_type, :timestamp, :previous_timestamp, :date,
-				:event_params, :user_id, :user_pseudo_id, :user_params,
-				:device, :app_info, :items
-			)
-		`
-
+	var stored []*domain.Event
+	err := postgresql.Transaction(r.db, func(tx *sqlx.Tx) error {
+		stored = stored[:0]
 		for _, event := range events {
 			model, err := toModel(event)
 			if err != nil {
 				return fmt.Errorf("failed to convert event to model: %w", err)
 			}
 
-			if _, err := tx.NamedExecContext(ctx, query, model); err != nil {
+			result, err := tx.NamedExecContext(ctx, insertEventQuery, model)
+			if err != nil {
 				return fmt.Errorf("failed to insert event in batch: %w", err)
 			}
+			if inserted, err := result.RowsAffected(); err != nil || inserted > 0 {
+				stored = append(stored, event)
+			}
 		}
 
 		return nil
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	return stored, nil
+}
+
+// FindByID returns the event with the given ID
+func (r *EventRepository) FindByID(ctx context.Context, id string) (*domain.Event, error) {
+	query := fmt.Sprintf("SELECT %s\nFROM events\nWHERE id = $1", eventColumns)
+
+	var rows []eventModel
+	if err := postgresql.SelectWithContext(ctx, r.db, &rows, query, id); err != nil {
+		return nil, fmt.Errorf("failed to find event: %w", err)
+	}
+
+	if len(rows) == 0 {
+		return nil, eventDomain.ErrEventNotFound
+	}
+
+	return fromModel(&rows[0])
+}
+
+// Search returns a page of events matching the query. Pages continue after
+// the cursor's date and ID rather than at an offset, so they are not shifted
+// by concurrent inserts and can use the (date, id) index deep into the result.
+func (r *EventRepository) Search(ctx context.Context, query *eventDomain.EventSearchQuery) ([]*domain.Event, error) {
+	where, args := filterConditions(query.Filter)
+
+	direction, comparison := "ASC", ">"
+	if query.Order == eventDomain.SortDescending {
+		direction, comparison = "DESC", "<"
+	}
+	if query.After != nil {
+		args = append(args, query.After.Date, query.After.ID)
+		condition := fmt.Sprintf("(date, id) %s ($%d, $%d)", comparison, len(args)-1, len(args))
+		if where == "" {
+			where = "WHERE " + condition
+		} else {
+			where += " AND " + condition
+		}
+	}
+
+	sql := fmt.Sprintf("SELECT %s\nFROM events\n%s\nORDER BY date %[3]s, id %[3]s\nLIMIT %[4]d",
+		eventColumns, where, direction, query.Limit)
+
+	var rows []eventModel
+	if err := postgresql.SelectWithContext(ctx, r.db, &rows, sql, args...); err != nil {
+		return nil, fmt.Errorf("failed to search events: %w", err)
+	}
+
+	events := make([]*domain.Event, len(rows))
+	for i := range rows {
+		event, err := fromModel(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+		events[i] = event
+	}
+	return events, nil
+}
+
+// Stream calls fn for each event matching filter, ordered by date and ID
+func (r *EventRepository) Stream(ctx context.Context, filter eventDomain.EventFilter, fn func(*domain.Event) error) error {
+	where, args := filterConditions(filter)
+	query := fmt.Sprintf("SELECT %s\nFROM events\n%s\nORDER BY date, id", eventColumns, where)
+
+	rows, err := postgresql.QueryWithContext(ctx, r.db, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query events: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var model eventModel
+		if err := rows.StructScan(&model); err != nil {
+			return fmt.Errorf("failed to scan event: %w", err)
+		}
+		event, err := fromModel(&model)
+		if err != nil {
+			return err
+		}
+		if err := fn(event); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read events: %w", err)
+	}
+	return nil
+}
+
+// ParamKeys returns the sorted distinct event and user param keys of the events matching filter
+func (r *EventRepository) ParamKeys(ctx context.Context, filter eventDomain.EventFilter) (*eventDomain.ParamKeys, error) {
+	where, args := filterConditions(filter)
+	keys := &eventDomain.ParamKeys{}
+
+	// Params are stored as JSON arrays of domain.Param, keyed by field name
+	for _, target := range []struct {
+		column string
+		keys   *[]string
+	}{
+		{column: "event_params", keys: &keys.EventParams},
+		{column: "user_params", keys: &keys.UserParams},
+	} {
+		query := fmt.Sprintf(`
+			SELECT DISTINCT param->>'Key'
+			FROM events, jsonb_array_elements(events.%s) AS param
+			%s
+			ORDER BY 1
+		`, target.column, where)
+
+		if err := postgresql.SelectWithContext(ctx, r.db, target.keys, query, args...); err != nil {
+			return nil, fmt.Errorf("failed to query %s keys: %w", target.column, err)
+		}
+	}
+
+	return keys, nil
+}
+
+// DeleteRange removes the events matching filter
+func (r *EventRepository) DeleteRange(ctx context.Context, filter eventDomain.EventFilter) error {
+	if filter.From.IsZero() || filter.To.IsZero() {
+		return eventDomain.ErrUnboundedDelete
+	}
+
+	where, args := filterConditions(filter)
+	if err := postgresql.ExecWithContext(ctx, r.db, "DELETE FROM events "+where, args...); err != nil {
+		return fmt.Errorf("failed to delete events: %w", err)
+	}
+	return nil
+}
+
+// filterConditions returns the WHERE clause and arguments for filter
+func filterConditions(filter eventDomain.EventFilter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	add := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if !filter.From.IsZero() {
+		add("date >= $%d", filter.From)
+	}
+	if !filter.To.IsZero() {
+		add("date < $%d", filter.To)
+	}
+	if len(filter.EventNames) > 0 {
+		add("name = ANY($%d)", pq.Array(filter.EventNames))
+	}
+	if filter.ChannelType != "" {
+		add("channel_type = $%d", filter.ChannelType)
+	}
+	if filter.UserID != "" {
+		add("user_id = $%d", filter.UserID)
+	}
+	if filter.UserPseudoID != "" {
+		add("user_pseudo_id = $%d", filter.UserPseudoID)
+	}
+	if filter.AppID != "" {
+		add("app_info->>'ID' = $%d", filter.AppID)
+	}
+	if filter.AppVersion != "" {
+		add("app_info->>'Version' = $%d", filter.AppVersion)
+	}
+	if filter.DeviceCategory != "" {
+		add("device->>'Category' = $%d", filter.DeviceCategory)
+	}
+	if filter.OperatingSystem != "" {
+		add("device->>'OperatingSystem' = $%d", filter.OperatingSystem)
+	}
+	if filter.BrowserName != "" {
+		add("device->>'BrowserName' = $%d", filter.BrowserName)
+	}
+	if filter.Language != "" {
+		add("device->>'Language' = $%d", filter.Language)
+	}
+
+	if paramFilter, ok := filter.ParamFilter(); ok {
+		bound := filterArgs(args)
+		conditions = append(conditions, fieldFilterCondition(paramFilter, &bound))
+		args = bound
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return "WHERE " + strings.Join(conditions, " AND "), args
 }
 
 func toModel(event *domain.Event) (*eventModel, error) {
@@ -114,6 +321,11 @@ func toModel(event *domain.Event) (*eventModel, error) {
 		return nil, fmt.Errorf("failed to marshal device: %w", err)
 	}
 
+	geo, err := json.Marshal(event.Geo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal geo: %w", err)
+	}
+
 	appInfo, err := json.Marshal(event.AppInfo)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal app_info: %w", err)
@@ -136,7 +348,63 @@ func toModel(event *domain.Event) (*eventModel, error) {
 		UserPseudoID:      event.UserPseudoID,
 		UserParams:        string(userParams),
 		Device:            string(device),
+		Geo:               string(geo),
 		AppInfo:           string(appInfo),
 		Items:             string(items),
+		ReceivedAt:        event.Ingestion.ReceivedAt,
+		IngestEndpoint:    event.Ingestion.Endpoint,
+		RequestID:         event.Ingestion.RequestID,
+		ClientIPHash:      event.Ingestion.ClientIPHash,
+		SDKName:           event.Ingestion.SDKName,
+		SDKVersion:        event.Ingestion.SDKVersion,
+		APIKeyID:          event.Ingestion.APIKeyID,
 	}, nil
 }
+
+func fromModel(model *eventModel) (*domain.Event, error) {
+	event := &domain.Event{
+		ID:                model.ID,
+		Name:              model.Name,
+		ChannelType:       domain.ChannelType(model.ChannelType),
+		Timestamp:         model.Timestamp,
+		PreviousTimestamp: model.PreviousTimestamp,
+		Date:              model.Date,
+		UserID:            model.UserID,
+		UserPseudoID:      model.UserPseudoID,
+		Ingestion: domain.IngestionMetadata{
+			ReceivedAt:   model.ReceivedAt,
+			Endpoint:     model.IngestEndpoint,
+			RequestID:    model.RequestID,
+			ClientIPHash: model.ClientIPHash,
+			SDKName:      model.SDKName,
+			SDKVersion:   model.SDKVersion,
+			APIKeyID:     model.APIKeyID,
+		},
+	}
+
+	if err := json.Unmarshal([]byte(model.EventParams), &event.EventParams); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal event_params: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(model.UserParams), &event.UserParams); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal user_params: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(model.Device), &event.Device); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal device: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(model.Geo), &event.Geo); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal geo: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(model.AppInfo), &event.AppInfo); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal app_info: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(model.Items), &event.Items); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal items: %w", err)
+	}
+
+	return event, nil
+}
@@ -0,0 +1,156 @@
+package postgres
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lib/pq"
+
+	eventDomain "github.com/ebubekir/event-stream/internal/domain/event"
+)
+
+// paramColumns are the JSONB arrays each param scope is stored in
+var paramColumns = map[eventDomain.FieldScope]string{
+	eventDomain.FieldScopeEventParam: "events.event_params",
+	eventDomain.FieldScopeUserParam:  "events.user_params",
+}
+
+// jsonFieldKeys maps device and app info field names to the keys of their
+// JSON columns, which hold the domain structs
+var jsonFieldKeys = map[string]string{
+	"category":                 "Category",
+	"mobile_brand_name":        "MobileBrandName",
+	"mobile_model_name":        "MobileModelName",
+	"operating_system":         "OperatingSystem",
+	"operating_system_version": "OperatingSystemVersion",
+	"language":                 "Language",
+	"browser_name":             "BrowserName",
+	"browser_version":          "BrowserVersion",
+	"hostname":                 "Hostname",
+	"id":                       "ID",
+	"version":                  "Version",
+}
+
+// fieldExpr returns the expression a device, app info or channel field is read with
+func fieldExpr(field eventDomain.Field) string {
+	switch field.Scope {
+	case eventDomain.FieldScopeDevice:
+		return fmt.Sprintf("(events.device->>'%s')", jsonFieldKeys[field.Name])
+	case eventDomain.FieldScopeAppInfo:
+		return fmt.Sprintf("(events.app_info->>'%s')", jsonFieldKeys[field.Name])
+	default:
+		return "events.channel_type"
+	}
+}
+
+// filterArgs collects the arguments of a query's conditions and numbers
+// their placeholders
+type filterArgs []interface{}
+
+// bind adds an argument and returns its placeholder
+func (a *filterArgs) bind(arg interface{}) string {
+	*a = append(*a, arg)
+	return fmt.Sprintf("$%d", len(*a))
+}
+
+// fieldFilterCondition compiles a validated filter into a condition, binding
+// its values to args. Field names come from a fixed set, so only values are bound.
+func fieldFilterCondition(filter eventDomain.FieldFilter, args *filterArgs) string {
+	if filter.Field.IsParam() {
+		return paramFilterCondition(filter, args)
+	}
+
+	expr := fieldExpr(filter.Field)
+	switch filter.Operator {
+	case eventDomain.FilterNotEquals:
+		// Missing JSON keys read as NULL, which never equals the value
+		return fmt.Sprintf("COALESCE(%s, '') <> %s", expr, args.bind(filter.Values[0]))
+	case eventDomain.FilterIn:
+		return fmt.Sprintf("%s = ANY(%s)", expr, args.bind(pq.Array(filter.Values)))
+	case eventDomain.FilterContains:
+		return fmt.Sprintf("strpos(%s, %s) > 0", expr, args.bind(filter.Values[0]))
+	case eventDomain.FilterRegex:
+		// ~ reads POSIX AREs; FieldFilter.Validate only admits the syntax they share with ClickHouse's RE2
+		return fmt.Sprintf("%s ~ %s", expr, args.bind(filter.Values[0]))
+	case eventDomain.FilterExists:
+		return expr + " <> ''"
+	default:
+		return fmt.Sprintf("%s = %s", expr, args.bind(filter.Values[0]))
+	}
+}
+
+// paramFilterCondition matches events with a param of the filter's key whose
+// value passes the filter, looking at the elements of the param array
+func paramFilterCondition(filter eventDomain.FieldFilter, args *filterArgs) string {
+	condition := "param->>'Key' = " + args.bind(filter.Field.Name)
+	if filter.Operator != eventDomain.FilterExists {
+		condition += " AND " + paramValueCondition(filter, args)
+	}
+
+	exists := fmt.Sprintf("EXISTS (SELECT 1 FROM jsonb_array_elements(%s) AS param WHERE %s)",
+		paramColumns[filter.Field.Scope], condition)
+	if filter.Operator == eventDomain.FilterNotEquals {
+		return "NOT " + exists
+	}
+	return exists
+}
+
+// Param value expressions; params are stored as JSON objects of domain.Param
+const (
+	paramString  = "(param->>'StringValue')"
+	paramNumber  = "(param->>'NumberValue')::float8"
+	paramBoolean = "(param->>'BooleanValue')::boolean"
+)
+
+// paramValueCondition compares a param's value with the filter's values
+func paramValueCondition(filter eventDomain.FieldFilter, args *filterArgs) string {
+	switch filter.Operator {
+	case eventDomain.FilterIn:
+		matches := make([]string, len(filter.Values))
+		for i, value := range filter.Values {
+			matches[i] = paramEquals(value, args)
+		}
+		return "(" + strings.Join(matches, " OR ") + ")"
+	case eventDomain.FilterContains:
+		return fmt.Sprintf("strpos(%s, %s) > 0", paramString, args.bind(filter.Values[0]))
+	case eventDomain.FilterRegex:
+		return fmt.Sprintf("%s ~ %s", paramString, args.bind(filter.Values[0]))
+	case eventDomain.FilterGreater, eventDomain.FilterGreaterOrEqual, eventDomain.FilterLess, eventDomain.FilterLessOrEqual:
+		number, _ := filter.Number()
+		return fmt.Sprintf("(%s = '' AND %s %s %s)", paramString, paramNumber, numericOperators[filter.Operator], args.bind(number))
+	default:
+		// eq, and neq negated by the caller
+		return paramEquals(filter.Values[0], args)
+	}
+}
+
+// paramEquals matches a param value by its string value, its number value
+// when value is a number, or a true boolean value when value is "true"
+func paramEquals(value string, args *filterArgs) string {
+	matches := []string{paramString + " = " + args.bind(value)}
+	if number, err := strconv.ParseFloat(value, 64); err == nil {
+		matches = append(matches, fmt.Sprintf("(%s = '' AND %s = %s)", paramString, paramNumber, args.bind(number)))
+	}
+	if value == "true" {
+		matches = append(matches, paramBoolean)
+	}
+	return "(" + strings.Join(matches, " OR ") + ")"
+}
+
+// numericOperators maps the numeric filter operators to SQL
+var numericOperators = map[eventDomain.FilterOperator]string{
+	eventDomain.FilterGreater:        ">",
+	eventDomain.FilterGreaterOrEqual: ">=",
+	eventDomain.FilterLess:           "<",
+	eventDomain.FilterLessOrEqual:    "<=",
+}
+
+// paramValueExpr returns the value of the field's param as text: its string
+// value, "true" for a true boolean value, or else its number value. It is
+// NULL when the event has no such param.
+func paramValueExpr(field eventDomain.Field, args *filterArgs) string {
+	return fmt.Sprintf(
+		"(SELECT CASE WHEN %[1]s <> '' THEN %[1]s WHEN %[2]s THEN 'true' ELSE param->>'NumberValue' END FROM jsonb_array_elements(%[3]s) AS param WHERE param->>'Key' = %[4]s LIMIT 1)",
+		paramString, paramBoolean, paramColumns[field.Scope], args.bind(field.Name))
+}
@@ -0,0 +1,116 @@
+package postgres
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lib/pq"
+
+	eventDomain "github.com/ebubekir/event-stream/internal/domain/event"
+)
+
+func TestFieldFilterCondition(t *testing.T) {
+	device := eventDomain.Field{Scope: eventDomain.FieldScopeDevice, Name: "browser_name"}
+	channel := eventDomain.Field{Scope: eventDomain.FieldScopeChannel}
+	param := eventDomain.Field{Scope: eventDomain.FieldScopeEventParam, Name: "value"}
+	paramExists := func(column, condition string) string {
+		return fmt.Sprintf("EXISTS (SELECT 1 FROM jsonb_array_elements(%s) AS param WHERE param->>'Key' = $1%s)", column, condition)
+	}
+
+	tests := []struct {
+		name     string
+		filter   eventDomain.FieldFilter
+		want     string
+		wantArgs filterArgs
+	}{
+		{
+			name:     "eq on device",
+			filter:   eventDomain.FieldFilter{Field: device, Operator: eventDomain.FilterEquals, Values: []string{"Chrome"}},
+			want:     "(events.device->>'BrowserName') = $1",
+			wantArgs: filterArgs{"Chrome"},
+		},
+		{
+			name:     "neq on device",
+			filter:   eventDomain.FieldFilter{Field: device, Operator: eventDomain.FilterNotEquals, Values: []string{"Chrome"}},
+			want:     "COALESCE((events.device->>'BrowserName'), '') <> $1",
+			wantArgs: filterArgs{"Chrome"},
+		},
+		{
+			name:     "in on channel",
+			filter:   eventDomain.FieldFilter{Field: channel, Operator: eventDomain.FilterIn, Values: []string{"web", "mobile"}},
+			want:     "events.channel_type = ANY($1)",
+			wantArgs: filterArgs{pq.Array([]string{"web", "mobile"})},
+		},
+		{
+			name:     "contains on app info",
+			filter:   eventDomain.FieldFilter{Field: eventDomain.Field{Scope: eventDomain.FieldScopeAppInfo, Name: "version"}, Operator: eventDomain.FilterContains, Values: []string{"beta"}},
+			want:     "strpos((events.app_info->>'Version'), $1) > 0",
+			wantArgs: filterArgs{"beta"},
+		},
+		{
+			name:     "regex on channel",
+			filter:   eventDomain.FieldFilter{Field: channel, Operator: eventDomain.FilterRegex, Values: []string{"^w"}},
+			want:     "events.channel_type ~ $1",
+			wantArgs: filterArgs{"^w"},
+		},
+		{
+			name:   "exists on device",
+			filter: eventDomain.FieldFilter{Field: device, Operator: eventDomain.FilterExists},
+			want:   "(events.device->>'BrowserName') <> ''",
+		},
+		{
+			name:     "exists on user param",
+			filter:   eventDomain.FieldFilter{Field: eventDomain.Field{Scope: eventDomain.FieldScopeUserParam, Name: "plan"}, Operator: eventDomain.FilterExists},
+			want:     paramExists("events.user_params", ""),
+			wantArgs: filterArgs{"plan"},
+		},
+		{
+			name:     "eq on param with text",
+			filter:   eventDomain.FieldFilter{Field: param, Operator: eventDomain.FilterEquals, Values: []string{"gold"}},
+			want:     paramExists("events.event_params", " AND ((param->>'StringValue') = $2)"),
+			wantArgs: filterArgs{"value", "gold"},
+		},
+		{
+			name:     "neq on param with number",
+			filter:   eventDomain.FieldFilter{Field: param, Operator: eventDomain.FilterNotEquals, Values: []string{"10"}},
+			want:     "NOT " + paramExists("events.event_params", " AND ((param->>'StringValue') = $2 OR ((param->>'StringValue') = '' AND (param->>'NumberValue')::float8 = $3))"),
+			wantArgs: filterArgs{"value", "10", 10.0},
+		},
+		{
+			name:     "in on param",
+			filter:   eventDomain.FieldFilter{Field: param, Operator: eventDomain.FilterIn, Values: []string{"true", "gold"}},
+			want:     paramExists("events.event_params", " AND (((param->>'StringValue') = $2 OR (param->>'BooleanValue')::boolean) OR ((param->>'StringValue') = $3))"),
+			wantArgs: filterArgs{"value", "true", "gold"},
+		},
+		{
+			name:     "lt on param",
+			filter:   eventDomain.FieldFilter{Field: param, Operator: eventDomain.FilterLess, Values: []string{"3"}},
+			want:     paramExists("events.event_params", " AND ((param->>'StringValue') = '' AND (param->>'NumberValue')::float8 < $2)"),
+			wantArgs: filterArgs{"value", 3.0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var args filterArgs
+			got := fieldFilterCondition(tt.filter, &args)
+			if got != tt.want {
+				t.Errorf("fieldFilterCondition() = %s, want %s", got, tt.want)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("fieldFilterCondition() args = %#v, want %#v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestFilterArgsContinueNumbering(t *testing.T) {
+	args := filterArgs{"2024-01-01", "2024-01-31"}
+	filter := eventDomain.FieldFilter{Field: eventDomain.Field{Scope: eventDomain.FieldScopeChannel}, Operator: eventDomain.FilterEquals, Values: []string{"web"}}
+
+	if got := fieldFilterCondition(filter, &args); !strings.HasSuffix(got, "$3") || len(args) != 3 {
+		t.Errorf("fieldFilterCondition() = %s with %d args, want $3 after two bound args", got, len(args))
+	}
+}
@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/ebubekir/event-stream/internal/domain"
+	"github.com/ebubekir/event-stream/pkg/postgresql"
+)
+
+// IdentityRepository implements domain/event.IdentityRepository for PostgreSQL
+type IdentityRepository struct {
+	db *postgresql.PostgresDb
+}
+
+// NewIdentityRepository creates a new PostgreSQL identity repository
+func NewIdentityRepository(db *postgresql.PostgresDb) *IdentityRepository {
+	return &IdentityRepository{db: db}
+}
+
+// identityModel is the database model for identity links
+type identityModel struct {
+	UserPseudoID string    `db:"user_pseudo_id"`
+	UserID       string    `db:"user_id"`
+	LinkedAt     time.Time `db:"linked_at"`
+}
+
+// Link upserts identity links in a single transaction. Like the ClickHouse
+// ReplacingMergeTree, it keeps the link with the latest linked_at per
+// user_pseudo_id, so an older link never replaces a newer one.
+func (r *IdentityRepository) Link(ctx context.Context, links []domain.IdentityLink) error {
+	if len(links) == 0 {
+		return nil
+	}
+
+	return postgresql.Transaction(r.db, func(tx *sqlx.Tx) error {
+		query := `
+			INSERT INTO user_identities (user_pseudo_id, user_id, linked_at)
+			VALUES (:user_pseudo_id, :user_id, :linked_at)
+			ON CONFLICT (user_pseudo_id) DO UPDATE
+			SET user_id = EXCLUDED.user_id, linked_at = EXCLUDED.linked_at
+			WHERE user_identities.linked_at < EXCLUDED.linked_at
+		`
+
+		for _, link := range links {
+			model := identityModel{
+				UserPseudoID: link.UserPseudoID,
+				UserID:       link.UserID,
+				LinkedAt:     link.LinkedAt,
+			}
+
+			if _, err := tx.NamedExecContext(ctx, query, model); err != nil {
+				return fmt.Errorf("failed to upsert identity link: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+
+	"github.com/lib/pq"
+
+	eventDomain "github.com/ebubekir/event-stream/internal/domain/event"
+)
+
+// measureAggregates are the aggregates of the non-percentile measures over
+// their values, %s
+var measureAggregates = map[eventDomain.MeasureFunction]string{
+	eventDomain.MeasureSum:           "SUM(%s)",
+	eventDomain.MeasureAvg:           "AVG(%s)",
+	eventDomain.MeasureMin:           "MIN(%s)",
+	eventDomain.MeasureMax:           "MAX(%s)",
+	eventDomain.MeasureCountDistinct: "COUNT(DISTINCT %s)",
+}
+
+// measureValueExpr returns the value of the measure's param an event
+// contributes, NULL when it has none, binding the param key to args: its
+// number value, or its value as text for count_distinct
+func measureValueExpr(measure eventDomain.Measure, args *filterArgs) string {
+	if measure.Function == eventDomain.MeasureCountDistinct {
+		return paramValueExpr(measure.Field, args)
+	}
+
+	return fmt.Sprintf(
+		"(SELECT CASE WHEN %s = '' THEN %s END FROM jsonb_array_elements(%s) AS param WHERE param->>'Key' = %s LIMIT 1)",
+		paramString, paramNumber, paramColumns[measure.Field.Scope], args.bind(measure.Field.Name))
+}
+
+// measureAggregateExpr returns the aggregate of a measure over the column
+// holding its values, NULL when no event has a value
+func measureAggregateExpr(measure eventDomain.Measure, column string) string {
+	aggregate, ok := measureAggregates[measure.Function]
+	if !ok {
+		level := strconv.FormatFloat(eventDomain.Percentiles[measure.Function], 'f', -1, 64)
+		aggregate = "percentile_cont(" + level + ") WITHIN GROUP (ORDER BY %s)"
+	}
+	return fmt.Sprintf("("+aggregate+")::float8", column)
+}
+
+// measureColumns returns the value columns and aggregates of the query's
+// measures, binding the values' arguments to args. Values are named
+// measure_value_<i>.
+func measureColumns(measures []eventDomain.Measure, args *filterArgs) (values, aggregates []string) {
+	for i, measure := range measures {
+		column := fmt.Sprintf("measure_value_%d", i)
+		values = append(values, fmt.Sprintf("%s AS %s", measureValueExpr(measure, args), column))
+		aggregates = append(aggregates, measureAggregateExpr(measure, column))
+	}
+	return values, aggregates
+}
+
+// measureValues scans a float8[] of measures, which holds NULLs for the
+// measures without values
+type measureValues []sql.NullFloat64
+
+// Scan implements sql.Scanner
+func (m *measureValues) Scan(src interface{}) error {
+	return pq.Array((*[]sql.NullFloat64)(m)).Scan(src)
+}
+
+// values returns the measures with nil for NULL
+func (m measureValues) values() []*float64 {
+	if m == nil {
+		return nil
+	}
+	values := make([]*float64, len(m))
+	for i, value := range m {
+		if value.Valid {
+			values[i] = &value.Float64
+		}
+	}
+	return values
+}
@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	eventDomain "github.com/ebubekir/event-stream/internal/domain/event"
+)
+
+func TestMeasureAggregateExpr(t *testing.T) {
+	tests := []struct {
+		function eventDomain.MeasureFunction
+		want     string
+	}{
+		{function: eventDomain.MeasureAvg, want: "(AVG(v))::float8"},
+		{function: eventDomain.MeasureCountDistinct, want: "(COUNT(DISTINCT v))::float8"},
+		{function: eventDomain.MeasureP95, want: "(percentile_cont(0.95) WITHIN GROUP (ORDER BY v))::float8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.function), func(t *testing.T) {
+			measure := eventDomain.Measure{Function: tt.function, Field: eventDomain.Field{Scope: eventDomain.FieldScopeEventParam, Name: "load_time_ms"}}
+			if got := measureAggregateExpr(measure, "v"); got != tt.want {
+				t.Errorf("measureAggregateExpr() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMeasureColumns(t *testing.T) {
+	measures := []eventDomain.Measure{
+		{Function: eventDomain.MeasureSum, Field: eventDomain.Field{Scope: eventDomain.FieldScopeEventParam, Name: "value"}},
+		{Function: eventDomain.MeasureCountDistinct, Field: eventDomain.Field{Scope: eventDomain.FieldScopeUserParam, Name: "plan"}},
+	}
+
+	args := filterArgs{"2024-01-01"}
+	values, aggregates := measureColumns(measures, &args)
+
+	if len(values) != 2 || !strings.HasSuffix(values[0], " AS measure_value_0") || !strings.HasSuffix(values[1], " AS measure_value_1") {
+		t.Fatalf("measureColumns() values = %v", values)
+	}
+	if !strings.Contains(values[0], "jsonb_array_elements(events.event_params)") || !strings.Contains(values[0], "= $2") {
+		t.Errorf("measureColumns() values[0] = %s, want the event param bound to $2", values[0])
+	}
+	if !strings.Contains(values[1], "jsonb_array_elements(events.user_params)") || !strings.Contains(values[1], "= $3") {
+		t.Errorf("measureColumns() values[1] = %s, want the user param bound to $3", values[1])
+	}
+	if want := []string{"(SUM(measure_value_0))::float8", "(COUNT(DISTINCT measure_value_1))::float8"}; !reflect.DeepEqual(aggregates, want) {
+		t.Errorf("measureColumns() aggregates = %v, want %v", aggregates, want)
+	}
+	if want := (filterArgs{"2024-01-01", "value", "plan"}); !reflect.DeepEqual(args, want) {
+		t.Errorf("measureColumns() args = %v, want %v", args, want)
+	}
+}
+
+func TestMeasureValuesScan(t *testing.T) {
+	var values measureValues
+	if err := values.Scan([]byte("{1.5,NULL,3}")); err != nil {
+		t.Fatal(err)
+	}
+
+	want := measureValues{{Float64: 1.5, Valid: true}, {}, {Float64: 3, Valid: true}}
+	if !reflect.DeepEqual(values, want) {
+		t.Fatalf("Scan() = %v, want %v", values, want)
+	}
+
+	got := values.values()
+	if len(got) != 3 || *got[0] != 1.5 || got[1] != nil || *got[2] != 3 {
+		t.Errorf("values() = %v", got)
+	}
+	if (measureValues)(nil).values() != nil {
+		t.Error("values() of no measures is not nil")
+	}
+}
@@ -3,6 +3,9 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
 
 	eventDomain "github.com/ebubekir/event-stream/internal/domain/event"
 	"github.com/ebubekir/event-stream/pkg/postgresql"
@@ -20,60 +23,61 @@ func NewMetricsReader(db *postgresql.PostgresDb) *MetricsReader {
 
 // metricsRow represents a row from the metrics query
 type metricsRow struct {
-	TotalCount      int64 `db:"total_count"`
-	UniqueUserCount int64 `db:"unique_user_count"`
+	TotalCount      int64         `db:"total_count"`
+	UniqueUserCount int64         `db:"unique_user_count"`
+	Measures        measureValues `db:"measures"`
 }
 
 // groupedMetricsRow represents a row from the grouped metrics query
 type groupedMetricsRow struct {
-	GroupKey        string `db:"group_key"`
-	TotalCount      int64  `db:"total_count"`
-	UniqueUserCount int64  `db:"unique_user_count"`
+	GroupKeys       pq.StringArray `db:"group_keys"`
+	TotalCount      int64          `db:"total_count"`
+	UniqueUserCount int64          `db:"unique_user_count"`
+	Measures        measureValues  `db:"measures"`
+	Filled          bool           `db:"filled"`
 }
 
 // GetMetrics retrieves aggregated metrics for events matching the query
 func (r *MetricsReader) GetMetrics(ctx context.Context, query *eventDomain.MetricsQuery) (*eventDomain.MetricsResult, error) {
 	result := &eventDomain.MetricsResult{
-		EventName: query.EventName,
-		From:      query.From,
-		To:        query.To,
+		EventNames: query.EventNames,
+		GroupBy:    query.GroupBy,
+		Measures:   query.Measures,
+		Fill:       query.Fill,
+		Location:   query.Location,
+		From:       query.From,
+		To:         query.To,
 	}
 
-	// Build base WHERE clause
-	whereClause := "WHERE name = $1"
-	args := []interface{}{query.EventName}
-	argIndex := 2
-
-	if !query.From.IsZero() {
-		whereClause += fmt.Sprintf(" AND date >= $%d", argIndex)
-		args = append(args, query.From)
-		argIndex++
-	}
+	whereClause, args := metricsWhereClause(query)
 
-	if !query.To.IsZero() {
-		whereClause += fmt.Sprintf(" AND date <= $%d", argIndex)
-		args = append(args, query.To)
+	// Get totals, with the measures' values computed per event by a lateral subquery
+	from, measures := fromClause(query), ""
+	totalsArgs := filterArgs(args)
+	if len(query.Measures) > 0 {
+		measureValues, measureAggregates := measureColumns(query.Measures, &totalsArgs)
+		from += fmt.Sprintf("\n\t\tCROSS JOIN LATERAL (SELECT %s) AS measure_values", strings.Join(measureValues, ", "))
+		measures = fmt.Sprintf(",\n\t\t\tARRAY[%s] AS measures", strings.Join(measureAggregates, ", "))
 	}
-
-	// Get totals
 	totalsQuery := fmt.Sprintf(`
 		SELECT 
 			COUNT(*) AS total_count,
-			COUNT(DISTINCT user_id) AS unique_user_count
-		FROM events
+			COUNT(DISTINCT %s) AS unique_user_count%s
+		FROM %s
 		%s
-	`, whereClause)
+	`, uniqueUserExpr(query), measures, from, whereClause)
 
 	var totals metricsRow
-	if err := postgresql.Get(r.db, &totals, totalsQuery, args...); err != nil {
+	if err := postgresql.Get(r.db, &totals, totalsQuery, totalsArgs...); err != nil {
 		return nil, fmt.Errorf("failed to query totals: %w", err)
 	}
 
 	result.TotalCount = totals.TotalCount
 	result.UniqueUserCount = totals.UniqueUserCount
+	result.MeasureValues = totals.Measures.values()
 
-	// Get grouped metrics if aggregation is specified
-	if query.Aggregation != "" {
+	// Get grouped metrics if dimensions are specified
+	if len(query.GroupBy) > 0 {
 		groupedMetrics, err := r.getGroupedMetrics(ctx, query, whereClause, args)
 		if err != nil {
 			return nil, err
@@ -84,49 +88,316 @@ func (r *MetricsReader) GetMetrics(ctx context.Context, query *eventDomain.Metri
 	return result, nil
 }
 
-// getGroupedMetrics retrieves metrics grouped by the specified aggregation
-func (r *MetricsReader) getGroupedMetrics(ctx context.Context, query *eventDomain.MetricsQuery, whereClause string, args []interface{}) ([]eventDomain.GroupedMetric, error) {
-	var groupByExpr string
-	var selectExpr string
+// metricsWhereClause returns the WHERE clause selecting the events the
+// query counts, and its arguments
+func metricsWhereClause(query *eventDomain.MetricsQuery) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	add := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
 
-	switch query.Aggregation {
-	case eventDomain.AggregationByChannel:
-		groupByExpr = "channel_type"
-		selectExpr = "channel_type AS group_key"
-	case eventDomain.AggregationByDaily:
-		groupByExpr = "DATE(date)"
-		selectExpr = "TO_CHAR(DATE(date), 'YYYY-MM-DD') AS group_key"
-	case eventDomain.AggregationByHourly:
-		groupByExpr = "DATE_TRUNC('hour', date)"
-		selectExpr = "TO_CHAR(DATE_TRUNC('hour', date), 'YYYY-MM-DD HH24:00:00') AS group_key"
+	switch len(query.EventNames) {
+	case 0:
+		// Every event
+	case 1:
+		add("name = $%d", query.EventNames[0])
 	default:
-		return nil, nil
+		add("name = ANY($%d)", pq.Array(query.EventNames))
 	}
 
-	groupedQuery := fmt.Sprintf(`
+	if !query.From.IsZero() {
+		add("date >= $%d", query.From)
+	}
+
+	if !query.To.IsZero() {
+		add("date <= $%d", query.To)
+	}
+
+	// Narrow to events that arrived through a specific ingestion path
+	for _, cond := range ingestionConditions(query.Ingestion) {
+		add(cond.column+" = $%d", cond.value)
+	}
+
+	bound := filterArgs(args)
+	for _, filter := range query.Filters {
+		conditions = append(conditions, fieldFilterCondition(filter, &bound))
+	}
+
+	if len(conditions) == 0 {
+		return "", bound
+	}
+	return "WHERE " + strings.Join(conditions, " AND "), bound
+}
+
+// getGroupedMetrics retrieves metrics grouped by the query's dimensions.
+// grouped_events holds each event's key for every dimension; with a group
+// limit, top_groups holds the most frequent combinations of the non-time
+// keys and the keys of every other event become OtherGroupKey. Filling joins
+// grouped_metrics onto every time bucket of each combination of the other keys.
+// Measures are aggregated over the measure_value columns of grouped_events.
+func (r *MetricsReader) getGroupedMetrics(ctx context.Context, query *eventDomain.MetricsQuery, whereClause string, args []interface{}) ([]eventDomain.GroupedMetric, error) {
+	// Dimension arguments are numbered after the WHERE clause's
+	bound := filterArgs(args)
+	var dimensionColumns, limitedColumns []string
+	for i, dimension := range query.GroupBy {
+		expr := dimensionExpr(dimension, query.TimeZone(), &bound)
+		if !dimension.IsTime() {
+			// Time buckets stay timestamps to be filled and ordered
+			expr = fmt.Sprintf("COALESCE(%s, '')", expr)
+			limitedColumns = append(limitedColumns, fmt.Sprintf("dimension_%d", i))
+		}
+		dimensionColumns = append(dimensionColumns, fmt.Sprintf("%s AS dimension_%d", expr, i))
+	}
+	limited := query.GroupLimit > 0 && len(limitedColumns) > 0
+
+	// Filled rows leave the measures NULL
+	measureValues, measureAggregates := measureColumns(query.Measures, &bound)
+	eventColumns := append(dimensionColumns, measureValues...)
+	metricColumns := []string{"COUNT(*) AS total_count", "COUNT(DISTINCT user_key) AS unique_user_count"}
+	measures := make([]string, len(query.Measures))
+	for i, aggregate := range measureAggregates {
+		measures[i] = fmt.Sprintf("measure_%d", i)
+		metricColumns = append(metricColumns, fmt.Sprintf("%s AS %s", aggregate, measures[i]))
+	}
+	var measuresColumn string
+	if len(measures) > 0 {
+		measuresColumn = fmt.Sprintf(",\n\t\t\tARRAY[%s] AS measures", strings.Join(measures, ", "))
+	}
+
+	var topGroups string
+	if limited {
+		topGroups = fmt.Sprintf(`,
+		top_groups AS (
+			SELECT %[1]s
+			FROM grouped_events
+			GROUP BY %[1]s
+			ORDER BY COUNT(*) DESC, %[1]s
+			LIMIT %[2]d
+		)`, strings.Join(limitedColumns, ", "), query.GroupLimit)
+	}
+
+	keyColumns := make([]string, len(query.GroupBy))
+	keys := make([]string, len(query.GroupBy))
+	groupKeys := make([]string, len(query.GroupBy))
+	var timeKeys, otherKeys []string
+	var timeDimension eventDomain.Dimension
+	for i, dimension := range query.GroupBy {
+		keys[i] = fmt.Sprintf("key_%d", i)
+		keyColumns[i] = fmt.Sprintf("dimension_%d AS key_%d", i, i)
+		groupKeys[i] = keys[i]
+		if dimension.IsTime() {
+			timeKeys = append(timeKeys, keys[i])
+			groupKeys[i] = fmt.Sprintf("TO_CHAR(%s, '%s')", keys[i], timeBucketFormat(dimension))
+			timeDimension = dimension
+			continue
+		}
+		otherKeys = append(otherKeys, keys[i])
+		if limited {
+			keyColumns[i] = fmt.Sprintf("CASE WHEN (%s) IN (SELECT * FROM top_groups) THEN dimension_%d ELSE '%s' END AS key_%d",
+				strings.Join(limitedColumns, ", "), i, eventDomain.OtherGroupKey, i)
+		}
+	}
+
+	// Time buckets first, then the most frequent groups with Other last
+	orderBy := keys
+	if limited {
+		orderBy = append(timeKeys, fmt.Sprintf("%s = '%s'", otherKeys[0], eventDomain.OtherGroupKey), "total_count DESC")
+		orderBy = append(orderBy, otherKeys...)
+	}
+
+	metrics := `
 		SELECT 
-			%s,
-			COUNT(*) AS total_count,
-			COUNT(DISTINCT user_id) AS unique_user_count
-		FROM events
-		%s
-		GROUP BY %s
+			ARRAY[%s] AS group_keys,
+			total_count,
+			unique_user_count%s,
+			FALSE AS filled
+		FROM grouped_metrics`
+	if query.Fill == eventDomain.FillZero || query.Fill == eventDomain.FillNull {
+		metrics = filledMetrics(query, timeDimension, timeKeys[0], otherKeys, &bound)
+	}
+
+	groupedQuery := fmt.Sprintf(`
+		WITH grouped_events AS (
+			SELECT 
+				%s,
+				%s AS user_key
+			FROM %s
+			%s
+		)%s,
+		grouped_metrics AS (
+			SELECT 
+				%s,
+				%s
+			FROM grouped_events
+			GROUP BY %s
+		)`+metrics+`
 		ORDER BY %s
-	`, selectExpr, whereClause, groupByExpr, groupByExpr)
+	`, strings.Join(eventColumns, ",\n\t\t\t\t"), uniqueUserExpr(query), fromClause(query), whereClause, topGroups,
+		strings.Join(keyColumns, ",\n\t\t\t\t"), strings.Join(metricColumns, ",\n\t\t\t\t"), strings.Join(keys, ", "),
+		strings.Join(groupKeys, ", "), measuresColumn, strings.Join(orderBy, ", "))
 
 	var rows []groupedMetricsRow
-	if err := postgresql.Select(r.db, &rows, groupedQuery, args...); err != nil {
+	if err := postgresql.Select(r.db, &rows, groupedQuery, bound...); err != nil {
 		return nil, fmt.Errorf("failed to query grouped metrics: %w", err)
 	}
 
 	groupedMetrics := make([]eventDomain.GroupedMetric, len(rows))
 	for i, row := range rows {
 		groupedMetrics[i] = eventDomain.GroupedMetric{
-			GroupKey:        row.GroupKey,
+			GroupKeys:       row.GroupKeys,
 			TotalCount:      row.TotalCount,
 			UniqueUserCount: row.UniqueUserCount,
+			MeasureValues:   row.Measures.values(),
+			Filled:          row.Filled,
 		}
 	}
 
 	return groupedMetrics, nil
 }
+
+// filledMetrics returns the select of grouped_metrics joined onto every time
+// bucket, timeKey, of each combination of otherKeys, with %s left for the
+// group keys. Buckets run from the bucket of the query's from to the bucket
+// of its to; without from or to, from the first or to the last bucket with events.
+func filledMetrics(query *eventDomain.MetricsQuery, timeDimension eventDomain.Dimension, timeKey string, otherKeys []string, args *filterArgs) string {
+	bucket := timeBucketExprs[timeDimension.Aggregation]
+	localTime := func(t interface{}) string {
+		return fmt.Sprintf("(%s::timestamptz AT TIME ZONE %s)", args.bind(t), args.bind(query.TimeZone()))
+	}
+
+	first := fmt.Sprintf("(SELECT MIN(%s) FROM grouped_metrics)", timeKey)
+	if !query.From.IsZero() {
+		first = fmt.Sprintf(bucket, localTime(query.From))
+	}
+	last := fmt.Sprintf("(SELECT MAX(%s) FROM grouped_metrics)", timeKey)
+	if !query.To.IsZero() {
+		last = fmt.Sprintf(bucket, localTime(query.To))
+	}
+
+	// The other keys take %% as they are formatted again with the group keys
+	series, join := "", ""
+	if len(otherKeys) > 0 {
+		series = fmt.Sprintf(`,
+		series AS (
+			SELECT DISTINCT %s
+			FROM grouped_metrics
+		)`, strings.Join(otherKeys, ", "))
+		join = "\n\t\tCROSS JOIN series"
+	}
+
+	return fmt.Sprintf(`,
+		buckets AS (
+			SELECT generate_series(%s, %s, INTERVAL '%s') AS %s
+		)%s
+		SELECT 
+			ARRAY[%%s] AS group_keys,
+			COALESCE(grouped_metrics.total_count, 0) AS total_count,
+			COALESCE(grouped_metrics.unique_user_count, 0) AS unique_user_count%%s,
+			grouped_metrics.total_count IS NULL AS filled
+		FROM buckets%s
+		LEFT JOIN grouped_metrics USING (%s)`,
+		first, last, timeBucketSteps[timeDimension.Aggregation], timeKey, series, join, strings.Join(append(otherKeys, timeKey), ", "))
+}
+
+// timeBucketExprs are the expressions a local time, %[1]s, is bucketed by.
+// Buckets of a day or longer start at midnight.
+var timeBucketExprs = map[eventDomain.AggregationType]string{
+	eventDomain.AggregationByMinute:       "DATE_TRUNC('minute', %[1]s)",
+	eventDomain.AggregationByFiveMinutes:  "DATE_TRUNC('hour', %[1]s) + FLOOR(EXTRACT(MINUTE FROM %[1]s) / 5) * INTERVAL '5 minutes'",
+	eventDomain.AggregationByHourly:       "DATE_TRUNC('hour', %[1]s)",
+	eventDomain.AggregationByDaily:        "DATE_TRUNC('day', %[1]s)",
+	eventDomain.AggregationByWeekly:       "DATE_TRUNC('week', %[1]s)",
+	eventDomain.AggregationByWeeklySunday: "DATE_TRUNC('week', %[1]s + INTERVAL '1 day') - INTERVAL '1 day'",
+	eventDomain.AggregationByMonthly:      "DATE_TRUNC('month', %[1]s)",
+	eventDomain.AggregationByQuarterly:    "DATE_TRUNC('quarter', %[1]s)",
+}
+
+// timeBucketSteps are the lengths of the time buckets
+var timeBucketSteps = map[eventDomain.AggregationType]string{
+	eventDomain.AggregationByMinute:       "1 minute",
+	eventDomain.AggregationByFiveMinutes:  "5 minutes",
+	eventDomain.AggregationByHourly:       "1 hour",
+	eventDomain.AggregationByDaily:        "1 day",
+	eventDomain.AggregationByWeekly:       "1 week",
+	eventDomain.AggregationByWeeklySunday: "1 week",
+	eventDomain.AggregationByMonthly:      "1 month",
+	eventDomain.AggregationByQuarterly:    "3 months",
+}
+
+// timeBucketFormat returns the format of a time bucket's key: its first day
+// for buckets of a day or longer, its start time for shorter ones
+func timeBucketFormat(dimension eventDomain.Dimension) string {
+	switch dimension.Aggregation {
+	case eventDomain.AggregationByMinute, eventDomain.AggregationByFiveMinutes, eventDomain.AggregationByHourly:
+		return "YYYY-MM-DD HH24:MI:SS"
+	}
+	return "YYYY-MM-DD"
+}
+
+// dimensionExpr returns the expression a dimension's key is read with,
+// binding its arguments to args. Time buckets are computed in timeZone.
+func dimensionExpr(dimension eventDomain.Dimension, timeZone string, args *filterArgs) string {
+	switch dimension.Aggregation {
+	case eventDomain.AggregationByChannel:
+		return "events.channel_type"
+	case eventDomain.AggregationByEventName:
+		return "events.name"
+	}
+
+	if dimension.IsTime() {
+		localTime := fmt.Sprintf("(events.date AT TIME ZONE %s)", args.bind(timeZone))
+		return fmt.Sprintf(timeBucketExprs[dimension.Aggregation], localTime)
+	}
+
+	if dimension.Field.IsParam() {
+		return paramValueExpr(dimension.Field, args)
+	}
+	return fieldExpr(dimension.Field)
+}
+
+// fromClause returns the events source, joined with the identity graph
+// when identity resolution is requested
+func fromClause(query *eventDomain.MetricsQuery) string {
+	if !query.ResolveIdentity {
+		return "events"
+	}
+
+	return "events LEFT JOIN user_identities identities ON identities.user_pseudo_id = events.user_pseudo_id"
+}
+
+// uniqueUserExpr returns the expression unique users are counted by.
+// With identity resolution, anonymous events are attributed to their linked
+// user_id and fall back to the user_pseudo_id when no link exists.
+func uniqueUserExpr(query *eventDomain.MetricsQuery) string {
+	if !query.ResolveIdentity {
+		return "user_id"
+	}
+
+	return "COALESCE(NULLIF(events.user_id, ''), identities.user_id, events.user_pseudo_id)"
+}
+
+// columnCondition is an equality condition on a single column
+type columnCondition struct {
+	column string
+	value  string
+}
+
+// ingestionConditions returns the equality conditions for the ingestion filter fields that are set
+func ingestionConditions(filter eventDomain.IngestionFilter) []columnCondition {
+	candidates := []columnCondition{
+		{column: "ingest_endpoint", value: filter.Endpoint},
+		{column: "sdk_name", value: filter.SDKName},
+		{column: "sdk_version", value: filter.SDKVersion},
+		{column: "api_key_id", value: filter.APIKeyID},
+	}
+
+	var conditions []columnCondition
+	for _, c := range candidates {
+		if c.value != "" {
+			conditions = append(conditions, c)
+		}
+	}
+	return conditions
+}
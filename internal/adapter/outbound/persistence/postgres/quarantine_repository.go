@@ -0,0 +1,137 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/ebubekir/event-stream/internal/domain"
+	"github.com/ebubekir/event-stream/pkg/postgresql"
+)
+
+// QuarantineRepository implements domain/event.QuarantineRepository for PostgreSQL
+type QuarantineRepository struct {
+	db *postgresql.PostgresDb
+}
+
+// NewQuarantineRepository creates a new PostgreSQL quarantine repository
+func NewQuarantineRepository(db *postgresql.PostgresDb) *QuarantineRepository {
+	return &QuarantineRepository{db: db}
+}
+
+// quarantinedEventModel is the database model for quarantined events.
+// It shares the events table layout plus the quarantine bookkeeping columns.
+type quarantinedEventModel struct {
+	eventModel
+	QuarantineReason string    `db:"quarantine_reason"`
+	QuarantinedAt    time.Time `db:"quarantined_at"`
+}
+
+// SaveBatch persists quarantined events in a single transaction
+func (r *QuarantineRepository) SaveBatch(ctx context.Context, events []*domain.QuarantinedEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO quarantined_events (%s,
+			quarantine_reason, quarantined_at
+		) VALUES (%s,
+			:quarantine_reason, :quarantined_at
+		)
+		ON CONFLICT (id) DO NOTHING
+	`, eventColumns, eventValues)
+
+	return postgresql.Transaction(r.db, func(tx *sqlx.Tx) error {
+		for _, event := range events {
+			model, err := toModel(event.Event)
+			if err != nil {
+				return fmt.Errorf("failed to convert event to model: %w", err)
+			}
+
+			quarantined := quarantinedEventModel{
+				eventModel:       *model,
+				QuarantineReason: event.Reason,
+				QuarantinedAt:    event.QuarantinedAt,
+			}
+
+			if _, err := tx.NamedExecContext(ctx, query, quarantined); err != nil {
+				return fmt.Errorf("failed to insert quarantined event: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// List returns quarantined events, most recently quarantined first
+func (r *QuarantineRepository) List(ctx context.Context, limit, offset int) ([]*domain.QuarantinedEvent, error) {
+	query := fmt.Sprintf(`
+		SELECT %s,
+			quarantine_reason, quarantined_at
+		FROM quarantined_events
+		ORDER BY quarantined_at DESC, id
+		LIMIT $1 OFFSET $2
+	`, eventColumns)
+
+	var rows []quarantinedEventModel
+	if err := postgresql.SelectWithContext(ctx, r.db, &rows, query, limit, offset); err != nil {
+		return nil, fmt.Errorf("failed to list quarantined events: %w", err)
+	}
+
+	return fromQuarantinedModels(rows)
+}
+
+// FindByIDs returns the quarantined events with the given event IDs
+func (r *QuarantineRepository) FindByIDs(ctx context.Context, ids []string) ([]*domain.QuarantinedEvent, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s,
+			quarantine_reason, quarantined_at
+		FROM quarantined_events
+		WHERE id = ANY($1)
+	`, eventColumns)
+
+	var rows []quarantinedEventModel
+	if err := postgresql.SelectWithContext(ctx, r.db, &rows, query, pq.Array(ids)); err != nil {
+		return nil, fmt.Errorf("failed to find quarantined events: %w", err)
+	}
+
+	return fromQuarantinedModels(rows)
+}
+
+// Delete removes the quarantined events with the given event IDs
+func (r *QuarantineRepository) Delete(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	if err := postgresql.ExecWithContext(ctx, r.db, "DELETE FROM quarantined_events WHERE id = ANY($1)", pq.Array(ids)); err != nil {
+		return fmt.Errorf("failed to delete quarantined events: %w", err)
+	}
+
+	return nil
+}
+
+func fromQuarantinedModels(rows []quarantinedEventModel) ([]*domain.QuarantinedEvent, error) {
+	events := make([]*domain.QuarantinedEvent, len(rows))
+	for i := range rows {
+		event, err := fromModel(&rows[i].eventModel)
+		if err != nil {
+			return nil, err
+		}
+
+		events[i] = &domain.QuarantinedEvent{
+			Event:         event,
+			Reason:        rows[i].QuarantineReason,
+			QuarantinedAt: rows[i].QuarantinedAt,
+		}
+	}
+	return events, nil
+}
@@ -0,0 +1,266 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/ebubekir/event-stream/internal/domain"
+	eventDomain "github.com/ebubekir/event-stream/internal/domain/event"
+	"github.com/ebubekir/event-stream/pkg/postgresql"
+)
+
+// WebhookRepository implements domain/event.WebhookRepository for PostgreSQL
+type WebhookRepository struct {
+	db *postgresql.PostgresDb
+}
+
+// NewWebhookRepository creates a new PostgreSQL webhook repository
+func NewWebhookRepository(db *postgresql.PostgresDb) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+// webhookSubscriptionModel is the database model for webhook subscriptions
+type webhookSubscriptionModel struct {
+	ID                  string         `db:"id"`
+	URL                 string         `db:"url"`
+	Secret              string         `db:"secret"`
+	EventNames          pq.StringArray `db:"event_names"`
+	ParamFilters        []byte         `db:"param_filters"` // JSONB
+	Enabled             bool           `db:"enabled"`
+	ConsecutiveFailures int            `db:"consecutive_failures"`
+	DisabledReason      string         `db:"disabled_reason"`
+	CreatedAt           time.Time      `db:"created_at"`
+	UpdatedAt           time.Time      `db:"updated_at"`
+}
+
+// webhookDeliveryModel is the database model for webhook delivery attempts
+type webhookDeliveryModel struct {
+	ID             string    `db:"id"`
+	SubscriptionID string    `db:"subscription_id"`
+	EventID        string    `db:"event_id"`
+	EventName      string    `db:"event_name"`
+	Attempt        int       `db:"attempt"`
+	StatusCode     int       `db:"status_code"`
+	Error          string    `db:"error"`
+	Succeeded      bool      `db:"succeeded"`
+	DurationMs     int64     `db:"duration_ms"`
+	AttemptedAt    time.Time `db:"attempted_at"`
+}
+
+const webhookSubscriptionColumns = `
+	id, url, secret, event_names, param_filters, enabled,
+	consecutive_failures, disabled_reason, created_at, updated_at`
+
+// Create persists a new subscription
+func (r *WebhookRepository) Create(ctx context.Context, subscription *domain.WebhookSubscription) error {
+	model, err := toWebhookSubscriptionModel(subscription)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO webhook_subscriptions (%s)
+		VALUES (
+			:id, :url, :secret, :event_names, :param_filters, :enabled,
+			:consecutive_failures, :disabled_reason, :created_at, :updated_at
+		)
+	`, webhookSubscriptionColumns)
+
+	if err := postgresql.NamedExec(r.db, query, model); err != nil {
+		return fmt.Errorf("failed to insert webhook subscription: %w", err)
+	}
+
+	return nil
+}
+
+// Update replaces an existing subscription
+func (r *WebhookRepository) Update(ctx context.Context, subscription *domain.WebhookSubscription) error {
+	model, err := toWebhookSubscriptionModel(subscription)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE webhook_subscriptions SET
+			url = :url,
+			secret = :secret,
+			event_names = :event_names,
+			param_filters = :param_filters,
+			enabled = :enabled,
+			consecutive_failures = :consecutive_failures,
+			disabled_reason = :disabled_reason,
+			updated_at = :updated_at
+		WHERE id = :id
+	`
+
+	if err := postgresql.NamedExec(r.db, query, model); err != nil {
+		return fmt.Errorf("failed to update webhook subscription: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a subscription; its deliveries are removed by the foreign key cascade
+func (r *WebhookRepository) Delete(ctx context.Context, id string) error {
+	if err := postgresql.ExecWithContext(ctx, r.db, "DELETE FROM webhook_subscriptions WHERE id = $1", id); err != nil {
+		return fmt.Errorf("failed to delete webhook subscription: %w", err)
+	}
+
+	return nil
+}
+
+// FindByID returns the subscription with the given ID
+func (r *WebhookRepository) FindByID(ctx context.Context, id string) (*domain.WebhookSubscription, error) {
+	query := fmt.Sprintf("SELECT %s FROM webhook_subscriptions WHERE id = $1", webhookSubscriptionColumns)
+
+	var rows []webhookSubscriptionModel
+	if err := postgresql.SelectWithContext(ctx, r.db, &rows, query, id); err != nil {
+		return nil, fmt.Errorf("failed to find webhook subscription: %w", err)
+	}
+
+	if len(rows) == 0 {
+		return nil, eventDomain.ErrWebhookNotFound
+	}
+
+	return fromWebhookSubscriptionModel(&rows[0])
+}
+
+// List returns all subscriptions, oldest first
+func (r *WebhookRepository) List(ctx context.Context) ([]*domain.WebhookSubscription, error) {
+	query := fmt.Sprintf("SELECT %s FROM webhook_subscriptions ORDER BY created_at, id", webhookSubscriptionColumns)
+
+	var rows []webhookSubscriptionModel
+	if err := postgresql.SelectWithContext(ctx, r.db, &rows, query); err != nil {
+		return nil, fmt.Errorf("failed to list webhook subscriptions: %w", err)
+	}
+
+	subscriptions := make([]*domain.WebhookSubscription, len(rows))
+	for i := range rows {
+		subscription, err := fromWebhookSubscriptionModel(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+		subscriptions[i] = subscription
+	}
+	return subscriptions, nil
+}
+
+// SaveDelivery records a delivery attempt
+func (r *WebhookRepository) SaveDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	query := `
+		INSERT INTO webhook_deliveries (
+			id, subscription_id, event_id, event_name, attempt,
+			status_code, error, succeeded, duration_ms, attempted_at
+		) VALUES (
+			:id, :subscription_id, :event_id, :event_name, :attempt,
+			:status_code, :error, :succeeded, :duration_ms, :attempted_at
+		)
+	`
+
+	model := webhookDeliveryModel{
+		ID:             delivery.ID,
+		SubscriptionID: delivery.SubscriptionID,
+		EventID:        delivery.EventID,
+		EventName:      delivery.EventName,
+		Attempt:        delivery.Attempt,
+		StatusCode:     delivery.StatusCode,
+		Error:          delivery.Error,
+		Succeeded:      delivery.Succeeded,
+		DurationMs:     delivery.Duration.Milliseconds(),
+		AttemptedAt:    delivery.AttemptedAt,
+	}
+
+	if err := postgresql.NamedExec(r.db, query, model); err != nil {
+		return fmt.Errorf("failed to insert webhook delivery: %w", err)
+	}
+
+	return nil
+}
+
+// ListDeliveries returns a subscription's delivery attempts, most recent first
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, subscriptionID string, limit, offset int) ([]*domain.WebhookDelivery, error) {
+	query := `
+		SELECT
+			id, subscription_id, event_id, event_name, attempt,
+			status_code, error, succeeded, duration_ms, attempted_at
+		FROM webhook_deliveries
+		WHERE subscription_id = $1
+		ORDER BY attempted_at DESC, id
+		LIMIT $2 OFFSET $3
+	`
+
+	var rows []webhookDeliveryModel
+	if err := postgresql.SelectWithContext(ctx, r.db, &rows, query, subscriptionID, limit, offset); err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+
+	deliveries := make([]*domain.WebhookDelivery, len(rows))
+	for i, row := range rows {
+		deliveries[i] = &domain.WebhookDelivery{
+			ID:             row.ID,
+			SubscriptionID: row.SubscriptionID,
+			EventID:        row.EventID,
+			EventName:      row.EventName,
+			Attempt:        row.Attempt,
+			StatusCode:     row.StatusCode,
+			Error:          row.Error,
+			Succeeded:      row.Succeeded,
+			Duration:       time.Duration(row.DurationMs) * time.Millisecond,
+			AttemptedAt:    row.AttemptedAt,
+		}
+	}
+	return deliveries, nil
+}
+
+func toWebhookSubscriptionModel(s *domain.WebhookSubscription) (*webhookSubscriptionModel, error) {
+	filters := s.ParamFilters
+	if filters == nil {
+		filters = []domain.WebhookParamFilter{}
+	}
+	paramFilters, err := json.Marshal(filters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal param_filters: %w", err)
+	}
+
+	eventNames := s.EventNames
+	if eventNames == nil {
+		eventNames = []string{}
+	}
+
+	return &webhookSubscriptionModel{
+		ID:                  s.ID,
+		URL:                 s.URL,
+		Secret:              s.Secret,
+		EventNames:          eventNames,
+		ParamFilters:        paramFilters,
+		Enabled:             s.Enabled,
+		ConsecutiveFailures: s.ConsecutiveFailures,
+		DisabledReason:      s.DisabledReason,
+		CreatedAt:           s.CreatedAt,
+		UpdatedAt:           s.UpdatedAt,
+	}, nil
+}
+
+func fromWebhookSubscriptionModel(m *webhookSubscriptionModel) (*domain.WebhookSubscription, error) {
+	var filters []domain.WebhookParamFilter
+	if err := json.Unmarshal(m.ParamFilters, &filters); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal param_filters: %w", err)
+	}
+
+	return &domain.WebhookSubscription{
+		ID:                  m.ID,
+		URL:                 m.URL,
+		Secret:              m.Secret,
+		EventNames:          m.EventNames,
+		ParamFilters:        filters,
+		Enabled:             m.Enabled,
+		ConsecutiveFailures: m.ConsecutiveFailures,
+		DisabledReason:      m.DisabledReason,
+		CreatedAt:           m.CreatedAt,
+		UpdatedAt:           m.UpdatedAt,
+	}, nil
+}
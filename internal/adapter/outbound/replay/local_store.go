@@ -0,0 +1,192 @@
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	eventDomain "github.com/ebubekir/event-stream/internal/domain/event"
+)
+
+// LocalStore implements domain/event.ReplayStore on a local directory,
+// keeping each job and its checkpoint as "<id>.json"
+type LocalStore struct {
+	dir string
+}
+
+// NewLocalStore creates a LocalStore rooted at dir, creating the directory if needed
+func NewLocalStore(dir string) (*LocalStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create replay directory: %w", err)
+	}
+	return &LocalStore{dir: dir}, nil
+}
+
+// jobModel is the stored representation of a replay job
+type jobModel struct {
+	ID           string    `json:"id"`
+	Source       string    `json:"source"`
+	From         time.Time `json:"from"`
+	To           time.Time `json:"to"`
+	EventNames   []string  `json:"event_names,omitempty"`
+	ChannelType  string    `json:"channel_type,omitempty"`
+	UserID       string    `json:"user_id,omitempty"`
+	UserPseudoID string    `json:"user_pseudo_id,omitempty"`
+	AppID        string    `json:"app_id,omitempty"`
+	Reprocess    bool      `json:"reprocess"`
+	DeleteFirst  bool      `json:"delete_first"`
+	Rate         float64   `json:"rate"`
+	ChunkSize    string    `json:"chunk_size"`
+	Checkpoint   time.Time `json:"checkpoint"`
+	Runs         int       `json:"runs"`
+	Status       string    `json:"status"`
+	EventCount   int64     `json:"event_count"`
+	Error        string    `json:"error,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	StartedAt    time.Time `json:"started_at"`
+	FinishedAt   time.Time `json:"finished_at"`
+}
+
+// SaveJob writes the job file atomically, so a crash never loses a checkpoint
+func (s *LocalStore) SaveJob(ctx context.Context, job *eventDomain.ReplayJob) error {
+	data, err := json.Marshal(toJobModel(job))
+	if err != nil {
+		return fmt.Errorf("failed to encode replay job: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(s.dir, ".job-*")
+	if err != nil {
+		return fmt.Errorf("failed to save replay job: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to save replay job: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save replay job: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.jobPath(job.ID)); err != nil {
+		return fmt.Errorf("failed to save replay job: %w", err)
+	}
+	return nil
+}
+
+// FindJob returns the job with the given ID or ErrReplayNotFound
+func (s *LocalStore) FindJob(ctx context.Context, id string) (*eventDomain.ReplayJob, error) {
+	// IDs become file names, so anything but a UUID cannot be a job
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, eventDomain.ErrReplayNotFound
+	}
+	return s.readJob(s.jobPath(id))
+}
+
+// ListJobs returns all jobs, most recent first
+func (s *LocalStore) ListJobs(ctx context.Context) ([]*eventDomain.ReplayJob, error) {
+	paths, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list replay jobs: %w", err)
+	}
+
+	jobs := make([]*eventDomain.ReplayJob, 0, len(paths))
+	for _, path := range paths {
+		job, err := s.readJob(path)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].CreatedAt.After(jobs[j].CreatedAt)
+	})
+	return jobs, nil
+}
+
+func (s *LocalStore) readJob(path string) (*eventDomain.ReplayJob, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, eventDomain.ErrReplayNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read replay job: %w", err)
+	}
+
+	var model jobModel
+	if err := json.Unmarshal(data, &model); err != nil {
+		return nil, fmt.Errorf("failed to decode replay job %s: %w", filepath.Base(path), err)
+	}
+	return fromJobModel(&model)
+}
+
+func (s *LocalStore) jobPath(id string) string {
+	return filepath.Join(s.dir, id+".json")
+}
+
+func toJobModel(job *eventDomain.ReplayJob) *jobModel {
+	return &jobModel{
+		ID:           job.ID,
+		Source:       job.Source,
+		From:         job.Filter.From,
+		To:           job.Filter.To,
+		EventNames:   job.Filter.EventNames,
+		ChannelType:  job.Filter.ChannelType,
+		UserID:       job.Filter.UserID,
+		UserPseudoID: job.Filter.UserPseudoID,
+		AppID:        job.Filter.AppID,
+		Reprocess:    job.Reprocess,
+		DeleteFirst:  job.DeleteFirst,
+		Rate:         job.Rate,
+		ChunkSize:    job.ChunkSize.String(),
+		Checkpoint:   job.Checkpoint,
+		Runs:         job.Runs,
+		Status:       string(job.Status),
+		EventCount:   job.EventCount,
+		Error:        job.Error,
+		CreatedAt:    job.CreatedAt,
+		StartedAt:    job.StartedAt,
+		FinishedAt:   job.FinishedAt,
+	}
+}
+
+func fromJobModel(model *jobModel) (*eventDomain.ReplayJob, error) {
+	chunkSize, err := time.ParseDuration(model.ChunkSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode replay job %s: %w", model.ID, err)
+	}
+
+	return &eventDomain.ReplayJob{
+		ID:     model.ID,
+		Source: model.Source,
+		Filter: eventDomain.EventFilter{
+			From:         model.From,
+			To:           model.To,
+			EventNames:   model.EventNames,
+			ChannelType:  model.ChannelType,
+			UserID:       model.UserID,
+			UserPseudoID: model.UserPseudoID,
+			AppID:        model.AppID,
+		},
+		Reprocess:   model.Reprocess,
+		DeleteFirst: model.DeleteFirst,
+		Rate:        model.Rate,
+		ChunkSize:   chunkSize,
+		Checkpoint:  model.Checkpoint,
+		Runs:        model.Runs,
+		Status:      eventDomain.ReplayStatus(model.Status),
+		EventCount:  model.EventCount,
+		Error:       model.Error,
+		CreatedAt:   model.CreatedAt,
+		StartedAt:   model.StartedAt,
+		FinishedAt:  model.FinishedAt,
+	}, nil
+}
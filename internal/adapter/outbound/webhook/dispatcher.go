@@ -0,0 +1,505 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/ebubekir/event-stream/internal/adapter/eventcodec"
+	"github.com/ebubekir/event-stream/internal/domain"
+	eventDomain "github.com/ebubekir/event-stream/internal/domain/event"
+	"github.com/ebubekir/event-stream/pkg/logger"
+)
+
+const (
+	// Headers sent with every delivery
+	IDHeader        = "X-Webhook-ID"
+	TimestampHeader = "X-Webhook-Timestamp"
+	SignatureHeader = "X-Webhook-Signature"
+
+	// EventType identifies payloads sent for stored events
+	EventType = "event.stored"
+
+	repositoryTimeout = 5 * time.Second
+)
+
+// Config tunes delivery behaviour
+type Config struct {
+	Workers        int           // concurrent deliveries
+	QueueSize      int           // pending deliveries before new ones are dropped
+	MaxAttempts    int           // attempts per delivery, including the first
+	InitialBackoff time.Duration // wait before the first retry; doubles per attempt
+	MaxBackoff     time.Duration // upper bound for the wait between retries
+	Timeout        time.Duration // per-request timeout
+	DisableAfter   int           // consecutive failed deliveries before a subscription is disabled
+	CacheTTL       time.Duration // how long subscriptions are cached between reloads
+}
+
+// Payload is the JSON body POSTed to subscribers
+type Payload struct {
+	ID             string            `json:"id"`
+	Type           string            `json:"type"`
+	SubscriptionID string            `json:"subscription_id"`
+	CreatedAt      time.Time         `json:"created_at"`
+	Event          eventcodec.Record `json:"event"`
+}
+
+// delivery is a payload queued for a subscription
+type delivery struct {
+	id           string
+	subscription *domain.WebhookSubscription
+	event        *domain.Event
+	body         []byte
+	attempt      int
+}
+
+// Dispatcher implements domain/event.EventPublisher by POSTing signed payloads
+// to matching webhook subscriptions. Failed deliveries are retried with
+// exponential backoff; subscriptions are disabled after sustained failures.
+type Dispatcher struct {
+	repo   eventDomain.WebhookRepository
+	client *http.Client
+	config Config
+
+	queueMu sync.RWMutex
+	queue   chan *delivery
+	closed  bool
+	workers sync.WaitGroup
+
+	// Subscriptions are reloaded in the background; Publish serves the
+	// cached list meanwhile and only waits for the very first load
+	cacheMu       sync.Mutex
+	subscriptions []*domain.WebhookSubscription
+	loadedAt      time.Time
+	refreshing    bool
+	invalidated   bool // set when the cache is invalidated during a refresh
+	loaded        chan struct{}
+	loadedOnce    sync.Once
+
+	// Health updates of one subscription are serialized; those of
+	// different subscriptions run concurrently
+	healthMu    sync.Mutex
+	healthLocks map[string]*healthLock
+}
+
+// healthLock serializes health updates of one subscription and counts the
+// updates holding or waiting for it, so it can be dropped once unused
+type healthLock struct {
+	sync.Mutex
+	users int
+}
+
+// NewDispatcher creates a Dispatcher and starts its workers
+func NewDispatcher(repo eventDomain.WebhookRepository, config Config) *Dispatcher {
+	if config.Workers <= 0 {
+		config.Workers = 4
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = 10000
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = 5
+	}
+	if config.InitialBackoff <= 0 {
+		config.InitialBackoff = time.Second
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = 5 * time.Minute
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 10 * time.Second
+	}
+	if config.DisableAfter <= 0 {
+		config.DisableAfter = 10
+	}
+	if config.CacheTTL <= 0 {
+		config.CacheTTL = 30 * time.Second
+	}
+
+	d := &Dispatcher{
+		repo:   repo,
+		client: &http.Client{Timeout: config.Timeout},
+		config: config,
+		queue:  make(chan *delivery, config.QueueSize),
+		loaded: make(chan struct{}),
+
+		healthLocks: make(map[string]*healthLock),
+	}
+
+	d.cacheMu.Lock()
+	d.startRefresh()
+	d.cacheMu.Unlock()
+
+	for i := 0; i < config.Workers; i++ {
+		d.workers.Add(1)
+		go d.work()
+	}
+
+	return d
+}
+
+// Publish queues a delivery for every enabled subscription matching each event
+func (d *Dispatcher) Publish(ctx context.Context, events []*domain.Event) {
+	subscriptions := d.loadSubscriptions()
+	if len(subscriptions) == 0 {
+		return
+	}
+
+	now := time.Now().UTC()
+	for _, event := range events {
+		for _, subscription := range subscriptions {
+			if !subscription.Matches(event) {
+				continue
+			}
+
+			id := uuid.New().String()
+			body, err := json.Marshal(Payload{
+				ID:             id,
+				Type:           EventType,
+				SubscriptionID: subscription.ID,
+				CreatedAt:      now,
+				Event:          eventcodec.FromEvent(event),
+			})
+			if err != nil {
+				logger.Error("failed to encode webhook payload", zap.String("event_id", event.ID), zap.Error(err))
+				continue
+			}
+
+			d.enqueue(&delivery{id: id, subscription: subscription, event: event, body: body, attempt: 1})
+		}
+	}
+}
+
+// Close stops accepting deliveries and waits for queued ones to finish or ctx to expire.
+// Retries scheduled for later are dropped.
+func (d *Dispatcher) Close(ctx context.Context) error {
+	d.queueMu.Lock()
+	if !d.closed {
+		d.closed = true
+		close(d.queue)
+	}
+	d.queueMu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		d.workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// InvalidateCache reloads subscriptions in the background, e.g. after one was changed
+func (d *Dispatcher) InvalidateCache() {
+	d.cacheMu.Lock()
+	d.loadedAt = time.Time{}
+	d.startRefresh()
+	d.cacheMu.Unlock()
+}
+
+func (d *Dispatcher) enqueue(job *delivery) {
+	d.queueMu.RLock()
+	defer d.queueMu.RUnlock()
+
+	if d.closed {
+		return
+	}
+
+	select {
+	case d.queue <- job:
+	default:
+		logger.Warn("webhook queue full, dropping delivery",
+			zap.String("subscription_id", job.subscription.ID),
+			zap.String("event_id", job.event.ID),
+		)
+	}
+}
+
+func (d *Dispatcher) work() {
+	defer d.workers.Done()
+
+	for job := range d.queue {
+		d.deliver(job)
+	}
+}
+
+// deliver makes one attempt and schedules a retry or records the outcome
+func (d *Dispatcher) deliver(job *delivery) {
+	// A retry goes to the subscription as it is now: its URL or secret may
+	// have changed, or it may have been disabled or deleted since
+	if job.attempt > 1 {
+		subscription, ok := d.reload(job)
+		if !ok {
+			return
+		}
+		job.subscription = subscription
+	}
+
+	start := time.Now()
+	statusCode, err := d.post(job)
+
+	record := &domain.WebhookDelivery{
+		ID:             uuid.New().String(),
+		SubscriptionID: job.subscription.ID,
+		EventID:        job.event.ID,
+		EventName:      job.event.Name,
+		Attempt:        job.attempt,
+		StatusCode:     statusCode,
+		Succeeded:      err == nil,
+		Duration:       time.Since(start),
+		AttemptedAt:    start.UTC(),
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), repositoryTimeout)
+	defer cancel()
+
+	if saveErr := d.repo.SaveDelivery(ctx, record); saveErr != nil {
+		logger.Error("failed to record webhook delivery", zap.String("subscription_id", job.subscription.ID), zap.Error(saveErr))
+	}
+
+	if err == nil {
+		d.recordSuccess(ctx, job.subscription.ID)
+		return
+	}
+
+	if job.attempt < d.config.MaxAttempts {
+		retry := *job
+		retry.attempt++
+		time.AfterFunc(d.backoff(job.attempt), func() { d.enqueue(&retry) })
+		return
+	}
+
+	d.recordFailure(ctx, job.subscription.ID)
+}
+
+// reload returns the stored subscription a retry is delivered to, or false
+// when the retry is dropped because the subscription was deleted, disabled
+// or no longer matches the event. A failed lookup keeps the queued snapshot.
+func (d *Dispatcher) reload(job *delivery) (*domain.WebhookSubscription, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), repositoryTimeout)
+	defer cancel()
+
+	subscription, err := d.repo.FindByID(ctx, job.subscription.ID)
+	if errors.Is(err, eventDomain.ErrWebhookNotFound) {
+		return nil, false
+	}
+	if err != nil {
+		logger.Error("failed to reload webhook subscription", zap.String("subscription_id", job.subscription.ID), zap.Error(err))
+		return job.subscription, true
+	}
+
+	if !subscription.Enabled || !subscription.Matches(job.event) {
+		return nil, false
+	}
+	return subscription, true
+}
+
+// post sends the payload and returns the response status code
+func (d *Dispatcher) post(job *delivery) (int, error) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequest(http.MethodPost, job.subscription.URL, bytes.NewReader(job.body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "event-stream-webhooks/1.0")
+	req.Header.Set(IDHeader, job.id)
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, Sign(job.subscription.Secret, timestamp, job.body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// Sign returns the signature header value for a payload:
+// "sha256=" followed by the hex HMAC-SHA256 of "<timestamp>.<body>" keyed with secret
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// backoff returns the wait before the next attempt, with up to 20% jitter
+func (d *Dispatcher) backoff(attempt int) time.Duration {
+	wait := d.config.InitialBackoff << (attempt - 1)
+	if wait <= 0 || wait > d.config.MaxBackoff {
+		wait = d.config.MaxBackoff
+	}
+	return wait + time.Duration(rand.Int64N(int64(wait)/5+1))
+}
+
+// recordSuccess resets the stored failure count. It always consults the
+// stored subscription: the one a job was queued with may predate failures.
+func (d *Dispatcher) recordSuccess(ctx context.Context, id string) {
+	d.updateHealth(ctx, id, func(s *domain.WebhookSubscription) bool {
+		if s.ConsecutiveFailures == 0 {
+			return false
+		}
+		s.ConsecutiveFailures = 0
+		return true
+	})
+}
+
+func (d *Dispatcher) recordFailure(ctx context.Context, id string) {
+	d.updateHealth(ctx, id, func(s *domain.WebhookSubscription) bool {
+		s.ConsecutiveFailures++
+		if s.Enabled && s.ConsecutiveFailures >= d.config.DisableAfter {
+			s.Enabled = false
+			s.DisabledReason = fmt.Sprintf("disabled after %d consecutive failed deliveries", s.ConsecutiveFailures)
+			logger.Warn("disabling webhook subscription", zap.String("subscription_id", s.ID), zap.String("url", s.URL))
+		}
+		return true
+	})
+}
+
+// updateHealth applies change to the stored subscription and saves it when change reports a modification
+func (d *Dispatcher) updateHealth(ctx context.Context, id string, change func(*domain.WebhookSubscription) bool) {
+	unlock := d.lockHealth(id)
+	defer unlock()
+
+	subscription, err := d.repo.FindByID(ctx, id)
+	if err != nil {
+		logger.Error("failed to load webhook subscription", zap.String("subscription_id", id), zap.Error(err))
+		return
+	}
+
+	if !change(subscription) {
+		return
+	}
+
+	subscription.UpdatedAt = time.Now().UTC()
+	if err := d.repo.Update(ctx, subscription); err != nil {
+		logger.Error("failed to update webhook subscription", zap.String("subscription_id", id), zap.Error(err))
+		return
+	}
+
+	d.InvalidateCache()
+}
+
+// lockHealth locks health updates of subscription id and returns the unlock function
+func (d *Dispatcher) lockHealth(id string) func() {
+	d.healthMu.Lock()
+	lock, ok := d.healthLocks[id]
+	if !ok {
+		lock = &healthLock{}
+		d.healthLocks[id] = lock
+	}
+	lock.users++
+	d.healthMu.Unlock()
+
+	lock.Lock()
+	return func() {
+		lock.Unlock()
+
+		d.healthMu.Lock()
+		lock.users--
+		if lock.users == 0 {
+			delete(d.healthLocks, id)
+		}
+		d.healthMu.Unlock()
+	}
+}
+
+// loadSubscriptions returns the cached enabled subscriptions and starts a
+// background reload when they are stale. Only the first call waits, for the
+// initial load.
+func (d *Dispatcher) loadSubscriptions() []*domain.WebhookSubscription {
+	<-d.loaded
+
+	d.cacheMu.Lock()
+	defer d.cacheMu.Unlock()
+
+	if time.Since(d.loadedAt) >= d.config.CacheTTL {
+		d.startRefresh()
+	}
+	return d.subscriptions
+}
+
+// startRefresh reloads subscriptions in the background unless a reload is
+// running, in which case that reload runs once more. Callers hold cacheMu.
+func (d *Dispatcher) startRefresh() {
+	if d.refreshing {
+		d.invalidated = true
+		return
+	}
+	d.refreshing = true
+	go d.refresh()
+}
+
+// refresh reloads subscriptions until no invalidation arrived while it ran.
+// A failed reload keeps serving the previous subscriptions.
+func (d *Dispatcher) refresh() {
+	for {
+		enabled, err := d.listEnabled()
+
+		d.cacheMu.Lock()
+		if err != nil {
+			logger.Error("failed to load webhook subscriptions", zap.Error(err))
+		} else {
+			d.subscriptions = enabled
+		}
+		d.loadedAt = time.Now()
+		again := d.invalidated
+		d.invalidated = false
+		d.refreshing = again
+		d.cacheMu.Unlock()
+
+		d.loadedOnce.Do(func() { close(d.loaded) })
+		if !again {
+			return
+		}
+	}
+}
+
+// listEnabled loads the enabled subscriptions from the repository
+func (d *Dispatcher) listEnabled() ([]*domain.WebhookSubscription, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), repositoryTimeout)
+	defer cancel()
+
+	all, err := d.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	enabled := make([]*domain.WebhookSubscription, 0, len(all))
+	for _, s := range all {
+		if s.Enabled {
+			enabled = append(enabled, s)
+		}
+	}
+	return enabled, nil
+}
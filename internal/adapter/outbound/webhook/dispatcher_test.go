@@ -0,0 +1,318 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/ebubekir/event-stream/internal/domain"
+	eventDomain "github.com/ebubekir/event-stream/internal/domain/event"
+	"github.com/ebubekir/event-stream/pkg/logger"
+)
+
+func init() {
+	logger.Log = zap.NewNop()
+}
+
+// memoryRepository is an in-memory WebhookRepository. While block is set,
+// List waits for it to be closed, and FindByID waits for findBlock[id].
+type memoryRepository struct {
+	eventDomain.WebhookRepository
+
+	mu            sync.Mutex
+	subscriptions map[string]domain.WebhookSubscription
+	block         chan struct{}
+	findBlock     map[string]chan struct{}
+}
+
+func newMemoryRepository(subscriptions ...domain.WebhookSubscription) *memoryRepository {
+	r := &memoryRepository{subscriptions: make(map[string]domain.WebhookSubscription)}
+	for _, s := range subscriptions {
+		r.subscriptions[s.ID] = s
+	}
+	return r
+}
+
+func (r *memoryRepository) List(ctx context.Context) ([]*domain.WebhookSubscription, error) {
+	r.mu.Lock()
+	block := r.block
+	r.mu.Unlock()
+	if block != nil {
+		<-block
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var all []*domain.WebhookSubscription
+	for _, s := range r.subscriptions {
+		all = append(all, &s)
+	}
+	return all, nil
+}
+
+func (r *memoryRepository) FindByID(ctx context.Context, id string) (*domain.WebhookSubscription, error) {
+	r.mu.Lock()
+	block := r.findBlock[id]
+	r.mu.Unlock()
+	if block != nil {
+		<-block
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.subscriptions[id]
+	if !ok {
+		return nil, eventDomain.ErrWebhookNotFound
+	}
+	return &s, nil
+}
+
+func (r *memoryRepository) Update(ctx context.Context, subscription *domain.WebhookSubscription) error {
+	r.set(*subscription)
+	return nil
+}
+
+func (r *memoryRepository) SaveDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	return nil
+}
+
+func (r *memoryRepository) get(id string) domain.WebhookSubscription {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.subscriptions[id]
+}
+
+func (r *memoryRepository) set(subscription domain.WebhookSubscription) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.subscriptions[subscription.ID] = subscription
+}
+
+// receiver is a subscriber endpoint answering with status and recording
+// whether each request was signed with secret
+type receiver struct {
+	secret string
+
+	mu     sync.Mutex
+	signed []bool
+	server *httptest.Server
+}
+
+func newReceiver(t *testing.T, status int, secret string) *receiver {
+	r := &receiver{secret: secret}
+	r.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		want := Sign(r.secret, req.Header.Get(TimestampHeader), body)
+
+		r.mu.Lock()
+		r.signed = append(r.signed, req.Header.Get(SignatureHeader) == want)
+		r.mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(r.server.Close)
+	return r
+}
+
+func (r *receiver) requests() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.signed)
+}
+
+func newTestDispatcher(repo *memoryRepository, maxAttempts int) *Dispatcher {
+	return NewDispatcher(repo, Config{
+		Workers:        1,
+		MaxAttempts:    maxAttempts,
+		InitialBackoff: 20 * time.Millisecond,
+		MaxBackoff:     20 * time.Millisecond,
+		CacheTTL:       time.Hour,
+	})
+}
+
+// waitFor polls cond until it holds or a second has passed
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+var testEvent = &domain.Event{ID: "event-1", Name: "purchase"}
+
+func TestDispatcherSuccessResetsStoredFailures(t *testing.T) {
+	ok := newReceiver(t, http.StatusOK, "")
+	repo := newMemoryRepository(domain.WebhookSubscription{ID: "sub", URL: ok.server.URL, Enabled: true})
+	d := newTestDispatcher(repo, 1)
+	defer d.Close(context.Background())
+
+	// Load the subscription into the cache, then record failures of older
+	// deliveries that the cached copy does not know about
+	d.loadSubscriptions()
+	stored := repo.get("sub")
+	stored.ConsecutiveFailures = 3
+	repo.set(stored)
+
+	d.Publish(context.Background(), []*domain.Event{testEvent})
+
+	waitFor(t, "the failure count to reset", func() bool { return repo.get("sub").ConsecutiveFailures == 0 })
+}
+
+func TestDispatcherHealthUpdatesRunPerSubscription(t *testing.T) {
+	ok := newReceiver(t, http.StatusOK, "")
+	repo := newMemoryRepository(
+		domain.WebhookSubscription{ID: "slow", URL: ok.server.URL, Enabled: true, ConsecutiveFailures: 3},
+		domain.WebhookSubscription{ID: "fast", URL: ok.server.URL, Enabled: true, ConsecutiveFailures: 3},
+	)
+	d := NewDispatcher(repo, Config{Workers: 2, CacheTTL: time.Hour})
+	defer d.Close(context.Background())
+
+	// The lookup for one subscription hangs; the other still resets
+	block := make(chan struct{})
+	defer close(block)
+	repo.mu.Lock()
+	repo.findBlock = map[string]chan struct{}{"slow": block}
+	repo.mu.Unlock()
+
+	d.loadSubscriptions()
+	d.Publish(context.Background(), []*domain.Event{testEvent})
+
+	waitFor(t, "the other subscription to reset", func() bool { return repo.get("fast").ConsecutiveFailures == 0 })
+	if got := repo.get("slow").ConsecutiveFailures; got != 3 {
+		t.Errorf("blocked subscription has %d failures, want 3", got)
+	}
+}
+
+func TestDispatcherRetryUsesCurrentSubscription(t *testing.T) {
+	failing := newReceiver(t, http.StatusInternalServerError, "old")
+	moved := newReceiver(t, http.StatusOK, "new")
+	repo := newMemoryRepository(domain.WebhookSubscription{ID: "sub", URL: failing.server.URL, Secret: "old", Enabled: true})
+	d := newTestDispatcher(repo, 3)
+	defer d.Close(context.Background())
+
+	d.loadSubscriptions()
+	d.Publish(context.Background(), []*domain.Event{testEvent})
+	waitFor(t, "the first attempt", func() bool { return failing.requests() == 1 })
+
+	updated := repo.get("sub")
+	updated.URL = moved.server.URL
+	updated.Secret = "new"
+	repo.set(updated)
+
+	waitFor(t, "the retry", func() bool { return moved.requests() == 1 })
+	if failing.requests() != 1 {
+		t.Errorf("old URL got %d requests, want 1", failing.requests())
+	}
+
+	moved.mu.Lock()
+	defer moved.mu.Unlock()
+	if !moved.signed[0] {
+		t.Error("retry was not signed with the new secret")
+	}
+}
+
+func TestDispatcherRetryDroppedWhenDisabled(t *testing.T) {
+	failing := newReceiver(t, http.StatusInternalServerError, "")
+	repo := newMemoryRepository(domain.WebhookSubscription{ID: "sub", URL: failing.server.URL, Enabled: true})
+	d := newTestDispatcher(repo, 3)
+	defer d.Close(context.Background())
+
+	d.loadSubscriptions()
+	d.Publish(context.Background(), []*domain.Event{testEvent})
+	waitFor(t, "the first attempt", func() bool { return failing.requests() == 1 })
+
+	disabled := repo.get("sub")
+	disabled.Enabled = false
+	repo.set(disabled)
+
+	time.Sleep(100 * time.Millisecond)
+	if got := failing.requests(); got != 1 {
+		t.Errorf("disabled subscription got %d requests, want 1", got)
+	}
+}
+
+func TestDispatcherPublishDoesNotWaitForReload(t *testing.T) {
+	repo := newMemoryRepository()
+	d := NewDispatcher(repo, Config{Workers: 1, CacheTTL: time.Millisecond})
+	defer d.Close(context.Background())
+
+	d.loadSubscriptions()
+
+	block := make(chan struct{})
+	defer close(block)
+	repo.mu.Lock()
+	repo.block = block
+	repo.mu.Unlock()
+	time.Sleep(5 * time.Millisecond)
+
+	done := make(chan struct{})
+	go func() {
+		for range 3 {
+			d.Publish(context.Background(), []*domain.Event{testEvent})
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Publish waited for a subscription reload")
+	}
+}
+
+func TestSign(t *testing.T) {
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp string
+		body      string
+		want      string
+	}{
+		{
+			name:      "empty body",
+			secret:    "secret",
+			timestamp: "1700000000",
+			body:      "",
+			want:      "sha256=4bc5f74d868b97888288889c5d9d65df02526f94c1592a79fdf4fe8b26e311e5",
+		},
+		{
+			name:      "json body",
+			secret:    "secret",
+			timestamp: "1700000000",
+			body:      `{"id":"1"}`,
+			want:      "sha256=086f6aff7bd084c98679825129c5a64dbad88c760016d6d2c0fb123f27951d54",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sign(tt.secret, tt.timestamp, []byte(tt.body)); got != tt.want {
+				t.Errorf("Sign() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("depends on every input", func(t *testing.T) {
+		base := Sign("secret", "1700000000", []byte("body"))
+		variants := []string{
+			Sign("other", "1700000000", []byte("body")),
+			Sign("secret", strconv.Itoa(1700000001), []byte("body")),
+			Sign("secret", "1700000000", []byte("bodY")),
+		}
+		for i, v := range variants {
+			if v == base {
+				t.Errorf("variant %d has the same signature", i)
+			}
+		}
+	})
+}
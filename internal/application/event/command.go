@@ -1,11 +1,16 @@
 package event
 
 import (
+	"time"
+
 	"github.com/ebubekir/event-stream/internal/domain"
 )
 
 // CreateEventCommand represents the data needed to create a new event
 type CreateEventCommand struct {
+	// ID is optional; when empty the service generates a random one.
+	// Callers that may retry the same event set a deterministic ID.
+	ID                string
 	Name              string
 	ChannelType       domain.ChannelType
 	Timestamp         int64
@@ -16,8 +21,54 @@ type CreateEventCommand struct {
 	UserPseudoID      string
 	UserParams        []ParamDTO
 	Device            DeviceDTO
+	Geo               GeoDTO
 	AppInfo           AppInfoDTO
 	Items             []ItemDTO
+	Ingestion         IngestionMetadataDTO
+}
+
+// CreatedEventDTO reports the outcome of creating a single event
+type CreatedEventDTO struct {
+	ID string
+	// Quarantined is set when the acceptance window held the event back
+	// instead of storing it
+	Quarantined bool
+}
+
+// CreatedEventsDTO reports the outcome of creating a batch of events
+type CreatedEventsDTO struct {
+	// IDs holds the ID of every event, in command order
+	IDs []string
+	// QuarantinedIDs holds the IDs of the events the acceptance window held
+	// back instead of storing them
+	QuarantinedIDs []string
+}
+
+// IngestionMetadataDTO represents server-side ingestion details in application layer.
+// ClientIP is hashed by the service before it is persisted; ClientIPHash
+// carries the hash of an already stored event instead.
+type IngestionMetadataDTO struct {
+	ReceivedAt   time.Time
+	Endpoint     string
+	RequestID    string
+	ClientIP     string
+	ClientIPHash string
+	SDKName      string
+	SDKVersion   string
+	APIKeyID     string
+}
+
+// IdentifyCommand represents the data needed to link an anonymous user to a known user
+type IdentifyCommand struct {
+	UserPseudoID string
+	UserID       string
+}
+
+// ReleaseQuarantineCommand represents the data needed to release quarantined events into the events table
+type ReleaseQuarantineCommand struct {
+	IDs []string
+	// Clamp moves the released events' dates into the current acceptance window
+	Clamp bool
 }
 
 // ParamDTO represents a parameter in application layer
@@ -41,6 +92,16 @@ type DeviceDTO struct {
 	Hostname               string
 }
 
+// GeoDTO represents geographic information in application layer
+type GeoDTO struct {
+	Continent    string
+	SubContinent string
+	Country      string
+	Region       string
+	Metro        string
+	City         string
+}
+
 // AppInfoDTO represents app information in application layer
 type AppInfoDTO struct {
 	ID      string
@@ -64,6 +125,28 @@ type ItemDTO struct {
 	Params        []ParamDTO
 }
 
+// NewCreateEventCommand converts a stored event back to the command that
+// creates it, keeping its ID and ingestion metadata
+func NewCreateEventCommand(e *domain.Event) *CreateEventCommand {
+	return &CreateEventCommand{
+		ID:                e.ID,
+		Name:              e.Name,
+		ChannelType:       e.ChannelType,
+		Timestamp:         e.Timestamp,
+		PreviousTimestamp: e.PreviousTimestamp,
+		Date:              e.Date,
+		EventParams:       fromParams(e.EventParams),
+		UserID:            e.UserID,
+		UserPseudoID:      e.UserPseudoID,
+		UserParams:        fromParams(e.UserParams),
+		Device:            fromDevice(e.Device),
+		Geo:               fromGeo(e.Geo),
+		AppInfo:           fromAppInfo(e.AppInfo),
+		Items:             fromItems(e.Items),
+		Ingestion:         fromIngestionMetadata(e.Ingestion),
+	}
+}
+
 // ToEvent converts CreateEventCommand to domain.Event
 func (c *CreateEventCommand) ToEvent(id string) *domain.Event {
 	return &domain.Event{
@@ -78,8 +161,10 @@ func (c *CreateEventCommand) ToEvent(id string) *domain.Event {
 		UserPseudoID:      c.UserPseudoID,
 		UserParams:        toParams(c.UserParams),
 		Device:            toDevice(c.Device),
+		Geo:               toGeo(c.Geo),
 		AppInfo:           toAppInfo(c.AppInfo),
 		Items:             toItems(c.Items),
+		Ingestion:         toIngestionMetadata(c.Ingestion),
 	}
 }
 
@@ -110,6 +195,17 @@ func toDevice(dto DeviceDTO) domain.Device {
 	}
 }
 
+func toGeo(dto GeoDTO) domain.Geo {
+	return domain.Geo{
+		Continent:    dto.Continent,
+		SubContinent: dto.SubContinent,
+		Country:      dto.Country,
+		Region:       dto.Region,
+		Metro:        dto.Metro,
+		City:         dto.City,
+	}
+}
+
 func toAppInfo(dto AppInfoDTO) domain.AppInfo {
 	return domain.AppInfo{
 		ID:      dto.ID,
@@ -117,6 +213,18 @@ func toAppInfo(dto AppInfoDTO) domain.AppInfo {
 	}
 }
 
+func toIngestionMetadata(dto IngestionMetadataDTO) domain.IngestionMetadata {
+	return domain.IngestionMetadata{
+		ReceivedAt:   dto.ReceivedAt,
+		Endpoint:     dto.Endpoint,
+		RequestID:    dto.RequestID,
+		ClientIPHash: dto.ClientIPHash,
+		SDKName:      dto.SDKName,
+		SDKVersion:   dto.SDKVersion,
+		APIKeyID:     dto.APIKeyID,
+	}
+}
+
 func toItems(dtos []ItemDTO) []domain.Item {
 	items := make([]domain.Item, len(dtos))
 	for i, dto := range dtos {
@@ -138,3 +246,82 @@ func toItems(dtos []ItemDTO) []domain.Item {
 	}
 	return items
 }
+
+func fromParams(params []domain.Param) []ParamDTO {
+	dtos := make([]ParamDTO, len(params))
+	for i, p := range params {
+		dtos[i] = ParamDTO{
+			Key:          p.Key,
+			StringValue:  p.StringValue,
+			NumberValue:  p.NumberValue,
+			BooleanValue: p.BooleanValue,
+		}
+	}
+	return dtos
+}
+
+func fromItems(items []domain.Item) []ItemDTO {
+	dtos := make([]ItemDTO, len(items))
+	for i, item := range items {
+		dtos[i] = ItemDTO{
+			ID:            item.ID,
+			Name:          item.Name,
+			Brand:         item.Brand,
+			Variant:       item.Variant,
+			PriceInUsd:    item.PriceInUsd,
+			Quantity:      item.Quantity,
+			RevenueInUsd:  item.RevenueInUsd,
+			LocationId:    item.LocationId,
+			ListId:        item.ListId,
+			ListName:      item.ListName,
+			PromotionId:   item.PromotionId,
+			PromotionName: item.PromotionName,
+			Params:        fromParams(item.Params),
+		}
+	}
+	return dtos
+}
+
+func fromDevice(device domain.Device) DeviceDTO {
+	return DeviceDTO{
+		Category:               device.Category,
+		MobileBrandName:        device.MobileBrandName,
+		MobileModelName:        device.MobileModelName,
+		OperatingSystem:        device.OperatingSystem,
+		OperatingSystemVersion: device.OperatingSystemVersion,
+		Language:               device.Language,
+		BrowserName:            device.BrowserName,
+		BrowserVersion:         device.BrowserVersion,
+		Hostname:               device.Hostname,
+	}
+}
+
+func fromGeo(geo domain.Geo) GeoDTO {
+	return GeoDTO{
+		Continent:    geo.Continent,
+		SubContinent: geo.SubContinent,
+		Country:      geo.Country,
+		Region:       geo.Region,
+		Metro:        geo.Metro,
+		City:         geo.City,
+	}
+}
+
+func fromAppInfo(appInfo domain.AppInfo) AppInfoDTO {
+	return AppInfoDTO{
+		ID:      appInfo.ID,
+		Version: appInfo.Version,
+	}
+}
+
+func fromIngestionMetadata(metadata domain.IngestionMetadata) IngestionMetadataDTO {
+	return IngestionMetadataDTO{
+		ReceivedAt:   metadata.ReceivedAt,
+		Endpoint:     metadata.Endpoint,
+		RequestID:    metadata.RequestID,
+		ClientIPHash: metadata.ClientIPHash,
+		SDKName:      metadata.SDKName,
+		SDKVersion:   metadata.SDKVersion,
+		APIKeyID:     metadata.APIKeyID,
+	}
+}
@@ -0,0 +1,56 @@
+package event
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ebubekir/event-stream/internal/domain"
+	eventDomain "github.com/ebubekir/event-stream/internal/domain/event"
+)
+
+// ErrInvalidCursor is returned when a search cursor cannot be decoded or
+// belongs to a search in the other sort order
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// searchCursor is the decoded form of the opaque cursor handed to clients
+type searchCursor struct {
+	Order string    `json:"o"`
+	Date  time.Time `json:"d"`
+	ID    string    `json:"i"`
+}
+
+// encodeCursor returns the cursor continuing a search after event
+func encodeCursor(order eventDomain.SortOrder, event *domain.Event) (string, error) {
+	date, err := event.OccurredAt()
+	if err != nil {
+		return "", fmt.Errorf("failed to build cursor for event %s: %w", event.ID, err)
+	}
+
+	data, err := json.Marshal(searchCursor{Order: string(order), Date: date, ID: event.ID})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodeCursor returns the position encoded in cursor, checking that it was
+// issued for a search in the same order
+func decodeCursor(cursor string, order eventDomain.SortOrder) (*eventDomain.EventCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var decoded searchCursor
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded.ID == "" {
+		return nil, ErrInvalidCursor
+	}
+	if decoded.Order != string(order) {
+		return nil, fmt.Errorf("%w: it was issued for %s order", ErrInvalidCursor, decoded.Order)
+	}
+
+	return &eventDomain.EventCursor{Date: decoded.Date, ID: decoded.ID}, nil
+}
@@ -0,0 +1,61 @@
+package event
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ebubekir/event-stream/internal/domain"
+	eventDomain "github.com/ebubekir/event-stream/internal/domain/event"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	for _, order := range []eventDomain.SortOrder{eventDomain.SortAscending, eventDomain.SortDescending} {
+		t.Run(string(order), func(t *testing.T) {
+			cursor, err := encodeCursor(order, &domain.Event{ID: "evt-1", Date: "2024-01-15T10:00:00+03:00"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := decodeCursor(cursor, order)
+			if err != nil {
+				t.Fatalf("decodeCursor() error = %v", err)
+			}
+			if want := time.Date(2024, 1, 15, 7, 0, 0, 0, time.UTC); got.ID != "evt-1" || !got.Date.Equal(want) {
+				t.Errorf("decodeCursor() = %+v, want evt-1 at %v", got, want)
+			}
+		})
+	}
+}
+
+func TestEncodeCursorInvalidDate(t *testing.T) {
+	if _, err := encodeCursor(eventDomain.SortAscending, &domain.Event{ID: "evt-1", Date: "yesterday"}); err == nil {
+		t.Error("encodeCursor() error = nil, want an error for an unparseable date")
+	}
+}
+
+func TestDecodeCursorInvalid(t *testing.T) {
+	descending, err := encodeCursor(eventDomain.SortDescending, &domain.Event{ID: "evt-1", Date: "2024-01-15T10:00:00Z"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{name: "other order", cursor: descending},
+		{name: "not base64", cursor: "not a cursor!"},
+		{name: "not json", cursor: base64.RawURLEncoding.EncodeToString([]byte("evt-1"))},
+		{name: "no id", cursor: base64.RawURLEncoding.EncodeToString([]byte(`{"o":"asc","d":"2024-01-15T10:00:00Z"}`))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := decodeCursor(tt.cursor, eventDomain.SortAscending); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("decodeCursor() error = %v, want ErrInvalidCursor", err)
+			}
+		})
+	}
+}
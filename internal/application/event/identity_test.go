@@ -0,0 +1,121 @@
+package event
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ebubekir/event-stream/internal/domain"
+	eventRepo "github.com/ebubekir/event-stream/internal/domain/event"
+)
+
+// identityStore records the links it is given, keeping the latest per
+// user_pseudo_id like the real stores
+type identityStore struct {
+	links  []domain.IdentityLink
+	latest map[string]domain.IdentityLink
+}
+
+func (s *identityStore) Link(ctx context.Context, links []domain.IdentityLink) error {
+	for _, link := range links {
+		s.links = append(s.links, link)
+		if current, ok := s.latest[link.UserPseudoID]; !ok || link.LinkedAt.After(current.LinkedAt) {
+			s.latest[link.UserPseudoID] = link
+		}
+	}
+	return nil
+}
+
+var _ eventRepo.IdentityRepository = (*identityStore)(nil)
+
+func TestLinkIdentities(t *testing.T) {
+	received := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	linked := func(pseudoID, userID, date string) *domain.Event {
+		return &domain.Event{UserPseudoID: pseudoID, UserID: userID, Date: date, Ingestion: domain.IngestionMetadata{ReceivedAt: received}}
+	}
+
+	tests := []struct {
+		name     string
+		existing []domain.IdentityLink
+		events   []*domain.Event
+		want     map[string]string
+		wantAt   map[string]time.Time
+	}{
+		{
+			name: "latest event in a batch wins",
+			events: []*domain.Event{
+				linked("p1", "u1", "2024-01-01T10:00:00Z"),
+				linked("p1", "u2", "2024-01-01T12:00:00Z"),
+				linked("p2", "u3", "2024-01-01T10:00:00Z"),
+				linked("p1", "u1", "2024-01-01T11:00:00Z"),
+			},
+			want:   map[string]string{"p1": "u2", "p2": "u3"},
+			wantAt: map[string]time.Time{"p1": time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)},
+		},
+		{
+			name: "later in batch order wins a tie",
+			events: []*domain.Event{
+				linked("p1", "u1", "2024-01-01T10:00:00Z"),
+				linked("p1", "u2", "2024-01-01T10:00:00Z"),
+			},
+			want: map[string]string{"p1": "u2"},
+		},
+		{
+			name:     "older events keep a newer link",
+			existing: []domain.IdentityLink{{UserPseudoID: "p1", UserID: "live", LinkedAt: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)}},
+			events:   []*domain.Event{linked("p1", "backfill", "2023-05-01T00:00:00Z")},
+			want:     map[string]string{"p1": "live"},
+		},
+		{
+			name:   "undated events link when received",
+			events: []*domain.Event{linked("p1", "u1", "yesterday")},
+			want:   map[string]string{"p1": "u1"},
+			wantAt: map[string]time.Time{"p1": received},
+		},
+		{
+			name: "anonymous and logged-out events are skipped",
+			events: []*domain.Event{
+				linked("p1", "u1", "2024-01-01T10:00:00Z"),
+				linked("p1", "", "2024-01-01T11:00:00Z"),
+				linked("", "u2", "2024-01-01T11:00:00Z"),
+			},
+			want: map[string]string{"p1": "u1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &identityStore{latest: make(map[string]domain.IdentityLink)}
+			if err := store.Link(context.Background(), tt.existing); err != nil {
+				t.Fatal(err)
+			}
+			store.links = nil
+			s := &EventService{identityRepo: store}
+
+			if err := s.linkIdentities(context.Background(), tt.events); err != nil {
+				t.Fatalf("linkIdentities() error = %v", err)
+			}
+
+			seen := make(map[string]bool)
+			for _, link := range store.links {
+				if seen[link.UserPseudoID] {
+					t.Errorf("%s linked more than once in a batch", link.UserPseudoID)
+				}
+				seen[link.UserPseudoID] = true
+			}
+			if len(store.latest) != len(tt.want) {
+				t.Fatalf("linked %d pseudo IDs, want %d", len(store.latest), len(tt.want))
+			}
+			for pseudoID, userID := range tt.want {
+				if got := store.latest[pseudoID].UserID; got != userID {
+					t.Errorf("%s linked to %s, want %s", pseudoID, got, userID)
+				}
+			}
+			for pseudoID, at := range tt.wantAt {
+				if got := store.latest[pseudoID].LinkedAt; !got.Equal(at) {
+					t.Errorf("%s linked at %v, want %v", pseudoID, got, at)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package event
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ebubekir/event-stream/internal/domain"
+)
+
+// ErrInvalidEvent is returned by CreateEventCommand.Validate
+var ErrInvalidEvent = errors.New("invalid event")
+
+// maxGroupLimit bounds GetMetricsQuery.GroupLimit
+const maxGroupLimit = 1000
+
+// Validate checks the fields every transport requires of a new event. The
+// rules match the binding tags of the HTTP request, so events are accepted
+// alike over HTTP, gRPC and Kafka.
+func (c *CreateEventCommand) Validate() error {
+	if c.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidEvent)
+	}
+
+	switch c.ChannelType {
+	case domain.ChannelTypeWeb, domain.ChannelTypeMobile, domain.ChannelTypeDesktop,
+		domain.ChannelTypeTV, domain.ChannelTypeConsole, domain.ChannelTypeOther:
+		return nil
+	case "":
+		return fmt.Errorf("%w: channel_type is required", ErrInvalidEvent)
+	default:
+		return fmt.Errorf("%w: unknown channel_type %q", ErrInvalidEvent, c.ChannelType)
+	}
+}
+
+// MetricsParams holds the metrics options as clients send them, with times,
+// timezone and filters still in text form
+type MetricsParams struct {
+	EventNames      []string
+	From            string // RFC3339, or a local date or date-time in Timezone
+	To              string // RFC3339, or a local date (inclusive) or date-time in Timezone; empty is now
+	Timezone        string // IANA name; empty is UTC
+	GroupBy         string
+	GroupLimit      int
+	Fill            string
+	Measures        string
+	ResolveIdentity bool
+	IngestEndpoint  string
+	SDKName         string
+	SDKVersion      string
+	APIKeyID        string
+	Filters         []string // <field>:<operator>[:<value>]
+}
+
+// ToQuery parses the params into a metrics query
+func (p *MetricsParams) ToQuery() (*GetMetricsQuery, error) {
+	if p.GroupLimit < 0 || p.GroupLimit > maxGroupLimit {
+		return nil, fmt.Errorf("group_limit must be between 1 and %d", maxGroupLimit)
+	}
+
+	query := &GetMetricsQuery{
+		EventNames:      p.EventNames,
+		GroupBy:         p.GroupBy,
+		GroupLimit:      p.GroupLimit,
+		Fill:            p.Fill,
+		Measures:        p.Measures,
+		ResolveIdentity: p.ResolveIdentity,
+		IngestEndpoint:  p.IngestEndpoint,
+		SDKName:         p.SDKName,
+		SDKVersion:      p.SDKVersion,
+		APIKeyID:        p.APIKeyID,
+	}
+
+	location, err := LoadTimezone(p.Timezone)
+	if err != nil {
+		return nil, err
+	}
+	query.Location = location
+
+	query.Filters, err = ParseFilters(p.Filters)
+	if err != nil {
+		return nil, err
+	}
+
+	query.From, query.To, err = ParseTimeRange(p.From, p.To, location)
+	if err != nil {
+		return nil, err
+	}
+
+	return query, nil
+}
+
+// LoadTimezone loads an IANA time zone, defaulting to UTC. The server's
+// local zone is not accepted, as results would depend on where it runs.
+func LoadTimezone(name string) (*time.Location, error) {
+	if name == "Local" {
+		return nil, errors.New("timezone must be an IANA name such as Europe/Istanbul")
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone %q", name)
+	}
+	return location, nil
+}
+
+// ParseTimeRange parses the from and to of a query in location. An empty from
+// is left zero and an empty to is now.
+func ParseTimeRange(from, to string, location *time.Location) (time.Time, time.Time, error) {
+	var start, end time.Time
+	if from != "" {
+		t, err := ParseTime(from, location, false)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		start = t
+	}
+
+	if to != "" {
+		t, err := ParseTime(to, location, true)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		end = t
+	} else {
+		end = time.Now().In(location)
+	}
+
+	return start, end, nil
+}
+
+// Local time layouts accepted by ParseTime, besides RFC3339
+const (
+	localDateTimeLayout = "2006-01-02T15:04:05"
+	localDateLayout     = "2006-01-02"
+)
+
+// ParseTime parses an RFC3339 timestamp, or a date or date-time without
+// offset in location. A date is its first instant, or with endOfDay its
+// last, so that a to date includes the whole day.
+func ParseTime(value string, location *time.Location, endOfDay bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.In(location), nil
+	}
+	if t, err := time.ParseInLocation(localDateTimeLayout, value, location); err == nil {
+		return t, nil
+	}
+
+	t, err := time.ParseInLocation(localDateLayout, value, location)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is not an RFC3339 timestamp, local date-time or date", value)
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+	return t, nil
+}
+
+// ParseFilters parses filters of the form <field>:<operator>[:<value>]
+func ParseFilters(values []string) ([]FilterDTO, error) {
+	var filters []FilterDTO
+	for _, value := range values {
+		filter, err := ParseFilter(value)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter)
+	}
+	return filters, nil
+}
+
+// ParseFilter parses a filter of the form <field>:<operator>[:<value>], e.g.
+// "event_param.page_title:eq:Checkout". The in operator takes a
+// comma-separated list of values and exists takes none.
+func ParseFilter(s string) (FilterDTO, error) {
+	parts := strings.SplitN(s, ":", 3)
+	if len(parts) < 2 {
+		return FilterDTO{}, fmt.Errorf("filter %q is not of the form <field>:<operator>[:<value>]", s)
+	}
+
+	filter := FilterDTO{Field: parts[0], Operator: parts[1]}
+	if len(parts) == 3 {
+		if filter.Operator == "in" {
+			filter.Values = strings.Split(parts[2], ",")
+		} else {
+			filter.Values = []string{parts[2]}
+		}
+	}
+	return filter, nil
+}
@@ -0,0 +1,141 @@
+package event
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ebubekir/event-stream/internal/domain"
+)
+
+func TestCreateEventCommandValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cmd     CreateEventCommand
+		wantErr bool
+	}{
+		{name: "valid", cmd: CreateEventCommand{Name: "page_view", ChannelType: domain.ChannelTypeWeb}},
+		{name: "other channel", cmd: CreateEventCommand{Name: "page_view", ChannelType: domain.ChannelTypeOther}},
+		{name: "missing name", cmd: CreateEventCommand{ChannelType: domain.ChannelTypeWeb}, wantErr: true},
+		{name: "missing channel", cmd: CreateEventCommand{Name: "page_view"}, wantErr: true},
+		{name: "unknown channel", cmd: CreateEventCommand{Name: "page_view", ChannelType: "fax"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cmd.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidEvent) {
+				t.Errorf("Validate() error = %v, want ErrInvalidEvent", err)
+			}
+		})
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	istanbul, err := time.LoadLocation("Europe/Istanbul")
+	if err != nil {
+		t.Skip("time zone database not available")
+	}
+
+	tests := []struct {
+		name     string
+		value    string
+		endOfDay bool
+		want     time.Time
+		wantErr  bool
+	}{
+		{
+			name:  "RFC3339 keeps its offset",
+			value: "2024-01-15T10:00:00Z",
+			want:  time.Date(2024, 1, 15, 13, 0, 0, 0, istanbul),
+		},
+		{
+			name:  "local date-time",
+			value: "2024-01-15T10:00:00",
+			want:  time.Date(2024, 1, 15, 10, 0, 0, 0, istanbul),
+		},
+		{
+			name:  "date starts the day",
+			value: "2024-01-15",
+			want:  time.Date(2024, 1, 15, 0, 0, 0, 0, istanbul),
+		},
+		{
+			name:     "date ends the day",
+			value:    "2024-01-15",
+			endOfDay: true,
+			want:     time.Date(2024, 1, 15, 23, 59, 59, int(time.Second-time.Nanosecond), istanbul),
+		},
+		{name: "not a time", value: "yesterday", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTime(tt.value, istanbul, tt.endOfDay)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTime() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseTime() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    FilterDTO
+		wantErr bool
+	}{
+		{value: "device.category:eq:mobile", want: FilterDTO{Field: "device.category", Operator: "eq", Values: []string{"mobile"}}},
+		{value: "channel_type:in:web,mobile", want: FilterDTO{Field: "channel_type", Operator: "in", Values: []string{"web", "mobile"}}},
+		{value: "event_param.url:contains:a:b,c", want: FilterDTO{Field: "event_param.url", Operator: "contains", Values: []string{"a:b,c"}}},
+		{value: "user_id:exists", want: FilterDTO{Field: "user_id", Operator: "exists"}},
+		{value: "user_id", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseFilter(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFilter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got.Field != tt.want.Field || got.Operator != tt.want.Operator || !slices.Equal(got.Values, tt.want.Values) {
+				t.Errorf("ParseFilter() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMetricsParamsToQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  MetricsParams
+		wantErr bool
+	}{
+		{name: "defaults", params: MetricsParams{}},
+		{name: "full", params: MetricsParams{From: "2024-01-01", To: "2024-01-31", Timezone: "UTC", GroupLimit: 1000, Filters: []string{"user_id:exists"}}},
+		{name: "negative group limit", params: MetricsParams{GroupLimit: -1}, wantErr: true},
+		{name: "group limit too high", params: MetricsParams{GroupLimit: 1001}, wantErr: true},
+		{name: "local timezone", params: MetricsParams{Timezone: "Local"}, wantErr: true},
+		{name: "unknown timezone", params: MetricsParams{Timezone: "Mars/Olympus"}, wantErr: true},
+		{name: "bad filter", params: MetricsParams{Filters: []string{"user_id"}}, wantErr: true},
+		{name: "bad from", params: MetricsParams{From: "yesterday"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := tt.params.ToQuery()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ToQuery() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && query.To.IsZero() {
+				t.Error("ToQuery() left To zero, want now by default")
+			}
+		})
+	}
+}
@@ -1,43 +1,130 @@
 package event
 
 import (
+	"fmt"
 	"time"
 
+	"github.com/ebubekir/event-stream/internal/domain"
 	eventDomain "github.com/ebubekir/event-stream/internal/domain/event"
 )
 
 // GetMetricsQuery represents the query for fetching event metrics
 type GetMetricsQuery struct {
-	EventName   string
-	From        time.Time
-	To          time.Time
-	Aggregation string // "channel", "daily", "hourly"
+	EventNames      []string // empty counts every event
+	From            time.Time
+	To              time.Time
+	Location        *time.Location // time zone of the time buckets; nil is UTC
+	GroupBy         string         // comma-separated dimensions, e.g. "daily,device.browser_name"
+	GroupLimit      int            // top groups to keep, the rest are counted as Other; 0 keeps all
+	Fill            string         // zero, null or none; empty fills with zeros when grouping by a single time bucket
+	Measures        string         // comma-separated measures, e.g. "avg:event_param.load_time_ms"
+	ResolveIdentity bool
+	IngestEndpoint  string
+	SDKName         string
+	SDKVersion      string
+	APIKeyID        string
+	Filters         []FilterDTO
+}
+
+// FilterDTO represents a field filter in application layer
+type FilterDTO struct {
+	Field    string // e.g. "event_param.page_title", "device.browser_name", "channel_type"
+	Operator string // eq, neq, in, contains, regex, gt, gte, lt, lte, exists
+	Values   []string
+}
+
+// ToFieldFilter converts and validates the filter, returning an error
+// wrapping domain/event.ErrInvalidFilter when it is not valid
+func (f FilterDTO) ToFieldFilter() (eventDomain.FieldFilter, error) {
+	field, err := eventDomain.ParseField(f.Field)
+	if err != nil {
+		return eventDomain.FieldFilter{}, err
+	}
+
+	filter := eventDomain.FieldFilter{
+		Field:    field,
+		Operator: eventDomain.FilterOperator(f.Operator),
+		Values:   f.Values,
+	}
+	if err := filter.Validate(); err != nil {
+		return eventDomain.FieldFilter{}, err
+	}
+	return filter, nil
 }
 
 // ToMetricsQuery converts application query to domain query
-func (q *GetMetricsQuery) ToMetricsQuery() *eventDomain.MetricsQuery {
-	return &eventDomain.MetricsQuery{
-		EventName:   q.EventName,
-		From:        q.From,
-		To:          q.To,
-		Aggregation: eventDomain.AggregationType(q.Aggregation),
+func (q *GetMetricsQuery) ToMetricsQuery() (*eventDomain.MetricsQuery, error) {
+	filters := make([]eventDomain.FieldFilter, len(q.Filters))
+	for i, f := range q.Filters {
+		filter, err := f.ToFieldFilter()
+		if err != nil {
+			return nil, err
+		}
+		filters[i] = filter
+	}
+
+	groupBy, err := eventDomain.ParseGroupBy(q.GroupBy)
+	if err != nil {
+		return nil, err
+	}
+
+	measures, err := eventDomain.ParseMeasures(q.Measures)
+	if err != nil {
+		return nil, err
 	}
+
+	fill, err := eventDomain.ResolveFill(eventDomain.FillMode(q.Fill), groupBy)
+	if err != nil {
+		return nil, err
+	}
+
+	location := q.Location
+	if location == nil {
+		location = time.UTC
+	}
+
+	return &eventDomain.MetricsQuery{
+		EventNames:      q.EventNames,
+		From:            q.From,
+		To:              q.To,
+		Location:        location,
+		GroupBy:         groupBy,
+		Fill:            fill,
+		Measures:        measures,
+		GroupLimit:      q.GroupLimit,
+		ResolveIdentity: q.ResolveIdentity,
+		Ingestion: eventDomain.IngestionFilter{
+			Endpoint:   q.IngestEndpoint,
+			SDKName:    q.SDKName,
+			SDKVersion: q.SDKVersion,
+			APIKeyID:   q.APIKeyID,
+		},
+		Filters: filters,
+	}, nil
 }
 
 // GroupedMetricDTO represents metrics for a specific group in application layer
 type GroupedMetricDTO struct {
-	GroupKey        string
+	GroupKey        string   // GroupKeys joined by "|"
+	GroupKeys       []string // one per GroupBy dimension
 	TotalCount      int64
 	UniqueUserCount int64
+	MeasureValues   []*float64 // one per measure, nil without values
+	Filled          bool       // a time bucket without events
 }
 
 // MetricsResultDTO represents the metrics result in application layer
 type MetricsResultDTO struct {
-	EventName       string
+	EventNames      []string
+	GroupBy         []string
+	Measures        []string
+	Fill            string
+	Timezone        string
 	From            time.Time
 	To              time.Time
 	TotalCount      int64
 	UniqueUserCount int64
+	MeasureValues   []*float64 // one per measure, nil without values
 	GroupedMetrics  []GroupedMetricDTO
 }
 
@@ -46,18 +133,476 @@ func FromMetricsResult(result *eventDomain.MetricsResult) *MetricsResultDTO {
 	groupedMetrics := make([]GroupedMetricDTO, len(result.GroupedMetrics))
 	for i, gm := range result.GroupedMetrics {
 		groupedMetrics[i] = GroupedMetricDTO{
-			GroupKey:        gm.GroupKey,
+			GroupKey:        gm.GroupKey(),
+			GroupKeys:       gm.GroupKeys,
 			TotalCount:      gm.TotalCount,
 			UniqueUserCount: gm.UniqueUserCount,
+			MeasureValues:   gm.MeasureValues,
+			Filled:          gm.Filled,
 		}
 	}
 
+	groupBy := make([]string, len(result.GroupBy))
+	for i, dimension := range result.GroupBy {
+		groupBy[i] = dimension.String()
+	}
+
+	measures := make([]string, len(result.Measures))
+	for i, measure := range result.Measures {
+		measures[i] = measure.String()
+	}
+
+	var timezone string
+	if result.Location != nil {
+		timezone = result.Location.String()
+	}
+
 	return &MetricsResultDTO{
-		EventName:       result.EventName,
+		EventNames:      result.EventNames,
+		GroupBy:         groupBy,
+		Measures:        measures,
+		Fill:            string(result.Fill),
+		Timezone:        timezone,
 		From:            result.From,
 		To:              result.To,
 		TotalCount:      result.TotalCount,
 		UniqueUserCount: result.UniqueUserCount,
+		MeasureValues:   result.MeasureValues,
 		GroupedMetrics:  groupedMetrics,
 	}
 }
+
+// GetEcommerceMetricsQuery represents the query for fetching ecommerce metrics
+type GetEcommerceMetricsQuery struct {
+	EventNames      []string // purchase events; empty counts domain/event.DefaultPurchaseEventNames
+	From            time.Time
+	To              time.Time
+	Location        *time.Location // time zone of the time buckets; nil is UTC
+	Interval        string         // time bucket aggregation, e.g. "daily"; empty returns totals only
+	ResolveIdentity bool
+	Filters         []FilterDTO
+}
+
+// ToEcommerceQuery converts application query to domain query
+func (q *GetEcommerceMetricsQuery) ToEcommerceQuery() (*eventDomain.EcommerceQuery, error) {
+	filters := make([]eventDomain.FieldFilter, len(q.Filters))
+	for i, f := range q.Filters {
+		filter, err := f.ToFieldFilter()
+		if err != nil {
+			return nil, err
+		}
+		filters[i] = filter
+	}
+
+	interval := eventDomain.AggregationType(q.Interval)
+	if interval != "" && !(eventDomain.Dimension{Aggregation: interval}).IsTime() {
+		return nil, fmt.Errorf("%w: %q is not a time bucket", eventDomain.ErrInvalidGroupBy, q.Interval)
+	}
+
+	eventNames := q.EventNames
+	if len(eventNames) == 0 {
+		eventNames = eventDomain.DefaultPurchaseEventNames
+	}
+
+	location := q.Location
+	if location == nil {
+		location = time.UTC
+	}
+
+	return &eventDomain.EcommerceQuery{
+		EventNames:      eventNames,
+		From:            q.From,
+		To:              q.To,
+		Location:        location,
+		Interval:        interval,
+		ResolveIdentity: q.ResolveIdentity,
+		Filters:         filters,
+	}, nil
+}
+
+// EcommerceMetricsDTO represents ecommerce metrics in application layer
+type EcommerceMetricsDTO struct {
+	Revenue           float64 // USD
+	Orders            int64
+	PurchasingUsers   int64
+	AverageOrderValue float64 // USD
+	UnitsSold         int64
+}
+
+// EcommerceBucketDTO represents the ecommerce metrics of a time bucket in application layer
+type EcommerceBucketDTO struct {
+	Bucket string
+	EcommerceMetricsDTO
+}
+
+// EcommerceResultDTO represents the ecommerce metrics result in application layer
+type EcommerceResultDTO struct {
+	EventNames []string
+	Interval   string
+	Timezone   string
+	From       time.Time
+	To         time.Time
+	Totals     EcommerceMetricsDTO
+	Buckets    []EcommerceBucketDTO
+}
+
+// FromEcommerceResult converts domain result to application DTO
+func FromEcommerceResult(result *eventDomain.EcommerceResult) *EcommerceResultDTO {
+	buckets := make([]EcommerceBucketDTO, len(result.Buckets))
+	for i, bucket := range result.Buckets {
+		buckets[i] = EcommerceBucketDTO{
+			Bucket:              bucket.Bucket,
+			EcommerceMetricsDTO: fromEcommerceMetrics(bucket.EcommerceMetrics),
+		}
+	}
+
+	var timezone string
+	if result.Location != nil {
+		timezone = result.Location.String()
+	}
+
+	return &EcommerceResultDTO{
+		EventNames: result.EventNames,
+		Interval:   string(result.Interval),
+		Timezone:   timezone,
+		From:       result.From,
+		To:         result.To,
+		Totals:     fromEcommerceMetrics(result.Totals),
+		Buckets:    buckets,
+	}
+}
+
+// fromEcommerceMetrics converts domain metrics, deriving the average order value
+func fromEcommerceMetrics(metrics eventDomain.EcommerceMetrics) EcommerceMetricsDTO {
+	return EcommerceMetricsDTO{
+		Revenue:           metrics.Revenue,
+		Orders:            metrics.Orders,
+		PurchasingUsers:   metrics.PurchasingUsers,
+		AverageOrderValue: metrics.AverageOrderValue(),
+		UnitsSold:         metrics.UnitsSold,
+	}
+}
+
+// Top items defaults
+const (
+	DefaultTopItemsLimit = 10
+	MaxTopItemsLimit     = 100
+)
+
+// GetTopItemsQuery represents the query for ranking the items of purchase events
+type GetTopItemsQuery struct {
+	GetEcommerceMetricsQuery
+	By     string // item (default), brand or variant
+	SortBy string // revenue (default) or quantity
+	Limit  int    // 0 is DefaultTopItemsLimit
+}
+
+// ToTopItemsQuery converts application query to domain query
+func (q *GetTopItemsQuery) ToTopItemsQuery() (*eventDomain.TopItemsQuery, error) {
+	ecommerceQuery, err := q.ToEcommerceQuery()
+	if err != nil {
+		return nil, err
+	}
+
+	by := eventDomain.ItemDimension(q.By)
+	switch by {
+	case "":
+		by = eventDomain.ItemDimensionItem
+	case eventDomain.ItemDimensionItem, eventDomain.ItemDimensionBrand, eventDomain.ItemDimensionVariant:
+	default:
+		return nil, fmt.Errorf("%w: cannot rank items by %q", eventDomain.ErrInvalidGroupBy, q.By)
+	}
+
+	sortBy := eventDomain.ItemSort(q.SortBy)
+	switch sortBy {
+	case "":
+		sortBy = eventDomain.ItemSortRevenue
+	case eventDomain.ItemSortRevenue, eventDomain.ItemSortQuantity:
+	default:
+		return nil, fmt.Errorf("%w: cannot sort items by %q", eventDomain.ErrInvalidGroupBy, q.SortBy)
+	}
+
+	limit := q.Limit
+	if limit <= 0 {
+		limit = DefaultTopItemsLimit
+	}
+	limit = min(limit, MaxTopItemsLimit)
+
+	return &eventDomain.TopItemsQuery{
+		EcommerceQuery: *ecommerceQuery,
+		By:             by,
+		SortBy:         sortBy,
+		Limit:          limit,
+	}, nil
+}
+
+// ItemMetricsDTO represents the sales of an item, brand or variant in application layer
+type ItemMetricsDTO struct {
+	Key      string
+	Name     string
+	Revenue  float64
+	Quantity int64
+	Orders   int64
+	Series   []ItemBucketDTO
+}
+
+// ItemBucketDTO represents the sales of an item in a time bucket in application layer
+type ItemBucketDTO struct {
+	Bucket   string
+	Revenue  float64
+	Quantity int64
+}
+
+// TopItemsResultDTO represents the top items result in application layer
+type TopItemsResultDTO struct {
+	EventNames []string
+	By         string
+	SortBy     string
+	Interval   string
+	Timezone   string
+	From       time.Time
+	To         time.Time
+	Items      []ItemMetricsDTO
+}
+
+// FromTopItemsResult converts domain result to application DTO
+func FromTopItemsResult(result *eventDomain.TopItemsResult) *TopItemsResultDTO {
+	items := make([]ItemMetricsDTO, len(result.Items))
+	for i, item := range result.Items {
+		series := make([]ItemBucketDTO, len(item.Series))
+		for j, bucket := range item.Series {
+			series[j] = ItemBucketDTO{
+				Bucket:   bucket.Bucket,
+				Revenue:  bucket.Revenue,
+				Quantity: bucket.Quantity,
+			}
+		}
+		items[i] = ItemMetricsDTO{
+			Key:      item.Key,
+			Name:     item.Name,
+			Revenue:  item.Revenue,
+			Quantity: item.Quantity,
+			Orders:   item.Orders,
+			Series:   series,
+		}
+	}
+
+	var timezone string
+	if result.Location != nil {
+		timezone = result.Location.String()
+	}
+
+	return &TopItemsResultDTO{
+		EventNames: result.EventNames,
+		By:         string(result.By),
+		SortBy:     string(result.SortBy),
+		Interval:   string(result.Interval),
+		Timezone:   timezone,
+		From:       result.From,
+		To:         result.To,
+		Items:      items,
+	}
+}
+
+// Search page sizes
+const (
+	DefaultSearchLimit = 100
+	MaxSearchLimit     = 1000
+)
+
+// SearchEventsQuery represents the query for searching stored events.
+// Empty filters match any value.
+type SearchEventsQuery struct {
+	EventNames      []string
+	From            time.Time
+	To              time.Time
+	ChannelType     string
+	UserID          string
+	UserPseudoID    string
+	DeviceCategory  string
+	OperatingSystem string
+	BrowserName     string
+	Language        string
+	AppID           string
+	AppVersion      string
+	ParamKey        string
+	ParamValue      string
+	Order           string // "asc" or "desc"
+	Cursor          string // NextCursor of the previous page
+	Limit           int
+}
+
+// ToSearchQuery converts application query to domain query, without the cursor
+func (q *SearchEventsQuery) ToSearchQuery() *eventDomain.EventSearchQuery {
+	limit := q.Limit
+	if limit <= 0 {
+		limit = DefaultSearchLimit
+	}
+	limit = min(limit, MaxSearchLimit)
+
+	order := eventDomain.SortDescending
+	if q.Order == string(eventDomain.SortAscending) {
+		order = eventDomain.SortAscending
+	}
+
+	return &eventDomain.EventSearchQuery{
+		Filter: eventDomain.EventFilter{
+			From:            q.From,
+			To:              q.To,
+			EventNames:      q.EventNames,
+			ChannelType:     q.ChannelType,
+			UserID:          q.UserID,
+			UserPseudoID:    q.UserPseudoID,
+			AppID:           q.AppID,
+			AppVersion:      q.AppVersion,
+			DeviceCategory:  q.DeviceCategory,
+			OperatingSystem: q.OperatingSystem,
+			BrowserName:     q.BrowserName,
+			Language:        q.Language,
+			ParamKey:        q.ParamKey,
+			ParamValue:      q.ParamValue,
+		},
+		Order: order,
+		Limit: limit,
+	}
+}
+
+// User ID types a timeline can be looked up by
+const (
+	UserIDTypeUserID       = "user_id"
+	UserIDTypeUserPseudoID = "user_pseudo_id"
+)
+
+// UserTimelineQuery represents the query for a user's events
+type UserTimelineQuery struct {
+	UserID     string
+	IDType     string // UserIDTypeUserID or UserIDTypeUserPseudoID
+	EventNames []string
+	From       time.Time
+	To         time.Time
+	Order      string // "asc" (default) or "desc"
+	Cursor     string
+	Limit      int
+}
+
+// ToSearchEventsQuery converts the timeline to a search on the user's ID,
+// oldest first unless another order is asked for
+func (q *UserTimelineQuery) ToSearchEventsQuery() *SearchEventsQuery {
+	query := &SearchEventsQuery{
+		EventNames: q.EventNames,
+		From:       q.From,
+		To:         q.To,
+		Order:      q.Order,
+		Cursor:     q.Cursor,
+		Limit:      q.Limit,
+	}
+	if query.Order == "" {
+		query.Order = string(eventDomain.SortAscending)
+	}
+
+	if q.IDType == UserIDTypeUserPseudoID {
+		query.UserPseudoID = q.UserID
+	} else {
+		query.UserID = q.UserID
+	}
+	return query
+}
+
+// EventPageDTO represents a page of searched events in application layer
+type EventPageDTO struct {
+	Events []*EventDTO
+	// NextCursor continues the search; empty on the last page
+	NextCursor string
+}
+
+// EventDTO represents a stored event in application layer
+type EventDTO struct {
+	ID                string
+	Name              string
+	ChannelType       string
+	Timestamp         int64
+	PreviousTimestamp int64
+	Date              string
+	EventParams       []ParamDTO
+	UserID            string
+	UserPseudoID      string
+	UserParams        []ParamDTO
+	Device            DeviceDTO
+	Geo               GeoDTO
+	AppInfo           AppInfoDTO
+	Items             []ItemDTO
+	Ingestion         IngestionMetadataDTO
+}
+
+// FromEvent converts a domain event to application DTO
+func FromEvent(e *domain.Event) *EventDTO {
+	return &EventDTO{
+		ID:                e.ID,
+		Name:              e.Name,
+		ChannelType:       string(e.ChannelType),
+		Timestamp:         e.Timestamp,
+		PreviousTimestamp: e.PreviousTimestamp,
+		Date:              e.Date,
+		EventParams:       fromParams(e.EventParams),
+		UserID:            e.UserID,
+		UserPseudoID:      e.UserPseudoID,
+		UserParams:        fromParams(e.UserParams),
+		Device:            fromDevice(e.Device),
+		Geo:               fromGeo(e.Geo),
+		AppInfo:           fromAppInfo(e.AppInfo),
+		Items:             fromItems(e.Items),
+		Ingestion:         fromIngestionMetadata(e.Ingestion),
+	}
+}
+
+// ListQuarantineQuery represents the query for listing quarantined events
+type ListQuarantineQuery struct {
+	Limit  int
+	Offset int
+}
+
+// QuarantinedEventDTO represents a quarantined event in application layer
+type QuarantinedEventDTO struct {
+	ID            string
+	Name          string
+	ChannelType   string
+	Date          string
+	UserID        string
+	UserPseudoID  string
+	Reason        string
+	QuarantinedAt time.Time
+}
+
+// FromQuarantinedEvents converts domain quarantined events to application DTOs
+func FromQuarantinedEvents(events []*domain.QuarantinedEvent) []QuarantinedEventDTO {
+	dtos := make([]QuarantinedEventDTO, len(events))
+	for i, q := range events {
+		dtos[i] = QuarantinedEventDTO{
+			ID:            q.Event.ID,
+			Name:          q.Event.Name,
+			ChannelType:   string(q.Event.ChannelType),
+			Date:          q.Event.Date,
+			UserID:        q.Event.UserID,
+			UserPseudoID:  q.Event.UserPseudoID,
+			Reason:        q.Reason,
+			QuarantinedAt: q.QuarantinedAt,
+		}
+	}
+	return dtos
+}
+
+// TailEventsQuery selects which newly persisted events a live tail receives.
+// Empty fields match any value.
+type TailEventsQuery struct {
+	EventName    string
+	UserPseudoID string
+	ChannelType  string
+	AppID        string
+}
+
+func (q *TailEventsQuery) matches(event *domain.Event) bool {
+	return (q.EventName == "" || q.EventName == event.Name) &&
+		(q.UserPseudoID == "" || q.UserPseudoID == event.UserPseudoID) &&
+		(q.ChannelType == "" || q.ChannelType == string(event.ChannelType)) &&
+		(q.AppID == "" || q.AppID == event.AppInfo.ID)
+}
@@ -2,72 +2,553 @@ package event
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
+	"go.uber.org/zap"
 
 	"github.com/ebubekir/event-stream/internal/domain"
 	eventRepo "github.com/ebubekir/event-stream/internal/domain/event"
+	"github.com/ebubekir/event-stream/pkg/logger"
 )
 
+// ErrQuarantineDisabled is returned by quarantine use cases when no quarantine repository is configured
+var ErrQuarantineDisabled = errors.New("event quarantine is not configured")
+
 // EventService handles event-related use cases
 type EventService struct {
-	repo          eventRepo.EventRepository
-	metricsReader eventRepo.EventMetricsReader
+	repo           eventRepo.EventRepository
+	metricsReader  eventRepo.EventMetricsReader
+	identityRepo   eventRepo.IdentityRepository
+	quarantineRepo eventRepo.QuarantineRepository
+	publisher      eventRepo.EventPublisher
+	sinks          []eventRepo.EventSink
+	tail           *tailHub
+	window         eventRepo.AcceptanceWindow
+	clientIPSalt   string
+}
+
+// Option configures optional EventService behaviour
+type Option func(*EventService)
+
+// WithAcceptanceWindow checks event dates against the given window.
+// Out-of-window events are quarantined in quarantineRepo when the window's action is quarantine.
+func WithAcceptanceWindow(window eventRepo.AcceptanceWindow, quarantineRepo eventRepo.QuarantineRepository) Option {
+	return func(s *EventService) {
+		s.window = window
+		s.quarantineRepo = quarantineRepo
+	}
+}
+
+// WithClientIPSalt sets the salt mixed into client IP hashes stored with each event
+func WithClientIPSalt(salt string) Option {
+	return func(s *EventService) {
+		s.clientIPSalt = salt
+	}
+}
+
+// WithPublisher notifies publisher about every event after it is persisted
+func WithPublisher(publisher eventRepo.EventPublisher) Option {
+	return func(s *EventService) {
+		s.publisher = publisher
+	}
 }
 
-// NewEventService creates a new EventService with the given repository and metrics reader
-func NewEventService(repo eventRepo.EventRepository, metricsReader eventRepo.EventMetricsReader) *EventService {
-	return &EventService{
+// WithSinks copies every persisted event to the given sinks
+func WithSinks(sinks ...eventRepo.EventSink) Option {
+	return func(s *EventService) {
+		s.sinks = append(s.sinks, sinks...)
+	}
+}
+
+// NewEventService creates a new EventService with the given repositories and metrics reader
+func NewEventService(repo eventRepo.EventRepository, metricsReader eventRepo.EventMetricsReader, identityRepo eventRepo.IdentityRepository, opts ...Option) *EventService {
+	s := &EventService{
 		repo:          repo,
 		metricsReader: metricsReader,
+		identityRepo:  identityRepo,
+		tail:          newTailHub(),
+		window:        eventRepo.AcceptanceWindow{Action: eventRepo.OutOfWindowAccept},
+	}
+
+	for _, opt := range opts {
+		opt(s)
 	}
+
+	return s
 }
 
 // CreateEvent handles the creation of a new event
-func (s *EventService) CreateEvent(ctx context.Context, cmd *CreateEventCommand) (string, error) {
-	// Generate unique ID
-	id := uuid.New().String()
+func (s *EventService) CreateEvent(ctx context.Context, cmd *CreateEventCommand) (*CreatedEventDTO, error) {
+	// Generate unique ID unless the caller supplied one
+	id := eventID(cmd)
 
 	// Convert command to domain entity
-	event := cmd.ToEvent(id)
+	event := s.newEvent(cmd, id)
+
+	// Check the event date against the acceptance window
+	accepted, quarantined, err := s.screenEvents([]*domain.Event{event})
+	if err != nil {
+		return nil, err
+	}
+
+	// Stitch identities before persisting so a retried request re-links them.
+	// Quarantined events are linked only once they are released.
+	if err := s.linkIdentities(ctx, accepted); err != nil {
+		return nil, err
+	}
+
+	if err := s.quarantine(ctx, quarantined); err != nil {
+		return nil, err
+	}
 
 	// Persist via repository (PostgreSQL or ClickHouse - service doesn't know)
-	if err := s.repo.Save(ctx, event); err != nil {
-		return "", fmt.Errorf("failed to save event: %w", err)
+	if len(accepted) > 0 {
+		if err := s.repo.Save(ctx, event); err != nil {
+			return nil, fmt.Errorf("failed to save event: %w", err)
+		}
+		s.publish(ctx, accepted)
 	}
 
-	return id, nil
+	return &CreatedEventDTO{ID: id, Quarantined: len(quarantined) > 0}, nil
 }
 
 // CreateEvents handles batch creation of events
-func (s *EventService) CreateEvents(ctx context.Context, cmds []*CreateEventCommand) ([]string, error) {
+func (s *EventService) CreateEvents(ctx context.Context, cmds []*CreateEventCommand) (*CreatedEventsDTO, error) {
 	if len(cmds) == 0 {
-		return []string{}, nil
+		return &CreatedEventsDTO{IDs: []string{}}, nil
 	}
 
 	ids := make([]string, len(cmds))
 	events := make([]*domain.Event, len(cmds))
 
 	for i, cmd := range cmds {
-		id := uuid.New().String()
+		id := eventID(cmd)
 		ids[i] = id
-		events[i] = cmd.ToEvent(id)
+		events[i] = s.newEvent(cmd, id)
+	}
+
+	accepted, quarantined, err := s.screenEvents(events)
+	if err != nil {
+		return nil, err
 	}
 
-	if err := s.repo.SaveBatch(ctx, events); err != nil {
+	if err := s.linkIdentities(ctx, accepted); err != nil {
+		return nil, err
+	}
+
+	if err := s.quarantine(ctx, quarantined); err != nil {
+		return nil, err
+	}
+
+	// Only the events stored now are published, so a redelivered batch
+	// does not notify webhooks, sinks or live tails again
+	stored, err := s.repo.SaveBatch(ctx, accepted)
+	if err != nil {
 		return nil, fmt.Errorf("failed to save events batch: %w", err)
 	}
+	s.publish(ctx, stored)
+
+	result := &CreatedEventsDTO{IDs: ids}
+	for _, q := range quarantined {
+		result.QuarantinedIDs = append(result.QuarantinedIDs, q.Event.ID)
+	}
+	return result, nil
+}
+
+// ImportEvents stores historical events as they are, e.g. from a bulk import.
+// Unlike CreateEvents it skips the acceptance window and does not notify sinks,
+// webhooks or live tails; identities are still linked.
+func (s *EventService) ImportEvents(ctx context.Context, cmds []*CreateEventCommand) ([]string, error) {
+	if len(cmds) == 0 {
+		return []string{}, nil
+	}
+
+	ids := make([]string, len(cmds))
+	events := make([]*domain.Event, len(cmds))
+
+	for i, cmd := range cmds {
+		id := eventID(cmd)
+		ids[i] = id
+		events[i] = s.newEvent(cmd, id)
+	}
+
+	if err := s.linkIdentities(ctx, events); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repo.SaveBatch(ctx, events); err != nil {
+		return nil, fmt.Errorf("failed to import events batch: %w", err)
+	}
 
 	return ids, nil
 }
 
+// ReprocessEvents runs stored events through the current ingestion pipeline
+// again and stores the result, e.g. to apply a fixed processing rule to a
+// replayed window. Like ImportEvents it keeps the events' IDs and dates and
+// does not notify sinks, webhooks or live tails.
+func (s *EventService) ReprocessEvents(ctx context.Context, events []*domain.Event) error {
+	cmds := make([]*CreateEventCommand, len(events))
+	for i, event := range events {
+		cmds[i] = NewCreateEventCommand(event)
+	}
+
+	_, err := s.ImportEvents(ctx, cmds)
+	return err
+}
+
+// Identify explicitly links an anonymous user_pseudo_id to a user_id
+func (s *EventService) Identify(ctx context.Context, cmd *IdentifyCommand) error {
+	link := domain.IdentityLink{
+		UserPseudoID: cmd.UserPseudoID,
+		UserID:       cmd.UserID,
+		LinkedAt:     time.Now().UTC().Truncate(time.Millisecond),
+	}
+
+	if err := s.identityRepo.Link(ctx, []domain.IdentityLink{link}); err != nil {
+		return fmt.Errorf("failed to link identity: %w", err)
+	}
+
+	return nil
+}
+
+// GetEvent returns the stored event with the given ID
+func (s *EventService) GetEvent(ctx context.Context, id string) (*EventDTO, error) {
+	event, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return FromEvent(event), nil
+}
+
+// SearchEvents returns a page of stored events matching the query, newest
+// first unless the query asks for ascending order
+func (s *EventService) SearchEvents(ctx context.Context, query *SearchEventsQuery) (*EventPageDTO, error) {
+	search := query.ToSearchQuery()
+	if query.Cursor != "" {
+		after, err := decodeCursor(query.Cursor, search.Order)
+		if err != nil {
+			return nil, err
+		}
+		search.After = after
+	}
+
+	// One extra event tells whether another page follows
+	limit := search.Limit
+	search.Limit++
+	events, err := s.repo.Search(ctx, search)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search events: %w", err)
+	}
+
+	page := &EventPageDTO{Events: make([]*EventDTO, 0, min(len(events), limit))}
+	if len(events) > limit {
+		events = events[:limit]
+		if page.NextCursor, err = encodeCursor(search.Order, events[limit-1]); err != nil {
+			return nil, err
+		}
+	}
+	for _, event := range events {
+		page.Events = append(page.Events, FromEvent(event))
+	}
+
+	return page, nil
+}
+
+// GetUserTimeline returns a page of a user's events, oldest first by default
+func (s *EventService) GetUserTimeline(ctx context.Context, query *UserTimelineQuery) (*EventPageDTO, error) {
+	return s.SearchEvents(ctx, query.ToSearchEventsQuery())
+}
+
 // GetMetrics retrieves aggregated metrics for events
 func (s *EventService) GetMetrics(ctx context.Context, query *GetMetricsQuery) (*MetricsResultDTO, error) {
-	result, err := s.metricsReader.GetMetrics(ctx, query.ToMetricsQuery())
+	metricsQuery, err := query.ToMetricsQuery()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.metricsReader.GetMetrics(ctx, metricsQuery)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get metrics: %w", err)
 	}
 
 	return FromMetricsResult(result), nil
 }
+
+// GetEcommerceMetrics retrieves revenue, orders, purchasing users and units sold of purchase events
+func (s *EventService) GetEcommerceMetrics(ctx context.Context, query *GetEcommerceMetricsQuery) (*EcommerceResultDTO, error) {
+	ecommerceQuery, err := query.ToEcommerceQuery()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.metricsReader.GetEcommerceMetrics(ctx, ecommerceQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ecommerce metrics: %w", err)
+	}
+
+	return FromEcommerceResult(result), nil
+}
+
+// GetTopItems ranks the items, brands or variants of purchase events by revenue or quantity
+func (s *EventService) GetTopItems(ctx context.Context, query *GetTopItemsQuery) (*TopItemsResultDTO, error) {
+	topItemsQuery, err := query.ToTopItemsQuery()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.metricsReader.GetTopItems(ctx, topItemsQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get top items: %w", err)
+	}
+
+	return FromTopItemsResult(result), nil
+}
+
+// ListQuarantined returns events held in quarantine for review
+func (s *EventService) ListQuarantined(ctx context.Context, query *ListQuarantineQuery) ([]QuarantinedEventDTO, error) {
+	if s.quarantineRepo == nil {
+		return nil, ErrQuarantineDisabled
+	}
+
+	events, err := s.quarantineRepo.List(ctx, query.Limit, query.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list quarantined events: %w", err)
+	}
+
+	return FromQuarantinedEvents(events), nil
+}
+
+// ReleaseQuarantined moves quarantined events into the events table and returns how many were released
+func (s *EventService) ReleaseQuarantined(ctx context.Context, cmd *ReleaseQuarantineCommand) (int, error) {
+	if s.quarantineRepo == nil {
+		return 0, ErrQuarantineDisabled
+	}
+
+	quarantined, err := s.quarantineRepo.FindByIDs(ctx, cmd.IDs)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find quarantined events: %w", err)
+	}
+
+	if len(quarantined) == 0 {
+		return 0, nil
+	}
+
+	now := time.Now().UTC()
+	ids := make([]string, len(quarantined))
+	events := make([]*domain.Event, len(quarantined))
+	for i, q := range quarantined {
+		if cmd.Clamp {
+			if date, err := q.Event.OccurredAt(); err == nil {
+				q.Event.Date = s.window.Clamp(date, now).Format(time.RFC3339)
+			}
+		}
+		ids[i] = q.Event.ID
+		events[i] = q.Event
+	}
+
+	if err := s.linkIdentities(ctx, events); err != nil {
+		return 0, err
+	}
+
+	stored, err := s.repo.SaveBatch(ctx, events)
+	if err != nil {
+		return 0, fmt.Errorf("failed to save released events: %w", err)
+	}
+
+	s.publish(ctx, stored)
+
+	if err := s.quarantineRepo.Delete(ctx, ids); err != nil {
+		return 0, fmt.Errorf("failed to remove released events from quarantine: %w", err)
+	}
+
+	return len(events), nil
+}
+
+// DiscardQuarantined permanently removes events from quarantine
+func (s *EventService) DiscardQuarantined(ctx context.Context, ids []string) error {
+	if s.quarantineRepo == nil {
+		return ErrQuarantineDisabled
+	}
+
+	if err := s.quarantineRepo.Delete(ctx, ids); err != nil {
+		return fmt.Errorf("failed to discard quarantined events: %w", err)
+	}
+
+	return nil
+}
+
+// TailEvents subscribes to events persisted from now on that match query.
+// Callers must Close the subscription when done.
+func (s *EventService) TailEvents(query *TailEventsQuery) *TailSubscription {
+	return s.tail.subscribe(*query)
+}
+
+// CloseTails ends all live tail subscriptions, e.g. before shutting down
+func (s *EventService) CloseTails() {
+	s.tail.close()
+}
+
+// publish fans persisted events out to the configured sinks, publisher and live tails.
+// The events are already stored, so sink failures are logged rather than
+// returned; failing the request would only make clients retry and duplicate them.
+func (s *EventService) publish(ctx context.Context, events []*domain.Event) {
+	if len(events) == 0 {
+		return
+	}
+
+	for _, sink := range s.sinks {
+		if err := sink.Write(ctx, events); err != nil {
+			logger.Error("failed to write events to sink", zap.Int("events", len(events)), zap.Error(err))
+		}
+	}
+
+	if s.publisher != nil {
+		s.publisher.Publish(ctx, events)
+	}
+
+	s.tail.broadcast(events)
+}
+
+// eventID returns the caller-supplied ID or a new random one
+func eventID(cmd *CreateEventCommand) string {
+	if cmd.ID != "" {
+		return cmd.ID
+	}
+	return uuid.New().String()
+}
+
+// newEvent converts a command to a domain event and completes its server-side ingestion metadata
+func (s *EventService) newEvent(cmd *CreateEventCommand, id string) *domain.Event {
+	event := cmd.ToEvent(id)
+
+	if event.Ingestion.ReceivedAt.IsZero() {
+		event.Ingestion.ReceivedAt = time.Now().UTC()
+	}
+
+	if cmd.Ingestion.ClientIP != "" {
+		sum := sha256.Sum256([]byte(s.clientIPSalt + cmd.Ingestion.ClientIP))
+		event.Ingestion.ClientIPHash = hex.EncodeToString(sum[:])
+	}
+
+	return event
+}
+
+// screenEvents applies the acceptance window to events. Out-of-window events are
+// clamped in place, quarantined or rejected depending on the configured action.
+func (s *EventService) screenEvents(events []*domain.Event) ([]*domain.Event, []*domain.QuarantinedEvent, error) {
+	if s.window.Action == eventRepo.OutOfWindowAccept || s.window.Action == "" {
+		return events, nil, nil
+	}
+
+	now := time.Now().UTC()
+	accepted := make([]*domain.Event, 0, len(events))
+	var quarantined []*domain.QuarantinedEvent
+
+	for i, event := range events {
+		// Events without a parseable date are left for the store to validate
+		date, err := event.OccurredAt()
+		if err != nil {
+			accepted = append(accepted, event)
+			continue
+		}
+
+		reason, ok := s.window.Check(date, now)
+		if ok {
+			accepted = append(accepted, event)
+			continue
+		}
+
+		switch s.window.Action {
+		case eventRepo.OutOfWindowClamp:
+			event.Date = s.window.Clamp(date, now).Format(time.RFC3339)
+			accepted = append(accepted, event)
+		case eventRepo.OutOfWindowQuarantine:
+			quarantined = append(quarantined, &domain.QuarantinedEvent{
+				Event:         event,
+				Reason:        reason,
+				QuarantinedAt: now,
+			})
+		default:
+			if len(events) == 1 {
+				return nil, nil, fmt.Errorf("%w: event %q is %s", eventRepo.ErrEventOutOfWindow, event.Name, reason)
+			}
+			return nil, nil, fmt.Errorf("%w: events[%d] (%q) is %s", eventRepo.ErrEventOutOfWindow, i, event.Name, reason)
+		}
+	}
+
+	return accepted, quarantined, nil
+}
+
+// quarantine stores events held back by the acceptance window
+func (s *EventService) quarantine(ctx context.Context, events []*domain.QuarantinedEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	if s.quarantineRepo == nil {
+		return ErrQuarantineDisabled
+	}
+
+	if err := s.quarantineRepo.SaveBatch(ctx, events); err != nil {
+		return fmt.Errorf("failed to quarantine events: %w", err)
+	}
+
+	return nil
+}
+
+// linkIdentities updates the identity graph for events that carry both a user_id and a user_pseudo_id.
+// Links are timed by their events, so importing or replaying older events
+// never replaces a newer link. Within a batch the latest event per
+// user_pseudo_id wins, and the later one in batch order on a tie.
+func (s *EventService) linkIdentities(ctx context.Context, events []*domain.Event) error {
+	positions := make(map[string]int)
+
+	var links []domain.IdentityLink
+	for _, event := range events {
+		if event.UserID == "" || event.UserPseudoID == "" {
+			continue
+		}
+
+		link := domain.IdentityLink{
+			UserPseudoID: event.UserPseudoID,
+			UserID:       event.UserID,
+			LinkedAt:     linkTime(event),
+		}
+		if i, ok := positions[event.UserPseudoID]; ok {
+			if !link.LinkedAt.Before(links[i].LinkedAt) {
+				links[i] = link
+			}
+			continue
+		}
+		positions[event.UserPseudoID] = len(links)
+		links = append(links, link)
+	}
+
+	if len(links) == 0 {
+		return nil
+	}
+
+	if err := s.identityRepo.Link(ctx, links); err != nil {
+		return fmt.Errorf("failed to link identities: %w", err)
+	}
+
+	return nil
+}
+
+// linkTime returns the time an event links its identities at: when it
+// occurred, or when it was received if its date cannot be parsed. The
+// identity stores keep link times at millisecond precision.
+func linkTime(event *domain.Event) time.Time {
+	date, err := event.OccurredAt()
+	if err != nil {
+		date = event.Ingestion.ReceivedAt
+	}
+	return date.UTC().Truncate(time.Millisecond)
+}
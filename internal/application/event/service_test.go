@@ -0,0 +1,94 @@
+package event
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ebubekir/event-stream/internal/domain"
+	eventRepo "github.com/ebubekir/event-stream/internal/domain/event"
+)
+
+// eventStore keeps saved events by ID
+type eventStore struct {
+	eventRepo.EventRepository
+	events map[string]*domain.Event
+}
+
+func (s *eventStore) Save(ctx context.Context, event *domain.Event) error {
+	_, err := s.SaveBatch(ctx, []*domain.Event{event})
+	return err
+}
+
+func (s *eventStore) SaveBatch(ctx context.Context, events []*domain.Event) ([]*domain.Event, error) {
+	var saved []*domain.Event
+	for _, event := range events {
+		if _, ok := s.events[event.ID]; ok {
+			continue
+		}
+		s.events[event.ID] = event
+		saved = append(saved, event)
+	}
+	return saved, nil
+}
+
+// quarantineStore keeps quarantined events by ID
+type quarantineStore struct {
+	eventRepo.QuarantineRepository
+	events map[string]*domain.QuarantinedEvent
+}
+
+func (s *quarantineStore) SaveBatch(ctx context.Context, events []*domain.QuarantinedEvent) error {
+	for _, event := range events {
+		s.events[event.Event.ID] = event
+	}
+	return nil
+}
+
+func (s *quarantineStore) FindByIDs(ctx context.Context, ids []string) ([]*domain.QuarantinedEvent, error) {
+	var events []*domain.QuarantinedEvent
+	for _, id := range ids {
+		if event, ok := s.events[id]; ok {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func (s *quarantineStore) Delete(ctx context.Context, ids []string) error {
+	for _, id := range ids {
+		delete(s.events, id)
+	}
+	return nil
+}
+
+func TestQuarantinedEventsLinkIdentitiesOnRelease(t *testing.T) {
+	events := &eventStore{events: make(map[string]*domain.Event)}
+	quarantined := &quarantineStore{events: make(map[string]*domain.QuarantinedEvent)}
+	identities := &identityStore{latest: make(map[string]domain.IdentityLink)}
+	window := eventRepo.AcceptanceWindow{MaxLateness: time.Hour, Action: eventRepo.OutOfWindowQuarantine}
+	s := NewEventService(events, nil, identities, WithAcceptanceWindow(window, quarantined))
+
+	now := time.Now().UTC()
+	created, err := s.CreateEvents(context.Background(), []*CreateEventCommand{
+		{ID: "on-time", Name: "login", ChannelType: "web", Date: now.Format(time.RFC3339), UserPseudoID: "p1", UserID: "u1"},
+		{ID: "late", Name: "login", ChannelType: "web", Date: now.Add(-48 * time.Hour).Format(time.RFC3339), UserPseudoID: "p2", UserID: "u2"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(created.QuarantinedIDs) != 1 {
+		t.Fatalf("quarantined %v, want the late event", created.QuarantinedIDs)
+	}
+	if _, ok := identities.latest["p2"]; ok || identities.latest["p1"].UserID != "u1" {
+		t.Fatalf("links before release = %v, want only the accepted event's", identities.latest)
+	}
+
+	released, err := s.ReleaseQuarantined(context.Background(), &ReleaseQuarantineCommand{IDs: []string{"late"}})
+	if err != nil || released != 1 {
+		t.Fatalf("ReleaseQuarantined() = %d, %v", released, err)
+	}
+	if identities.latest["p2"].UserID != "u2" {
+		t.Errorf("links after release = %v, want the released event's", identities.latest)
+	}
+}
@@ -0,0 +1,110 @@
+package event
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/ebubekir/event-stream/internal/domain"
+)
+
+// tailBufferSize is the number of events buffered per subscriber before new ones are dropped
+const tailBufferSize = 256
+
+// TailSubscription receives newly persisted events matching its query.
+// Events are dropped instead of blocking ingestion when the subscriber falls behind.
+type TailSubscription struct {
+	query   TailEventsQuery
+	events  chan *domain.Event
+	dropped atomic.Uint64
+	hub     *tailHub
+	once    sync.Once
+}
+
+// Events returns the channel of matching events. It is closed when the subscription ends.
+func (s *TailSubscription) Events() <-chan *domain.Event {
+	return s.events
+}
+
+// Dropped returns the number of matching events dropped because the buffer was full
+func (s *TailSubscription) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Close ends the subscription and closes its events channel
+func (s *TailSubscription) Close() {
+	s.hub.unsubscribe(s)
+}
+
+// tailHub broadcasts persisted events to live tail subscribers
+type tailHub struct {
+	mu          sync.RWMutex
+	subscribers map[*TailSubscription]struct{}
+	closed      bool
+}
+
+func newTailHub() *tailHub {
+	return &tailHub{subscribers: make(map[*TailSubscription]struct{})}
+}
+
+func (h *tailHub) subscribe(query TailEventsQuery) *TailSubscription {
+	sub := &TailSubscription{
+		query:  query,
+		events: make(chan *domain.Event, tailBufferSize),
+		hub:    h,
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		sub.once.Do(func() {
+			close(sub.events)
+		})
+		return sub
+	}
+	h.subscribers[sub] = struct{}{}
+	return sub
+}
+
+func (h *tailHub) unsubscribe(sub *TailSubscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	sub.once.Do(func() {
+		delete(h.subscribers, sub)
+		close(sub.events)
+	})
+}
+
+// broadcast hands events to every matching subscriber without blocking
+func (h *tailHub) broadcast(events []*domain.Event) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for sub := range h.subscribers {
+		for _, event := range events {
+			if !sub.query.matches(event) {
+				continue
+			}
+			select {
+			case sub.events <- event:
+			default:
+				sub.dropped.Add(1)
+			}
+		}
+	}
+}
+
+// close ends all subscriptions and rejects new ones
+func (h *tailHub) close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for sub := range h.subscribers {
+		sub.once.Do(func() {
+			close(sub.events)
+		})
+		delete(h.subscribers, sub)
+	}
+}
@@ -0,0 +1,36 @@
+package export
+
+import (
+	"time"
+
+	eventDomain "github.com/ebubekir/event-stream/internal/domain/event"
+)
+
+// ExportCommand represents the data needed to export stored events
+type ExportCommand struct {
+	Format       string // "ndjson", "csv", "parquet"
+	From         time.Time
+	To           time.Time
+	EventNames   []string
+	ChannelType  string
+	UserID       string
+	UserPseudoID string
+	AppID        string
+	// EventParams and UserParams list the param keys flattened into CSV
+	// columns; when both are empty every key found in the range is used
+	EventParams []string
+	UserParams  []string
+}
+
+// ToFilter converts the command to the domain event filter
+func (c *ExportCommand) ToFilter() eventDomain.EventFilter {
+	return eventDomain.EventFilter{
+		From:         c.From,
+		To:           c.To,
+		EventNames:   c.EventNames,
+		ChannelType:  c.ChannelType,
+		UserID:       c.UserID,
+		UserPseudoID: c.UserPseudoID,
+		AppID:        c.AppID,
+	}
+}
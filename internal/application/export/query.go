@@ -0,0 +1,47 @@
+package export
+
+import (
+	"time"
+
+	eventDomain "github.com/ebubekir/event-stream/internal/domain/event"
+)
+
+// ExportJobDTO represents an export job in application layer
+type ExportJobDTO struct {
+	ID           string
+	Format       string
+	From         time.Time
+	To           time.Time
+	EventNames   []string
+	ChannelType  string
+	UserID       string
+	UserPseudoID string
+	AppID        string
+	Status       string
+	EventCount   int64
+	Error        string
+	CreatedAt    time.Time
+	StartedAt    time.Time
+	FinishedAt   time.Time
+}
+
+// FromExportJob converts a domain export job to DTO
+func FromExportJob(job *eventDomain.ExportJob) *ExportJobDTO {
+	return &ExportJobDTO{
+		ID:           job.ID,
+		Format:       string(job.Format),
+		From:         job.Filter.From,
+		To:           job.Filter.To,
+		EventNames:   job.Filter.EventNames,
+		ChannelType:  job.Filter.ChannelType,
+		UserID:       job.Filter.UserID,
+		UserPseudoID: job.Filter.UserPseudoID,
+		AppID:        job.Filter.AppID,
+		Status:       string(job.Status),
+		EventCount:   job.EventCount,
+		Error:        job.Error,
+		CreatedAt:    job.CreatedAt,
+		StartedAt:    job.StartedAt,
+		FinishedAt:   job.FinishedAt,
+	}
+}
@@ -0,0 +1,332 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/ebubekir/event-stream/internal/domain"
+	eventDomain "github.com/ebubekir/event-stream/internal/domain/event"
+	"github.com/ebubekir/event-stream/pkg/logger"
+)
+
+// defaultMaxConcurrentJobs is used when WithMaxConcurrentJobs is not set
+const defaultMaxConcurrentJobs = 2
+
+// ErrUnsupportedFormat is returned for an unknown export format
+var ErrUnsupportedFormat = errors.New("unsupported export format")
+
+// EncoderFactory creates an encoder writing events to w in format. For CSV,
+// columns lists the param keys flattened into columns.
+type EncoderFactory func(w io.Writer, format eventDomain.ExportFormat, columns eventDomain.ParamKeys) (eventDomain.ExportEncoder, error)
+
+// ExportService handles exporting stored events, either streamed directly to
+// a writer or as asynchronous jobs whose output is kept in an ExportStore
+type ExportService struct {
+	events     eventDomain.EventRepository
+	store      eventDomain.ExportStore
+	newEncoder EncoderFactory
+
+	maxJobs int
+	slots   chan struct{}
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// Option configures optional ExportService behaviour
+type Option func(*ExportService)
+
+// WithStore keeps export jobs and their output in store. Without a store
+// only Export is available.
+func WithStore(store eventDomain.ExportStore) Option {
+	return func(s *ExportService) {
+		s.store = store
+	}
+}
+
+// WithMaxConcurrentJobs limits how many jobs run at once; later jobs wait as pending
+func WithMaxConcurrentJobs(n int) Option {
+	return func(s *ExportService) {
+		if n > 0 {
+			s.maxJobs = n
+		}
+	}
+}
+
+// NewExportService creates a new ExportService reading from events
+func NewExportService(events eventDomain.EventRepository, newEncoder EncoderFactory, opts ...Option) *ExportService {
+	s := &ExportService{
+		events:     events,
+		newEncoder: newEncoder,
+		maxJobs:    defaultMaxConcurrentJobs,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.slots = make(chan struct{}, s.maxJobs)
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	return s
+}
+
+// Export streams the events matching cmd to w and returns how many were written
+func (s *ExportService) Export(ctx context.Context, cmd *ExportCommand, w io.Writer) (int64, error) {
+	format, err := parseFormat(cmd.Format)
+	if err != nil {
+		return 0, err
+	}
+
+	requested := eventDomain.ParamKeys{EventParams: cmd.EventParams, UserParams: cmd.UserParams}
+	columns, err := s.columns(ctx, format, cmd.ToFilter(), requested)
+	if err != nil {
+		return 0, err
+	}
+
+	return s.export(ctx, format, cmd.ToFilter(), columns, w)
+}
+
+// CreateJob starts an asynchronous export and returns the pending job
+func (s *ExportService) CreateJob(ctx context.Context, cmd *ExportCommand) (*ExportJobDTO, error) {
+	if s.store == nil {
+		return nil, fmt.Errorf("export jobs are not configured")
+	}
+
+	format, err := parseFormat(cmd.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	job := &eventDomain.ExportJob{
+		ID:     uuid.New().String(),
+		Format: format,
+		Filter: cmd.ToFilter(),
+		Columns: eventDomain.ParamKeys{
+			EventParams: cmd.EventParams,
+			UserParams:  cmd.UserParams,
+		},
+		Status:    eventDomain.ExportStatusPending,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := s.store.SaveJob(ctx, job); err != nil {
+		return nil, err
+	}
+
+	// Snapshot the job before the export starts changing it
+	dto := FromExportJob(job)
+	s.wg.Add(1)
+	go s.run(job)
+
+	return dto, nil
+}
+
+// GetJob returns the export job with the given ID
+func (s *ExportService) GetJob(ctx context.Context, id string) (*ExportJobDTO, error) {
+	if s.store == nil {
+		return nil, eventDomain.ErrExportNotFound
+	}
+
+	job, err := s.store.FindJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return FromExportJob(job), nil
+}
+
+// ListJobs returns all export jobs, most recent first
+func (s *ExportService) ListJobs(ctx context.Context) ([]*ExportJobDTO, error) {
+	if s.store == nil {
+		return nil, nil
+	}
+
+	jobs, err := s.store.ListJobs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]*ExportJobDTO, len(jobs))
+	for i, job := range jobs {
+		dtos[i] = FromExportJob(job)
+	}
+	return dtos, nil
+}
+
+// OpenJobOutput returns the output of a succeeded job, or ErrExportNotReady
+func (s *ExportService) OpenJobOutput(ctx context.Context, id string) (io.ReadCloser, *ExportJobDTO, error) {
+	if s.store == nil {
+		return nil, nil, eventDomain.ErrExportNotFound
+	}
+
+	job, err := s.store.FindJob(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if job.Status != eventDomain.ExportStatusSucceeded {
+		return nil, nil, eventDomain.ErrExportNotReady
+	}
+
+	output, err := s.store.Open(ctx, job)
+	if err != nil {
+		return nil, nil, err
+	}
+	return output, FromExportJob(job), nil
+}
+
+// FailInterruptedJobs marks jobs left pending or running by a previous
+// process as failed, since nothing will finish them
+func (s *ExportService) FailInterruptedJobs(ctx context.Context) error {
+	if s.store == nil {
+		return nil
+	}
+
+	jobs, err := s.store.ListJobs(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, job := range jobs {
+		if job.Status != eventDomain.ExportStatusPending && job.Status != eventDomain.ExportStatusRunning {
+			continue
+		}
+		job.Status = eventDomain.ExportStatusFailed
+		job.Error = "interrupted by a restart"
+		job.FinishedAt = time.Now().UTC()
+		if err := s.store.SaveJob(ctx, job); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close cancels running jobs, marking them failed, and waits for them to stop
+func (s *ExportService) Close(ctx context.Context) error {
+	s.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run waits for a free slot, then writes the job's output to the store
+func (s *ExportService) run(job *eventDomain.ExportJob) {
+	defer s.wg.Done()
+
+	select {
+	case s.slots <- struct{}{}:
+		defer func() { <-s.slots }()
+	case <-s.ctx.Done():
+		s.finish(job, s.ctx.Err())
+		return
+	}
+
+	job.Status = eventDomain.ExportStatusRunning
+	job.StartedAt = time.Now().UTC()
+	if err := s.store.SaveJob(s.ctx, job); err != nil {
+		logger.Error("failed to update export job", zap.String("export_id", job.ID), zap.Error(err))
+	}
+
+	s.finish(job, s.runJob(job))
+}
+
+func (s *ExportService) runJob(job *eventDomain.ExportJob) error {
+	columns, err := s.columns(s.ctx, job.Format, job.Filter, job.Columns)
+	if err != nil {
+		return err
+	}
+
+	file, err := s.store.Create(s.ctx, job)
+	if err != nil {
+		return err
+	}
+
+	count, err := s.export(s.ctx, job.Format, job.Filter, columns, file)
+	job.EventCount = count
+	if err != nil {
+		_ = file.Abort()
+		return err
+	}
+	return file.Close()
+}
+
+// finish records the outcome of a job
+func (s *ExportService) finish(job *eventDomain.ExportJob, err error) {
+	job.Status = eventDomain.ExportStatusSucceeded
+	if err != nil {
+		job.Status = eventDomain.ExportStatusFailed
+		job.Error = err.Error()
+		if s.ctx.Err() != nil {
+			job.Error = "interrupted by shutdown"
+		}
+		logger.Warn("export failed", zap.String("export_id", job.ID), zap.Error(err))
+	}
+	job.FinishedAt = time.Now().UTC()
+
+	// The service context may be cancelled already; the outcome must still be saved
+	if err := s.store.SaveJob(context.Background(), job); err != nil {
+		logger.Error("failed to update export job", zap.String("export_id", job.ID), zap.Error(err))
+	}
+}
+
+// export streams the events matching filter through an encoder into w
+func (s *ExportService) export(ctx context.Context, format eventDomain.ExportFormat, filter eventDomain.EventFilter, columns eventDomain.ParamKeys, w io.Writer) (int64, error) {
+	encoder, err := s.newEncoder(w, format, columns)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	err = s.events.Stream(ctx, filter, func(event *domain.Event) error {
+		if err := encoder.Encode(event); err != nil {
+			return fmt.Errorf("failed to encode event %s: %w", event.ID, err)
+		}
+		count++
+		return nil
+	})
+	if err != nil {
+		return count, err
+	}
+
+	if err := encoder.Close(); err != nil {
+		return count, fmt.Errorf("failed to finish export: %w", err)
+	}
+	return count, nil
+}
+
+// columns returns the CSV param columns: the requested keys, or every key
+// used by the events matching filter when none were requested
+func (s *ExportService) columns(ctx context.Context, format eventDomain.ExportFormat, filter eventDomain.EventFilter, requested eventDomain.ParamKeys) (eventDomain.ParamKeys, error) {
+	if format != eventDomain.ExportFormatCSV || len(requested.EventParams) > 0 || len(requested.UserParams) > 0 {
+		return requested, nil
+	}
+
+	keys, err := s.events.ParamKeys(ctx, filter)
+	if err != nil {
+		return requested, err
+	}
+	return *keys, nil
+}
+
+func parseFormat(format string) (eventDomain.ExportFormat, error) {
+	switch f := eventDomain.ExportFormat(format); f {
+	case eventDomain.ExportFormatNDJSON, eventDomain.ExportFormatCSV, eventDomain.ExportFormatParquet:
+		return f, nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+	}
+}
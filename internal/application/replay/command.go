@@ -0,0 +1,40 @@
+package replay
+
+import (
+	"time"
+
+	eventDomain "github.com/ebubekir/event-stream/internal/domain/event"
+)
+
+// ReplayCommand represents the data needed to replay a time window from a source
+type ReplayCommand struct {
+	Source       string // "archive", "quarantine", or the name of another database such as "clickhouse"
+	From         time.Time
+	To           time.Time
+	EventNames   []string
+	ChannelType  string
+	UserID       string
+	UserPseudoID string
+	AppID        string
+	// Reprocess runs events through the current ingestion pipeline instead of storing them as read
+	Reprocess bool
+	// DeleteFirst removes the target's events matching the filter before each chunk is replayed
+	DeleteFirst bool
+	// Rate limits replayed events per second; 0 is unlimited
+	Rate float64
+	// ChunkSize is the span of event time replayed between checkpoints; 0 uses an hour
+	ChunkSize time.Duration
+}
+
+// ToFilter converts the command to the domain event filter
+func (c *ReplayCommand) ToFilter() eventDomain.EventFilter {
+	return eventDomain.EventFilter{
+		From:         c.From,
+		To:           c.To,
+		EventNames:   c.EventNames,
+		ChannelType:  c.ChannelType,
+		UserID:       c.UserID,
+		UserPseudoID: c.UserPseudoID,
+		AppID:        c.AppID,
+	}
+}
@@ -0,0 +1,55 @@
+package replay
+
+import (
+	"context"
+
+	"github.com/ebubekir/event-stream/internal/domain"
+	eventDomain "github.com/ebubekir/event-stream/internal/domain/event"
+)
+
+// quarantinePageSize is the number of quarantined events listed per query
+const quarantinePageSize = 1000
+
+// QuarantineSource replays quarantined events, removing each from the
+// quarantine once it was written to the target
+type QuarantineSource struct {
+	repo eventDomain.QuarantineRepository
+}
+
+// NewQuarantineSource creates a QuarantineSource on repo
+func NewQuarantineSource(repo eventDomain.QuarantineRepository) *QuarantineSource {
+	return &QuarantineSource{repo: repo}
+}
+
+// Stream calls fn for each quarantined event matching filter. The matching
+// events are collected before fn is called, since removing replayed events
+// would otherwise shift the pages still to be listed.
+func (s *QuarantineSource) Stream(ctx context.Context, filter eventDomain.EventFilter, fn func(*domain.Event) error) error {
+	var matched []*domain.Event
+	for offset := 0; ; offset += quarantinePageSize {
+		page, err := s.repo.List(ctx, quarantinePageSize, offset)
+		if err != nil {
+			return err
+		}
+		for _, q := range page {
+			if filter.Matches(q.Event) {
+				matched = append(matched, q.Event)
+			}
+		}
+		if len(page) < quarantinePageSize {
+			break
+		}
+	}
+
+	for _, event := range matched {
+		if err := fn(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Remove deletes replayed events from the quarantine
+func (s *QuarantineSource) Remove(ctx context.Context, ids []string) error {
+	return s.repo.Delete(ctx, ids)
+}
@@ -0,0 +1,59 @@
+package replay
+
+import (
+	"time"
+
+	eventDomain "github.com/ebubekir/event-stream/internal/domain/event"
+)
+
+// ReplayJobDTO represents a replay job in application layer
+type ReplayJobDTO struct {
+	ID           string
+	Source       string
+	From         time.Time
+	To           time.Time
+	EventNames   []string
+	ChannelType  string
+	UserID       string
+	UserPseudoID string
+	AppID        string
+	Reprocess    bool
+	DeleteFirst  bool
+	Rate         float64
+	ChunkSize    time.Duration
+	Checkpoint   time.Time
+	Runs         int
+	Status       string
+	EventCount   int64
+	Error        string
+	CreatedAt    time.Time
+	StartedAt    time.Time
+	FinishedAt   time.Time
+}
+
+// FromReplayJob converts a domain replay job to DTO
+func FromReplayJob(job *eventDomain.ReplayJob) *ReplayJobDTO {
+	return &ReplayJobDTO{
+		ID:           job.ID,
+		Source:       job.Source,
+		From:         job.Filter.From,
+		To:           job.Filter.To,
+		EventNames:   job.Filter.EventNames,
+		ChannelType:  job.Filter.ChannelType,
+		UserID:       job.Filter.UserID,
+		UserPseudoID: job.Filter.UserPseudoID,
+		AppID:        job.Filter.AppID,
+		Reprocess:    job.Reprocess,
+		DeleteFirst:  job.DeleteFirst,
+		Rate:         job.Rate,
+		ChunkSize:    job.ChunkSize,
+		Checkpoint:   job.Checkpoint,
+		Runs:         job.Runs,
+		Status:       string(job.Status),
+		EventCount:   job.EventCount,
+		Error:        job.Error,
+		CreatedAt:    job.CreatedAt,
+		StartedAt:    job.StartedAt,
+		FinishedAt:   job.FinishedAt,
+	}
+}
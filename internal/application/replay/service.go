@@ -0,0 +1,586 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/ebubekir/event-stream/internal/domain"
+	eventDomain "github.com/ebubekir/event-stream/internal/domain/event"
+	"github.com/ebubekir/event-stream/pkg/logger"
+)
+
+const (
+	// defaultBatchSize is used when WithBatchSize is not set
+	defaultBatchSize = 1000
+	// defaultChunkSize is used when a command does not set a chunk size
+	defaultChunkSize = time.Hour
+)
+
+// ErrInvalidReplay is returned for a replay command that cannot be run
+var ErrInvalidReplay = errors.New("invalid replay")
+
+// SourceOpener opens the named event source. Sources implementing io.Closer
+// are closed once a run is over. Unknown names return an error wrapping
+// ErrInvalidReplay.
+type SourceOpener func(ctx context.Context, source string) (eventDomain.EventSource, error)
+
+// Pipeline runs events through the current ingestion processing and stores
+// the result. It is implemented by event.EventService.
+type Pipeline interface {
+	ReprocessEvents(ctx context.Context, events []*domain.Event) error
+}
+
+// ReplayService re-ingests time windows of events from a source into the
+// target event repository. Windows are replayed in chunks of event time; the
+// job is checkpointed after each chunk, so an interrupted replay resumes with
+// the first unfinished chunk instead of starting over.
+type ReplayService struct {
+	target     eventDomain.EventRepository
+	pipeline   Pipeline
+	openSource SourceOpener
+	store      eventDomain.ReplayStore
+	batchSize  int
+	onProgress func(*ReplayJobDTO)
+
+	mu      sync.Mutex
+	running map[string]*run
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// Option configures optional ReplayService behaviour
+type Option func(*ReplayService)
+
+// WithBatchSize sets how many events are written at once
+func WithBatchSize(n int) Option {
+	return func(s *ReplayService) {
+		if n > 0 {
+			s.batchSize = n
+		}
+	}
+}
+
+// WithProgress calls fn with the job after every completed chunk
+func WithProgress(fn func(*ReplayJobDTO)) Option {
+	return func(s *ReplayService) {
+		s.onProgress = fn
+	}
+}
+
+// NewReplayService creates a new ReplayService writing to target. Reprocessed
+// replays are written through pipeline instead; jobs are kept in store.
+func NewReplayService(target eventDomain.EventRepository, pipeline Pipeline, openSource SourceOpener, store eventDomain.ReplayStore, opts ...Option) *ReplayService {
+	s := &ReplayService{
+		target:     target,
+		pipeline:   pipeline,
+		openSource: openSource,
+		store:      store,
+		batchSize:  defaultBatchSize,
+		running:    make(map[string]*run),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	return s
+}
+
+// run is the state of a replay executing in this process
+type run struct {
+	cancel    context.CancelFunc
+	cancelled atomic.Bool
+	rate      atomic.Uint64 // math.Float64bits of the events per second limit
+}
+
+func newRun(cancel context.CancelFunc, rate float64) *run {
+	r := &run{cancel: cancel}
+	r.setRate(rate)
+	return r
+}
+
+func (r *run) setRate(rate float64) {
+	r.rate.Store(math.Float64bits(rate))
+}
+
+func (r *run) getRate() float64 {
+	return math.Float64frombits(r.rate.Load())
+}
+
+// Run creates a replay job and runs it to completion. Cancelling ctx stops
+// the replay after the current batch; the job can be resumed with RunJob.
+func (s *ReplayService) Run(ctx context.Context, cmd *ReplayCommand) (*ReplayJobDTO, error) {
+	job, err := s.newJob(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+	return s.runSync(ctx, job)
+}
+
+// RunJob resumes the replay job with the given ID and runs it to completion
+func (s *ReplayService) RunJob(ctx context.Context, id string) (*ReplayJobDTO, error) {
+	job, err := s.resumable(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.runSync(ctx, job)
+}
+
+// StartJob creates a replay job and runs it in the background
+func (s *ReplayService) StartJob(ctx context.Context, cmd *ReplayCommand) (*ReplayJobDTO, error) {
+	job, err := s.newJob(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	// Snapshot the job before the replay starts changing it
+	dto := FromReplayJob(job)
+	if err := s.start(job); err != nil {
+		return nil, err
+	}
+	return dto, nil
+}
+
+// ResumeJob continues a cancelled, failed or interrupted replay job from its
+// checkpoint in the background
+func (s *ReplayService) ResumeJob(ctx context.Context, id string) (*ReplayJobDTO, error) {
+	job, err := s.resumable(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	job.Status = eventDomain.ReplayStatusPending
+	if err := s.store.SaveJob(ctx, job); err != nil {
+		return nil, err
+	}
+	dto := FromReplayJob(job)
+	if err := s.start(job); err != nil {
+		return nil, err
+	}
+	return dto, nil
+}
+
+// CancelJob stops a replay running in this process after its current batch
+func (s *ReplayService) CancelJob(ctx context.Context, id string) (*ReplayJobDTO, error) {
+	s.mu.Lock()
+	r, ok := s.running[id]
+	s.mu.Unlock()
+
+	job, err := s.store.FindJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, eventDomain.ErrReplayNotRunning
+	}
+
+	r.cancelled.Store(true)
+	r.cancel()
+	return FromReplayJob(job), nil
+}
+
+// SetRate changes the events per second limit of a replay running in this
+// process; it takes effect with the next batch. 0 removes the limit.
+func (s *ReplayService) SetRate(ctx context.Context, id string, rate float64) (*ReplayJobDTO, error) {
+	if rate < 0 {
+		return nil, fmt.Errorf("%w: rate must not be negative", ErrInvalidReplay)
+	}
+
+	s.mu.Lock()
+	r, ok := s.running[id]
+	s.mu.Unlock()
+
+	job, err := s.store.FindJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, eventDomain.ErrReplayNotRunning
+	}
+
+	// The running replay saves the new rate with its next checkpoint
+	r.setRate(rate)
+	job.Rate = rate
+	return FromReplayJob(job), nil
+}
+
+// GetJob returns the replay job with the given ID
+func (s *ReplayService) GetJob(ctx context.Context, id string) (*ReplayJobDTO, error) {
+	job, err := s.store.FindJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return FromReplayJob(job), nil
+}
+
+// ListJobs returns all replay jobs, most recent first
+func (s *ReplayService) ListJobs(ctx context.Context) ([]*ReplayJobDTO, error) {
+	jobs, err := s.store.ListJobs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]*ReplayJobDTO, len(jobs))
+	for i, job := range jobs {
+		dtos[i] = FromReplayJob(job)
+	}
+	return dtos, nil
+}
+
+// ResumeInterruptedJobs restarts jobs left pending or running by a previous
+// process from their checkpoints
+func (s *ReplayService) ResumeInterruptedJobs(ctx context.Context) error {
+	jobs, err := s.store.ListJobs(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, job := range jobs {
+		if job.Status != eventDomain.ReplayStatusPending && job.Status != eventDomain.ReplayStatusRunning {
+			continue
+		}
+		logger.Info("resuming interrupted replay", zap.String("replay_id", job.ID), zap.Time("checkpoint", job.Checkpoint))
+		if err := s.start(job); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close stops running replays after their current batch and waits for them.
+// They are left running in the store and resumed by ResumeInterruptedJobs.
+func (s *ReplayService) Close(ctx context.Context) error {
+	s.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// newJob validates cmd and saves it as a pending job
+func (s *ReplayService) newJob(ctx context.Context, cmd *ReplayCommand) (*eventDomain.ReplayJob, error) {
+	switch {
+	case cmd.From.IsZero() || cmd.To.IsZero():
+		return nil, fmt.Errorf("%w: a time range is required", ErrInvalidReplay)
+	case !cmd.To.After(cmd.From):
+		return nil, fmt.Errorf("%w: the end of the time range must be after its start", ErrInvalidReplay)
+	case cmd.Rate < 0:
+		return nil, fmt.Errorf("%w: rate must not be negative", ErrInvalidReplay)
+	case cmd.ChunkSize < 0:
+		return nil, fmt.Errorf("%w: chunk size must not be negative", ErrInvalidReplay)
+	}
+
+	// Open the source once so an unknown or unreachable one is reported now
+	source, err := s.openSource(ctx, cmd.Source)
+	if err != nil {
+		return nil, err
+	}
+	_, consumed := source.(eventDomain.ConsumedEventSource)
+	closeSource(source)
+
+	// A consumed source such as the quarantine holds events the target does
+	// not, so deleting the window would lose the target's own events
+	if consumed && cmd.DeleteFirst {
+		return nil, fmt.Errorf("%w: %s events cannot replace the stored ones", ErrInvalidReplay, cmd.Source)
+	}
+
+	chunkSize := cmd.ChunkSize
+	if chunkSize == 0 {
+		chunkSize = defaultChunkSize
+	}
+
+	job := &eventDomain.ReplayJob{
+		ID:          uuid.New().String(),
+		Source:      cmd.Source,
+		Filter:      cmd.ToFilter(),
+		Reprocess:   cmd.Reprocess,
+		DeleteFirst: cmd.DeleteFirst,
+		Rate:        cmd.Rate,
+		ChunkSize:   chunkSize,
+		Checkpoint:  cmd.From,
+		Status:      eventDomain.ReplayStatusPending,
+		CreatedAt:   time.Now().UTC(),
+	}
+	if err := s.store.SaveJob(ctx, job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// resumable returns the job with the given ID if it can be resumed
+func (s *ReplayService) resumable(ctx context.Context, id string) (*eventDomain.ReplayJob, error) {
+	job, err := s.store.FindJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status == eventDomain.ReplayStatusSucceeded {
+		return nil, eventDomain.ErrReplaySucceeded
+	}
+
+	s.mu.Lock()
+	_, running := s.running[id]
+	s.mu.Unlock()
+	if running {
+		return nil, eventDomain.ErrReplayRunning
+	}
+	return job, nil
+}
+
+// start runs job in the background
+func (s *ReplayService) start(job *eventDomain.ReplayJob) error {
+	ctx, cancel := context.WithCancel(s.ctx)
+	r := newRun(cancel, job.Rate)
+
+	s.mu.Lock()
+	if _, ok := s.running[job.ID]; ok {
+		s.mu.Unlock()
+		cancel()
+		return eventDomain.ErrReplayRunning
+	}
+	s.running[job.ID] = r
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer func() {
+			s.mu.Lock()
+			delete(s.running, job.ID)
+			s.mu.Unlock()
+			cancel()
+		}()
+
+		_ = s.execute(ctx, job, r)
+	}()
+	return nil
+}
+
+// runSync runs job in the calling goroutine
+func (s *ReplayService) runSync(ctx context.Context, job *eventDomain.ReplayJob) (*ReplayJobDTO, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	err := s.execute(ctx, job, newRun(cancel, job.Rate))
+	return FromReplayJob(job), err
+}
+
+// execute replays job chunk by chunk from its checkpoint and records the outcome
+func (s *ReplayService) execute(ctx context.Context, job *eventDomain.ReplayJob, r *run) error {
+	job.Runs++
+	job.Status = eventDomain.ReplayStatusRunning
+	job.StartedAt = time.Now().UTC()
+	job.FinishedAt = time.Time{}
+	job.Error = ""
+	s.save(job, r)
+
+	err := s.replay(ctx, job, r)
+	s.finish(ctx, job, r, err)
+	return err
+}
+
+func (s *ReplayService) replay(ctx context.Context, job *eventDomain.ReplayJob, r *run) error {
+	source, err := s.openSource(ctx, job.Source)
+	if err != nil {
+		return err
+	}
+	defer closeSource(source)
+
+	start := job.Checkpoint
+	if start.IsZero() {
+		start = job.Filter.From
+	}
+
+	for start.Before(job.Filter.To) {
+		end := start.Add(job.ChunkSize)
+		if end.After(job.Filter.To) {
+			end = job.Filter.To
+		}
+
+		count, err := s.replayChunk(ctx, job, r, source, start, end)
+		if err != nil {
+			return fmt.Errorf("failed to replay %s to %s: %w", start.Format(time.RFC3339), end.Format(time.RFC3339), err)
+		}
+
+		job.Checkpoint = end
+		job.EventCount += count
+		s.save(job, r)
+		if s.onProgress != nil {
+			s.onProgress(FromReplayJob(job))
+		}
+		start = end
+	}
+	return nil
+}
+
+// replayChunk replays the events of job dated in [start, end) and returns how many were written
+func (s *ReplayService) replayChunk(ctx context.Context, job *eventDomain.ReplayJob, r *run, source eventDomain.EventSource, start, end time.Time) (int64, error) {
+	filter := job.Filter
+	filter.From, filter.To = start, end
+
+	if job.DeleteFirst {
+		if err := s.target.DeleteRange(ctx, filter); err != nil {
+			return 0, err
+		}
+	}
+
+	var count int64
+	batchNo := 0
+	batch := make([]*domain.Event, 0, s.batchSize)
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		written := time.Now()
+		if err := s.write(s.writeContext(ctx, job, start, batchNo), job, batch); err != nil {
+			return err
+		}
+
+		if consumed, ok := source.(eventDomain.ConsumedEventSource); ok {
+			ids := make([]string, len(batch))
+			for i, event := range batch {
+				ids[i] = event.ID
+			}
+			if err := consumed.Remove(ctx, ids); err != nil {
+				return err
+			}
+		}
+
+		count += int64(len(batch))
+		batchNo++
+		n := len(batch)
+		batch = batch[:0]
+		return throttle(ctx, r.getRate(), n, time.Since(written))
+	}
+
+	err := source.Stream(ctx, filter, func(event *domain.Event) error {
+		batch = append(batch, event)
+		if len(batch) < s.limit(r) {
+			return nil
+		}
+		return flush()
+	})
+	if err != nil {
+		return count, err
+	}
+	return count, flush()
+}
+
+// writeContext prepares ctx for writing batch batchNo of the chunk starting at
+// start. A chunk replayed after DeleteFirst is empty, so its batches only need
+// tokens that differ from every earlier run's: stores that deduplicate inserts
+// would otherwise drop a rewrite of the deleted copy. Without DeleteFirst a
+// resumed run rewrites the checkpoint chunk on top of what the interrupted run
+// stored, cut into batches that can differ with the rate, so the events that
+// are already stored are skipped by ID instead.
+func (s *ReplayService) writeContext(ctx context.Context, job *eventDomain.ReplayJob, start time.Time, batchNo int) context.Context {
+	if !job.DeleteFirst {
+		return eventDomain.WithSkipStored(ctx)
+	}
+	token := fmt.Sprintf("replay/%s/%d/%d/%d", job.ID, job.Runs, start.Unix(), batchNo)
+	return eventDomain.WithDedupToken(ctx, token)
+}
+
+// write stores one batch in the target, reprocessing it first if the job asks for it
+func (s *ReplayService) write(ctx context.Context, job *eventDomain.ReplayJob, events []*domain.Event) error {
+	if job.Reprocess {
+		return s.pipeline.ReprocessEvents(ctx, events)
+	}
+	_, err := s.target.SaveBatch(ctx, events)
+	return err
+}
+
+// limit returns the size of the next batch. Under a low rate limit batches
+// shrink to about a second of events, so the rate stays even.
+func (s *ReplayService) limit(r *run) int {
+	rate := r.getRate()
+	if rate > 0 && rate < float64(s.batchSize) {
+		return max(1, int(rate))
+	}
+	return s.batchSize
+}
+
+// throttle waits until writing n events took at least n/rate seconds
+func throttle(ctx context.Context, rate float64, n int, took time.Duration) error {
+	if rate <= 0 {
+		return nil
+	}
+
+	wait := time.Duration(float64(n)/rate*float64(time.Second)) - took
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// finish records the outcome of a run
+func (s *ReplayService) finish(ctx context.Context, job *eventDomain.ReplayJob, r *run, err error) {
+	switch {
+	case err == nil:
+		job.Status = eventDomain.ReplayStatusSucceeded
+	case r.cancelled.Load():
+		job.Status = eventDomain.ReplayStatusCancelled
+	case s.ctx.Err() != nil:
+		// Shutdown: the job stays running and is resumed on the next start
+		logger.Info("replay interrupted by shutdown", zap.String("replay_id", job.ID), zap.Time("checkpoint", job.Checkpoint))
+		s.save(job, r)
+		return
+	case ctx.Err() != nil:
+		job.Status = eventDomain.ReplayStatusCancelled
+		job.Error = "interrupted"
+	default:
+		job.Status = eventDomain.ReplayStatusFailed
+		job.Error = err.Error()
+		logger.Warn("replay failed", zap.String("replay_id", job.ID), zap.Error(err))
+	}
+	job.FinishedAt = time.Now().UTC()
+	s.save(job, r)
+}
+
+// save stores the job with the run's current rate
+func (s *ReplayService) save(job *eventDomain.ReplayJob, r *run) {
+	job.Rate = r.getRate()
+
+	// The run's context may be cancelled already; the checkpoint must still be saved
+	if err := s.store.SaveJob(context.Background(), job); err != nil {
+		logger.Error("failed to update replay job", zap.String("replay_id", job.ID), zap.Error(err))
+	}
+}
+
+func closeSource(source eventDomain.EventSource) {
+	if closer, ok := source.(io.Closer); ok {
+		_ = closer.Close()
+	}
+}
@@ -0,0 +1,207 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/ebubekir/event-stream/internal/domain"
+	eventDomain "github.com/ebubekir/event-stream/internal/domain/event"
+	"github.com/ebubekir/event-stream/pkg/logger"
+)
+
+func init() {
+	logger.Log = zap.NewNop()
+}
+
+// jobStore keeps copies of replay jobs in memory
+type jobStore struct {
+	mu   sync.Mutex
+	jobs map[string]eventDomain.ReplayJob
+}
+
+func (s *jobStore) SaveJob(ctx context.Context, job *eventDomain.ReplayJob) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs[job.ID] = *job
+	return nil
+}
+
+func (s *jobStore) FindJob(ctx context.Context, id string) (*eventDomain.ReplayJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.jobs[id]
+	if !ok {
+		return nil, eventDomain.ErrReplayNotFound
+	}
+	return &job, nil
+}
+
+func (s *jobStore) ListJobs(ctx context.Context) ([]*eventDomain.ReplayJob, error) {
+	return nil, nil
+}
+
+// sliceSource streams a fixed list of events, all inside the replayed window
+type sliceSource []*domain.Event
+
+func (s sliceSource) Stream(ctx context.Context, filter eventDomain.EventFilter, fn func(*domain.Event) error) error {
+	for _, event := range s {
+		if err := fn(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// targetStore is an in-memory EventRepository that, like the real stores,
+// drops events already stored when the write is marked WithSkipStored. Its
+// write number failOn fails once.
+type targetStore struct {
+	eventDomain.EventRepository
+
+	rows   []string
+	tokens []string
+	writes int
+	failOn int
+}
+
+func (s *targetStore) SaveBatch(ctx context.Context, events []*domain.Event) ([]*domain.Event, error) {
+	s.writes++
+	if s.writes == s.failOn {
+		return nil, errors.New("store unavailable")
+	}
+
+	if token := eventDomain.DedupToken(ctx); token != "" {
+		s.tokens = append(s.tokens, token)
+	}
+	var saved []*domain.Event
+	for _, event := range events {
+		if eventDomain.SkipStored(ctx) && s.stored(event.ID) {
+			continue
+		}
+		s.rows = append(s.rows, event.ID)
+		saved = append(saved, event)
+	}
+	return saved, nil
+}
+
+func (s *targetStore) DeleteRange(ctx context.Context, filter eventDomain.EventFilter) error {
+	s.rows = nil
+	return nil
+}
+
+func (s *targetStore) stored(id string) bool {
+	for _, row := range s.rows {
+		if row == id {
+			return true
+		}
+	}
+	return false
+}
+
+func TestReplayResumeWritesCheckpointChunkOnce(t *testing.T) {
+	from := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	source := make(sliceSource, 6)
+	for i := range source {
+		source[i] = &domain.Event{ID: fmt.Sprintf("event-%d", i), Name: "page_view", Date: from.Add(time.Duration(i) * time.Minute).Format(time.RFC3339)}
+	}
+	openSource := func(ctx context.Context, name string) (eventDomain.EventSource, error) {
+		return source, nil
+	}
+
+	tests := []struct {
+		name        string
+		deleteFirst bool
+	}{
+		{name: "without delete", deleteFirst: false},
+		{name: "with delete", deleteFirst: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := &targetStore{failOn: 2}
+			store := &jobStore{jobs: make(map[string]eventDomain.ReplayJob)}
+			cmd := &ReplayCommand{Source: "archive", From: from, To: from.Add(time.Hour), DeleteFirst: tt.deleteFirst}
+
+			// The first run stores one batch of two and fails on the second
+			first := NewReplayService(target, nil, openSource, store, WithBatchSize(2))
+			job, err := first.Run(context.Background(), cmd)
+			if err == nil {
+				t.Fatal("Run() error = nil, want the store's error")
+			}
+			firstTokens := len(target.tokens)
+
+			// The resumed run cuts the chunk into different batches
+			resumed := NewReplayService(target, nil, openSource, store, WithBatchSize(4))
+			if _, err := resumed.RunJob(context.Background(), job.ID); err != nil {
+				t.Fatalf("RunJob() error = %v", err)
+			}
+
+			if len(target.rows) != len(source) {
+				t.Errorf("stored %v, want each of the %d events once", target.rows, len(source))
+			}
+			for _, token := range target.tokens[firstTokens:] {
+				for _, earlier := range target.tokens[:firstTokens] {
+					if token == earlier {
+						t.Errorf("resumed run reused token %s", token)
+					}
+				}
+			}
+		})
+	}
+}
+
+// consumedSource is a sliceSource whose replayed events are removed, like the quarantine
+type consumedSource struct {
+	sliceSource
+}
+
+func (s *consumedSource) Remove(ctx context.Context, ids []string) error {
+	return nil
+}
+
+func TestReplayConsumedSourceRejectsDeleteFirst(t *testing.T) {
+	from := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	source := &consumedSource{sliceSource{{ID: "late", Name: "page_view", Date: from.Format(time.RFC3339)}}}
+	openSource := func(ctx context.Context, name string) (eventDomain.EventSource, error) {
+		return source, nil
+	}
+
+	tests := []struct {
+		name        string
+		deleteFirst bool
+		wantErr     error
+	}{
+		{name: "with delete", deleteFirst: true, wantErr: ErrInvalidReplay},
+		{name: "without delete", deleteFirst: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := &targetStore{rows: []string{"stored"}}
+			store := &jobStore{jobs: make(map[string]eventDomain.ReplayJob)}
+			s := NewReplayService(target, nil, openSource, store)
+
+			cmd := &ReplayCommand{Source: "quarantine", From: from, To: from.Add(time.Hour), DeleteFirst: tt.deleteFirst}
+			_, err := s.Run(context.Background(), cmd)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Run() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr != nil {
+				if len(store.jobs) != 0 || len(target.rows) != 1 {
+					t.Errorf("rejected replay saved %d jobs and left %v, want none and the stored event", len(store.jobs), target.rows)
+				}
+				return
+			}
+			if len(target.rows) != 2 {
+				t.Errorf("stored %v, want the stored and the replayed event", target.rows)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package webhook
+
+// CreateSubscriptionCommand represents the data needed to create a webhook subscription
+type CreateSubscriptionCommand struct {
+	URL string
+	// Secret signs payloads; a random one is generated when empty
+	Secret       string
+	EventNames   []string
+	ParamFilters []ParamFilterDTO
+}
+
+// UpdateSubscriptionCommand represents the data needed to replace a webhook subscription.
+// Re-enabling a subscription resets its failure count.
+type UpdateSubscriptionCommand struct {
+	ID  string
+	URL string
+	// Secret rotates the signing secret when set
+	Secret       string
+	EventNames   []string
+	ParamFilters []ParamFilterDTO
+	Enabled      bool
+}
+
+// ParamFilterDTO represents a webhook param filter in application layer
+type ParamFilterDTO struct {
+	Key   string
+	Value string
+}
@@ -0,0 +1,81 @@
+package webhook
+
+import (
+	"time"
+
+	"github.com/ebubekir/event-stream/internal/domain"
+)
+
+// ListDeliveriesQuery represents the query parameters for listing delivery attempts
+type ListDeliveriesQuery struct {
+	SubscriptionID string
+	Limit          int
+	Offset         int
+}
+
+// SubscriptionDTO represents a webhook subscription in application layer.
+// Secret is only populated when the subscription is created or its secret rotated.
+type SubscriptionDTO struct {
+	ID                  string
+	URL                 string
+	Secret              string
+	EventNames          []string
+	ParamFilters        []ParamFilterDTO
+	Enabled             bool
+	ConsecutiveFailures int
+	DisabledReason      string
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+}
+
+// DeliveryDTO represents a delivery attempt in application layer
+type DeliveryDTO struct {
+	ID          string
+	EventID     string
+	EventName   string
+	Attempt     int
+	StatusCode  int
+	Error       string
+	Succeeded   bool
+	Duration    time.Duration
+	AttemptedAt time.Time
+}
+
+// FromSubscription converts a domain subscription to DTO without its secret
+func FromSubscription(s *domain.WebhookSubscription) *SubscriptionDTO {
+	filters := make([]ParamFilterDTO, len(s.ParamFilters))
+	for i, f := range s.ParamFilters {
+		filters[i] = ParamFilterDTO{Key: f.Key, Value: f.Value}
+	}
+
+	return &SubscriptionDTO{
+		ID:                  s.ID,
+		URL:                 s.URL,
+		EventNames:          s.EventNames,
+		ParamFilters:        filters,
+		Enabled:             s.Enabled,
+		ConsecutiveFailures: s.ConsecutiveFailures,
+		DisabledReason:      s.DisabledReason,
+		CreatedAt:           s.CreatedAt,
+		UpdatedAt:           s.UpdatedAt,
+	}
+}
+
+// FromDeliveries converts domain delivery attempts to DTOs
+func FromDeliveries(deliveries []*domain.WebhookDelivery) []DeliveryDTO {
+	dtos := make([]DeliveryDTO, len(deliveries))
+	for i, d := range deliveries {
+		dtos[i] = DeliveryDTO{
+			ID:          d.ID,
+			EventID:     d.EventID,
+			EventName:   d.EventName,
+			Attempt:     d.Attempt,
+			StatusCode:  d.StatusCode,
+			Error:       d.Error,
+			Succeeded:   d.Succeeded,
+			Duration:    d.Duration,
+			AttemptedAt: d.AttemptedAt,
+		}
+	}
+	return dtos
+}
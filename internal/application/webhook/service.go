@@ -0,0 +1,188 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ebubekir/event-stream/internal/domain"
+	eventRepo "github.com/ebubekir/event-stream/internal/domain/event"
+)
+
+// WebhookService handles webhook subscription use cases
+type WebhookService struct {
+	repo     eventRepo.WebhookRepository
+	onChange func()
+}
+
+// Option configures optional WebhookService behaviour
+type Option func(*WebhookService)
+
+// WithOnChange calls fn after a subscription is created, updated or deleted,
+// e.g. to refresh a dispatcher's subscription cache
+func WithOnChange(fn func()) Option {
+	return func(s *WebhookService) {
+		s.onChange = fn
+	}
+}
+
+// NewWebhookService creates a new WebhookService with the given repository
+func NewWebhookService(repo eventRepo.WebhookRepository, opts ...Option) *WebhookService {
+	s := &WebhookService{
+		repo: repo,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// CreateSubscription registers a new webhook endpoint. The returned DTO carries the signing secret.
+func (s *WebhookService) CreateSubscription(ctx context.Context, cmd *CreateSubscriptionCommand) (*SubscriptionDTO, error) {
+	secret := cmd.Secret
+	if secret == "" {
+		generated, err := newSecret()
+		if err != nil {
+			return nil, err
+		}
+		secret = generated
+	}
+
+	now := time.Now().UTC()
+	subscription := &domain.WebhookSubscription{
+		ID:           uuid.New().String(),
+		URL:          cmd.URL,
+		Secret:       secret,
+		EventNames:   cmd.EventNames,
+		ParamFilters: toParamFilters(cmd.ParamFilters),
+		Enabled:      true,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+
+	if err := s.repo.Create(ctx, subscription); err != nil {
+		return nil, fmt.Errorf("failed to create webhook subscription: %w", err)
+	}
+	s.changed()
+
+	dto := FromSubscription(subscription)
+	dto.Secret = secret
+	return dto, nil
+}
+
+// UpdateSubscription replaces a subscription's settings
+func (s *WebhookService) UpdateSubscription(ctx context.Context, cmd *UpdateSubscriptionCommand) (*SubscriptionDTO, error) {
+	subscription, err := s.repo.FindByID(ctx, cmd.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if cmd.Enabled && !subscription.Enabled {
+		subscription.ConsecutiveFailures = 0
+		subscription.DisabledReason = ""
+	}
+	if !cmd.Enabled && subscription.Enabled {
+		subscription.DisabledReason = "disabled via API"
+	}
+
+	subscription.URL = cmd.URL
+	subscription.EventNames = cmd.EventNames
+	subscription.ParamFilters = toParamFilters(cmd.ParamFilters)
+	subscription.Enabled = cmd.Enabled
+	if cmd.Secret != "" {
+		subscription.Secret = cmd.Secret
+	}
+	subscription.UpdatedAt = time.Now().UTC()
+
+	if err := s.repo.Update(ctx, subscription); err != nil {
+		return nil, fmt.Errorf("failed to update webhook subscription: %w", err)
+	}
+	s.changed()
+
+	dto := FromSubscription(subscription)
+	if cmd.Secret != "" {
+		dto.Secret = cmd.Secret
+	}
+	return dto, nil
+}
+
+// DeleteSubscription removes a subscription and its delivery history
+func (s *WebhookService) DeleteSubscription(ctx context.Context, id string) error {
+	if _, err := s.repo.FindByID(ctx, id); err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete webhook subscription: %w", err)
+	}
+	s.changed()
+
+	return nil
+}
+
+// GetSubscription returns a single subscription
+func (s *WebhookService) GetSubscription(ctx context.Context, id string) (*SubscriptionDTO, error) {
+	subscription, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return FromSubscription(subscription), nil
+}
+
+// ListSubscriptions returns all subscriptions
+func (s *WebhookService) ListSubscriptions(ctx context.Context) ([]*SubscriptionDTO, error) {
+	subscriptions, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook subscriptions: %w", err)
+	}
+
+	dtos := make([]*SubscriptionDTO, len(subscriptions))
+	for i, subscription := range subscriptions {
+		dtos[i] = FromSubscription(subscription)
+	}
+	return dtos, nil
+}
+
+// ListDeliveries returns a subscription's delivery attempts, most recent first
+func (s *WebhookService) ListDeliveries(ctx context.Context, query *ListDeliveriesQuery) ([]DeliveryDTO, error) {
+	if _, err := s.repo.FindByID(ctx, query.SubscriptionID); err != nil {
+		return nil, err
+	}
+
+	deliveries, err := s.repo.ListDeliveries(ctx, query.SubscriptionID, query.Limit, query.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+
+	return FromDeliveries(deliveries), nil
+}
+
+func (s *WebhookService) changed() {
+	if s.onChange != nil {
+		s.onChange()
+	}
+}
+
+func toParamFilters(dtos []ParamFilterDTO) []domain.WebhookParamFilter {
+	filters := make([]domain.WebhookParamFilter, len(dtos))
+	for i, f := range dtos {
+		filters[i] = domain.WebhookParamFilter{Key: f.Key, Value: f.Value}
+	}
+	return filters
+}
+
+// newSecret returns a random 32-byte hex-encoded signing secret
+func newSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
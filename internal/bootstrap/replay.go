@@ -0,0 +1,99 @@
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ebubekir/event-stream/internal/adapter/outbound/archive"
+	chRepo "github.com/ebubekir/event-stream/internal/adapter/outbound/persistence/clickhouse"
+	pgRepo "github.com/ebubekir/event-stream/internal/adapter/outbound/persistence/postgres"
+	replayApp "github.com/ebubekir/event-stream/internal/application/replay"
+	eventDomain "github.com/ebubekir/event-stream/internal/domain/event"
+	"github.com/ebubekir/event-stream/pkg/clickhouse"
+	"github.com/ebubekir/event-stream/pkg/config"
+	"github.com/ebubekir/event-stream/pkg/postgresql"
+)
+
+// Replay sources besides the database types
+const (
+	ReplaySourceArchive    = "archive"
+	ReplaySourceQuarantine = "quarantine"
+)
+
+// ReplaySources configures where replays can read from
+type ReplaySources struct {
+	// ArchiveDir is the local raw event archive; empty uses replays.archive_dir,
+	// or archive.dir when the archive is written locally
+	ArchiveDir string
+	// ArchiveSlack is how far outside a window archive partitions are still read
+	ArchiveSlack time.Duration
+	// Quarantine holds the events read by the quarantine source
+	Quarantine eventDomain.QuarantineRepository
+}
+
+// NewReplaySourceOpener returns a SourceOpener for the archive, the
+// quarantine and the database types other than the configured one
+func NewReplaySourceOpener(cfg *config.AppConfig, sources ReplaySources) replayApp.SourceOpener {
+	if sources.ArchiveDir == "" {
+		sources.ArchiveDir = cfg.Replays.ArchiveDir
+	}
+	if sources.ArchiveDir == "" && cfg.Archive.Type == "local" {
+		sources.ArchiveDir = cfg.Archive.Dir
+	}
+
+	return func(ctx context.Context, source string) (eventDomain.EventSource, error) {
+		switch source {
+		case ReplaySourceArchive:
+			if sources.ArchiveDir == "" {
+				return nil, fmt.Errorf("%w: no archive directory is configured", replayApp.ErrInvalidReplay)
+			}
+			return archive.NewReader(sources.ArchiveDir, sources.ArchiveSlack), nil
+
+		case ReplaySourceQuarantine:
+			return replayApp.NewQuarantineSource(sources.Quarantine), nil
+
+		case string(config.DatabaseTypePostgres), string(config.DatabaseTypeClickhouse):
+			if source == string(cfg.DatabaseType) {
+				return nil, fmt.Errorf("%w: %s is the target database", replayApp.ErrInvalidReplay, source)
+			}
+			return openEventSource(cfg, config.SupportedDatabaseType(source))
+
+		default:
+			return nil, fmt.Errorf("%w: unknown source %q", replayApp.ErrInvalidReplay, source)
+		}
+	}
+}
+
+// closingSource is a database event source that closes its connection
+type closingSource struct {
+	eventDomain.EventSource
+	close func() error
+}
+
+func (s *closingSource) Close() error {
+	return s.close()
+}
+
+// openEventSource connects to a database read as a replay source. Unlike
+// OpenStorage it does not run migrations, so the source is left as it is.
+func openEventSource(cfg *config.AppConfig, databaseType config.SupportedDatabaseType) (eventDomain.EventSource, error) {
+	switch databaseType {
+	case config.DatabaseTypePostgres:
+		db := postgresql.New(cfg.PostgresSQLUrl, "public")
+		if err := db.CheckConnection(); err != nil {
+			return nil, fmt.Errorf("failed to connect to PostgreSQL: %w", err)
+		}
+		return &closingSource{EventSource: pgRepo.NewEventRepository(db), close: db.Close}, nil
+
+	case config.DatabaseTypeClickhouse:
+		db := clickhouse.New(cfg.ClickhouseUrl, "default")
+		if err := db.CheckConnection(); err != nil {
+			return nil, fmt.Errorf("failed to connect to ClickHouse: %w", err)
+		}
+		return &closingSource{EventSource: chRepo.NewEventRepository(db), close: db.Close}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported database type: %s", databaseType)
+	}
+}
@@ -0,0 +1,90 @@
+// Package bootstrap wires adapters from configuration for the binaries in cmd/
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	chRepo "github.com/ebubekir/event-stream/internal/adapter/outbound/persistence/clickhouse"
+	pgRepo "github.com/ebubekir/event-stream/internal/adapter/outbound/persistence/postgres"
+	eventDomain "github.com/ebubekir/event-stream/internal/domain/event"
+	chMigrations "github.com/ebubekir/event-stream/migrations/clickhouse"
+	pgMigrations "github.com/ebubekir/event-stream/migrations/postgres"
+	"github.com/ebubekir/event-stream/pkg/clickhouse"
+	"github.com/ebubekir/event-stream/pkg/config"
+	"github.com/ebubekir/event-stream/pkg/postgresql"
+)
+
+// Storage holds the repositories of the configured database
+type Storage struct {
+	Events     eventDomain.EventRepository
+	Metrics    eventDomain.EventMetricsReader
+	Identities eventDomain.IdentityRepository
+	Quarantine eventDomain.QuarantineRepository
+	Webhooks   eventDomain.WebhookRepository
+
+	db io.Closer
+}
+
+// Close closes the database connection
+func (s *Storage) Close() error {
+	return s.db.Close()
+}
+
+// OpenStorage connects to the configured database, runs its migrations and
+// returns its repositories
+func OpenStorage(ctx context.Context, cfg *config.AppConfig) (*Storage, error) {
+	switch cfg.DatabaseType {
+	case config.DatabaseTypePostgres:
+		db := postgresql.New(cfg.PostgresSQLUrl, "public")
+		if err := db.CheckConnection(); err != nil {
+			return nil, fmt.Errorf("failed to connect to PostgreSQL: %w", err)
+		}
+
+		// Run PostgreSQL migrations
+		migrator, err := postgresql.NewMigrator(db, pgMigrations.MigrationFS)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create migrator: %w", err)
+		}
+		if err := migrator.Up(ctx); err != nil {
+			return nil, fmt.Errorf("failed to run migrations: %w", err)
+		}
+
+		return &Storage{
+			Events:     pgRepo.NewEventRepository(db),
+			Metrics:    pgRepo.NewMetricsReader(db),
+			Identities: pgRepo.NewIdentityRepository(db),
+			Quarantine: pgRepo.NewQuarantineRepository(db),
+			Webhooks:   pgRepo.NewWebhookRepository(db),
+			db:         db,
+		}, nil
+
+	case config.DatabaseTypeClickhouse:
+		db := clickhouse.New(cfg.ClickhouseUrl, "default")
+		if err := db.CheckConnection(); err != nil {
+			return nil, fmt.Errorf("failed to connect to ClickHouse: %w", err)
+		}
+
+		// Run ClickHouse migrations
+		migrator, err := clickhouse.NewMigrator(db, chMigrations.MigrationFS)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create migrator: %w", err)
+		}
+		if err := migrator.Up(ctx); err != nil {
+			return nil, fmt.Errorf("failed to run migrations: %w", err)
+		}
+
+		return &Storage{
+			Events:     chRepo.NewEventRepository(db),
+			Metrics:    chRepo.NewMetricsReader(db),
+			Identities: chRepo.NewIdentityRepository(db),
+			Quarantine: chRepo.NewQuarantineRepository(db),
+			Webhooks:   chRepo.NewWebhookRepository(db),
+			db:         db,
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported database type: %s", cfg.DatabaseType)
+	}
+}
@@ -1,5 +1,7 @@
 package domain
 
+import "time"
+
 type ChannelType string
 
 const (
@@ -60,6 +62,18 @@ type Item struct {
 	Params        []Param
 }
 
+// IngestionMetadata describes how an event reached the server.
+// It is populated server-side and never taken from the event payload.
+type IngestionMetadata struct {
+	ReceivedAt   time.Time
+	Endpoint     string
+	RequestID    string
+	ClientIPHash string
+	SDKName      string
+	SDKVersion   string
+	APIKeyID     string
+}
+
 type Event struct {
 	ID                string
 	Timestamp         int64
@@ -72,6 +86,13 @@ type Event struct {
 	UserPseudoID string
 	UserParams   []Param
 	Device       Device
+	Geo          Geo
 	AppInfo      AppInfo
 	Items        []Item
+	Ingestion    IngestionMetadata
+}
+
+// OccurredAt parses the event date. Dates are expected in RFC3339 format.
+func (e *Event) OccurredAt() (time.Time, error) {
+	return time.Parse(time.RFC3339, e.Date)
 }
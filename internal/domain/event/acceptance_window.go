@@ -0,0 +1,60 @@
+package event
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrEventOutOfWindow is returned when an event is rejected because its date
+// falls outside the acceptance window
+var ErrEventOutOfWindow = errors.New("event date is outside the acceptance window")
+
+// OutOfWindowAction defines what happens to events dated outside the acceptance window
+type OutOfWindowAction string
+
+const (
+	OutOfWindowAccept     OutOfWindowAction = "accept"
+	OutOfWindowClamp      OutOfWindowAction = "clamp"
+	OutOfWindowReject     OutOfWindowAction = "reject"
+	OutOfWindowQuarantine OutOfWindowAction = "quarantine"
+)
+
+// AcceptanceWindow bounds how late or how far in the future an event may be dated
+// relative to the time it is received. A zero duration disables that bound.
+type AcceptanceWindow struct {
+	MaxLateness   time.Duration
+	MaxFutureSkew time.Duration
+	Action        OutOfWindowAction
+}
+
+// Check reports whether date is inside the window at now.
+// When it is not, the returned reason describes the violation.
+func (w AcceptanceWindow) Check(date, now time.Time) (string, bool) {
+	if w.MaxLateness > 0 && date.Before(now.Add(-w.MaxLateness)) {
+		return fmt.Sprintf("dated %s, more than %s late", date.UTC().Format(time.RFC3339), w.MaxLateness), false
+	}
+
+	if w.MaxFutureSkew > 0 && date.After(now.Add(w.MaxFutureSkew)) {
+		return fmt.Sprintf("dated %s, more than %s in the future", date.UTC().Format(time.RFC3339), w.MaxFutureSkew), false
+	}
+
+	return "", true
+}
+
+// Clamp moves date onto the nearest edge of the window at now
+func (w AcceptanceWindow) Clamp(date, now time.Time) time.Time {
+	if w.MaxLateness > 0 {
+		if earliest := now.Add(-w.MaxLateness); date.Before(earliest) {
+			return earliest
+		}
+	}
+
+	if w.MaxFutureSkew > 0 {
+		if latest := now.Add(w.MaxFutureSkew); date.After(latest) {
+			return latest
+		}
+	}
+
+	return date
+}
@@ -0,0 +1,42 @@
+package event
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAcceptanceWindow(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	window := AcceptanceWindow{MaxLateness: 24 * time.Hour, MaxFutureSkew: 5 * time.Minute}
+
+	tests := []struct {
+		name        string
+		window      AcceptanceWindow
+		date        time.Time
+		wantOK      bool
+		wantClamped time.Time
+	}{
+		{name: "now", window: window, date: now, wantOK: true, wantClamped: now},
+		{name: "late edge", window: window, date: now.Add(-24 * time.Hour), wantOK: true, wantClamped: now.Add(-24 * time.Hour)},
+		{name: "too late", window: window, date: now.Add(-25 * time.Hour), wantClamped: now.Add(-24 * time.Hour)},
+		{name: "future edge", window: window, date: now.Add(5 * time.Minute), wantOK: true, wantClamped: now.Add(5 * time.Minute)},
+		{name: "too far ahead", window: window, date: now.Add(time.Hour), wantClamped: now.Add(5 * time.Minute)},
+		{name: "no lateness bound", window: AcceptanceWindow{MaxFutureSkew: time.Minute}, date: now.AddDate(-1, 0, 0), wantOK: true, wantClamped: now.AddDate(-1, 0, 0)},
+		{name: "no future bound", window: AcceptanceWindow{MaxLateness: time.Hour}, date: now.AddDate(1, 0, 0), wantOK: true, wantClamped: now.AddDate(1, 0, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, ok := tt.window.Check(tt.date, now)
+			if ok != tt.wantOK {
+				t.Errorf("Check() = %v, want %v", ok, tt.wantOK)
+			}
+			if ok != (reason == "") {
+				t.Errorf("Check() reason = %q with ok %v", reason, ok)
+			}
+			if got := tt.window.Clamp(tt.date, now); !got.Equal(tt.wantClamped) {
+				t.Errorf("Clamp() = %v, want %v", got, tt.wantClamped)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package event
+
+import "context"
+
+type dedupTokenKey struct{}
+
+// WithDedupToken attaches an idempotency token to ctx. Repositories that
+// support it use the token to drop a repeated write of the same batch,
+// e.g. when a message broker redelivers events that were already stored.
+func WithDedupToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, dedupTokenKey{}, token)
+}
+
+// DedupToken returns the idempotency token attached to ctx, if any
+func DedupToken(ctx context.Context) string {
+	token, _ := ctx.Value(dedupTokenKey{}).(string)
+	return token
+}
+
+type skipStoredKey struct{}
+
+// WithSkipStored marks a write whose events have deterministic IDs, e.g.
+// derived from a broker offset or kept from a replayed source. Repositories drop the events whose ID is
+// already stored, so a redelivered event is stored once however the
+// redelivered range is cut into batches.
+func WithSkipStored(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipStoredKey{}, true)
+}
+
+// SkipStored reports whether events already stored must be dropped from a write
+func SkipStored(ctx context.Context) bool {
+	skip, _ := ctx.Value(skipStoredKey{}).(bool)
+	return skip
+}
@@ -0,0 +1,116 @@
+package event
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrInvalidGroupBy is returned when a group by names an unknown or repeated dimension
+var ErrInvalidGroupBy = errors.New("invalid group by")
+
+// OtherGroupKey is the key of the group that collects the values beyond a
+// MetricsQuery.GroupLimit
+const OtherGroupKey = "Other"
+
+// GroupKeySeparator joins the keys of a multi-dimension group into its GroupKey
+const GroupKeySeparator = "|"
+
+// Dimension is one key grouped metrics are broken down by: a built-in
+// aggregation, or a field of the event as in field filters
+type Dimension struct {
+	// Aggregation is set for the built-in channel, event_name and time bucket dimensions
+	Aggregation AggregationType
+	// Field is the event field grouped by when Aggregation is empty
+	Field Field
+}
+
+// ParseDimension parses a built-in aggregation or the text form of a field,
+// e.g. "daily", "device.browser_name" or "event_param.currency"
+func ParseDimension(s string) (Dimension, error) {
+	aggregation := AggregationType(s)
+	if aggregation == AggregationByChannel || aggregation == AggregationByEventName || slices.Contains(TimeAggregations, aggregation) {
+		return Dimension{Aggregation: aggregation}, nil
+	}
+
+	field, err := ParseField(s)
+	if err != nil {
+		return Dimension{}, fmt.Errorf("%w: unknown dimension %q", ErrInvalidGroupBy, s)
+	}
+	return Dimension{Field: field}, nil
+}
+
+// ParseGroupBy parses a comma-separated list of dimensions, e.g. "daily,channel"
+func ParseGroupBy(s string) ([]Dimension, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	var dimensions []Dimension
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(s, ",") {
+		dimension, err := ParseDimension(strings.TrimSpace(part))
+		if err != nil {
+			return nil, err
+		}
+		if seen[dimension.String()] {
+			return nil, fmt.Errorf("%w: %s is repeated", ErrInvalidGroupBy, dimension)
+		}
+		seen[dimension.String()] = true
+		dimensions = append(dimensions, dimension)
+	}
+	return dimensions, nil
+}
+
+// FillMode controls the time buckets without events in grouped metrics
+type FillMode string
+
+const (
+	FillNone FillMode = "none" // leave them out
+	FillZero FillMode = "zero" // return them with zero counts
+	FillNull FillMode = "null" // return them without counts
+)
+
+// ResolveFill checks a fill mode against the dimensions it applies to. Filling
+// needs exactly one time dimension; an empty mode fills with zeros when there
+// is one and leaves gaps otherwise.
+func ResolveFill(fill FillMode, groupBy []Dimension) (FillMode, error) {
+	timeDimensions := 0
+	for _, dimension := range groupBy {
+		if dimension.IsTime() {
+			timeDimensions++
+		}
+	}
+
+	switch fill {
+	case "":
+		if timeDimensions == 1 {
+			return FillZero, nil
+		}
+		return FillNone, nil
+	case FillNone:
+		return FillNone, nil
+	case FillZero, FillNull:
+		if timeDimensions != 1 {
+			return "", fmt.Errorf("%w: fill %s needs exactly one time dimension", ErrInvalidGroupBy, fill)
+		}
+		return fill, nil
+	default:
+		return "", fmt.Errorf("%w: unknown fill %q", ErrInvalidGroupBy, fill)
+	}
+}
+
+// IsTime reports whether the dimension is a time bucket. Time buckets are
+// never folded into the OtherGroupKey group.
+func (d Dimension) IsTime() bool {
+	return slices.Contains(TimeAggregations, d.Aggregation)
+}
+
+// String returns the text form of the dimension
+func (d Dimension) String() string {
+	if d.Aggregation != "" {
+		return string(d.Aggregation)
+	}
+	return d.Field.String()
+}
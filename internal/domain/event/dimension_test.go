@@ -0,0 +1,92 @@
+package event
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestParseGroupBy(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    []Dimension
+		wantErr bool
+	}{
+		{value: "", want: nil},
+		{value: "daily", want: []Dimension{{Aggregation: AggregationByDaily}}},
+		{
+			value: "daily, channel,event_name",
+			want:  []Dimension{{Aggregation: AggregationByDaily}, {Aggregation: AggregationByChannel}, {Aggregation: AggregationByEventName}},
+		},
+		{
+			value: "device.browser_name,event_param.currency",
+			want:  []Dimension{{Field: Field{Scope: FieldScopeDevice, Name: "browser_name"}}, {Field: Field{Scope: FieldScopeEventParam, Name: "currency"}}},
+		},
+		{value: "daily,daily", wantErr: true},
+		{value: "event_param.plan,event_param.plan", wantErr: true},
+		{value: "yearly", wantErr: true},
+		{value: "daily,", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseGroupBy(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseGroupBy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if !errors.Is(err, ErrInvalidGroupBy) {
+					t.Errorf("ParseGroupBy() error = %v, want ErrInvalidGroupBy", err)
+				}
+				return
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ParseGroupBy() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveFill(t *testing.T) {
+	daily := Dimension{Aggregation: AggregationByDaily}
+	hourly := Dimension{Aggregation: AggregationByHourly}
+	channel := Dimension{Aggregation: AggregationByChannel}
+
+	tests := []struct {
+		name    string
+		fill    FillMode
+		groupBy []Dimension
+		want    FillMode
+		wantErr bool
+	}{
+		{name: "default with time", groupBy: []Dimension{daily, channel}, want: FillZero},
+		{name: "default without time", groupBy: []Dimension{channel}, want: FillNone},
+		{name: "default with two times", groupBy: []Dimension{daily, hourly}, want: FillNone},
+		{name: "default without group by", want: FillNone},
+		{name: "none", fill: FillNone, groupBy: []Dimension{daily}, want: FillNone},
+		{name: "none without time", fill: FillNone, groupBy: []Dimension{channel}, want: FillNone},
+		{name: "zero", fill: FillZero, groupBy: []Dimension{daily}, want: FillZero},
+		{name: "null", fill: FillNull, groupBy: []Dimension{channel, daily}, want: FillNull},
+		{name: "zero without time", fill: FillZero, groupBy: []Dimension{channel}, wantErr: true},
+		{name: "null with two times", fill: FillNull, groupBy: []Dimension{daily, hourly}, wantErr: true},
+		{name: "unknown", fill: "previous", groupBy: []Dimension{daily}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveFill(tt.fill, tt.groupBy)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveFill() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if !errors.Is(err, ErrInvalidGroupBy) {
+					t.Errorf("ResolveFill() error = %v, want ErrInvalidGroupBy", err)
+				}
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ResolveFill() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
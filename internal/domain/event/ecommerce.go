@@ -0,0 +1,140 @@
+package event
+
+import "time"
+
+// DefaultPurchaseEventNames are the events counted as orders when an
+// EcommerceQuery names none
+var DefaultPurchaseEventNames = []string{"purchase"}
+
+// EcommerceQuery selects the purchase events ecommerce metrics are computed
+// over. Each purchase event is one order of its items.
+type EcommerceQuery struct {
+	EventNames []string
+	From       time.Time
+	To         time.Time
+	// Location is the time zone time buckets are computed in; nil is UTC
+	Location *time.Location
+	// Interval is the time bucket aggregation series are broken down by;
+	// empty returns totals only
+	Interval AggregationType
+	// ResolveIdentity counts purchasing users by their canonical identity
+	ResolveIdentity bool
+	// Filters must all match for a purchase event to be counted
+	Filters []FieldFilter
+}
+
+// TimeZone returns the IANA name of the query's time zone
+func (q *EcommerceQuery) TimeZone() string {
+	if q.Location == nil {
+		return time.UTC.String()
+	}
+	return q.Location.String()
+}
+
+// MetricsQuery returns the metrics query counting the same purchase events
+func (q *EcommerceQuery) MetricsQuery() *MetricsQuery {
+	return &MetricsQuery{
+		EventNames:      q.EventNames,
+		From:            q.From,
+		To:              q.To,
+		Location:        q.Location,
+		ResolveIdentity: q.ResolveIdentity,
+		Filters:         q.Filters,
+	}
+}
+
+// EcommerceMetrics are the revenue metrics of a set of orders
+type EcommerceMetrics struct {
+	// Revenue is the sum of the items' revenue in USD
+	Revenue         float64
+	Orders          int64
+	PurchasingUsers int64
+	// UnitsSold is the sum of the items' quantities
+	UnitsSold int64
+}
+
+// AverageOrderValue returns the revenue per order, or zero without orders
+func (m EcommerceMetrics) AverageOrderValue() float64 {
+	if m.Orders == 0 {
+		return 0
+	}
+	return m.Revenue / float64(m.Orders)
+}
+
+// EcommerceBucket holds the metrics of the orders of one time bucket
+type EcommerceBucket struct {
+	// Bucket is keyed as time bucket group keys are
+	Bucket string
+	EcommerceMetrics
+}
+
+// EcommerceResult represents the result of an ecommerce metrics query
+type EcommerceResult struct {
+	EventNames []string
+	Interval   AggregationType
+	Location   *time.Location
+	From       time.Time
+	To         time.Time
+	Totals     EcommerceMetrics
+	// Buckets holds the buckets with orders, oldest first, with an Interval
+	Buckets []EcommerceBucket
+}
+
+// ItemDimension is the item field top items are ranked by
+type ItemDimension string
+
+const (
+	ItemDimensionItem    ItemDimension = "item" // by item ID, or name for items without one
+	ItemDimensionBrand   ItemDimension = "brand"
+	ItemDimensionVariant ItemDimension = "variant"
+)
+
+// ItemSort is the value top items are ranked by
+type ItemSort string
+
+const (
+	ItemSortRevenue  ItemSort = "revenue"
+	ItemSortQuantity ItemSort = "quantity"
+)
+
+// TopItemsQuery ranks the items of the purchase events selected by
+// EcommerceQuery. Items without a value for By are left out.
+type TopItemsQuery struct {
+	EcommerceQuery
+	By     ItemDimension
+	SortBy ItemSort
+	Limit  int
+}
+
+// ItemMetrics are the sales of one item, brand or variant
+type ItemMetrics struct {
+	// Key is the item ID, brand or variant
+	Key string
+	// Name is the item's name when ranking by item
+	Name     string
+	Revenue  float64
+	Quantity int64
+	Orders   int64
+	// Series holds the buckets with sales, oldest first, with an Interval
+	Series []ItemBucket
+}
+
+// ItemBucket holds the sales of an item in one time bucket
+type ItemBucket struct {
+	Bucket   string
+	Revenue  float64
+	Quantity int64
+}
+
+// TopItemsResult represents the result of a top items query
+type TopItemsResult struct {
+	EventNames []string
+	By         ItemDimension
+	SortBy     ItemSort
+	Interval   AggregationType
+	Location   *time.Location
+	From       time.Time
+	To         time.Time
+	// Items are ranked by SortBy, highest first
+	Items []ItemMetrics
+}
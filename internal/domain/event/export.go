@@ -0,0 +1,89 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/ebubekir/event-stream/internal/domain"
+)
+
+var (
+	// ErrExportNotFound is returned when an export job does not exist
+	ErrExportNotFound = errors.New("export not found")
+
+	// ErrExportNotReady is returned when the output of an unfinished export is requested
+	ErrExportNotReady = errors.New("export has not succeeded")
+)
+
+// ExportFormat is the file format events are exported in
+type ExportFormat string
+
+const (
+	ExportFormatNDJSON  ExportFormat = "ndjson"
+	ExportFormatCSV     ExportFormat = "csv"
+	ExportFormatParquet ExportFormat = "parquet"
+)
+
+// ExportStatus is the state of an export job
+type ExportStatus string
+
+const (
+	ExportStatusPending   ExportStatus = "pending"
+	ExportStatusRunning   ExportStatus = "running"
+	ExportStatusSucceeded ExportStatus = "succeeded"
+	ExportStatusFailed    ExportStatus = "failed"
+)
+
+// ExportJob describes an asynchronous export of stored events to a file
+type ExportJob struct {
+	ID     string
+	Format ExportFormat
+	Filter EventFilter
+	// Columns lists the param keys flattened into CSV columns
+	Columns    ParamKeys
+	Status     ExportStatus
+	EventCount int64
+	Error      string
+	CreatedAt  time.Time
+	StartedAt  time.Time
+	FinishedAt time.Time
+}
+
+// ExportEncoder writes events to an export file in a single format
+type ExportEncoder interface {
+	// Encode writes one event
+	Encode(event *domain.Event) error
+
+	// Close writes any buffered output and the file footer, if the format has one
+	Close() error
+}
+
+// ExportStore defines the contract for storing export jobs and their output files
+// This interface lives in domain layer - implementations in adapter/outbound
+type ExportStore interface {
+	// SaveJob creates or replaces a job
+	SaveJob(ctx context.Context, job *ExportJob) error
+
+	// FindJob returns the job with the given ID or ErrExportNotFound
+	FindJob(ctx context.Context, id string) (*ExportJob, error)
+
+	// ListJobs returns all jobs, most recent first
+	ListJobs(ctx context.Context) ([]*ExportJob, error)
+
+	// Create returns a writer for the job's output. The output becomes
+	// readable once the writer is closed; Abort discards it.
+	Create(ctx context.Context, job *ExportJob) (ExportFile, error)
+
+	// Open returns the job's output
+	Open(ctx context.Context, job *ExportJob) (io.ReadCloser, error)
+}
+
+// ExportFile is an export output being written
+type ExportFile interface {
+	io.WriteCloser
+
+	// Abort discards the partially written output
+	Abort() error
+}
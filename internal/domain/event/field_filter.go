@@ -0,0 +1,267 @@
+package event
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidFilter is returned when a field filter names an unknown field or
+// operator, or its values do not suit the operator
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// FieldScope is the part of an event a field belongs to
+type FieldScope string
+
+const (
+	FieldScopeEventParam FieldScope = "event_param"
+	FieldScopeUserParam  FieldScope = "user_param"
+	FieldScopeDevice     FieldScope = "device"
+	FieldScopeAppInfo    FieldScope = "app_info"
+	FieldScopeChannel    FieldScope = "channel_type"
+)
+
+// DeviceFields are the device field names, as in the JSON event payload
+var DeviceFields = []string{
+	"category",
+	"mobile_brand_name",
+	"mobile_model_name",
+	"operating_system",
+	"operating_system_version",
+	"language",
+	"browser_name",
+	"browser_version",
+	"hostname",
+}
+
+// AppInfoFields are the app info field names, as in the JSON event payload
+var AppInfoFields = []string{"id", "version"}
+
+// Field references a value of an event: a param by key, a device or app info
+// field, or the channel type. Its text form is "event_param.<key>",
+// "user_param.<key>", "device.<field>", "app_info.<field>" or "channel_type".
+type Field struct {
+	Scope FieldScope
+	// Name is the param key or field name; empty for the channel type
+	Name string
+}
+
+// ParseField parses the text form of a field
+func ParseField(s string) (Field, error) {
+	if s == string(FieldScopeChannel) {
+		return Field{Scope: FieldScopeChannel}, nil
+	}
+
+	scope, name, ok := strings.Cut(s, ".")
+	if !ok || name == "" {
+		return Field{}, fmt.Errorf("%w: unknown field %q", ErrInvalidFilter, s)
+	}
+
+	field := Field{Scope: FieldScope(scope), Name: name}
+	switch field.Scope {
+	case FieldScopeEventParam, FieldScopeUserParam:
+		return field, nil
+	case FieldScopeDevice:
+		if slices.Contains(DeviceFields, name) {
+			return field, nil
+		}
+	case FieldScopeAppInfo:
+		if slices.Contains(AppInfoFields, name) {
+			return field, nil
+		}
+	}
+	return Field{}, fmt.Errorf("%w: unknown field %q", ErrInvalidFilter, s)
+}
+
+// IsParam reports whether the field is an event or user param
+func (f Field) IsParam() bool {
+	return f.Scope == FieldScopeEventParam || f.Scope == FieldScopeUserParam
+}
+
+// String returns the text form of the field
+func (f Field) String() string {
+	if f.Scope == FieldScopeChannel {
+		return string(f.Scope)
+	}
+	return string(f.Scope) + "." + f.Name
+}
+
+// FilterOperator compares a field with a filter's values
+type FilterOperator string
+
+const (
+	FilterEquals         FilterOperator = "eq"
+	FilterNotEquals      FilterOperator = "neq"
+	FilterIn             FilterOperator = "in"
+	FilterContains       FilterOperator = "contains"
+	FilterRegex          FilterOperator = "regex"
+	FilterGreater        FilterOperator = "gt"
+	FilterGreaterOrEqual FilterOperator = "gte"
+	FilterLess           FilterOperator = "lt"
+	FilterLessOrEqual    FilterOperator = "lte"
+	FilterExists         FilterOperator = "exists"
+)
+
+// IsNumeric reports whether the operator compares number values
+func (o FilterOperator) IsNumeric() bool {
+	switch o {
+	case FilterGreater, FilterGreaterOrEqual, FilterLess, FilterLessOrEqual:
+		return true
+	}
+	return false
+}
+
+// FieldFilter selects events by comparing one field with its values.
+//
+// Params match eq, neq and in by their string value, by their number value
+// when the filter value is a number, or by a true boolean value for "true".
+// neq also matches events without the param. contains and regex look at
+// string values, the numeric operators at number values, and exists matches
+// events that have the param. Device, app info and channel fields are
+// strings; exists matches a non-empty value and numeric operators do not apply.
+type FieldFilter struct {
+	Field    Field
+	Operator FilterOperator
+	Values   []string
+}
+
+// Validate checks that the operator suits the field and has the right values
+func (f FieldFilter) Validate() error {
+	if _, err := ParseField(f.Field.String()); err != nil {
+		return err
+	}
+
+	switch f.Operator {
+	case FilterExists:
+		if len(f.Values) != 0 {
+			return fmt.Errorf("%w: %s %s takes no value", ErrInvalidFilter, f.Field, f.Operator)
+		}
+		return nil
+
+	case FilterIn:
+		if len(f.Values) == 0 {
+			return fmt.Errorf("%w: %s %s needs at least one value", ErrInvalidFilter, f.Field, f.Operator)
+		}
+		return nil
+
+	case FilterEquals, FilterNotEquals, FilterContains, FilterRegex,
+		FilterGreater, FilterGreaterOrEqual, FilterLess, FilterLessOrEqual:
+		if len(f.Values) != 1 {
+			return fmt.Errorf("%w: %s %s needs exactly one value", ErrInvalidFilter, f.Field, f.Operator)
+		}
+
+	default:
+		return fmt.Errorf("%w: unknown operator %q", ErrInvalidFilter, f.Operator)
+	}
+
+	if f.Operator == FilterRegex {
+		if err := validateRegex(f.Values[0]); err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrInvalidFilter, f.Field, err)
+		}
+	}
+	if f.Operator.IsNumeric() {
+		if !f.Field.IsParam() {
+			return fmt.Errorf("%w: %s only applies to params, not %s", ErrInvalidFilter, f.Operator, f.Field)
+		}
+		if _, err := strconv.ParseFloat(f.Values[0], 64); err != nil {
+			return fmt.Errorf("%w: %s %s needs a number, got %q", ErrInvalidFilter, f.Field, f.Operator, f.Values[0])
+		}
+	}
+	return nil
+}
+
+// validateRegex checks that pattern keeps to the regular expression syntax
+// that ClickHouse (RE2) and PostgreSQL (POSIX ARE) read alike: literals, ".",
+// bracket expressions with [:class:] names, "^" and "$", groups with "|",
+// non-capturing "(?:", and the quantifiers "*", "+", "?" and "{n,m}".
+// Backslash escapes only quote punctuation; class escapes such as \d or \b,
+// flags and named groups mean different things or nothing in one of them.
+func validateRegex(pattern string) error {
+	if _, err := regexp.Compile(pattern); err != nil {
+		return err
+	}
+
+	inBracket := false
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		rest := pattern[i+1:]
+
+		if c == '\\' {
+			if rest != "" && isAlphanumeric(rest[0]) {
+				return fmt.Errorf("escape \\%c is not supported; use a bracket expression such as [0-9] or [[:space:]]", rest[0])
+			}
+			i++
+			continue
+		}
+
+		if inBracket {
+			switch {
+			case c == ']':
+				inBracket = false
+			case strings.HasPrefix(rest, ":") && c == '[':
+				// Skip the class name, so its closing ] does not end the expression
+				end := strings.Index(rest, ":]")
+				if end < 0 {
+					return errors.New("unterminated character class name")
+				}
+				i += end + 2
+			case c == '[' && (strings.HasPrefix(rest, ".") || strings.HasPrefix(rest, "=")):
+				return errors.New("collating elements and equivalence classes are not supported")
+			}
+			continue
+		}
+
+		switch c {
+		case '[':
+			inBracket = true
+			// A ] right after [ or [^ is a literal
+			if strings.HasPrefix(rest, "^") {
+				i++
+				rest = rest[1:]
+			}
+			if strings.HasPrefix(rest, "]") {
+				i++
+			}
+		case '(':
+			if strings.HasPrefix(rest, "?") && !strings.HasPrefix(rest, "?:") {
+				return errors.New("flags and named groups are not supported; only (?: groups are")
+			}
+		case '{':
+			end := strings.IndexByte(rest, '}')
+			if end < 0 || !isRepeat(rest[:end]) {
+				return errors.New("a literal { must be escaped as \\{")
+			}
+			i += end + 1
+		}
+	}
+	return nil
+}
+
+// isRepeat reports whether s is the inside of a {n}, {n,} or {n,m} quantifier
+func isRepeat(s string) bool {
+	min, max, _ := strings.Cut(s, ",")
+	if _, err := strconv.Atoi(min); err != nil {
+		return false
+	}
+	if max == "" {
+		return true
+	}
+	_, err := strconv.Atoi(max)
+	return err == nil
+}
+
+func isAlphanumeric(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// Number returns the filter's first value as a number, if it is one
+func (f FieldFilter) Number() (float64, bool) {
+	if len(f.Values) == 0 {
+		return 0, false
+	}
+	number, err := strconv.ParseFloat(f.Values[0], 64)
+	return number, err == nil
+}
@@ -0,0 +1,130 @@
+package event
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseField(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    Field
+		wantErr bool
+	}{
+		{value: "channel_type", want: Field{Scope: FieldScopeChannel}},
+		{value: "event_param.page_title", want: Field{Scope: FieldScopeEventParam, Name: "page_title"}},
+		{value: "user_param.plan.tier", want: Field{Scope: FieldScopeUserParam, Name: "plan.tier"}},
+		{value: "device.browser_name", want: Field{Scope: FieldScopeDevice, Name: "browser_name"}},
+		{value: "app_info.version", want: Field{Scope: FieldScopeAppInfo, Name: "version"}},
+		{value: "device.shoe_size", wantErr: true},
+		{value: "app_info.name", wantErr: true},
+		{value: "event_param.", wantErr: true},
+		{value: "geo.city", wantErr: true},
+		{value: "name", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseField(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseField() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if !errors.Is(err, ErrInvalidFilter) {
+					t.Errorf("ParseField() error = %v, want ErrInvalidFilter", err)
+				}
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseField() = %+v, want %+v", got, tt.want)
+			}
+			if got.String() != tt.value {
+				t.Errorf("String() = %q, want %q", got.String(), tt.value)
+			}
+		})
+	}
+}
+
+func TestFieldFilterValidate(t *testing.T) {
+	param := Field{Scope: FieldScopeEventParam, Name: "value"}
+	device := Field{Scope: FieldScopeDevice, Name: "category"}
+
+	tests := []struct {
+		name    string
+		filter  FieldFilter
+		wantErr bool
+	}{
+		{name: "eq", filter: FieldFilter{Field: device, Operator: FilterEquals, Values: []string{"mobile"}}},
+		{name: "eq without value", filter: FieldFilter{Field: device, Operator: FilterEquals}, wantErr: true},
+		{name: "eq with two values", filter: FieldFilter{Field: device, Operator: FilterEquals, Values: []string{"a", "b"}}, wantErr: true},
+		{name: "in", filter: FieldFilter{Field: device, Operator: FilterIn, Values: []string{"mobile", "tablet"}}},
+		{name: "in without values", filter: FieldFilter{Field: device, Operator: FilterIn}, wantErr: true},
+		{name: "exists", filter: FieldFilter{Field: param, Operator: FilterExists}},
+		{name: "exists with value", filter: FieldFilter{Field: param, Operator: FilterExists, Values: []string{"x"}}, wantErr: true},
+		{name: "gt on param", filter: FieldFilter{Field: param, Operator: FilterGreater, Values: []string{"1.5"}}},
+		{name: "gt on device", filter: FieldFilter{Field: device, Operator: FilterGreater, Values: []string{"1"}}, wantErr: true},
+		{name: "gt with text", filter: FieldFilter{Field: param, Operator: FilterGreater, Values: []string{"many"}}, wantErr: true},
+		{name: "unknown operator", filter: FieldFilter{Field: param, Operator: "like", Values: []string{"x"}}, wantErr: true},
+		{name: "unknown field", filter: FieldFilter{Field: Field{Scope: "geo", Name: "city"}, Operator: FilterEquals, Values: []string{"x"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.filter.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidFilter) {
+				t.Errorf("Validate() error = %v, want ErrInvalidFilter", err)
+			}
+		})
+	}
+}
+
+func TestFieldFilterValidateRegex(t *testing.T) {
+	tests := []struct {
+		pattern string
+		wantErr bool
+	}{
+		{pattern: "^/checkout"},
+		{pattern: "^(home|pricing)$"},
+		{pattern: "(?:a|b)+c"},
+		{pattern: "order-[0-9]{4,}"},
+		{pattern: "v[0-9]{1}\\.[0-9]{1,3}"},
+		{pattern: "[[:alpha:]][[:digit:]]*"},
+		{pattern: "[]a]"},
+		{pattern: "[^]a]"},
+		{pattern: "[(?{]"},
+		{pattern: "\\{\\}\\(\\)\\[\\]\\.\\*"},
+		{pattern: ".*?x"},
+
+		// Valid RE2 that PostgreSQL reads differently or not at all
+		{pattern: "\\d+", wantErr: true},
+		{pattern: "\\bword\\b", wantErr: true},
+		{pattern: "\\pL", wantErr: true},
+		{pattern: "end\\z", wantErr: true},
+		{pattern: "(?i)checkout", wantErr: true},
+		{pattern: "(?P<page>home)", wantErr: true},
+		{pattern: "a{", wantErr: true},
+		{pattern: "a{x}", wantErr: true},
+		{pattern: "[[.a.]]", wantErr: true},
+		{pattern: "[[=a=]]", wantErr: true},
+
+		// Not valid RE2
+		{pattern: "(unclosed", wantErr: true},
+		{pattern: "(?=ahead)", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			filter := FieldFilter{Field: Field{Scope: FieldScopeEventParam, Name: "page"}, Operator: FilterRegex, Values: []string{tt.pattern}}
+			err := filter.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidFilter) {
+				t.Errorf("Validate() error = %v, want ErrInvalidFilter", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,16 @@
+package event
+
+import (
+	"context"
+
+	"github.com/ebubekir/event-stream/internal/domain"
+)
+
+// IdentityRepository defines the contract for the identity graph that maps
+// user_pseudo_id values to their canonical user_id
+// This interface lives in domain layer - implementations in adapter/outbound
+type IdentityRepository interface {
+	// Link records that the given pseudo IDs belong to the given user IDs.
+	// The most recent link for a user_pseudo_id wins.
+	Link(ctx context.Context, links []domain.IdentityLink) error
+}
@@ -0,0 +1,113 @@
+package event
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrInvalidMeasure is returned when a measure names an unknown function or
+// a field that is not a param
+var ErrInvalidMeasure = errors.New("invalid measure")
+
+// MeasureFunction aggregates the values of a param over the counted events
+type MeasureFunction string
+
+const (
+	MeasureSum           MeasureFunction = "sum"
+	MeasureAvg           MeasureFunction = "avg"
+	MeasureMin           MeasureFunction = "min"
+	MeasureMax           MeasureFunction = "max"
+	MeasureP50           MeasureFunction = "p50"
+	MeasureP90           MeasureFunction = "p90"
+	MeasureP95           MeasureFunction = "p95"
+	MeasureP99           MeasureFunction = "p99"
+	MeasureCountDistinct MeasureFunction = "count_distinct"
+)
+
+// MeasureFunctions are the measure functions
+var MeasureFunctions = []MeasureFunction{
+	MeasureSum,
+	MeasureAvg,
+	MeasureMin,
+	MeasureMax,
+	MeasureP50,
+	MeasureP90,
+	MeasureP95,
+	MeasureP99,
+	MeasureCountDistinct,
+}
+
+// Percentiles maps the percentile functions to their levels
+var Percentiles = map[MeasureFunction]float64{
+	MeasureP50: 0.5,
+	MeasureP90: 0.9,
+	MeasureP95: 0.95,
+	MeasureP99: 0.99,
+}
+
+// Measure aggregates a param over the counted events, e.g. the average of
+// event_param.load_time_ms. Its text form is "<function>:<field>".
+//
+// Every function but count_distinct reads the param's number value, leaving
+// out events whose param is missing or has a string value; count_distinct
+// counts the distinct values of the param as field filters compare them.
+type Measure struct {
+	Function MeasureFunction
+	Field    Field
+}
+
+// ParseMeasure parses the text form of a measure, e.g. "avg:event_param.load_time_ms"
+func ParseMeasure(s string) (Measure, error) {
+	function, name, ok := strings.Cut(s, ":")
+	if !ok {
+		return Measure{}, fmt.Errorf("%w: %q is not of the form <function>:<field>", ErrInvalidMeasure, s)
+	}
+
+	measure := Measure{Function: MeasureFunction(function)}
+	if !slices.Contains(MeasureFunctions, measure.Function) {
+		return Measure{}, fmt.Errorf("%w: unknown function %q", ErrInvalidMeasure, function)
+	}
+
+	field, err := ParseField(name)
+	if err != nil || !field.IsParam() {
+		return Measure{}, fmt.Errorf("%w: %q is not an event_param or user_param field", ErrInvalidMeasure, name)
+	}
+	measure.Field = field
+	return measure, nil
+}
+
+// ParseMeasures parses a comma-separated list of measures, e.g.
+// "avg:event_param.load_time_ms,p95:event_param.load_time_ms"
+func ParseMeasures(s string) ([]Measure, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	var measures []Measure
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(s, ",") {
+		measure, err := ParseMeasure(strings.TrimSpace(part))
+		if err != nil {
+			return nil, err
+		}
+		if seen[measure.String()] {
+			return nil, fmt.Errorf("%w: %s is repeated", ErrInvalidMeasure, measure)
+		}
+		seen[measure.String()] = true
+		measures = append(measures, measure)
+	}
+	return measures, nil
+}
+
+// IsPercentile reports whether the measure is one of the percentile functions
+func (m Measure) IsPercentile() bool {
+	_, ok := Percentiles[m.Function]
+	return ok
+}
+
+// String returns the text form of the measure
+func (m Measure) String() string {
+	return string(m.Function) + ":" + m.Field.String()
+}
@@ -0,0 +1,74 @@
+package event
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestParseMeasure(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    Measure
+		wantErr bool
+	}{
+		{value: "avg:event_param.load_time_ms", want: Measure{Function: MeasureAvg, Field: Field{Scope: FieldScopeEventParam, Name: "load_time_ms"}}},
+		{value: "p95:user_param.age", want: Measure{Function: MeasureP95, Field: Field{Scope: FieldScopeUserParam, Name: "age"}}},
+		{value: "count_distinct:event_param.sku", want: Measure{Function: MeasureCountDistinct, Field: Field{Scope: FieldScopeEventParam, Name: "sku"}}},
+		{value: "avg", wantErr: true},
+		{value: "median:event_param.load_time_ms", wantErr: true},
+		{value: "sum:device.category", wantErr: true},
+		{value: "sum:channel_type", wantErr: true},
+		{value: "sum:event_param.", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseMeasure(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseMeasure() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if !errors.Is(err, ErrInvalidMeasure) {
+					t.Errorf("ParseMeasure() error = %v, want ErrInvalidMeasure", err)
+				}
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseMeasure() = %+v, want %+v", got, tt.want)
+			}
+			if got.String() != tt.value {
+				t.Errorf("String() = %q, want %q", got.String(), tt.value)
+			}
+		})
+	}
+}
+
+func TestParseMeasures(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    []string
+		wantErr bool
+	}{
+		{value: "", want: nil},
+		{value: "avg:event_param.load_time_ms, p95:event_param.load_time_ms", want: []string{"avg:event_param.load_time_ms", "p95:event_param.load_time_ms"}},
+		{value: "sum:event_param.value,sum:event_param.value", wantErr: true},
+		{value: "sum:event_param.value,", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseMeasures(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseMeasures() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var names []string
+			for _, measure := range got {
+				names = append(names, measure.String())
+			}
+			if !slices.Equal(names, tt.want) {
+				t.Errorf("ParseMeasures() = %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
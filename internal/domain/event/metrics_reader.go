@@ -2,40 +2,117 @@ package event
 
 import (
 	"context"
+	"strings"
 	"time"
 )
 
-// AggregationType defines how metrics should be grouped
+// AggregationType names the built-in dimensions metrics can be grouped by
 type AggregationType string
 
 const (
-	AggregationByChannel AggregationType = "channel"
-	AggregationByDaily   AggregationType = "daily"
-	AggregationByHourly  AggregationType = "hourly"
+	AggregationByChannel   AggregationType = "channel"
+	AggregationByEventName AggregationType = "event_name"
 )
 
+// Time bucket aggregations, computed in MetricsQuery.Location
+const (
+	AggregationByMinute       AggregationType = "minute"
+	AggregationByFiveMinutes  AggregationType = "5min"
+	AggregationByHourly       AggregationType = "hourly"
+	AggregationByDaily        AggregationType = "daily"
+	AggregationByWeekly       AggregationType = "weekly" // ISO weeks, starting on Monday
+	AggregationByWeeklySunday AggregationType = "weekly_sunday"
+	AggregationByMonthly      AggregationType = "monthly"
+	AggregationByQuarterly    AggregationType = "quarterly"
+)
+
+// TimeAggregations are the time bucket aggregations, finest first
+var TimeAggregations = []AggregationType{
+	AggregationByMinute,
+	AggregationByFiveMinutes,
+	AggregationByHourly,
+	AggregationByDaily,
+	AggregationByWeekly,
+	AggregationByWeeklySunday,
+	AggregationByMonthly,
+	AggregationByQuarterly,
+}
+
+// IngestionFilter narrows metrics to events that arrived through a specific path.
+// Empty fields are not filtered on.
+type IngestionFilter struct {
+	Endpoint   string
+	SDKName    string
+	SDKVersion string
+	APIKeyID   string
+}
+
 // MetricsQuery represents the query parameters for fetching metrics
 type MetricsQuery struct {
-	EventName   string
-	From        time.Time
-	To          time.Time
-	Aggregation AggregationType
+	// EventNames limits the metrics to these events; empty counts every event
+	EventNames []string
+	From       time.Time
+	To         time.Time
+	// Location is the time zone time buckets are computed in; nil is UTC
+	Location *time.Location
+	// GroupBy lists the dimensions grouped metrics are broken down by, one
+	// group per combination of their values; empty returns totals only
+	GroupBy []Dimension
+	// Fill controls the time buckets without events, with a single time dimension
+	Fill FillMode
+	// Measures are aggregated over the counted events, in total and per group
+	Measures []Measure
+	// GroupLimit keeps the GroupLimit most frequent combinations of the
+	// non-time dimensions and counts every other event in an OtherGroupKey
+	// group, per time bucket; zero keeps every combination
+	GroupLimit int
+	// ResolveIdentity counts unique users by their canonical identity,
+	// attributing anonymous events to the user_id linked to their user_pseudo_id
+	ResolveIdentity bool
+	Ingestion       IngestionFilter
+	// Filters must all match for an event to be counted
+	Filters []FieldFilter
+}
+
+// TimeZone returns the IANA name of the query's time zone
+func (q *MetricsQuery) TimeZone() string {
+	if q.Location == nil {
+		return time.UTC.String()
+	}
+	return q.Location.String()
 }
 
 // GroupedMetric represents metrics for a specific group
 type GroupedMetric struct {
-	GroupKey        string
+	// GroupKeys holds the group's value of each dimension, in GroupBy order
+	GroupKeys       []string
 	TotalCount      int64
 	UniqueUserCount int64
+	// MeasureValues holds the group's value of each measure, in Measures
+	// order; nil when no event has a value, as in filled buckets
+	MeasureValues []*float64
+	// Filled marks a time bucket without events added by Fill
+	Filled bool
+}
+
+// GroupKey returns the group's keys joined by GroupKeySeparator, which is
+// the only key for a single dimension
+func (m GroupedMetric) GroupKey() string {
+	return strings.Join(m.GroupKeys, GroupKeySeparator)
 }
 
 // MetricsResult represents the result of a metrics query
 type MetricsResult struct {
-	EventName       string
+	EventNames      []string
+	GroupBy         []Dimension
+	Measures        []Measure
+	Fill            FillMode
+	Location        *time.Location
 	From            time.Time
 	To              time.Time
 	TotalCount      int64
 	UniqueUserCount int64
+	MeasureValues   []*float64 // one per measure, nil when no event has a value
 	GroupedMetrics  []GroupedMetric
 }
 
@@ -44,4 +121,8 @@ type MetricsResult struct {
 type EventMetricsReader interface {
 	// GetMetrics retrieves aggregated metrics for events matching the query
 	GetMetrics(ctx context.Context, query *MetricsQuery) (*MetricsResult, error)
+	// GetEcommerceMetrics retrieves the revenue metrics of the purchase events matching the query
+	GetEcommerceMetrics(ctx context.Context, query *EcommerceQuery) (*EcommerceResult, error)
+	// GetTopItems ranks the items of the purchase events matching the query
+	GetTopItems(ctx context.Context, query *TopItemsQuery) (*TopItemsResult, error)
 }
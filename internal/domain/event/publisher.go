@@ -0,0 +1,15 @@
+package event
+
+import (
+	"context"
+
+	"github.com/ebubekir/event-stream/internal/domain"
+)
+
+// EventPublisher notifies downstream consumers about events after they are persisted
+// This interface lives in domain layer - implementations in adapter/outbound
+type EventPublisher interface {
+	// Publish hands stored events to the publisher. It must not block ingestion:
+	// delivery happens asynchronously and outlives ctx.
+	Publish(ctx context.Context, events []*domain.Event)
+}
@@ -0,0 +1,24 @@
+package event
+
+import (
+	"context"
+
+	"github.com/ebubekir/event-stream/internal/domain"
+)
+
+// QuarantineRepository defines the contract for storing events held back for review
+// This interface lives in domain layer - implementations in adapter/outbound
+type QuarantineRepository interface {
+	// SaveBatch persists quarantined events, leaving out the ones already
+	// quarantined when the write is marked WithSkipStored
+	SaveBatch(ctx context.Context, events []*domain.QuarantinedEvent) error
+
+	// List returns quarantined events, most recently quarantined first
+	List(ctx context.Context, limit, offset int) ([]*domain.QuarantinedEvent, error)
+
+	// FindByIDs returns the quarantined events with the given event IDs
+	FindByIDs(ctx context.Context, ids []string) ([]*domain.QuarantinedEvent, error)
+
+	// Delete removes the quarantined events with the given event IDs
+	Delete(ctx context.Context, ids []string) error
+}
@@ -0,0 +1,93 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ebubekir/event-stream/internal/domain"
+)
+
+var (
+	// ErrReplayNotFound is returned when a replay job does not exist
+	ErrReplayNotFound = errors.New("replay not found")
+
+	// ErrReplayNotRunning is returned when a replay that is not running is cancelled or throttled
+	ErrReplayNotRunning = errors.New("replay is not running")
+
+	// ErrReplayRunning is returned when a running replay is resumed
+	ErrReplayRunning = errors.New("replay is already running")
+
+	// ErrReplaySucceeded is returned when a replay that already succeeded is resumed
+	ErrReplaySucceeded = errors.New("replay has already succeeded")
+
+	// ErrUnboundedDelete is returned by DeleteRange for a filter without both ends of a time range
+	ErrUnboundedDelete = errors.New("refusing to delete events without a time range")
+)
+
+// EventSource is where replayed events are read from. EventRepository
+// implements it, so another backend can be replayed from.
+type EventSource interface {
+	// Stream calls fn for each event matching filter. It stops at the first error fn returns.
+	Stream(ctx context.Context, filter EventFilter, fn func(*domain.Event) error) error
+}
+
+// ConsumedEventSource is an EventSource whose events are removed once they
+// have been replayed, such as the quarantine
+type ConsumedEventSource interface {
+	EventSource
+
+	// Remove deletes the replayed events with the given IDs from the source
+	Remove(ctx context.Context, ids []string) error
+}
+
+// ReplayStatus is the state of a replay job
+type ReplayStatus string
+
+const (
+	ReplayStatusPending   ReplayStatus = "pending"
+	ReplayStatusRunning   ReplayStatus = "running"
+	ReplayStatusSucceeded ReplayStatus = "succeeded"
+	ReplayStatusFailed    ReplayStatus = "failed"
+	ReplayStatusCancelled ReplayStatus = "cancelled"
+)
+
+// ReplayJob describes re-ingesting the events of a time window from a source.
+// The window is replayed in chunks; Checkpoint records how far it got, so an
+// interrupted job resumes with the first unfinished chunk.
+type ReplayJob struct {
+	ID     string
+	Source string
+	Filter EventFilter
+	// Reprocess runs events through the current ingestion pipeline instead of storing them as read
+	Reprocess bool
+	// DeleteFirst removes the target's events matching the filter chunk by chunk before replaying them
+	DeleteFirst bool
+	// Rate limits replayed events per second; 0 is unlimited
+	Rate      float64
+	ChunkSize time.Duration
+	// Checkpoint is the end of the last completed chunk; From before the first
+	Checkpoint time.Time
+	// Runs counts how often the job was started or resumed
+	Runs   int
+	Status ReplayStatus
+	// EventCount is the number of events replayed in completed chunks
+	EventCount int64
+	Error      string
+	CreatedAt  time.Time
+	StartedAt  time.Time
+	FinishedAt time.Time
+}
+
+// ReplayStore defines the contract for storing replay jobs and their checkpoints
+// This interface lives in domain layer - implementations in adapter/outbound
+type ReplayStore interface {
+	// SaveJob creates or replaces a job
+	SaveJob(ctx context.Context, job *ReplayJob) error
+
+	// FindJob returns the job with the given ID or ErrReplayNotFound
+	FindJob(ctx context.Context, id string) (*ReplayJob, error)
+
+	// ListJobs returns all jobs, most recent first
+	ListJobs(ctx context.Context) ([]*ReplayJob, error)
+}
@@ -2,16 +2,142 @@ package event
 
 import (
 	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"time"
 
 	"github.com/ebubekir/event-stream/internal/domain"
 )
 
+// ErrEventNotFound is returned when no stored event has the requested ID
+var ErrEventNotFound = errors.New("event not found")
+
+// EventFilter selects stored events. Empty fields are not filtered on.
+type EventFilter struct {
+	// From and To bound the event date to [From, To)
+	From         time.Time
+	To           time.Time
+	EventNames   []string
+	ChannelType  string
+	UserID       string
+	UserPseudoID string
+	AppID        string
+	AppVersion   string
+
+	// Device fields
+	DeviceCategory  string
+	OperatingSystem string
+	BrowserName     string
+	Language        string
+
+	// ParamKey selects events with this event param. ParamValue, if set, must
+	// equal the param's string value, its number value when ParamValue parses
+	// as a number, or a true boolean value when ParamValue is "true".
+	ParamKey   string
+	ParamValue string
+}
+
+// Matches reports whether event passes the filter. Events with an
+// unparseable date never match a filter with a time range.
+func (f EventFilter) Matches(event *domain.Event) bool {
+	if !f.From.IsZero() || !f.To.IsZero() {
+		date, err := event.OccurredAt()
+		if err != nil {
+			return false
+		}
+		if !f.From.IsZero() && date.Before(f.From) {
+			return false
+		}
+		if !f.To.IsZero() && !date.Before(f.To) {
+			return false
+		}
+	}
+	if len(f.EventNames) > 0 && !slices.Contains(f.EventNames, event.Name) {
+		return false
+	}
+
+	if f.ParamKey != "" && !slices.ContainsFunc(event.EventParams, f.matchesParam) {
+		return false
+	}
+
+	return (f.ChannelType == "" || f.ChannelType == string(event.ChannelType)) &&
+		(f.UserID == "" || f.UserID == event.UserID) &&
+		(f.UserPseudoID == "" || f.UserPseudoID == event.UserPseudoID) &&
+		(f.AppID == "" || f.AppID == event.AppInfo.ID) &&
+		(f.AppVersion == "" || f.AppVersion == event.AppInfo.Version) &&
+		(f.DeviceCategory == "" || f.DeviceCategory == event.Device.Category) &&
+		(f.OperatingSystem == "" || f.OperatingSystem == event.Device.OperatingSystem) &&
+		(f.BrowserName == "" || f.BrowserName == event.Device.BrowserName) &&
+		(f.Language == "" || f.Language == event.Device.Language)
+}
+
+func (f EventFilter) matchesParam(param domain.Param) bool {
+	if param.Key != f.ParamKey {
+		return false
+	}
+	if f.ParamValue == "" || param.StringValue == f.ParamValue {
+		return true
+	}
+	if number, ok := f.ParamNumber(); ok && param.StringValue == "" && param.NumberValue == number {
+		return true
+	}
+	return f.ParamValue == "true" && param.BooleanValue
+}
+
+// ParamFilter returns the ParamKey and ParamValue condition as a field
+// filter, or false when no param is filtered on
+func (f EventFilter) ParamFilter() (FieldFilter, bool) {
+	if f.ParamKey == "" {
+		return FieldFilter{}, false
+	}
+
+	filter := FieldFilter{Field: Field{Scope: FieldScopeEventParam, Name: f.ParamKey}, Operator: FilterExists}
+	if f.ParamValue != "" {
+		filter.Operator = FilterEquals
+		filter.Values = []string{f.ParamValue}
+	}
+	return filter, true
+}
+
+// ParamNumber returns ParamValue as a number, if it is one
+func (f EventFilter) ParamNumber() (float64, bool) {
+	number, err := strconv.ParseFloat(f.ParamValue, 64)
+	return number, err == nil
+}
+
+// ParamKeys lists the distinct param keys used by a set of events
+type ParamKeys struct {
+	EventParams []string
+	UserParams  []string
+}
+
 // EventRepository defines the contract for event persistence
 // This interface lives in domain layer - implementations in adapter/outbound
 type EventRepository interface {
 	// Save persists a single event
 	Save(ctx context.Context, event *domain.Event) error
 
-	// SaveBatch persists multiple events in a single operation
-	SaveBatch(ctx context.Context, events []*domain.Event) error
+	// SaveBatch persists multiple events in a single operation and returns the
+	// ones it stored, leaving out the events dropped because their ID was
+	// already stored, e.g. when a redelivered write is marked WithSkipStored
+	SaveBatch(ctx context.Context, events []*domain.Event) ([]*domain.Event, error)
+
+	// FindByID returns the event with the given ID or ErrEventNotFound
+	FindByID(ctx context.Context, id string) (*domain.Event, error)
+
+	// Search returns up to query.Limit events matching the query's filter,
+	// ordered by date and ID and starting after its cursor
+	Search(ctx context.Context, query *EventSearchQuery) ([]*domain.Event, error)
+
+	// Stream calls fn for each event matching filter, ordered by date and ID,
+	// without loading the result into memory. It stops at the first error fn returns.
+	Stream(ctx context.Context, filter EventFilter, fn func(*domain.Event) error) error
+
+	// ParamKeys returns the sorted distinct event and user param keys of the events matching filter
+	ParamKeys(ctx context.Context, filter EventFilter) (*ParamKeys, error)
+
+	// DeleteRange removes the events matching filter, e.g. before a window is
+	// replayed. It returns ErrUnboundedDelete unless both From and To are set.
+	DeleteRange(ctx context.Context, filter EventFilter) error
 }
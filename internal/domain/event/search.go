@@ -0,0 +1,28 @@
+package event
+
+import "time"
+
+// SortOrder orders searched events by date and ID
+type SortOrder string
+
+const (
+	SortAscending  SortOrder = "asc"
+	SortDescending SortOrder = "desc"
+)
+
+// EventCursor is the position of an event in date and ID order. Searches
+// continue strictly after it, so events stored in the meantime never shift
+// the pages that are still to be read.
+type EventCursor struct {
+	Date time.Time
+	ID   string
+}
+
+// EventSearchQuery selects a page of stored events
+type EventSearchQuery struct {
+	Filter EventFilter
+	Order  SortOrder
+	// After is the last event of the previous page; nil starts at the first page
+	After *EventCursor
+	Limit int
+}
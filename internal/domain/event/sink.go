@@ -0,0 +1,18 @@
+package event
+
+import (
+	"context"
+
+	"github.com/ebubekir/event-stream/internal/domain"
+)
+
+// EventSink receives a copy of every persisted event, e.g. to archive raw events
+// outside the primary store.
+// This interface lives in domain layer - implementations in adapter/outbound
+type EventSink interface {
+	// Write hands persisted events to the sink
+	Write(ctx context.Context, events []*domain.Event) error
+
+	// Close flushes buffered events and releases resources
+	Close(ctx context.Context) error
+}
@@ -0,0 +1,36 @@
+package event
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ebubekir/event-stream/internal/domain"
+)
+
+// ErrWebhookNotFound is returned when a webhook subscription does not exist
+var ErrWebhookNotFound = errors.New("webhook subscription not found")
+
+// WebhookRepository defines the contract for storing webhook subscriptions and delivery attempts
+// This interface lives in domain layer - implementations in adapter/outbound
+type WebhookRepository interface {
+	// Create persists a new subscription
+	Create(ctx context.Context, subscription *domain.WebhookSubscription) error
+
+	// Update replaces an existing subscription
+	Update(ctx context.Context, subscription *domain.WebhookSubscription) error
+
+	// Delete removes a subscription and its delivery history
+	Delete(ctx context.Context, id string) error
+
+	// FindByID returns the subscription with the given ID or ErrWebhookNotFound
+	FindByID(ctx context.Context, id string) (*domain.WebhookSubscription, error)
+
+	// List returns all subscriptions, oldest first
+	List(ctx context.Context) ([]*domain.WebhookSubscription, error)
+
+	// SaveDelivery records a delivery attempt
+	SaveDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error
+
+	// ListDeliveries returns a subscription's delivery attempts, most recent first
+	ListDeliveries(ctx context.Context, subscriptionID string, limit, offset int) ([]*domain.WebhookDelivery, error)
+}
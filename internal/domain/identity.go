@@ -0,0 +1,10 @@
+package domain
+
+import "time"
+
+// IdentityLink connects an anonymous user_pseudo_id to a known user_id
+type IdentityLink struct {
+	UserPseudoID string
+	UserID       string
+	LinkedAt     time.Time
+}
@@ -0,0 +1,11 @@
+package domain
+
+import "time"
+
+// QuarantinedEvent is an event held back from the events table for review,
+// e.g. because its date fell outside the acceptance window
+type QuarantinedEvent struct {
+	Event         *Event
+	Reason        string
+	QuarantinedAt time.Time
+}
@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"slices"
+	"strconv"
+	"time"
+)
+
+// WebhookSubscription describes an endpoint notified about stored events
+type WebhookSubscription struct {
+	ID     string
+	URL    string
+	Secret string // HMAC-SHA256 key used to sign payloads
+	// EventNames limits the subscription to these event names; empty matches every event
+	EventNames []string
+	// ParamFilters must all match an event param for the event to be delivered
+	ParamFilters []WebhookParamFilter
+	Enabled      bool
+	// ConsecutiveFailures counts deliveries that failed after all retries since the last success
+	ConsecutiveFailures int
+	DisabledReason      string
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+}
+
+// WebhookParamFilter matches an event param by key and value.
+// Value is compared with the param's string value, or with its number or
+// boolean value formatted as text when the string value is empty.
+type WebhookParamFilter struct {
+	Key   string
+	Value string
+}
+
+// WebhookDelivery records a single attempt to deliver an event to a subscription
+type WebhookDelivery struct {
+	ID             string
+	SubscriptionID string
+	EventID        string
+	EventName      string
+	Attempt        int
+	StatusCode     int
+	Error          string
+	Succeeded      bool
+	Duration       time.Duration
+	AttemptedAt    time.Time
+}
+
+// Matches reports whether the event should be delivered to the subscription
+func (s *WebhookSubscription) Matches(event *Event) bool {
+	if !s.Enabled {
+		return false
+	}
+
+	if len(s.EventNames) > 0 && !slices.Contains(s.EventNames, event.Name) {
+		return false
+	}
+
+	for _, filter := range s.ParamFilters {
+		if !slices.ContainsFunc(event.EventParams, filter.matches) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (f WebhookParamFilter) matches(p Param) bool {
+	if p.Key != f.Key {
+		return false
+	}
+
+	switch {
+	case p.StringValue != "":
+		return p.StringValue == f.Value
+	case p.BooleanValue:
+		return f.Value == "true"
+	default:
+		return strconv.FormatFloat(p.NumberValue, 'f', -1, 64) == f.Value
+	}
+}
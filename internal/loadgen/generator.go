@@ -0,0 +1,306 @@
+// Package loadgen produces synthetic GA4-style traffic and measures how fast
+// a target ingests it.
+package loadgen
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"github.com/ebubekir/event-stream/internal/adapter/inbound/http/dto"
+)
+
+// sessionTimeout ends a user's session after this much inactivity, as in GA4
+const sessionTimeout = 30 * time.Minute
+
+// catalogSize is the number of distinct products events refer to
+const catalogSize = 200
+
+// Generator produces a stream of events from a fixed population of users.
+// User activity follows a power law: a few users produce most events. Each
+// user keeps a device, location and session state, so events form plausible
+// sessions that start with first_visit or session_start and move through an
+// ecommerce funnel.
+//
+// A Generator is not safe for concurrent use.
+type Generator struct {
+	rng     *rand.Rand
+	zipf    *rand.Zipf
+	users   []*user
+	catalog []product
+}
+
+// user is a simulated visitor
+type user struct {
+	pseudoID string
+	userID   string // set for signed-in users
+	plan     string
+	device   *deviceProfile
+	geo      *geoProfile
+
+	sessions int   // sessions started so far
+	session  int64 // current ga_session_id; 0 before the first event
+	viewed   *product
+	cart     []product
+	lastAt   time.Time
+}
+
+type product struct {
+	id       string
+	name     string
+	brand    string
+	category string
+	price    float64
+}
+
+type deviceProfile struct {
+	weight  int
+	channel string
+	device  dto.DeviceRequest
+	app     dto.AppInfoRequest
+}
+
+type geoProfile struct {
+	weight int
+	geo    dto.GeoRequest
+}
+
+var deviceProfiles = []*deviceProfile{
+	{weight: 30, channel: "web", device: dto.DeviceRequest{Category: "desktop", OperatingSystem: "Windows", OperatingSystemVersion: "Windows 11", Language: "en-us", BrowserName: "Chrome", BrowserVersion: "126.0", Hostname: "shop.example.com"}},
+	{weight: 10, channel: "web", device: dto.DeviceRequest{Category: "desktop", OperatingSystem: "Macintosh", OperatingSystemVersion: "Macintosh 14.5", Language: "en-gb", BrowserName: "Safari", BrowserVersion: "17.5", Hostname: "shop.example.com"}},
+	{weight: 20, channel: "web", device: dto.DeviceRequest{Category: "mobile", MobileBrandName: "Apple", MobileModelName: "iPhone", OperatingSystem: "iOS", OperatingSystemVersion: "iOS 17.5", Language: "en-us", BrowserName: "Safari", BrowserVersion: "17.5", Hostname: "shop.example.com"}},
+	{weight: 15, channel: "web", device: dto.DeviceRequest{Category: "mobile", MobileBrandName: "Samsung", MobileModelName: "Galaxy S23", OperatingSystem: "Android", OperatingSystemVersion: "Android 14", Language: "de-de", BrowserName: "Chrome", BrowserVersion: "126.0", Hostname: "shop.example.com"}},
+	{weight: 12, channel: "mobile", device: dto.DeviceRequest{Category: "mobile", MobileBrandName: "Apple", MobileModelName: "iPhone 15", OperatingSystem: "iOS", OperatingSystemVersion: "iOS 17.5", Language: "en-us"}, app: dto.AppInfoRequest{ID: "com.example.shop", Version: "4.12.0"}},
+	{weight: 10, channel: "mobile", device: dto.DeviceRequest{Category: "mobile", MobileBrandName: "Google", MobileModelName: "Pixel 8", OperatingSystem: "Android", OperatingSystemVersion: "Android 14", Language: "fr-fr"}, app: dto.AppInfoRequest{ID: "com.example.shop", Version: "4.11.2"}},
+	{weight: 3, channel: "web", device: dto.DeviceRequest{Category: "tablet", MobileBrandName: "Apple", MobileModelName: "iPad", OperatingSystem: "iOS", OperatingSystemVersion: "iPadOS 17.5", Language: "ja-jp", BrowserName: "Safari", BrowserVersion: "17.5", Hostname: "shop.example.com"}},
+}
+
+var geoProfiles = []*geoProfile{
+	{weight: 35, geo: dto.GeoRequest{Continent: "Americas", SubContinent: "Northern America", Country: "United States", Region: "California", Metro: "San Francisco-Oakland-San Jose CA", City: "San Francisco"}},
+	{weight: 15, geo: dto.GeoRequest{Continent: "Americas", SubContinent: "Northern America", Country: "United States", Region: "New York", Metro: "New York NY", City: "New York"}},
+	{weight: 12, geo: dto.GeoRequest{Continent: "Europe", SubContinent: "Northern Europe", Country: "United Kingdom", Region: "England", City: "London"}},
+	{weight: 10, geo: dto.GeoRequest{Continent: "Europe", SubContinent: "Western Europe", Country: "Germany", Region: "Berlin", City: "Berlin"}},
+	{weight: 8, geo: dto.GeoRequest{Continent: "Europe", SubContinent: "Western Europe", Country: "France", Region: "Ile-de-France", City: "Paris"}},
+	{weight: 8, geo: dto.GeoRequest{Continent: "Asia", SubContinent: "Western Asia", Country: "Turkey", Region: "Istanbul", City: "Istanbul"}},
+	{weight: 7, geo: dto.GeoRequest{Continent: "Asia", SubContinent: "Eastern Asia", Country: "Japan", Region: "Tokyo", City: "Tokyo"}},
+	{weight: 5, geo: dto.GeoRequest{Continent: "Americas", SubContinent: "South America", Country: "Brazil", Region: "State of Sao Paulo", City: "Sao Paulo"}},
+}
+
+var (
+	categories = []string{"Apparel", "Shoes", "Accessories", "Home", "Electronics"}
+	brands     = []string{"Northwind", "Contoso", "Fabrikam", "Tailspin", "Litware"}
+	adjectives = []string{"Classic", "Everyday", "Premium", "Lightweight", "Organic", "Vintage"}
+	pages      = []string{"/", "/collections/new", "/collections/sale", "/search", "/account", "/help", "/blog"}
+	referrers  = []string{"", "", "https://www.google.com/", "https://www.instagram.com/", "https://news.example.org/"}
+)
+
+// NewGenerator creates a Generator for users simulated visitors. The same
+// seed produces the same events for the same sequence of times.
+func NewGenerator(users int, seed uint64) *Generator {
+	if users <= 0 {
+		users = 1
+	}
+
+	rng := rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15))
+	g := &Generator{
+		rng:   rng,
+		zipf:  rand.NewZipf(rng, 1.1, 1, uint64(users-1)),
+		users: make([]*user, users),
+	}
+
+	g.catalog = make([]product, catalogSize)
+	for i := range g.catalog {
+		category := categories[i%len(categories)]
+		g.catalog[i] = product{
+			id:       fmt.Sprintf("SKU-%05d", 10000+i),
+			name:     fmt.Sprintf("%s %s %d", adjectives[rng.IntN(len(adjectives))], category, i),
+			brand:    brands[rng.IntN(len(brands))],
+			category: category,
+			// Log-normal prices between a few and a few hundred dollars
+			price: math.Round(math.Exp(3+rng.NormFloat64()*0.8)*100) / 100,
+		}
+	}
+	return g
+}
+
+// Next returns the next event, occurring at at
+func (g *Generator) Next(at time.Time) dto.CreateEventRequest {
+	u := g.user(int(g.zipf.Uint64()))
+
+	if u.session == 0 || at.Sub(u.lastAt) > sessionTimeout {
+		return g.startSession(u, at)
+	}
+
+	switch roll := g.rng.IntN(100); {
+	case len(u.cart) > 0 && roll < 8:
+		return g.checkout(u, at)
+	case u.viewed != nil && roll < 20:
+		return g.addToCart(u, at)
+	case roll < 35:
+		return g.viewItem(u, at)
+	case roll < 50:
+		return g.event(u, at, "scroll", dto.ParamRequest{Key: "percent_scrolled", NumberValue: 90})
+	case roll < 58:
+		return g.event(u, at, "user_engagement", dto.ParamRequest{Key: "engagement_time_msec", NumberValue: float64(1000 + g.rng.IntN(60000))})
+	default:
+		return g.pageView(u, at)
+	}
+}
+
+// user returns the user at index, creating it on first use
+func (g *Generator) user(index int) *user {
+	if u := g.users[index]; u != nil {
+		return u
+	}
+
+	u := &user{
+		pseudoID: fmt.Sprintf("%d.%d", 1000000000+g.rng.Int64N(1000000000), 1600000000+index),
+		plan:     "free",
+		device:   deviceProfiles[g.pick(len(deviceProfiles), func(i int) int { return deviceProfiles[i].weight })],
+		geo:      geoProfiles[g.pick(len(geoProfiles), func(i int) int { return geoProfiles[i].weight })],
+	}
+	if g.rng.IntN(100) < 30 {
+		u.userID = fmt.Sprintf("user-%07d", index)
+		if g.rng.IntN(100) < 25 {
+			u.plan = "pro"
+		}
+	}
+	g.users[index] = u
+	return u
+}
+
+// pick returns an index in [0, n) chosen with the given weights
+func (g *Generator) pick(n int, weight func(i int) int) int {
+	total := 0
+	for i := 0; i < n; i++ {
+		total += weight(i)
+	}
+	roll := g.rng.IntN(total)
+	for i := 0; i < n; i++ {
+		if roll -= weight(i); roll < 0 {
+			return i
+		}
+	}
+	return n - 1
+}
+
+func (g *Generator) startSession(u *user, at time.Time) dto.CreateEventRequest {
+	first := u.session == 0
+	u.sessions++
+	u.session = at.Unix()
+	u.viewed = nil
+	u.cart = nil
+
+	if first {
+		return g.event(u, at, "first_visit")
+	}
+	return g.event(u, at, "session_start")
+}
+
+func (g *Generator) pageView(u *user, at time.Time) dto.CreateEventRequest {
+	page := pages[g.rng.IntN(len(pages))]
+	return g.event(u, at, "page_view",
+		dto.ParamRequest{Key: "page_location", StringValue: "https://shop.example.com" + page},
+		dto.ParamRequest{Key: "page_referrer", StringValue: referrers[g.rng.IntN(len(referrers))]},
+	)
+}
+
+func (g *Generator) viewItem(u *user, at time.Time) dto.CreateEventRequest {
+	p := g.catalog[g.rng.IntN(len(g.catalog))]
+	u.viewed = &p
+	return g.ecommerce(u, at, "view_item", p.price, []dto.ItemRequest{g.item(p, 1)},
+		dto.ParamRequest{Key: "page_location", StringValue: "https://shop.example.com/products/" + p.id},
+	)
+}
+
+func (g *Generator) addToCart(u *user, at time.Time) dto.CreateEventRequest {
+	p := *u.viewed
+	quantity := 1 + g.rng.IntN(3)
+	u.cart = append(u.cart, p)
+	u.viewed = nil
+	return g.ecommerce(u, at, "add_to_cart", p.price*float64(quantity), []dto.ItemRequest{g.item(p, quantity)})
+}
+
+// checkout emits begin_checkout, or purchase for a user who already began
+func (g *Generator) checkout(u *user, at time.Time) dto.CreateEventRequest {
+	items := make([]dto.ItemRequest, len(u.cart))
+	value := 0.0
+	for i, p := range u.cart {
+		items[i] = g.item(p, 1)
+		value += p.price
+	}
+	value = math.Round(value*100) / 100
+
+	if g.rng.IntN(100) < 60 {
+		return g.ecommerce(u, at, "begin_checkout", value, items)
+	}
+
+	transactionID := fmt.Sprintf("T-%d-%d", u.session, g.rng.IntN(1000000))
+	for i := range items {
+		items[i].RevenueInUsd = items[i].PriceInUsd * float64(items[i].Quantity)
+	}
+	u.cart = nil
+	return g.ecommerce(u, at, "purchase", value, items,
+		dto.ParamRequest{Key: "transaction_id", StringValue: transactionID},
+		dto.ParamRequest{Key: "tax", NumberValue: math.Round(value*8) / 100},
+		dto.ParamRequest{Key: "shipping", NumberValue: 4.99},
+	)
+}
+
+func (g *Generator) ecommerce(u *user, at time.Time, name string, value float64, items []dto.ItemRequest, params ...dto.ParamRequest) dto.CreateEventRequest {
+	params = append(params,
+		dto.ParamRequest{Key: "currency", StringValue: "USD"},
+		dto.ParamRequest{Key: "value", NumberValue: math.Round(value*100) / 100},
+	)
+	event := g.event(u, at, name, params...)
+	event.Items = items
+	return event
+}
+
+func (g *Generator) item(p product, quantity int) dto.ItemRequest {
+	return dto.ItemRequest{
+		ID:         p.id,
+		Name:       p.name,
+		Brand:      p.brand,
+		PriceInUsd: p.price,
+		Quantity:   quantity,
+		ListName:   p.category,
+		Params: []dto.ParamRequest{
+			{Key: "item_category", StringValue: p.category},
+		},
+	}
+}
+
+// event builds an event for u with the session params every GA4 event carries
+func (g *Generator) event(u *user, at time.Time, name string, params ...dto.ParamRequest) dto.CreateEventRequest {
+	var previous int64
+	if !u.lastAt.IsZero() {
+		previous = u.lastAt.UnixMicro()
+	}
+	u.lastAt = at
+
+	params = append(params,
+		dto.ParamRequest{Key: "ga_session_id", NumberValue: float64(u.session)},
+		dto.ParamRequest{Key: "ga_session_number", NumberValue: float64(u.sessions)},
+		dto.ParamRequest{Key: "engaged_session_event", NumberValue: 1},
+	)
+	userParams := []dto.ParamRequest{
+		{Key: "plan", StringValue: u.plan},
+	}
+
+	return dto.CreateEventRequest{
+		Name:              name,
+		ChannelType:       u.device.channel,
+		Timestamp:         at.UnixMicro(),
+		PreviousTimestamp: previous,
+		Date:              at.UTC().Format(time.RFC3339),
+		EventParams:       params,
+		UserID:            u.userID,
+		UserPseudoID:      u.pseudoID,
+		UserParams:        userParams,
+		Device:            u.device.device,
+		Geo:               u.geo.geo,
+		AppInfo:           u.device.app,
+	}
+}
@@ -0,0 +1,182 @@
+package loadgen
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ebubekir/event-stream/internal/adapter/inbound/http/dto"
+)
+
+// Config controls a load generation run
+type Config struct {
+	// Rate is the target events per second; 0 sends as fast as the target accepts
+	Rate float64
+	// Duration stops the run after this long; 0 runs until Events are sent
+	Duration time.Duration
+	// Events stops the run after this many events; 0 runs for Duration
+	Events int64
+	// BatchSize is the number of events per Send
+	BatchSize int
+	// Workers is the number of concurrent Sends
+	Workers int
+	// From and To, when set, spread event times evenly over [From, To)
+	// instead of stamping events with the time they are sent. Events must be set.
+	From time.Time
+	To   time.Time
+	// Progress receives a status line every ProgressInterval; nil disables it
+	Progress         io.Writer
+	ProgressInterval time.Duration
+}
+
+// Runner sends generated events to a target at the configured rate
+type Runner struct {
+	generator *Generator
+	target    Target
+	config    Config
+}
+
+// NewRunner creates a Runner
+func NewRunner(generator *Generator, target Target, config Config) *Runner {
+	if config.BatchSize <= 0 {
+		config.BatchSize = 1
+	}
+	if config.Workers <= 0 {
+		config.Workers = 1
+	}
+	if config.ProgressInterval <= 0 {
+		config.ProgressInterval = 5 * time.Second
+	}
+	return &Runner{generator: generator, target: target, config: config}
+}
+
+// Run sends events until Duration passes, Events are sent or ctx is
+// cancelled. Requests in flight when it stops are completed and counted.
+func (r *Runner) Run(ctx context.Context) (*Report, error) {
+	if r.config.Duration <= 0 && r.config.Events <= 0 {
+		return nil, fmt.Errorf("either a duration or an event count is required")
+	}
+	if !r.config.From.IsZero() && (r.config.Events <= 0 || !r.config.To.After(r.config.From)) {
+		return nil, fmt.Errorf("a time range requires an event count and an end after its start")
+	}
+
+	if r.config.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.config.Duration)
+		defer cancel()
+	}
+
+	stats := newStats()
+	batches := make(chan []dto.CreateEventRequest, r.config.Workers)
+	done := make(chan struct{})
+	start := time.Now()
+
+	// Sends outlive ctx so stopping does not turn in-flight requests into errors
+	sendCtx := context.WithoutCancel(ctx)
+	for i := 0; i < r.config.Workers; i++ {
+		go func() {
+			defer func() { done <- struct{}{} }()
+			for batch := range batches {
+				sent := time.Now()
+				err := r.target.Send(sendCtx, batch)
+				stats.record(len(batch), time.Since(sent), err)
+			}
+		}()
+	}
+
+	stopProgress := r.progress(stats, start)
+	r.produce(ctx, start, batches)
+	close(batches)
+	for i := 0; i < r.config.Workers; i++ {
+		<-done
+	}
+	stopProgress()
+
+	return stats.report(time.Since(start)), nil
+}
+
+// produce generates batches on schedule until the run ends
+func (r *Runner) produce(ctx context.Context, start time.Time, batches chan<- []dto.CreateEventRequest) {
+	var produced int64
+	for r.config.Events <= 0 || produced < r.config.Events {
+		size := int64(r.config.BatchSize)
+		if r.config.Events > 0 {
+			size = min(size, r.config.Events-produced)
+		}
+
+		// Pace by when this batch is due rather than sleeping a fixed
+		// interval, so slow sends do not lower the average rate
+		if r.config.Rate > 0 {
+			due := start.Add(time.Duration(float64(produced) / r.config.Rate * float64(time.Second)))
+			if wait := time.Until(due); wait > 0 {
+				select {
+				case <-time.After(wait):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+
+		batch := make([]dto.CreateEventRequest, size)
+		now := time.Now()
+		for i := range batch {
+			batch[i] = r.generator.Next(r.eventTime(now, produced+int64(i)))
+		}
+		produced += size
+
+		select {
+		case batches <- batch:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// eventTime returns the time of the n-th event
+func (r *Runner) eventTime(now time.Time, n int64) time.Time {
+	if r.config.From.IsZero() {
+		return now
+	}
+	span := r.config.To.Sub(r.config.From)
+	return r.config.From.Add(time.Duration(float64(span) * float64(n) / float64(r.config.Events)))
+}
+
+// progress prints a status line every ProgressInterval until the returned
+// function is called
+func (r *Runner) progress(stats *stats, start time.Time) func() {
+	if r.config.Progress == nil {
+		return func() {}
+	}
+
+	stop := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(r.config.ProgressInterval)
+		defer ticker.Stop()
+
+		var last *Report
+		for {
+			select {
+			case <-ticker.C:
+			case <-stop:
+				return
+			}
+
+			report := stats.report(time.Since(start))
+			rate := report.EventsPerSecond()
+			if last != nil {
+				rate = perSecond(report.Events-last.Events, report.Elapsed-last.Elapsed)
+			}
+			fmt.Fprintf(r.config.Progress, "%6s  %d events  %.1f events/s  %d failed requests  p99 %s\n",
+				report.Elapsed.Round(time.Second), report.Events, rate, report.FailedRequests, roundLatency(report.P99))
+			last = report
+		}
+	}()
+
+	return func() {
+		close(stop)
+		<-stopped
+	}
+}
@@ -0,0 +1,207 @@
+package loadgen
+
+import (
+	"fmt"
+	"io"
+	"math/bits"
+	"sort"
+	"sync"
+	"time"
+)
+
+// histogramSubBuckets is the number of linear buckets per power of two, which
+// bounds the error of a recorded latency to about 3%
+const histogramSubBuckets = 32
+
+// histogram records latencies in microseconds in log-linear buckets, so long
+// runs use constant memory
+type histogram struct {
+	counts [64 + 58*histogramSubBuckets]int64
+	total  int64
+	sum    time.Duration
+	max    time.Duration
+}
+
+func (h *histogram) record(d time.Duration) {
+	h.counts[bucket(uint64(d.Microseconds()))]++
+	h.total++
+	h.sum += d
+	h.max = max(h.max, d)
+}
+
+// quantile returns the latency below which q of the recorded latencies fall
+func (h *histogram) quantile(q float64) time.Duration {
+	if h.total == 0 {
+		return 0
+	}
+
+	rank := int64(q*float64(h.total-1)) + 1
+	var seen int64
+	for b, count := range h.counts {
+		if seen += count; seen >= rank {
+			return min(time.Duration(bucketUpper(b))*time.Microsecond, h.max)
+		}
+	}
+	return h.max
+}
+
+// bucket maps a value to its bucket: values below 64 have their own bucket,
+// larger values share one with others of the same top 6 bits
+func bucket(v uint64) int {
+	n := bits.Len64(v)
+	if n <= 6 {
+		return int(v)
+	}
+	shift := n - 6
+	return 64 + (shift-1)*histogramSubBuckets + int(v>>shift) - histogramSubBuckets
+}
+
+// bucketUpper returns the largest value in bucket b
+func bucketUpper(b int) uint64 {
+	if b < 64 {
+		return uint64(b)
+	}
+	shift := (b-64)/histogramSubBuckets + 1
+	mantissa := uint64((b-64)%histogramSubBuckets + histogramSubBuckets)
+	return (mantissa+1)<<shift - 1
+}
+
+// stats collects the outcome of every request
+type stats struct {
+	mu        sync.Mutex
+	requests  int64
+	failed    int64
+	events    int64
+	lost      int64
+	latencies histogram
+	errors    map[string]int64
+}
+
+func newStats() *stats {
+	return &stats{errors: make(map[string]int64)}
+}
+
+func (s *stats) record(events int, latency time.Duration, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests++
+	s.latencies.record(latency)
+	if err != nil {
+		s.failed++
+		s.lost += int64(events)
+		s.errors[err.Error()]++
+		return
+	}
+	s.events += int64(events)
+}
+
+// report returns a snapshot of the collected stats
+func (s *stats) report(elapsed time.Duration) *Report {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	errs := make(map[string]int64, len(s.errors))
+	for msg, count := range s.errors {
+		errs[msg] = count
+	}
+
+	report := &Report{
+		Elapsed:        elapsed,
+		Requests:       s.requests,
+		FailedRequests: s.failed,
+		Events:         s.events,
+		FailedEvents:   s.lost,
+		Errors:         errs,
+		P50:            s.latencies.quantile(0.50),
+		P90:            s.latencies.quantile(0.90),
+		P99:            s.latencies.quantile(0.99),
+		P999:           s.latencies.quantile(0.999),
+		Max:            s.latencies.max,
+	}
+	if s.requests > 0 {
+		report.Mean = s.latencies.sum / time.Duration(s.requests)
+	}
+	return report
+}
+
+// Report summarizes a run. Latencies are per request, so with batching one
+// latency covers a whole batch.
+type Report struct {
+	Elapsed        time.Duration
+	Requests       int64
+	FailedRequests int64
+	Events         int64 // events the target accepted
+	FailedEvents   int64 // events in failed requests
+	Errors         map[string]int64
+
+	Mean, P50, P90, P99, P999, Max time.Duration
+}
+
+// EventsPerSecond is the rate of accepted events
+func (r *Report) EventsPerSecond() float64 {
+	return perSecond(r.Events, r.Elapsed)
+}
+
+// RequestsPerSecond is the rate of completed requests, failed or not
+func (r *Report) RequestsPerSecond() float64 {
+	return perSecond(r.Requests, r.Elapsed)
+}
+
+// ErrorRate is the fraction of requests that failed
+func (r *Report) ErrorRate() float64 {
+	if r.Requests == 0 {
+		return 0
+	}
+	return float64(r.FailedRequests) / float64(r.Requests)
+}
+
+// Print writes the report in a human-readable form
+func (r *Report) Print(w io.Writer) {
+	fmt.Fprintf(w, "duration      %s\n", r.Elapsed.Round(time.Millisecond))
+	fmt.Fprintf(w, "events        %d accepted, %d failed\n", r.Events, r.FailedEvents)
+	fmt.Fprintf(w, "throughput    %.1f events/s, %.1f requests/s\n", r.EventsPerSecond(), r.RequestsPerSecond())
+	fmt.Fprintf(w, "requests      %d, %d failed (%.2f%%)\n", r.Requests, r.FailedRequests, 100*r.ErrorRate())
+	fmt.Fprintf(w, "latency       mean %s, p50 %s, p90 %s, p99 %s, p99.9 %s, max %s\n",
+		roundLatency(r.Mean), roundLatency(r.P50), roundLatency(r.P90),
+		roundLatency(r.P99), roundLatency(r.P999), roundLatency(r.Max))
+
+	if len(r.Errors) == 0 {
+		return
+	}
+
+	messages := make([]string, 0, len(r.Errors))
+	for msg := range r.Errors {
+		messages = append(messages, msg)
+	}
+	sort.Slice(messages, func(i, j int) bool {
+		return r.Errors[messages[i]] > r.Errors[messages[j]]
+	})
+
+	fmt.Fprintln(w, "errors")
+	for i, msg := range messages {
+		if i == 10 {
+			fmt.Fprintf(w, "  ... %d more\n", len(messages)-i)
+			break
+		}
+		fmt.Fprintf(w, "  %8d  %s\n", r.Errors[msg], msg)
+	}
+}
+
+func perSecond(n int64, elapsed time.Duration) float64 {
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(n) / elapsed.Seconds()
+}
+
+func roundLatency(d time.Duration) time.Duration {
+	switch {
+	case d >= time.Second:
+		return d.Round(time.Millisecond)
+	case d >= time.Millisecond:
+		return d.Round(10 * time.Microsecond)
+	default:
+		return d.Round(time.Microsecond)
+	}
+}
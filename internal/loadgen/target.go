@@ -0,0 +1,207 @@
+package loadgen
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+
+	eventstreamv1 "github.com/ebubekir/event-stream/api/gen/eventstream/v1"
+	"github.com/ebubekir/event-stream/internal/adapter/inbound/grpc/interceptor"
+	"github.com/ebubekir/event-stream/internal/adapter/inbound/grpc/server"
+	"github.com/ebubekir/event-stream/internal/adapter/inbound/http/dto"
+	"github.com/ebubekir/event-stream/internal/adapter/inbound/http/handler"
+	"github.com/ebubekir/event-stream/internal/application/event"
+)
+
+// sdkName identifies load generator traffic in the ingestion metadata
+const sdkName = "event-stream-loadgen/1"
+
+// Target receives generated events. Send is called concurrently; each call
+// is one request whose latency is measured.
+type Target interface {
+	Send(ctx context.Context, events []dto.CreateEventRequest) error
+	Close() error
+}
+
+// HTTPTarget posts events to the HTTP API, one per request to /v1/events or
+// all at once to /v1/events/batch
+type HTTPTarget struct {
+	client *http.Client
+	url    string
+	apiKey string
+	batch  bool
+	gzip   bool
+}
+
+// NewHTTPTarget creates an HTTPTarget for the API at baseURL
+func NewHTTPTarget(baseURL, apiKey string, batch, gzip bool) *HTTPTarget {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// Workers reuse connections instead of exhausting ephemeral ports
+	transport.MaxIdleConnsPerHost = 1024
+
+	return &HTTPTarget{
+		client: &http.Client{Transport: transport, Timeout: 30 * time.Second},
+		url:    strings.TrimRight(baseURL, "/"),
+		apiKey: apiKey,
+		batch:  batch,
+		gzip:   gzip,
+	}
+}
+
+// Send posts events; without batching each event is its own request
+func (t *HTTPTarget) Send(ctx context.Context, events []dto.CreateEventRequest) error {
+	if t.batch {
+		return t.post(ctx, "/v1/events/batch", dto.CreateEventBatchRequest{Events: events})
+	}
+	for i := range events {
+		if err := t.post(ctx, "/v1/events", &events[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close releases idle connections
+func (t *HTTPTarget) Close() error {
+	t.client.CloseIdleConnections()
+	return nil
+}
+
+func (t *HTTPTarget) post(ctx context.Context, path string, body any) error {
+	var buf bytes.Buffer
+	var w io.Writer = &buf
+	var zw *gzip.Writer
+	if t.gzip {
+		zw = gzip.NewWriter(&buf)
+		w = zw
+	}
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		return err
+	}
+	if zw != nil {
+		if err := zw.Close(); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.url+path, &buf)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(handler.SDKHeader, sdkName)
+	if t.gzip {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	if t.apiKey != "" {
+		req.Header.Set("X-API-Key", t.apiKey)
+	}
+
+	res, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 300 {
+		var payload struct {
+			Message string `json:"message"`
+		}
+		_ = json.NewDecoder(res.Body).Decode(&payload)
+		if payload.Message == "" {
+			payload.Message = http.StatusText(res.StatusCode)
+		}
+		return fmt.Errorf("HTTP %d: %s", res.StatusCode, payload.Message)
+	}
+
+	// Drain the body so the connection can be reused
+	_, _ = io.Copy(io.Discard, res.Body)
+	return nil
+}
+
+// GRPCTarget sends each batch over one StreamEvents call
+type GRPCTarget struct {
+	conn   *grpc.ClientConn
+	client eventstreamv1.EventServiceClient
+	md     metadata.MD
+}
+
+// NewGRPCTarget creates a GRPCTarget for the server at addr, without TLS
+func NewGRPCTarget(addr, apiKey string) (*GRPCTarget, error) {
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+
+	md := metadata.Pairs(server.SDKMetadataKey, sdkName)
+	if apiKey != "" {
+		md.Set(interceptor.APIKeyMetadataKey, apiKey)
+	}
+	return &GRPCTarget{conn: conn, client: eventstreamv1.NewEventServiceClient(conn), md: md}, nil
+}
+
+// Send streams events and waits for the server to store them
+func (t *GRPCTarget) Send(ctx context.Context, events []dto.CreateEventRequest) error {
+	stream, err := t.client.StreamEvents(metadata.NewOutgoingContext(ctx, t.md))
+	if err != nil {
+		return err
+	}
+	for i := range events {
+		if err := stream.Send(dto.ToProtoCreateEventRequest(&events[i])); err != nil {
+			// The server's status is returned by CloseAndRecv
+			break
+		}
+	}
+	_, err = stream.CloseAndRecv()
+	return err
+}
+
+// Close closes the connection
+func (t *GRPCTarget) Close() error {
+	return t.conn.Close()
+}
+
+// StoreTarget writes events straight to the event store, bypassing the API.
+// Like the import command it keeps historical dates and does not notify
+// webhooks, sinks or live tails, which makes it suited to seeding demo data.
+type StoreTarget struct {
+	service *event.EventService
+}
+
+// NewStoreTarget creates a StoreTarget on service
+func NewStoreTarget(service *event.EventService) *StoreTarget {
+	return &StoreTarget{service: service}
+}
+
+// Send stores events as one batch
+func (t *StoreTarget) Send(ctx context.Context, events []dto.CreateEventRequest) error {
+	now := time.Now().UTC()
+	cmds := make([]*event.CreateEventCommand, len(events))
+	for i := range events {
+		cmds[i] = events[i].ToCommand()
+		cmds[i].Ingestion = event.IngestionMetadataDTO{
+			ReceivedAt: now,
+			Endpoint:   "loadgen://store",
+			SDKName:    "event-stream-loadgen",
+			SDKVersion: "1",
+		}
+	}
+
+	_, err := t.service.ImportEvents(ctx, cmds)
+	return err
+}
+
+// Close is a no-op; the service's storage is owned by the caller
+func (t *StoreTarget) Close() error {
+	return nil
+}
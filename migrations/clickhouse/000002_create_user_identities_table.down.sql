@@ -0,0 +1,2 @@
+-- Drop identity graph table
+DROP TABLE IF EXISTS user_identities;
@@ -0,0 +1,10 @@
+-- Identity graph linking anonymous user_pseudo_id values to a user_id.
+-- ReplacingMergeTree keeps the most recent link per user_pseudo_id.
+CREATE TABLE IF NOT EXISTS user_identities
+(
+    user_pseudo_id  String,
+    user_id         String,
+    linked_at       DateTime64(3)
+)
+ENGINE = ReplacingMergeTree(linked_at)
+ORDER BY user_pseudo_id;
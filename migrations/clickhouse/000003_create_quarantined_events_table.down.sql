@@ -0,0 +1,2 @@
+-- Drop quarantined events table
+DROP TABLE IF EXISTS quarantined_events;
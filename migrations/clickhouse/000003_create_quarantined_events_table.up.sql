@@ -0,0 +1,62 @@
+-- Events held back for review, e.g. dated outside the acceptance window.
+-- Same layout as events, but not partitioned by event date so that
+-- out-of-window dates cannot create stray partitions.
+CREATE TABLE IF NOT EXISTS quarantined_events
+(
+    -- Core identifiers
+    id                           String,
+    name                         LowCardinality(String),
+    channel_type                 LowCardinality(String),
+    
+    -- Timestamps
+    timestamp                    UInt16,
+    previous_timestamp           UInt16,
+    date                         DateTime,
+    
+    -- User identifiers
+    user_id                      String,
+    user_pseudo_id               String,
+    
+    -- Event Parameters (parallel arrays pattern)
+    event_param_keys             Array(String),
+    event_param_string_values    Array(String),
+    event_param_number_values    Array(Float64),
+    event_param_boolean_values   Array(UInt8),
+    
+    -- User Parameters (parallel arrays pattern)
+    user_param_keys              Array(String),
+    user_param_string_values     Array(String),
+    user_param_number_values     Array(Float64),
+    user_param_boolean_values    Array(UInt8),
+    
+    -- Device info (flattened)
+    device_category                  LowCardinality(String),
+    device_mobile_brand_name         LowCardinality(String),
+    device_mobile_model_name         LowCardinality(String),
+    device_operating_system          LowCardinality(String),
+    device_operating_system_version  LowCardinality(String),
+    device_language                  LowCardinality(String),
+    device_browser_name              LowCardinality(String),
+    device_browser_version           LowCardinality(String),
+    device_hostname                  String,
+    
+    -- App info (flattened)
+    app_info_id                  String,
+    app_info_version             LowCardinality(String),
+    
+    -- Items (parallel arrays pattern)
+    item_ids                     Array(String),
+    item_names                   Array(String),
+    item_brands                  Array(String),
+    item_variants                Array(String),
+    item_prices_in_usd           Array(Float64),
+    item_quantities              Array(Int32),
+    item_revenues_in_usd         Array(Float64),
+
+    -- Quarantine bookkeeping
+    quarantine_reason            String,
+    quarantined_at               DateTime64(3)
+)
+ENGINE = MergeTree()
+ORDER BY (quarantined_at, id)
+SETTINGS index_granularity = 8192;
@@ -0,0 +1,20 @@
+-- Drop ingestion metadata columns
+ALTER TABLE events DROP INDEX IF EXISTS idx_request_id;
+
+ALTER TABLE events
+    DROP COLUMN IF EXISTS received_at,
+    DROP COLUMN IF EXISTS ingest_endpoint,
+    DROP COLUMN IF EXISTS request_id,
+    DROP COLUMN IF EXISTS client_ip_hash,
+    DROP COLUMN IF EXISTS sdk_name,
+    DROP COLUMN IF EXISTS sdk_version,
+    DROP COLUMN IF EXISTS api_key_id;
+
+ALTER TABLE quarantined_events
+    DROP COLUMN IF EXISTS received_at,
+    DROP COLUMN IF EXISTS ingest_endpoint,
+    DROP COLUMN IF EXISTS request_id,
+    DROP COLUMN IF EXISTS client_ip_hash,
+    DROP COLUMN IF EXISTS sdk_name,
+    DROP COLUMN IF EXISTS sdk_version,
+    DROP COLUMN IF EXISTS api_key_id;
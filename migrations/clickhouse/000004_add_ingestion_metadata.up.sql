@@ -0,0 +1,21 @@
+-- Server-populated ingestion metadata describing how each event arrived
+ALTER TABLE events
+    ADD COLUMN IF NOT EXISTS received_at      DateTime64(3),
+    ADD COLUMN IF NOT EXISTS ingest_endpoint  LowCardinality(String),
+    ADD COLUMN IF NOT EXISTS request_id       String,
+    ADD COLUMN IF NOT EXISTS client_ip_hash   String,
+    ADD COLUMN IF NOT EXISTS sdk_name         LowCardinality(String),
+    ADD COLUMN IF NOT EXISTS sdk_version      LowCardinality(String),
+    ADD COLUMN IF NOT EXISTS api_key_id       LowCardinality(String);
+
+ALTER TABLE quarantined_events
+    ADD COLUMN IF NOT EXISTS received_at      DateTime64(3),
+    ADD COLUMN IF NOT EXISTS ingest_endpoint  LowCardinality(String),
+    ADD COLUMN IF NOT EXISTS request_id       String,
+    ADD COLUMN IF NOT EXISTS client_ip_hash   String,
+    ADD COLUMN IF NOT EXISTS sdk_name         LowCardinality(String),
+    ADD COLUMN IF NOT EXISTS sdk_version      LowCardinality(String),
+    ADD COLUMN IF NOT EXISTS api_key_id       LowCardinality(String);
+
+-- Index for tracing events back to the request that delivered them
+ALTER TABLE events ADD INDEX IF NOT EXISTS idx_request_id request_id TYPE bloom_filter GRANULARITY 1;
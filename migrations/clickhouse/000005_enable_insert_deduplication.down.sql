@@ -0,0 +1,3 @@
+-- Disable insert deduplication
+ALTER TABLE events MODIFY SETTING non_replicated_deduplication_window = 0;
+ALTER TABLE quarantined_events MODIFY SETTING non_replicated_deduplication_window = 0;
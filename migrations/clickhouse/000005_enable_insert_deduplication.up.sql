@@ -0,0 +1,4 @@
+-- Remember recent insert blocks so a retried insert carrying the same
+-- insert_deduplication_token is dropped instead of double-counted
+ALTER TABLE events MODIFY SETTING non_replicated_deduplication_window = 1000;
+ALTER TABLE quarantined_events MODIFY SETTING non_replicated_deduplication_window = 1000;
@@ -0,0 +1,37 @@
+-- Webhook subscriptions notified about stored events.
+-- Updates insert a new version of the row; reads use FINAL to see the latest one.
+CREATE TABLE IF NOT EXISTS webhook_subscriptions
+(
+    id                    String,
+    url                   String,
+    secret                String,
+    event_names           Array(String),
+    param_filter_keys     Array(String),
+    param_filter_values   Array(String),
+    enabled               UInt8,
+    consecutive_failures  UInt32,
+    disabled_reason       String,
+    created_at            DateTime64(3),
+    updated_at            DateTime64(3),
+    deleted               UInt8 DEFAULT 0
+)
+ENGINE = ReplacingMergeTree(updated_at)
+ORDER BY id;
+
+-- One row per delivery attempt, kept for 30 days
+CREATE TABLE IF NOT EXISTS webhook_deliveries
+(
+    id               String,
+    subscription_id  String,
+    event_id         String,
+    event_name       LowCardinality(String),
+    attempt          UInt16,
+    status_code      UInt16,
+    error            String,
+    succeeded        UInt8,
+    duration_ms      UInt32,
+    attempted_at     DateTime64(3)
+)
+ENGINE = MergeTree()
+ORDER BY (subscription_id, attempted_at, id)
+TTL toDateTime(attempted_at) + INTERVAL 30 DAY;
@@ -0,0 +1,38 @@
+-- Drop the full payload columns and restore the original timestamp type
+ALTER TABLE events
+    DROP COLUMN IF EXISTS geo_continent,
+    DROP COLUMN IF EXISTS geo_sub_continent,
+    DROP COLUMN IF EXISTS geo_country,
+    DROP COLUMN IF EXISTS geo_region,
+    DROP COLUMN IF EXISTS geo_metro,
+    DROP COLUMN IF EXISTS geo_city,
+    DROP COLUMN IF EXISTS item_location_ids,
+    DROP COLUMN IF EXISTS item_list_ids,
+    DROP COLUMN IF EXISTS item_list_names,
+    DROP COLUMN IF EXISTS item_promotion_ids,
+    DROP COLUMN IF EXISTS item_promotion_names,
+    DROP COLUMN IF EXISTS item_param_keys,
+    DROP COLUMN IF EXISTS item_param_string_values,
+    DROP COLUMN IF EXISTS item_param_number_values,
+    DROP COLUMN IF EXISTS item_param_boolean_values,
+    MODIFY COLUMN timestamp           UInt16,
+    MODIFY COLUMN previous_timestamp  UInt16;
+
+ALTER TABLE quarantined_events
+    DROP COLUMN IF EXISTS geo_continent,
+    DROP COLUMN IF EXISTS geo_sub_continent,
+    DROP COLUMN IF EXISTS geo_country,
+    DROP COLUMN IF EXISTS geo_region,
+    DROP COLUMN IF EXISTS geo_metro,
+    DROP COLUMN IF EXISTS geo_city,
+    DROP COLUMN IF EXISTS item_location_ids,
+    DROP COLUMN IF EXISTS item_list_ids,
+    DROP COLUMN IF EXISTS item_list_names,
+    DROP COLUMN IF EXISTS item_promotion_ids,
+    DROP COLUMN IF EXISTS item_promotion_names,
+    DROP COLUMN IF EXISTS item_param_keys,
+    DROP COLUMN IF EXISTS item_param_string_values,
+    DROP COLUMN IF EXISTS item_param_number_values,
+    DROP COLUMN IF EXISTS item_param_boolean_values,
+    MODIFY COLUMN timestamp           UInt16,
+    MODIFY COLUMN previous_timestamp  UInt16;
@@ -0,0 +1,40 @@
+-- Store event payloads without loss so imported exports round-trip:
+-- microsecond timestamps, geographic information and every item field.
+ALTER TABLE events
+    MODIFY COLUMN timestamp           Int64,
+    MODIFY COLUMN previous_timestamp  Int64,
+    ADD COLUMN IF NOT EXISTS geo_continent      LowCardinality(String),
+    ADD COLUMN IF NOT EXISTS geo_sub_continent  LowCardinality(String),
+    ADD COLUMN IF NOT EXISTS geo_country        LowCardinality(String),
+    ADD COLUMN IF NOT EXISTS geo_region         LowCardinality(String),
+    ADD COLUMN IF NOT EXISTS geo_metro          LowCardinality(String),
+    ADD COLUMN IF NOT EXISTS geo_city           LowCardinality(String),
+    ADD COLUMN IF NOT EXISTS item_location_ids        Array(String),
+    ADD COLUMN IF NOT EXISTS item_list_ids            Array(String),
+    ADD COLUMN IF NOT EXISTS item_list_names          Array(String),
+    ADD COLUMN IF NOT EXISTS item_promotion_ids       Array(String),
+    ADD COLUMN IF NOT EXISTS item_promotion_names     Array(String),
+    -- Item params as one parallel-array set per item
+    ADD COLUMN IF NOT EXISTS item_param_keys            Array(Array(String)),
+    ADD COLUMN IF NOT EXISTS item_param_string_values   Array(Array(String)),
+    ADD COLUMN IF NOT EXISTS item_param_number_values   Array(Array(Float64)),
+    ADD COLUMN IF NOT EXISTS item_param_boolean_values  Array(Array(UInt8));
+
+ALTER TABLE quarantined_events
+    MODIFY COLUMN timestamp           Int64,
+    MODIFY COLUMN previous_timestamp  Int64,
+    ADD COLUMN IF NOT EXISTS geo_continent      LowCardinality(String),
+    ADD COLUMN IF NOT EXISTS geo_sub_continent  LowCardinality(String),
+    ADD COLUMN IF NOT EXISTS geo_country        LowCardinality(String),
+    ADD COLUMN IF NOT EXISTS geo_region         LowCardinality(String),
+    ADD COLUMN IF NOT EXISTS geo_metro          LowCardinality(String),
+    ADD COLUMN IF NOT EXISTS geo_city           LowCardinality(String),
+    ADD COLUMN IF NOT EXISTS item_location_ids        Array(String),
+    ADD COLUMN IF NOT EXISTS item_list_ids            Array(String),
+    ADD COLUMN IF NOT EXISTS item_list_names          Array(String),
+    ADD COLUMN IF NOT EXISTS item_promotion_ids       Array(String),
+    ADD COLUMN IF NOT EXISTS item_promotion_names     Array(String),
+    ADD COLUMN IF NOT EXISTS item_param_keys            Array(Array(String)),
+    ADD COLUMN IF NOT EXISTS item_param_string_values   Array(Array(String)),
+    ADD COLUMN IF NOT EXISTS item_param_number_values   Array(Array(Float64)),
+    ADD COLUMN IF NOT EXISTS item_param_boolean_values  Array(Array(UInt8));
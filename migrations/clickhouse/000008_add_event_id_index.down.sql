@@ -0,0 +1,2 @@
+-- Drop the event ID index
+ALTER TABLE events DROP INDEX IF EXISTS idx_id;
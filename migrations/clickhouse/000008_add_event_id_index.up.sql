@@ -0,0 +1,4 @@
+-- Bloom filter on the event ID so lookups by ID skip granules instead of
+-- scanning the whole table. Existing parts are indexed in the background.
+ALTER TABLE events ADD INDEX IF NOT EXISTS idx_id id TYPE bloom_filter GRANULARITY 1;
+ALTER TABLE events MATERIALIZE INDEX idx_id;
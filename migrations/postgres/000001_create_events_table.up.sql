@@ -0,0 +1,27 @@
+-- Create events table for analytics data
+CREATE TABLE IF NOT EXISTS events
+(
+    -- Core identifiers
+    id                  TEXT PRIMARY KEY,
+    name                TEXT        NOT NULL,
+    channel_type        TEXT        NOT NULL,
+
+    -- Timestamps
+    timestamp           BIGINT      NOT NULL DEFAULT 0,
+    previous_timestamp  BIGINT      NOT NULL DEFAULT 0,
+    date                TIMESTAMPTZ NOT NULL,
+
+    -- User identifiers
+    user_id             TEXT        NOT NULL DEFAULT '',
+    user_pseudo_id      TEXT        NOT NULL DEFAULT '',
+
+    -- Nested structures stored as JSON
+    event_params        JSONB       NOT NULL DEFAULT '[]',
+    user_params         JSONB       NOT NULL DEFAULT '[]',
+    device              JSONB       NOT NULL DEFAULT '{}',
+    app_info            JSONB       NOT NULL DEFAULT '{}',
+    items               JSONB       NOT NULL DEFAULT '[]'
+);
+
+-- Create index for metrics queries filtered by name and time range
+CREATE INDEX IF NOT EXISTS idx_events_name_date ON events (name, date);
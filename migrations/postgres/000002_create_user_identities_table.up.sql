@@ -0,0 +1,10 @@
+-- Identity graph linking anonymous user_pseudo_id values to a user_id.
+-- Each user_pseudo_id keeps only its most recent link.
+CREATE TABLE IF NOT EXISTS user_identities
+(
+    user_pseudo_id  TEXT PRIMARY KEY,
+    user_id         TEXT        NOT NULL,
+    linked_at       TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_identities_user_id ON user_identities (user_id);
@@ -0,0 +1,13 @@
+-- Events held back for review, e.g. dated outside the acceptance window
+CREATE TABLE IF NOT EXISTS quarantined_events
+(
+    LIKE events INCLUDING DEFAULTS INCLUDING CONSTRAINTS,
+
+    -- Quarantine bookkeeping
+    quarantine_reason   TEXT        NOT NULL DEFAULT '',
+    quarantined_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_quarantined_events_quarantined_at ON quarantined_events (quarantined_at DESC);
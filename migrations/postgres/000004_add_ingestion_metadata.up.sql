@@ -0,0 +1,22 @@
+-- Server-populated ingestion metadata describing how each event arrived.
+-- Rows written before this migration keep the epoch as received_at.
+ALTER TABLE events
+    ADD COLUMN IF NOT EXISTS received_at      TIMESTAMPTZ NOT NULL DEFAULT '1970-01-01 00:00:00+00',
+    ADD COLUMN IF NOT EXISTS ingest_endpoint  TEXT        NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS request_id       TEXT        NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS client_ip_hash   TEXT        NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS sdk_name         TEXT        NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS sdk_version      TEXT        NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS api_key_id       TEXT        NOT NULL DEFAULT '';
+
+ALTER TABLE quarantined_events
+    ADD COLUMN IF NOT EXISTS received_at      TIMESTAMPTZ NOT NULL DEFAULT '1970-01-01 00:00:00+00',
+    ADD COLUMN IF NOT EXISTS ingest_endpoint  TEXT        NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS request_id       TEXT        NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS client_ip_hash   TEXT        NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS sdk_name         TEXT        NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS sdk_version      TEXT        NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS api_key_id       TEXT        NOT NULL DEFAULT '';
+
+-- Index for tracing events back to the request that delivered them
+CREATE INDEX IF NOT EXISTS idx_events_request_id ON events (request_id);
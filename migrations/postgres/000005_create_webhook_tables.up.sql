@@ -0,0 +1,31 @@
+-- Webhook subscriptions notified about stored events
+CREATE TABLE IF NOT EXISTS webhook_subscriptions
+(
+    id                    TEXT PRIMARY KEY,
+    url                   TEXT        NOT NULL,
+    secret                TEXT        NOT NULL,
+    event_names           TEXT[]      NOT NULL DEFAULT '{}',
+    param_filters         JSONB       NOT NULL DEFAULT '[]',
+    enabled               BOOLEAN     NOT NULL DEFAULT TRUE,
+    consecutive_failures  INTEGER     NOT NULL DEFAULT 0,
+    disabled_reason       TEXT        NOT NULL DEFAULT '',
+    created_at            TIMESTAMPTZ NOT NULL,
+    updated_at            TIMESTAMPTZ NOT NULL
+);
+
+-- One row per delivery attempt
+CREATE TABLE IF NOT EXISTS webhook_deliveries
+(
+    id               TEXT PRIMARY KEY,
+    subscription_id  TEXT        NOT NULL REFERENCES webhook_subscriptions (id) ON DELETE CASCADE,
+    event_id         TEXT        NOT NULL,
+    event_name       TEXT        NOT NULL,
+    attempt          INTEGER     NOT NULL,
+    status_code      INTEGER     NOT NULL DEFAULT 0,
+    error            TEXT        NOT NULL DEFAULT '',
+    succeeded        BOOLEAN     NOT NULL,
+    duration_ms      BIGINT      NOT NULL DEFAULT 0,
+    attempted_at     TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_subscription ON webhook_deliveries (subscription_id, attempted_at DESC);
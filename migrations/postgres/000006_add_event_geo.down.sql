@@ -0,0 +1,4 @@
+-- Drop geographic information
+ALTER TABLE events DROP COLUMN IF EXISTS geo;
+
+ALTER TABLE quarantined_events DROP COLUMN IF EXISTS geo;
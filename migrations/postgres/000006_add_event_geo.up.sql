@@ -0,0 +1,6 @@
+-- Geographic information, stored as JSON like the other nested structures
+ALTER TABLE events
+    ADD COLUMN IF NOT EXISTS geo JSONB NOT NULL DEFAULT '{}';
+
+ALTER TABLE quarantined_events
+    ADD COLUMN IF NOT EXISTS geo JSONB NOT NULL DEFAULT '{}';
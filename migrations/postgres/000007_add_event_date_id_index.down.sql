@@ -0,0 +1,2 @@
+-- Drop the event search index
+DROP INDEX IF EXISTS idx_events_date_id;
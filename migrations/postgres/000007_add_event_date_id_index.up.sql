@@ -0,0 +1,2 @@
+-- Index for event search, which pages through events in date and ID order
+CREATE INDEX IF NOT EXISTS idx_events_date_id ON events (date, id);
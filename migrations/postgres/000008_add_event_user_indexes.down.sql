@@ -0,0 +1,3 @@
+-- Drop the user timeline indexes
+DROP INDEX IF EXISTS idx_events_user_id_date;
+DROP INDEX IF EXISTS idx_events_user_pseudo_id_date;
@@ -0,0 +1,3 @@
+-- Indexes for user timelines, which page through one user's events in date and ID order
+CREATE INDEX IF NOT EXISTS idx_events_user_id_date ON events (user_id, date, id);
+CREATE INDEX IF NOT EXISTS idx_events_user_pseudo_id_date ON events (user_pseudo_id, date, id);
@@ -0,0 +1,25 @@
+package auth
+
+import "crypto/subtle"
+
+// APIKey is a configured API key and the ID recorded with events sent using it
+type APIKey struct {
+	ID  string
+	Key string
+}
+
+// Match returns the ID of the configured key equal to provided. Keys are
+// compared in constant time.
+func Match(keys []APIKey, provided string) (string, bool) {
+	if provided == "" {
+		return "", false
+	}
+
+	for _, key := range keys {
+		if subtle.ConstantTimeCompare([]byte(key.Key), []byte(provided)) == 1 {
+			return key.ID, true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,31 @@
+package auth
+
+import "testing"
+
+func TestMatch(t *testing.T) {
+	keys := []APIKey{{ID: "web", Key: "web-secret"}, {ID: "ios", Key: "ios-secret"}}
+
+	tests := []struct {
+		name     string
+		keys     []APIKey
+		provided string
+		wantID   string
+		wantOK   bool
+	}{
+		{name: "first key", keys: keys, provided: "web-secret", wantID: "web", wantOK: true},
+		{name: "second key", keys: keys, provided: "ios-secret", wantID: "ios", wantOK: true},
+		{name: "unknown key", keys: keys, provided: "other"},
+		{name: "prefix of a key", keys: keys, provided: "web"},
+		{name: "empty key", keys: keys, provided: ""},
+		{name: "no keys configured", provided: "web-secret"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, ok := Match(tt.keys, tt.provided)
+			if id != tt.wantID || ok != tt.wantOK {
+				t.Errorf("Match() = %q, %v, want %q, %v", id, ok, tt.wantID, tt.wantOK)
+			}
+		})
+	}
+}
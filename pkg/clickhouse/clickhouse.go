@@ -222,6 +222,47 @@ func BatchInsert[T any](db *ClickHouseDb, query string, items []T) error {
 	return tx.Commit()
 }
 
+// BatchInsertWithContext sends all items to ClickHouse as a single insert block.
+// Query settings attached to ctx with WithSettings apply to the whole block,
+// so an insert_deduplication_token covers the batch as a unit.
+func BatchInsertWithContext[T any](ctx context.Context, db *ClickHouseDb, query string, items []T) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	sqlxDB, err := db.getDB()
+	if err != nil {
+		return err
+	}
+
+	tx, err := sqlxDB.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin batch transaction: %w", err)
+	}
+
+	stmt, err := tx.PrepareNamedContext(ctx, query)
+	if err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("failed to prepare batch: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, item := range items {
+		if _, err := stmt.ExecContext(ctx, item); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("batch insert failed: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// WithSettings attaches ClickHouse query settings to ctx,
+// e.g. {"insert_deduplication_token": "..."}
+func WithSettings(ctx context.Context, settings map[string]any) context.Context {
+	return clickhouse.Context(ctx, clickhouse.WithSettings(settings))
+}
+
 // ExecWithContext executes a query with custom context
 func ExecWithContext(ctx context.Context, db *ClickHouseDb, query string, args ...interface{}) error {
 	sqlxDB, err := db.getDB()
@@ -233,6 +274,16 @@ func ExecWithContext(ctx context.Context, db *ClickHouseDb, query string, args .
 	return err
 }
 
+// GetWithContext retrieves a single row with custom context
+func GetWithContext[T any](ctx context.Context, db *ClickHouseDb, dest *T, query string, args ...interface{}) error {
+	sqlxDB, err := db.getDB()
+	if err != nil {
+		return err
+	}
+
+	return sqlxDB.GetContext(ctx, dest, query, args...)
+}
+
 // SelectWithContext retrieves multiple rows with custom context
 func SelectWithContext[T any](ctx context.Context, db *ClickHouseDb, dest *[]T, query string, args ...interface{}) error {
 	sqlxDB, err := db.getDB()
@@ -253,3 +304,14 @@ func (c *ClickHouseDb) GetDatabase() string {
 func (c *ClickHouseDb) GetRawDB() (*sqlx.DB, error) {
 	return c.getDB()
 }
+
+// QueryWithContext runs a query and returns its rows for iteration, so large
+// results can be processed without loading them into memory
+func QueryWithContext(ctx context.Context, db *ClickHouseDb, query string, args ...interface{}) (*sqlx.Rows, error) {
+	sqlxDB, err := db.getDB()
+	if err != nil {
+		return nil, err
+	}
+
+	return sqlxDB.QueryxContext(ctx, query, args...)
+}
@@ -0,0 +1,472 @@
+// Package client is the Go SDK for the event-stream HTTP API.
+//
+// Events passed to Track are queued in memory and sent in the background in
+// batches to /v1/events/batch, gzip-compressed and retried with jittered
+// exponential backoff. Delivery is at-least-once: a batch whose response was
+// lost is sent again and stored twice.
+//
+//	c, err := client.New("http://localhost:8080", client.WithAPIKey("secret"))
+//	if err != nil {
+//		return err
+//	}
+//	defer c.Close(context.Background())
+//
+//	c.Track(client.NewEvent("page_view", client.ChannelTypeWeb).UserPseudoID("p-123").Build())
+package client
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Version is the SDK version reported in the X-Client-SDK header
+const Version = "0.1.0"
+
+const sdkName = "event-stream-go"
+
+var (
+	// ErrQueueFull is returned by Track when the queue is full and the drop policy is DropNewest
+	ErrQueueFull = errors.New("event queue is full")
+	// ErrClosed is returned by Track after Close
+	ErrClosed = errors.New("client is closed")
+)
+
+// APIError is an error response returned by the server
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("event-stream: %d %s", e.StatusCode, e.Message)
+}
+
+// ErrorHandler is called with events that were given up on, e.g. because the
+// server rejected them or retries were exhausted without a spool directory
+type ErrorHandler func(err error, events []Event)
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithAPIKey sends key in the X-API-Key header
+func WithAPIKey(key string) Option {
+	return func(c *Client) {
+		c.apiKey = key
+	}
+}
+
+// WithHTTPClient replaces the default HTTP client, which has a 10s timeout
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithBatchSize sets the maximum number of events per request (default 100)
+func WithBatchSize(size int) Option {
+	return func(c *Client) {
+		c.batchSize = size
+	}
+}
+
+// WithFlushInterval sets how long queued events may wait before a partial batch is sent (default 1s)
+func WithFlushInterval(interval time.Duration) Option {
+	return func(c *Client) {
+		c.flushInterval = interval
+	}
+}
+
+// WithQueue sets the number of events held in memory (default 10000) and what to drop when it is full
+func WithQueue(size int, policy DropPolicy) Option {
+	return func(c *Client) {
+		c.queueSize = size
+		c.dropPolicy = policy
+	}
+}
+
+// WithRetries sets the number of retries per request and the backoff bounds (default 5, 200ms, 30s).
+// The backoff doubles per attempt and is jittered.
+func WithRetries(maxRetries int, minBackoff, maxBackoff time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.minBackoff = minBackoff
+		c.maxBackoff = maxBackoff
+	}
+}
+
+// WithGzip enables or disables gzip request bodies (default enabled)
+func WithGzip(enabled bool) Option {
+	return func(c *Client) {
+		c.gzip = enabled
+	}
+}
+
+// WithPersistence spools batches that could not be delivered to dir and sends
+// them again later, including after a restart
+func WithPersistence(dir string) Option {
+	return func(c *Client) {
+		c.spoolDir = dir
+	}
+}
+
+// WithErrorHandler sets the handler for events that were given up on
+func WithErrorHandler(handler ErrorHandler) Option {
+	return func(c *Client) {
+		c.onError = handler
+	}
+}
+
+// Client sends events to an event-stream server
+type Client struct {
+	baseURL       string
+	apiKey        string
+	httpClient    *http.Client
+	batchSize     int
+	flushInterval time.Duration
+	queueSize     int
+	dropPolicy    DropPolicy
+	maxRetries    int
+	minBackoff    time.Duration
+	maxBackoff    time.Duration
+	gzip          bool
+	spoolDir      string
+	onError       ErrorHandler
+
+	queue      *queue
+	spool      *spool
+	spoolDirty atomic.Bool
+
+	wake      chan struct{}
+	flushReq  chan chan struct{}
+	stop      chan struct{}
+	done      chan struct{}
+	runCtx    context.Context
+	cancelRun context.CancelFunc
+	closeOnce sync.Once
+	closed    atomic.Bool
+}
+
+// New creates a Client for the server at baseURL, e.g. "http://localhost:8080",
+// and starts its background sender
+func New(baseURL string, opts ...Option) (*Client, error) {
+	c := &Client{
+		baseURL:       strings.TrimRight(baseURL, "/"),
+		httpClient:    &http.Client{Timeout: 10 * time.Second},
+		batchSize:     100,
+		flushInterval: time.Second,
+		queueSize:     10000,
+		dropPolicy:    DropNewest,
+		maxRetries:    5,
+		minBackoff:    200 * time.Millisecond,
+		maxBackoff:    30 * time.Second,
+		gzip:          true,
+		onError:       func(error, []Event) {},
+		wake:          make(chan struct{}, 1),
+		flushReq:      make(chan chan struct{}),
+		stop:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.baseURL == "" {
+		return nil, errors.New("base URL is required")
+	}
+	if c.batchSize <= 0 || c.queueSize <= 0 || c.flushInterval <= 0 {
+		return nil, errors.New("batch size, queue size and flush interval must be positive")
+	}
+
+	if c.spoolDir != "" {
+		s, err := newSpool(c.spoolDir)
+		if err != nil {
+			return nil, err
+		}
+		c.spool = s
+		c.spoolDirty.Store(true)
+	}
+
+	c.queue = newQueue(c.queueSize, c.dropPolicy)
+	c.runCtx, c.cancelRun = context.WithCancel(context.Background())
+	go c.run()
+
+	return c, nil
+}
+
+// Track queues an event to be sent in the background. It never blocks; when
+// the queue is full an event is dropped according to the drop policy.
+func (c *Client) Track(event Event) error {
+	if c.closed.Load() {
+		return ErrClosed
+	}
+
+	dropped, length := c.queue.push(event)
+	if length >= c.batchSize {
+		select {
+		case c.wake <- struct{}{}:
+		default:
+		}
+	}
+
+	if dropped != nil {
+		c.onError(ErrQueueFull, []Event{*dropped})
+		if c.dropPolicy == DropNewest {
+			return ErrQueueFull
+		}
+	}
+	return nil
+}
+
+// Pending returns the number of queued events not yet sent
+func (c *Client) Pending() int {
+	return c.queue.len()
+}
+
+// Send synchronously stores events, bypassing the queue, and returns their IDs
+func (c *Client) Send(ctx context.Context, events ...Event) ([]string, error) {
+	return c.send(ctx, events)
+}
+
+// Flush sends all queued events and waits until they are delivered, spooled or given up on
+func (c *Client) Flush(ctx context.Context) error {
+	done := make(chan struct{})
+	select {
+	case c.flushReq <- done:
+	case <-c.done:
+		return ErrClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops accepting events and sends the queued ones. When ctx expires
+// first, in-flight requests are cancelled and the remaining events are spooled
+// if persistence is enabled, otherwise passed to the error handler.
+func (c *Client) Close(ctx context.Context) error {
+	c.closeOnce.Do(func() {
+		c.closed.Store(true)
+		close(c.stop)
+	})
+
+	select {
+	case <-c.done:
+		return nil
+	case <-ctx.Done():
+		c.cancelRun()
+		<-c.done
+		return ctx.Err()
+	}
+}
+
+// run sends queued events until the client is closed
+func (c *Client) run() {
+	defer close(c.done)
+	defer c.cancelRun()
+
+	ticker := time.NewTicker(c.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.wake:
+			c.drain(false)
+		case <-ticker.C:
+			c.drain(true)
+			c.resendSpooled()
+		case done := <-c.flushReq:
+			c.drain(true)
+			close(done)
+		case <-c.stop:
+			c.drain(true)
+			return
+		}
+	}
+}
+
+// drain sends queued events in batches. Unless all is set, a final partial batch is left queued.
+func (c *Client) drain(all bool) {
+	for {
+		n := c.queue.len()
+		if n == 0 || (!all && n < c.batchSize) {
+			return
+		}
+		c.deliver(c.queue.pop(c.batchSize))
+	}
+}
+
+func (c *Client) deliver(events []Event) {
+	_, err := c.send(c.runCtx, events)
+	if err == nil {
+		return
+	}
+
+	if c.spool != nil && retryable(err) {
+		spoolErr := c.spool.save(events)
+		if spoolErr == nil {
+			c.spoolDirty.Store(true)
+			return
+		}
+		err = errors.Join(err, spoolErr)
+	}
+	c.onError(err, events)
+}
+
+// resendSpooled sends spooled batches, oldest first, until one fails again
+func (c *Client) resendSpooled() {
+	if c.spool == nil || !c.spoolDirty.Load() {
+		return
+	}
+
+	paths, err := c.spool.pending()
+	if err != nil {
+		return
+	}
+
+	for _, path := range paths {
+		events, err := c.spool.load(path)
+		if err == nil {
+			_, err = c.send(c.runCtx, events)
+			if err != nil && retryable(err) {
+				// Still unreachable; try again on a later tick
+				return
+			}
+		}
+		if err != nil {
+			c.onError(err, events)
+		}
+		_ = os.Remove(path)
+	}
+	c.spoolDirty.Store(false)
+}
+
+type batchRequest struct {
+	Events []Event `json:"events"`
+}
+
+type batchResponse struct {
+	IDs []string `json:"ids"`
+}
+
+// send posts events to /v1/events/batch, retrying transient failures
+func (c *Client) send(ctx context.Context, events []Event) ([]string, error) {
+	body, err := json.Marshal(batchRequest{Events: events})
+	if err != nil {
+		return nil, err
+	}
+
+	var resp batchResponse
+	for attempt := 0; ; attempt++ {
+		err = c.do(ctx, http.MethodPost, "/v1/events/batch", body, &resp)
+		if err == nil {
+			return resp.IDs, nil
+		}
+		if !retryable(err) || attempt >= c.maxRetries {
+			return nil, err
+		}
+
+		select {
+		case <-time.After(c.backoff(attempt)):
+		case <-ctx.Done():
+			return nil, errors.Join(err, ctx.Err())
+		}
+	}
+}
+
+// backoff returns the jittered wait before retry attempt+1
+func (c *Client) backoff(attempt int) time.Duration {
+	d := c.maxBackoff
+	if attempt < 30 {
+		d = min(c.minBackoff<<attempt, c.maxBackoff)
+	}
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + rand.N(d/2+1)
+}
+
+// retryable reports whether a request may succeed when sent again
+func retryable(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= 500
+	}
+	return true
+}
+
+// do sends a request with an optional JSON body and decodes the JSON response into out
+func (c *Client) do(ctx context.Context, method, path string, body []byte, out any) error {
+	var reader io.Reader
+	encoding := ""
+	if body != nil {
+		reader = bytes.NewReader(body)
+		if c.gzip {
+			var buf bytes.Buffer
+			zw := gzip.NewWriter(&buf)
+			if _, err := zw.Write(body); err != nil {
+				return err
+			}
+			if err := zw.Close(); err != nil {
+				return err
+			}
+			reader = &buf
+			encoding = "gzip"
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-Client-SDK", sdkName+"/"+Version)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if encoding != "" {
+		req.Header.Set("Content-Encoding", encoding)
+	}
+	if c.apiKey != "" {
+		req.Header.Set("X-API-Key", c.apiKey)
+	}
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 300 {
+		apiErr := &APIError{StatusCode: res.StatusCode, Message: http.StatusText(res.StatusCode)}
+		var payload struct {
+			Message string `json:"message"`
+		}
+		if json.NewDecoder(res.Body).Decode(&payload) == nil && payload.Message != "" {
+			apiErr.Message = payload.Message
+		}
+		return apiErr
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(res.Body).Decode(out)
+}
@@ -0,0 +1,185 @@
+package client
+
+import (
+	"time"
+)
+
+// ChannelType is the platform an event was sent from
+type ChannelType string
+
+const (
+	ChannelTypeWeb     ChannelType = "web"
+	ChannelTypeMobile  ChannelType = "mobile"
+	ChannelTypeDesktop ChannelType = "desktop"
+	ChannelTypeTV      ChannelType = "tv"
+	ChannelTypeConsole ChannelType = "console"
+	ChannelTypeOther   ChannelType = "other"
+)
+
+// Event is the payload of POST /v1/events, matching the server's CreateEventRequest
+type Event struct {
+	Name              string      `json:"name"`
+	ChannelType       ChannelType `json:"channel_type"`
+	Timestamp         int64       `json:"timestamp"`
+	PreviousTimestamp int64       `json:"previous_timestamp"`
+	Date              string      `json:"date"`
+	EventParams       []Param     `json:"event_params"`
+	UserID            string      `json:"user_id"`
+	UserPseudoID      string      `json:"user_pseudo_id"`
+	UserParams        []Param     `json:"user_params"`
+	Device            Device      `json:"device"`
+	Geo               Geo         `json:"geo"`
+	AppInfo           AppInfo     `json:"app_info"`
+	Items             []Item      `json:"items"`
+}
+
+// Param is a key with a string, number or boolean value
+type Param struct {
+	Key          string  `json:"key"`
+	StringValue  string  `json:"string_value"`
+	NumberValue  float64 `json:"number_value"`
+	BooleanValue bool    `json:"boolean_value"`
+}
+
+// StringParam creates a string parameter
+func StringParam(key, value string) Param {
+	return Param{Key: key, StringValue: value}
+}
+
+// NumberParam creates a number parameter
+func NumberParam(key string, value float64) Param {
+	return Param{Key: key, NumberValue: value}
+}
+
+// BoolParam creates a boolean parameter
+func BoolParam(key string, value bool) Param {
+	return Param{Key: key, BooleanValue: value}
+}
+
+// Device describes the device an event was sent from
+type Device struct {
+	Category               string `json:"category"`
+	MobileBrandName        string `json:"mobile_brand_name"`
+	MobileModelName        string `json:"mobile_model_name"`
+	OperatingSystem        string `json:"operating_system"`
+	OperatingSystemVersion string `json:"operating_system_version"`
+	Language               string `json:"language"`
+	BrowserName            string `json:"browser_name"`
+	BrowserVersion         string `json:"browser_version"`
+	Hostname               string `json:"hostname"`
+}
+
+// Geo describes where an event was sent from
+type Geo struct {
+	Continent    string `json:"continent"`
+	SubContinent string `json:"sub_continent"`
+	Country      string `json:"country"`
+	Region       string `json:"region"`
+	Metro        string `json:"metro"`
+	City         string `json:"city"`
+}
+
+// AppInfo identifies the app an event was sent from
+type AppInfo struct {
+	ID      string `json:"id"`
+	Version string `json:"version"`
+}
+
+// Item is a product attached to an ecommerce event
+type Item struct {
+	ID            string  `json:"id"`
+	Name          string  `json:"name"`
+	Brand         string  `json:"brand"`
+	Variant       string  `json:"variant"`
+	PriceInUsd    float64 `json:"price_in_usd"`
+	Quantity      int     `json:"quantity"`
+	RevenueInUsd  float64 `json:"revenue_in_usd"`
+	LocationId    string  `json:"location_id"`
+	ListId        string  `json:"list_id"`
+	ListName      string  `json:"list_name"`
+	PromotionId   string  `json:"promotion_id"`
+	PromotionName string  `json:"promotion_name"`
+	Params        []Param `json:"params"`
+}
+
+// EventBuilder builds an Event step by step:
+//
+//	event := client.NewEvent("purchase", client.ChannelTypeWeb).
+//		UserPseudoID("p-123").
+//		Params(client.StringParam("currency", "USD"), client.NumberParam("value", 42)).
+//		Build()
+type EventBuilder struct {
+	event Event
+}
+
+// NewEvent starts an event with the given name and channel, occurring now
+func NewEvent(name string, channel ChannelType) *EventBuilder {
+	b := &EventBuilder{event: Event{Name: name, ChannelType: channel}}
+	return b.At(time.Now())
+}
+
+// At sets when the event occurred
+func (b *EventBuilder) At(t time.Time) *EventBuilder {
+	b.event.Timestamp = t.UnixMicro()
+	b.event.Date = t.UTC().Format(time.RFC3339)
+	return b
+}
+
+// PreviousTimestamp sets when the previous event of the same name occurred
+func (b *EventBuilder) PreviousTimestamp(t time.Time) *EventBuilder {
+	b.event.PreviousTimestamp = t.UnixMicro()
+	return b
+}
+
+// UserID sets the signed-in user's ID
+func (b *EventBuilder) UserID(id string) *EventBuilder {
+	b.event.UserID = id
+	return b
+}
+
+// UserPseudoID sets the anonymous device or browser ID
+func (b *EventBuilder) UserPseudoID(id string) *EventBuilder {
+	b.event.UserPseudoID = id
+	return b
+}
+
+// Params appends event parameters
+func (b *EventBuilder) Params(params ...Param) *EventBuilder {
+	b.event.EventParams = append(b.event.EventParams, params...)
+	return b
+}
+
+// UserParams appends user properties
+func (b *EventBuilder) UserParams(params ...Param) *EventBuilder {
+	b.event.UserParams = append(b.event.UserParams, params...)
+	return b
+}
+
+// Device sets the device information
+func (b *EventBuilder) Device(device Device) *EventBuilder {
+	b.event.Device = device
+	return b
+}
+
+// Geo sets the geographic information
+func (b *EventBuilder) Geo(geo Geo) *EventBuilder {
+	b.event.Geo = geo
+	return b
+}
+
+// App sets the app ID and version
+func (b *EventBuilder) App(id, version string) *EventBuilder {
+	b.event.AppInfo = AppInfo{ID: id, Version: version}
+	return b
+}
+
+// Items appends ecommerce items
+func (b *EventBuilder) Items(items ...Item) *EventBuilder {
+	b.event.Items = append(b.event.Items, items...)
+	return b
+}
+
+// Build returns the event
+func (b *EventBuilder) Build() Event {
+	return b.event
+}
@@ -0,0 +1,224 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Aggregation groups metrics results. Besides the constants below, any
+// field a Filter takes is a dimension too, e.g. Aggregation("device.browser_name").
+type Aggregation string
+
+const (
+	AggregationNone         Aggregation = ""
+	AggregationChannel      Aggregation = "channel"
+	AggregationMinute       Aggregation = "minute"
+	AggregationFiveMinutes  Aggregation = "5min"
+	AggregationHourly       Aggregation = "hourly"
+	AggregationDaily        Aggregation = "daily"
+	AggregationWeekly       Aggregation = "weekly" // ISO weeks, starting on Monday
+	AggregationWeeklySunday Aggregation = "weekly_sunday"
+	AggregationMonthly      Aggregation = "monthly"
+	AggregationQuarterly    Aggregation = "quarterly"
+	AggregationEvent        Aggregation = "event_name" // one group per event name
+)
+
+// Dimensions groups by several aggregations at once, one group per
+// combination of their values, e.g. Dimensions(AggregationDaily, AggregationChannel)
+func Dimensions(aggregations ...Aggregation) Aggregation {
+	parts := make([]string, len(aggregations))
+	for i, aggregation := range aggregations {
+		parts[i] = string(aggregation)
+	}
+	return Aggregation(strings.Join(parts, ","))
+}
+
+// Fill controls the time buckets without events when grouping by a single
+// time bucket aggregation
+type Fill string
+
+const (
+	FillDefault Fill = ""     // zero with a single time bucket aggregation, none otherwise
+	FillZero    Fill = "zero" // every bucket from From to To, with zero counts
+	FillNull    Fill = "null" // every bucket, with Filled set and no counts
+	FillNone    Fill = "none" // only buckets with events
+)
+
+// MetricsQuery are the parameters of GET /v1/events/metrics
+type MetricsQuery struct {
+	EventName       string         // shorthand for a single entry in EventNames
+	EventNames      []string       // with EventName empty too, every event is counted
+	From            time.Time      // zero means no lower bound
+	To              time.Time      // zero means now
+	Location        *time.Location // time zone time buckets are computed in; nil is UTC, time.Local is not accepted
+	GroupBy         Aggregation
+	GroupLimit      int  // keep the most frequent groups, counting the rest as "Other"; 0 keeps all
+	Fill            Fill // time buckets without events
+	ResolveIdentity bool // count unique users by canonical identity
+	IngestEndpoint  string
+	SDKName         string
+	SDKVersion      string
+	APIKeyID        string
+	Filters         []Filter // all must match
+	Measures        []Measure
+}
+
+// Filter narrows metrics by an event field, e.g.
+// Filter{Field: "event_param.value", Operator: "gt", Values: []string{"100"}}.
+// Field is "event_param.<key>", "user_param.<key>", "device.<field>",
+// "app_info.id", "app_info.version" or "channel_type".
+type Filter struct {
+	Field    string
+	Operator string // eq, neq, in, contains, regex, gt, gte, lt, lte, exists
+	Values   []string
+}
+
+// String returns the filter in the form of the filter query parameter
+func (f Filter) String() string {
+	if len(f.Values) == 0 {
+		return f.Field + ":" + f.Operator
+	}
+	return f.Field + ":" + f.Operator + ":" + strings.Join(f.Values, ",")
+}
+
+// Measure aggregates a numeric event or user param over the counted events,
+// e.g. Measure{Function: "avg", Field: "event_param.load_time_ms"}.
+// count_distinct counts the param's distinct values of any type.
+type Measure struct {
+	Function string // sum, avg, min, max, p50, p90, p95, p99, count_distinct
+	Field    string // "event_param.<key>" or "user_param.<key>"
+}
+
+// String returns the measure in the form of the measures query parameter,
+// which also keys its values in results
+func (m Measure) String() string {
+	return m.Function + ":" + m.Field
+}
+
+func (q *MetricsQuery) values() url.Values {
+	values := url.Values{}
+	if q.EventName != "" {
+		values.Add("event_name", q.EventName)
+	}
+	for _, name := range q.EventNames {
+		values.Add("event_name", name)
+	}
+	if !q.From.IsZero() {
+		values.Set("from", q.From.Format(time.RFC3339))
+	}
+	if !q.To.IsZero() {
+		values.Set("to", q.To.Format(time.RFC3339))
+	}
+	if q.GroupBy != AggregationNone {
+		values.Set("group_by", string(q.GroupBy))
+	}
+	if q.Location != nil {
+		values.Set("timezone", q.Location.String())
+	}
+	if q.GroupLimit > 0 {
+		values.Set("group_limit", strconv.Itoa(q.GroupLimit))
+	}
+	if q.Fill != FillDefault {
+		values.Set("fill", string(q.Fill))
+	}
+	if q.ResolveIdentity {
+		values.Set("resolve_identity", strconv.FormatBool(q.ResolveIdentity))
+	}
+	optional := map[string]string{
+		"ingest_endpoint": q.IngestEndpoint,
+		"sdk_name":        q.SDKName,
+		"sdk_version":     q.SDKVersion,
+		"api_key_id":      q.APIKeyID,
+	}
+	for key, value := range optional {
+		if value != "" {
+			values.Set(key, value)
+		}
+	}
+	for _, filter := range q.Filters {
+		values.Add("filter", filter.String())
+	}
+	if len(q.Measures) > 0 {
+		measures := make([]string, len(q.Measures))
+		for i, measure := range q.Measures {
+			measures[i] = measure.String()
+		}
+		values.Set("measures", strings.Join(measures, ","))
+	}
+	return values
+}
+
+// GroupedMetric is the metrics of one group
+type GroupedMetric struct {
+	GroupKey        string              `json:"group_key"`  // GroupKeys values joined by "|"
+	GroupKeys       map[string]string   `json:"group_keys"` // value per GroupBy dimension
+	TotalCount      int64               `json:"total_count"`
+	UniqueUserCount int64               `json:"unique_user_count"`
+	Measures        map[string]*float64 `json:"measures"` // value per Measure.String(), nil without values
+	Filled          bool                `json:"filled"`   // a time bucket without events; counts are 0 with FillNull
+}
+
+// MetricsResult is the response of GET /v1/events/metrics
+type MetricsResult struct {
+	EventNames      []string
+	GroupBy         []string
+	Fill            Fill
+	Timezone        string
+	From            time.Time
+	To              time.Time
+	TotalCount      int64
+	UniqueUserCount int64
+	Measures        map[string]*float64 // value per Measure.String(), nil without values
+	GroupedMetrics  []GroupedMetric
+}
+
+type metricsResponse struct {
+	EventNames      []string            `json:"event_names"`
+	GroupBy         []string            `json:"group_by"`
+	Fill            Fill                `json:"fill"`
+	Timezone        string              `json:"timezone"`
+	From            string              `json:"from"`
+	To              string              `json:"to"`
+	TotalCount      int64               `json:"total_count"`
+	UniqueUserCount int64               `json:"unique_user_count"`
+	Measures        map[string]*float64 `json:"measures"`
+	GroupedMetrics  []GroupedMetric     `json:"grouped_metrics"`
+}
+
+// GetMetrics fetches aggregated metrics for one, several or all events
+func (c *Client) GetMetrics(ctx context.Context, query MetricsQuery) (*MetricsResult, error) {
+	var resp metricsResponse
+	if err := c.do(ctx, http.MethodGet, "/v1/events/metrics?"+query.values().Encode(), nil, &resp); err != nil {
+		return nil, err
+	}
+
+	result := &MetricsResult{
+		EventNames:      resp.EventNames,
+		GroupBy:         resp.GroupBy,
+		Fill:            resp.Fill,
+		Timezone:        resp.Timezone,
+		TotalCount:      resp.TotalCount,
+		UniqueUserCount: resp.UniqueUserCount,
+		Measures:        resp.Measures,
+		GroupedMetrics:  resp.GroupedMetrics,
+	}
+
+	var err error
+	if resp.From != "" {
+		if result.From, err = time.Parse(time.RFC3339, resp.From); err != nil {
+			return nil, fmt.Errorf("invalid from in metrics response: %w", err)
+		}
+	}
+	if resp.To != "" {
+		if result.To, err = time.Parse(time.RFC3339, resp.To); err != nil {
+			return nil, fmt.Errorf("invalid to in metrics response: %w", err)
+		}
+	}
+
+	return result, nil
+}
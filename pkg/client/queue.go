@@ -0,0 +1,70 @@
+package client
+
+import (
+	"sync"
+)
+
+// DropPolicy decides which events are dropped when the queue is full
+type DropPolicy int
+
+const (
+	// DropNewest rejects new events while the queue is full
+	DropNewest DropPolicy = iota
+	// DropOldest discards the oldest queued event to make room for the new one
+	DropOldest
+)
+
+// queue is a bounded FIFO of events waiting to be sent
+type queue struct {
+	mu     sync.Mutex
+	events []Event
+	head   int
+	size   int
+	policy DropPolicy
+}
+
+func newQueue(capacity int, policy DropPolicy) *queue {
+	return &queue{events: make([]Event, capacity), policy: policy}
+}
+
+// push adds event to the queue. It returns the event that was dropped, if any.
+func (q *queue) push(event Event) (dropped *Event, length int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.size == len(q.events) {
+		if q.policy == DropNewest {
+			return &event, q.size
+		}
+		oldest := q.events[q.head]
+		q.events[q.head] = event
+		q.head = (q.head + 1) % len(q.events)
+		return &oldest, q.size
+	}
+
+	q.events[(q.head+q.size)%len(q.events)] = event
+	q.size++
+	return nil, q.size
+}
+
+// pop removes and returns up to n events from the front of the queue
+func (q *queue) pop(n int) []Event {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	n = min(n, q.size)
+	batch := make([]Event, n)
+	for i := range batch {
+		batch[i] = q.events[q.head]
+		q.events[q.head] = Event{}
+		q.head = (q.head + 1) % len(q.events)
+	}
+	q.size -= n
+	return batch
+}
+
+func (q *queue) len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.size
+}
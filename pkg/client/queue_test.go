@@ -0,0 +1,109 @@
+package client
+
+import (
+	"slices"
+	"testing"
+)
+
+func names(events []Event) []string {
+	var names []string
+	for _, event := range events {
+		names = append(names, event.Name)
+	}
+	return names
+}
+
+func TestQueue(t *testing.T) {
+	tests := []struct {
+		name        string
+		policy      DropPolicy
+		push        []string
+		popFirst    int
+		pushAfter   []string
+		wantDropped []string
+		wantQueued  []string
+	}{
+		{
+			name:       "fifo",
+			push:       []string{"a", "b"},
+			wantQueued: []string{"a", "b"},
+		},
+		{
+			name:        "drop newest",
+			policy:      DropNewest,
+			push:        []string{"a", "b", "c", "d", "e"},
+			wantDropped: []string{"d", "e"},
+			wantQueued:  []string{"a", "b", "c"},
+		},
+		{
+			name:        "drop oldest",
+			policy:      DropOldest,
+			push:        []string{"a", "b", "c", "d", "e"},
+			wantDropped: []string{"a", "b"},
+			wantQueued:  []string{"c", "d", "e"},
+		},
+		{
+			name:       "wraps around",
+			push:       []string{"a", "b", "c"},
+			popFirst:   2,
+			pushAfter:  []string{"d", "e"},
+			wantQueued: []string{"c", "d", "e"},
+		},
+		{
+			name:        "drop oldest after wrapping",
+			policy:      DropOldest,
+			push:        []string{"a", "b", "c"},
+			popFirst:    1,
+			pushAfter:   []string{"d", "e"},
+			wantDropped: []string{"b"},
+			wantQueued:  []string{"c", "d", "e"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := newQueue(3, tt.policy)
+
+			var dropped []string
+			push := func(names []string) {
+				for _, name := range names {
+					if event, length := q.push(Event{Name: name}); event != nil {
+						dropped = append(dropped, event.Name)
+						if length != 3 {
+							t.Errorf("push(%s) length = %d while full, want 3", name, length)
+						}
+					}
+				}
+			}
+
+			push(tt.push)
+			q.pop(tt.popFirst)
+			push(tt.pushAfter)
+
+			if !slices.Equal(dropped, tt.wantDropped) {
+				t.Errorf("dropped = %v, want %v", dropped, tt.wantDropped)
+			}
+			if q.len() != len(tt.wantQueued) {
+				t.Errorf("len() = %d, want %d", q.len(), len(tt.wantQueued))
+			}
+			if got := names(q.pop(10)); !slices.Equal(got, tt.wantQueued) {
+				t.Errorf("pop() = %v, want %v", got, tt.wantQueued)
+			}
+			if q.len() != 0 || len(q.pop(1)) != 0 {
+				t.Error("queue is not empty after popping every event")
+			}
+		})
+	}
+}
+
+func TestQueuePopClearsSlots(t *testing.T) {
+	q := newQueue(2, DropNewest)
+	q.push(Event{Name: "a", EventParams: []Param{{Key: "k"}}})
+	q.pop(1)
+
+	for i, event := range q.events {
+		if event.Name != "" || event.EventParams != nil {
+			t.Errorf("slot %d still holds %+v after pop", i, event)
+		}
+	}
+}
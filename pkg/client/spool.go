@@ -0,0 +1,83 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const spoolSuffix = ".json"
+
+// spool keeps batches that could not be delivered on disk, so they are sent
+// again later or after a restart
+type spool struct {
+	dir string
+	seq atomic.Uint64
+}
+
+func newSpool(dir string) (*spool, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create spool directory: %w", err)
+	}
+	return &spool{dir: dir}, nil
+}
+
+// save writes a batch atomically so a crash never leaves a partial file behind
+func (s *spool) save(events []Event) error {
+	body, err := json.Marshal(batchRequest{Events: events})
+	if err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("batch-%020d-%06d", time.Now().UnixNano(), s.seq.Add(1))
+	tmp, err := os.CreateTemp(s.dir, name+"-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to spool events: %w", err)
+	}
+	if _, err := tmp.Write(body); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to spool events: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to spool events: %w", err)
+	}
+
+	return os.Rename(tmp.Name(), filepath.Join(s.dir, name+spoolSuffix))
+}
+
+// pending returns spooled batch files, oldest first
+func (s *spool) pending() ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), spoolSuffix) {
+			paths = append(paths, filepath.Join(s.dir, entry.Name()))
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+func (s *spool) load(path string) ([]Event, error) {
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var batch batchRequest
+	if err := json.Unmarshal(body, &batch); err != nil {
+		return nil, fmt.Errorf("corrupt spool file %s: %w", path, err)
+	}
+	return batch.Events, nil
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -25,13 +26,86 @@ type LogConfig struct {
 	Format string `mapstructure:"format" yaml:"format"` // json, console
 }
 
+type IngestionConfig struct {
+	MaxLateness       time.Duration `mapstructure:"max_lateness" yaml:"max_lateness"`                 // 0 disables the check
+	MaxFutureSkew     time.Duration `mapstructure:"max_future_skew" yaml:"max_future_skew"`           // 0 disables the check
+	OutOfWindowAction string        `mapstructure:"out_of_window_action" yaml:"out_of_window_action"` // accept, clamp, reject, quarantine
+	ClientIPSalt      string        `mapstructure:"client_ip_salt" yaml:"client_ip_salt"`             // mixed into stored client IP hashes
+}
+
+type APIKeyConfig struct {
+	ID  string `mapstructure:"id" yaml:"id"`   // recorded with every event sent using this key
+	Key string `mapstructure:"key" yaml:"key"` // value expected in the X-API-Key header
+}
+
+type AuthConfig struct {
+	APIKeys []APIKeyConfig `mapstructure:"api_keys" yaml:"api_keys"` // empty disables API key authentication
+}
+
+type KafkaConfig struct {
+	Brokers       []string      `mapstructure:"brokers" yaml:"brokers"`               // empty disables the Kafka consumer
+	Topic         string        `mapstructure:"topic" yaml:"topic"`                   // topic carrying CreateEventRequest JSON messages
+	GroupID       string        `mapstructure:"group_id" yaml:"group_id"`             // consumer group used for offset commits
+	BatchSize     int           `mapstructure:"batch_size" yaml:"batch_size"`         // messages per partition stored at once
+	FlushInterval time.Duration `mapstructure:"flush_interval" yaml:"flush_interval"` // max wait before storing a partial batch
+}
+
+type WebhookConfig struct {
+	Workers        int           `mapstructure:"workers" yaml:"workers"`                 // concurrent deliveries
+	QueueSize      int           `mapstructure:"queue_size" yaml:"queue_size"`           // pending deliveries before new ones are dropped
+	MaxAttempts    int           `mapstructure:"max_attempts" yaml:"max_attempts"`       // attempts per delivery, including the first
+	InitialBackoff time.Duration `mapstructure:"initial_backoff" yaml:"initial_backoff"` // wait before the first retry; doubles per attempt
+	MaxBackoff     time.Duration `mapstructure:"max_backoff" yaml:"max_backoff"`         // upper bound for the wait between retries
+	Timeout        time.Duration `mapstructure:"timeout" yaml:"timeout"`                 // per-request timeout
+	DisableAfter   int           `mapstructure:"disable_after" yaml:"disable_after"`     // consecutive failed deliveries before a subscription is disabled
+}
+
+type ArchiveS3Config struct {
+	Endpoint  string `mapstructure:"endpoint" yaml:"endpoint"` // host[:port] of the S3-compatible service
+	Region    string `mapstructure:"region" yaml:"region"`
+	Bucket    string `mapstructure:"bucket" yaml:"bucket"`
+	Prefix    string `mapstructure:"prefix" yaml:"prefix"` // prepended to every object key
+	AccessKey string `mapstructure:"access_key" yaml:"access_key"`
+	SecretKey string `mapstructure:"secret_key" yaml:"secret_key"`
+	UseSSL    bool   `mapstructure:"use_ssl" yaml:"use_ssl"`
+}
+
+type ArchiveConfig struct {
+	Type        string          `mapstructure:"type" yaml:"type"`                   // local, s3; empty disables the archive
+	Dir         string          `mapstructure:"dir" yaml:"dir"`                     // destination directory for the local type
+	StagingDir  string          `mapstructure:"staging_dir" yaml:"staging_dir"`     // files being written or waiting for upload
+	MaxFileSize int64           `mapstructure:"max_file_size" yaml:"max_file_size"` // uncompressed bytes before a file is rolled
+	S3          ArchiveS3Config `mapstructure:"s3" yaml:"s3"`
+}
+
+type ExportConfig struct {
+	Dir               string `mapstructure:"dir" yaml:"dir"`                                 // export job files and their output
+	MaxConcurrentJobs int    `mapstructure:"max_concurrent_jobs" yaml:"max_concurrent_jobs"` // jobs running at once; later jobs wait as pending
+}
+
+type ReplayConfig struct {
+	Dir          string        `mapstructure:"dir" yaml:"dir"`                     // replay job files and their checkpoints
+	ArchiveDir   string        `mapstructure:"archive_dir" yaml:"archive_dir"`     // archive read by the archive source; defaults to archive.dir
+	ArchiveSlack time.Duration `mapstructure:"archive_slack" yaml:"archive_slack"` // how far outside a window archive partitions are still read; 0 reads all
+	BatchSize    int           `mapstructure:"batch_size" yaml:"batch_size"`       // events written per batch
+}
+
 type AppConfig struct {
 	EnvironmentType EnvironmentType       `mapstructure:"environment_type" yaml:"environment_type"`
 	Port            string                `mapstructure:"port" yaml:"port"`
+	GRPCPort        string                `mapstructure:"grpc_port" yaml:"grpc_port"`               // empty disables the gRPC server
+	ShutdownTimeout time.Duration         `mapstructure:"shutdown_timeout" yaml:"shutdown_timeout"` // time allowed for in-flight requests on shutdown
 	DatabaseType    SupportedDatabaseType `mapstructure:"database_type" yaml:"database_type"`
 	PostgresSQLUrl  string                `mapstructure:"postgres_url" yaml:"postgres_url"`
 	ClickhouseUrl   string                `mapstructure:"clickhouse_url" yaml:"clickhouse_url"`
 	Log             LogConfig             `mapstructure:"log" yaml:"log"`
+	Ingestion       IngestionConfig       `mapstructure:"ingestion" yaml:"ingestion"`
+	Auth            AuthConfig            `mapstructure:"auth" yaml:"auth"`
+	Kafka           KafkaConfig           `mapstructure:"kafka" yaml:"kafka"`
+	Webhooks        WebhookConfig         `mapstructure:"webhooks" yaml:"webhooks"`
+	Archive         ArchiveConfig         `mapstructure:"archive" yaml:"archive"`
+	Exports         ExportConfig          `mapstructure:"exports" yaml:"exports"`
+	Replays         ReplayConfig          `mapstructure:"replays" yaml:"replays"`
 }
 
 func Read() *AppConfig {
@@ -0,0 +1,255 @@
+package postgresql
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"log"
+	"regexp"
+	"sort"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Migration represents a single migration file
+type Migration struct {
+	Version  string
+	Name     string
+	UpSQL    string
+	DownSQL  string
+	Filename string
+}
+
+// MigrationStatus represents the status of a migration
+type MigrationStatus struct {
+	Version string
+	Name    string
+	Applied bool
+}
+
+// Migrator handles database migrations for PostgreSQL
+type Migrator struct {
+	db         *PostgresDb
+	migrations []Migration
+	tableName  string
+}
+
+// NewMigrator creates a new Migrator instance from embedded filesystem
+func NewMigrator(db *PostgresDb, migrationFS embed.FS) (*Migrator, error) {
+	m := &Migrator{
+		db:        db,
+		tableName: "schema_migrations",
+	}
+
+	if err := m.loadMigrations(migrationFS); err != nil {
+		return nil, fmt.Errorf("failed to load migrations: %w", err)
+	}
+
+	return m, nil
+}
+
+// loadMigrations reads all migration files from the embedded filesystem
+func (m *Migrator) loadMigrations(migrationFS embed.FS) error {
+	// Regex to parse migration filenames: 000001_create_events_table.up.sql
+	re := regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+	migrationMap := make(map[string]*Migration)
+
+	err := fs.WalkDir(migrationFS, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		matches := re.FindStringSubmatch(d.Name())
+		if matches == nil {
+			return nil // Skip non-migration files
+		}
+
+		version, name, direction := matches[1], matches[2], matches[3]
+
+		content, err := fs.ReadFile(migrationFS, path)
+		if err != nil {
+			return fmt.Errorf("failed to read migration file %s: %w", path, err)
+		}
+
+		key := version + "_" + name
+		if _, exists := migrationMap[key]; !exists {
+			migrationMap[key] = &Migration{
+				Version:  version,
+				Name:     name,
+				Filename: key,
+			}
+		}
+
+		if direction == "up" {
+			migrationMap[key].UpSQL = string(content)
+		} else {
+			migrationMap[key].DownSQL = string(content)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	for _, migration := range migrationMap {
+		m.migrations = append(m.migrations, *migration)
+	}
+
+	sort.Slice(m.migrations, func(i, j int) bool {
+		return m.migrations[i].Version < m.migrations[j].Version
+	})
+
+	return nil
+}
+
+// ensureMigrationTable creates the schema_migrations table if it doesn't exist
+func (m *Migrator) ensureMigrationTable(ctx context.Context) error {
+	query := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			version     TEXT PRIMARY KEY,
+			name        TEXT NOT NULL,
+			applied_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		)
+	`, m.tableName)
+
+	return ExecWithContext(ctx, m.db, query)
+}
+
+// getAppliedMigrations returns a set of applied migration versions
+func (m *Migrator) getAppliedMigrations(ctx context.Context) (map[string]bool, error) {
+	var versions []string
+	query := fmt.Sprintf("SELECT version FROM %s", m.tableName)
+
+	if err := SelectWithContext(ctx, m.db, &versions, query); err != nil {
+		return nil, fmt.Errorf("failed to get applied migrations: %w", err)
+	}
+
+	applied := make(map[string]bool)
+	for _, v := range versions {
+		applied[v] = true
+	}
+
+	return applied, nil
+}
+
+// Up runs all pending migrations, each inside its own transaction
+func (m *Migrator) Up(ctx context.Context) error {
+	if err := m.ensureMigrationTable(ctx); err != nil {
+		return fmt.Errorf("failed to ensure migration table: %w", err)
+	}
+
+	applied, err := m.getAppliedMigrations(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, migration := range m.migrations {
+		if applied[migration.Version] {
+			log.Printf("[migrate] Skipping %s (already applied)", migration.Filename)
+			continue
+		}
+
+		if migration.UpSQL == "" {
+			log.Printf("[migrate] Skipping %s (no up migration)", migration.Filename)
+			continue
+		}
+
+		log.Printf("[migrate] Applying %s...", migration.Filename)
+
+		err := Transaction(m.db, func(tx *sqlx.Tx) error {
+			if _, err := tx.ExecContext(ctx, migration.UpSQL); err != nil {
+				return err
+			}
+
+			query := fmt.Sprintf("INSERT INTO %s (version, name) VALUES ($1, $2)", m.tableName)
+			_, err := tx.ExecContext(ctx, query, migration.Version, migration.Name)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to apply migration %s: %w", migration.Filename, err)
+		}
+
+		log.Printf("[migrate] Applied %s successfully", migration.Filename)
+	}
+
+	return nil
+}
+
+// Down rolls back the last applied migration
+func (m *Migrator) Down(ctx context.Context) error {
+	if err := m.ensureMigrationTable(ctx); err != nil {
+		return fmt.Errorf("failed to ensure migration table: %w", err)
+	}
+
+	applied, err := m.getAppliedMigrations(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Find the last applied migration
+	var lastApplied *Migration
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		if applied[m.migrations[i].Version] {
+			lastApplied = &m.migrations[i]
+			break
+		}
+	}
+
+	if lastApplied == nil {
+		log.Println("[migrate] No migrations to rollback")
+		return nil
+	}
+
+	if lastApplied.DownSQL == "" {
+		return fmt.Errorf("migration %s has no down migration", lastApplied.Filename)
+	}
+
+	log.Printf("[migrate] Rolling back %s...", lastApplied.Filename)
+
+	err = Transaction(m.db, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, lastApplied.DownSQL); err != nil {
+			return err
+		}
+
+		query := fmt.Sprintf("DELETE FROM %s WHERE version = $1", m.tableName)
+		_, err := tx.ExecContext(ctx, query, lastApplied.Version)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to rollback migration %s: %w", lastApplied.Filename, err)
+	}
+
+	log.Printf("[migrate] Rolled back %s successfully", lastApplied.Filename)
+	return nil
+}
+
+// Status returns the current migration status
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	if err := m.ensureMigrationTable(ctx); err != nil {
+		return nil, fmt.Errorf("failed to ensure migration table: %w", err)
+	}
+
+	applied, err := m.getAppliedMigrations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []MigrationStatus
+	for _, migration := range m.migrations {
+		statuses = append(statuses, MigrationStatus{
+			Version: migration.Version,
+			Name:    migration.Name,
+			Applied: applied[migration.Version],
+		})
+	}
+
+	return statuses, nil
+}
@@ -218,7 +218,39 @@ func Transaction(db *PostgresDb, fn func(*sqlx.Tx) error) error {
 	return tx.Commit()
 }
 
+// ExecWithContext executes a query with custom context
+func ExecWithContext(ctx context.Context, db *PostgresDb, query string, args ...interface{}) error {
+	sqlxDB, err := db.getDB()
+	if err != nil {
+		return err
+	}
+
+	_, err = sqlxDB.ExecContext(ctx, query, args...)
+	return err
+}
+
+// SelectWithContext retrieves multiple rows with custom context
+func SelectWithContext[T any](ctx context.Context, db *PostgresDb, dest *[]T, query string, args ...interface{}) error {
+	sqlxDB, err := db.getDB()
+	if err != nil {
+		return err
+	}
+
+	return sqlxDB.SelectContext(ctx, dest, query, args...)
+}
+
 // GetSchema returns the schema name
 func (p *PostgresDb) GetSchema() string {
 	return p.Schema
 }
+
+// QueryWithContext runs a query and returns its rows for iteration, so large
+// results can be processed without loading them into memory
+func QueryWithContext(ctx context.Context, db *PostgresDb, query string, args ...interface{}) (*sqlx.Rows, error) {
+	sqlxDB, err := db.getDB()
+	if err != nil {
+		return nil, err
+	}
+
+	return sqlxDB.QueryxContext(ctx, query, args...)
+}